@@ -0,0 +1,29 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// The dl command manages the Go toolchains installed by the go1.N.M and
+// gotip commands.
+//
+// To install, run:
+//
+//	$ go install github.com/rustatian/dl/dl@latest
+//
+// Run "dl help" for the list of subcommands. "dl purge" removes every
+// toolchain, the gotip tree and the archive cache.
+//
+// Given a release instead of a subcommand, dl runs that release's go
+// command, installing it first if it isn't installed, so that
+//
+//	$ dl go1.22.7 build ./...
+//
+// needs no go1.22.7 wrapper.
+package main
+
+import (
+	"github.com/rustatian/dl/internal/version"
+)
+
+func main() {
+	version.RunDL()
+}
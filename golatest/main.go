@@ -0,0 +1,23 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// The golatest command runs the go command from the newest stable Go
+// release, downloading it first if it isn't installed.
+//
+// To install, run:
+//
+//	$ go install github.com/rustatian/dl/golatest@latest
+//
+// And then use the golatest command as if it were your normal go command.
+// Which release is newest is looked up in the go.dev release listing each
+// time it runs; the listing is cached, so this only asks the server once
+// in a while. "golatest download" installs the newest release without
+// running anything.
+package main
+
+import "github.com/rustatian/dl/internal/version"
+
+func main() {
+	version.RunLatest()
+}
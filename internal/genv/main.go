@@ -18,9 +18,10 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"regexp"
 	"strings"
 	"time"
+
+	"github.com/rustatian/dl/internal/version"
 )
 
 func usage() {
@@ -36,9 +37,10 @@ func main() {
 	if err != nil {
 		failf("golangOrgDlRoot: %v", err)
 	}
-	for _, version := range os.Args[1:] {
-		if !strings.HasPrefix(version, "go") {
-			failf("version names should have the 'go' prefix")
+	for _, ver := range os.Args[1:] {
+		v, err := version.ParseVersion(ver)
+		if err != nil {
+			failf("%v", err)
 		}
 		var buf bytes.Buffer
 		if err := mainTmpl.Execute(&buf, struct {
@@ -49,14 +51,14 @@ func main() {
 			DocHost             string // "golang.org" or "tip.golang.org" for rc/beta
 		}{
 			Year:                time.Now().Year(),
-			Version:             version,
-			VersionNoPatch:      versionNoPatch(version),
-			DocHost:             docHost(version),
-			CapitalSpaceVersion: strings.Replace(version, "go", "Go ", 1),
+			Version:             ver,
+			VersionNoPatch:      versionNoPatch(v),
+			DocHost:             docHost(v),
+			CapitalSpaceVersion: strings.Replace(ver, "go", "Go ", 1),
 		}); err != nil {
 			failf("mainTmpl.execute: %v", err)
 		}
-		path := filepath.Join(dlRoot, version, "main.go")
+		path := filepath.Join(dlRoot, ver, "main.go")
 		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
 			failf("%v", err)
 		}
@@ -70,23 +72,19 @@ func main() {
 	}
 }
 
-func docHost(ver string) string {
-	if strings.Contains(ver, "rc") || strings.Contains(ver, "beta") {
+func docHost(v version.Version) string {
+	if v.Pre != "" {
 		return "tip.golang.org"
 	}
 	return "golang.org"
 }
 
-func versionNoPatch(ver string) string {
-	rx := regexp.MustCompile(`^(go\d+\.\d+)($|[\.]?\d*)($|rc|beta|\.)`)
-	m := rx.FindStringSubmatch(ver)
-	if m == nil {
-		failf("unrecognized version %q", ver)
-	}
-	if m[2] != "" {
-		return "devel/release.html#" + m[1] + ".minor"
+func versionNoPatch(v version.Version) string {
+	minor := fmt.Sprintf("go%d.%d", v.Major, v.Minor)
+	if v.Patch > 0 {
+		return "devel/release.html#" + minor + ".minor"
 	}
-	return m[1]
+	return minor
 }
 
 func failf(format string, args ...interface{}) {
@@ -7,7 +7,11 @@
 
 package main
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/rustatian/dl/internal/version"
+)
 
 func TestVersionNoPatch(t *testing.T) {
 	data := []struct {
@@ -18,9 +22,15 @@ func TestVersionNoPatch(t *testing.T) {
 		{"go1.12", "go1.12"},
 		{"go1.12beta1", "go1.12"},
 		{"go1.12rc2", "go1.12"},
+		{"go1.21.0", "go1.21"},
+		{"go1.22.3", "devel/release.html#go1.22.minor"},
 	}
 	for _, item := range data {
-		if out := versionNoPatch(item.in); out != item.out {
+		v, err := version.ParseVersion(item.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out := versionNoPatch(v); out != item.out {
 			t.Errorf("versionNoPatch(%q) = %q; want %q", item.in, out, item.out)
 		}
 	}
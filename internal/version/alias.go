@@ -0,0 +1,86 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// aliasPrefix starts the config file keys that define aliases: a line
+//
+//	alias.work = "go1.21.13"
+//
+// lets dl work, dl install work and the like stand for dl go1.21.13.
+const aliasPrefix = "alias."
+
+// aliasNameRE matches the names an alias may have.
+var aliasNameRE = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+
+// checkAlias reports whether name = target is a valid alias definition.
+// Its name must be a word that isn't itself a release name, and its target
+// an exact release name. An alias named like a dl command, such as list,
+// is shadowed by the command when given to dl.
+func checkAlias(name, target string) error {
+	if _, err := ParseVersion(name); err == nil || name == "gotip" {
+		return fmt.Errorf("alias %s: can't redefine a release", name)
+	}
+	if !aliasNameRE.MatchString(name) {
+		return fmt.Errorf("invalid alias name %q: must be a letter followed by letters, digits, - or _", name)
+	}
+	if _, err := ParseVersion(target); err != nil {
+		return fmt.Errorf("alias %s: %v", name, err)
+	}
+	return nil
+}
+
+// An Alias is a user-defined name for a release, from the config file.
+type Alias struct {
+	Name    string `json:"name"`
+	Release string `json:"release"`
+}
+
+// Aliases returns the aliases the config file defines, sorted by name.
+func (c *Config) Aliases() []Alias {
+	var list []Alias
+	for name, target := range c.aliases {
+		list = append(list, Alias{name, target})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// resolveAlias returns the release the alias name stands for, or name
+// itself if it isn't an alias.
+func (c *Config) resolveAlias(name string) string {
+	if target, ok := c.aliases[name]; ok {
+		return target
+	}
+	return name
+}
+
+// saveAlias defines the alias name as target in the config file, or, if
+// target is empty, removes it.
+func (c *Config) saveAlias(name, target string) error {
+	if target == "" {
+		if err := c.unsave(aliasPrefix + name); err != nil {
+			return err
+		}
+		delete(c.aliases, name)
+		return nil
+	}
+	if err := checkAlias(name, target); err != nil {
+		return err
+	}
+	if err := c.save(aliasPrefix+name, target); err != nil {
+		return err
+	}
+	if c.aliases == nil {
+		c.aliases = map[string]string{}
+	}
+	c.aliases[name] = target
+	return nil
+}
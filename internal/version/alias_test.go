@@ -0,0 +1,87 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckAlias(t *testing.T) {
+	tests := []struct {
+		name, target string
+		ok           bool
+	}{
+		{"work", "go1.21.13", true},
+		{"stable", "go1.22.5", true},
+		{"ci-1_x", "go1.23rc1", true},
+		{"gotip", "go1.22.7", false},
+		{"go1.22", "go1.22.7", false},
+		{"1work", "go1.22.7", false},
+		{"", "go1.22.7", false},
+		{"work", "latest", false},
+		{"work", "gotip", false},
+		{"work", "", false},
+	}
+	for _, tt := range tests {
+		if err := checkAlias(tt.name, tt.target); (err == nil) != tt.ok {
+			t.Errorf("checkAlias(%q, %q) = %v; want ok %v", tt.name, tt.target, err, tt.ok)
+		}
+	}
+}
+
+func TestAliases(t *testing.T) {
+	file := writeConfig(t, "# aliases\nalias.work = go1.21.13\nalias.stable = 'go1.22.5'\noffline = true\n")
+	c, err := LoadConfig(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Alias{{"stable", "go1.22.5"}, {"work", "go1.21.13"}}
+	if got := c.Aliases(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Aliases() = %+v; want %+v", got, want)
+	}
+	for name, want := range map[string]string{"work": "go1.21.13", "stable": "go1.22.5", "go1.20.1": "go1.20.1", "latest": "latest"} {
+		if got := c.resolveAlias(name); got != want {
+			t.Errorf("resolveAlias(%q) = %q; want %q", name, got, want)
+		}
+	}
+
+	if err := c.saveAlias("old", "go1.20.14"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.saveAlias("work", "go1.21.12"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.saveAlias("stable", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.saveAlias("missing", ""); err != nil {
+		t.Errorf("removing an undefined alias: %v", err)
+	}
+	if err := c.saveAlias("go1.22", "go1.22.7"); err == nil {
+		t.Errorf("saveAlias of a release name succeeded; want an error")
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantFile := "# aliases\nalias.work = \"go1.21.12\"\noffline = true\nalias.old = \"go1.20.14\"\n"
+	if string(data) != wantFile {
+		t.Errorf("config file is\n%s\nwant\n%s", data, wantFile)
+	}
+	c, err = LoadConfig(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []Alias{{"old", "go1.20.14"}, {"work", "go1.21.12"}}
+	if got := c.Aliases(); !reflect.DeepEqual(got, want) {
+		t.Errorf("after saving, Aliases() = %+v; want %+v", got, want)
+	}
+	if strings.Contains(string(data), "stable") {
+		t.Errorf("removed alias is still in the config file")
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// An authDoer makes requests with d, adding credentials to those over
+// https that carry none: token, as a bearer token, for the hosts in
+// tokenHosts, and otherwise the login and password netrc has for the
+// host, if any. The client drops them if the request is redirected to
+// another host.
+type authDoer struct {
+	d          Doer
+	token      string
+	tokenHosts map[string]bool
+	netrc      []netrcLine
+}
+
+func (a *authDoer) Do(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme != "https" || req.Header.Get("Authorization") != "" {
+		return a.d.Do(req)
+	}
+	if a.token != "" && a.tokenHosts[req.URL.Host] {
+		r := req.Clone(req.Context())
+		r.Header.Set("Authorization", "Bearer "+a.token)
+		return a.d.Do(r)
+	}
+	for _, l := range a.netrc {
+		if l.machine == req.URL.Hostname() {
+			r := req.Clone(req.Context())
+			r.SetBasicAuth(l.login, l.password)
+			return a.d.Do(r)
+		}
+	}
+	return a.d.Do(req)
+}
+
+// newAuthDoer returns d with the credentials opts configures added to its
+// requests, or d itself if there are none. The token is for the hosts of
+// the base URL, the mirrors and the listing that opts names, but never
+// for the public download site or its mirror for China.
+func newAuthDoer(d Doer, opts *DownloaderOptions) (Doer, error) {
+	a := &authDoer{d: d, token: opts.Token, tokenHosts: map[string]bool{}}
+	if opts.Token != "" {
+		for _, s := range append([]string{opts.BaseURL, opts.CatalogURL}, opts.Mirrors...) {
+			if u, err := url.Parse(s); err == nil && u.Host != "" && !publicHosts[u.Host] {
+				a.tokenHosts[u.Host] = true
+			}
+		}
+	}
+	if opts.Netrc != "" {
+		data, err := ioutil.ReadFile(opts.Netrc)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		a.netrc = parseNetrc(string(data))
+	}
+	if len(a.tokenHosts) == 0 && len(a.netrc) == 0 {
+		return d, nil
+	}
+	return a, nil
+}
+
+// publicHosts are the hosts of the public download site, the release
+// listing and their mirror for China, which are never sent a token.
+var publicHosts = map[string]bool{
+	"dl.google.com":    true,
+	"go.dev":           true,
+	"golang.google.cn": true,
+}
+
+// A netrcLine is the login and password a .netrc file has for a machine.
+type netrcLine struct {
+	machine  string
+	login    string
+	password string
+}
+
+// parseNetrc parses a .netrc file, as the go command does: the default
+// entry is ignored, and so are macros.
+func parseNetrc(data string) []netrcLine {
+	var nrc []netrcLine
+	var l netrcLine
+	inMacro := false
+	for _, line := range strings.Split(data, "\n") {
+		if inMacro {
+			if line == "" {
+				inMacro = false
+			}
+			continue
+		}
+
+		f := strings.Fields(line)
+		i := 0
+		for ; i < len(f)-1; i += 2 {
+			// Reset at each "machine" token.
+			// "The auto-login process searches the .netrc file for a
+			// machine token that matches […]. Once a match is made, the
+			// subsequent .netrc tokens are processed, stopping when the
+			// end of file is reached or another machine or a default
+			// token is encountered."
+			switch f[i] {
+			case "machine":
+				l = netrcLine{machine: f[i+1]}
+			case "default":
+				// "There can be only one default token, and it must be
+				// after all machine tokens."
+				return nrc
+			case "login":
+				l.login = f[i+1]
+			case "password":
+				l.password = f[i+1]
+			case "macdef":
+				// "A macro is defined with the specified name; its
+				// contents begin with the next .netrc line and continue
+				// until a null line (consecutive new-line characters) is
+				// encountered."
+				inMacro = true
+			}
+			if l.machine != "" && l.login != "" && l.password != "" {
+				nrc = append(nrc, l)
+				l = netrcLine{}
+			}
+		}
+
+		if i < len(f) && f[i] == "default" {
+			break
+		}
+	}
+	return nrc
+}
+
+// defaultNetrc returns the .netrc file the commands read credentials
+// from: the one NETRC names, or the one in the home directory, which is
+// _netrc on Windows.
+func defaultNetrc() string {
+	if file := os.Getenv("NETRC"); file != "" {
+		return file
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	name := ".netrc"
+	if runtime.GOOS == "windows" {
+		name = "_netrc"
+	}
+	return filepath.Join(home, name)
+}
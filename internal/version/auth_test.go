@@ -0,0 +1,109 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseNetrc(t *testing.T) {
+	got := parseNetrc(`machine artifactory.example.com login ci password s3cret
+# a comment line is just tokens that match nothing
+machine nexus.example.com
+	login dev
+	password hunter2
+macdef init
+machine ignored.example.com login x password y
+
+machine git.example.com login me password pw
+default login anon password anon
+machine after.example.com login a password b
+`)
+	want := []netrcLine{
+		{"artifactory.example.com", "ci", "s3cret"},
+		{"nexus.example.com", "dev", "hunter2"},
+		{"git.example.com", "me", "pw"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseNetrc = %+v; want %+v", got, want)
+	}
+}
+
+// headerDoer records the Authorization header of each request.
+type headerDoer struct{ auth []string }
+
+func (h *headerDoer) Do(req *http.Request) (*http.Response, error) {
+	h.auth = append(h.auth, req.Header.Get("Authorization"))
+	return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+}
+
+func TestAuthDoer(t *testing.T) {
+	netrc := filepath.Join(t.TempDir(), ".netrc")
+	writeTestFile(t, netrc, []byte("machine nexus.example.com login dev password hunter2\nmachine dl.google.com login me password pw\n"))
+	h := &headerDoer{}
+	d, err := newAuthDoer(h, &DownloaderOptions{
+		BaseURL: "https://artifactory.example.com/go/",
+		Mirrors: []string{"https://nexus.example.com/go/", DefaultBaseURL},
+		Token:   "t0ken",
+		Netrc:   netrc,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, url := range []string{
+		"https://artifactory.example.com/go/go1.22.7.linux-amd64.tar.gz",
+		"http://artifactory.example.com/go/go1.22.7.linux-amd64.tar.gz",
+		"https://nexus.example.com/go/go1.22.7.linux-amd64.tar.gz.sha256",
+		DefaultBaseURL + "go1.22.7.linux-amd64.tar.gz",
+		"https://example.com/",
+	} {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := d.Do(req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []string{
+		"Bearer t0ken",
+		"", // not over https
+		"Bearer t0ken",
+		"Basic bWU6cHc=", // the netrc's, but never the token
+		"",
+	}
+	if !reflect.DeepEqual(h.auth, want) {
+		t.Errorf("Authorization headers = %q; want %q", h.auth, want)
+	}
+
+	// Without credentials, nothing is wrapped.
+	if got, err := newAuthDoer(h, &DownloaderOptions{Netrc: filepath.Join(t.TempDir(), "missing")}); err != nil || got != Doer(h) {
+		t.Errorf("newAuthDoer without credentials = %T, %v; want the Doer itself", got, err)
+	}
+}
+
+func TestConfigNetrc(t *testing.T) {
+	c, err := LoadConfig(writeConfig(t, "netrc = \"\"\ntoken = \"t0ken\"\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts, err := c.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.Netrc != "" || opts.Token != "t0ken" {
+		t.Errorf("Options() = Netrc %q, Token %q; want none and t0ken", opts.Netrc, opts.Token)
+	}
+	for _, s := range c.Settings() {
+		if s.Key == "token" && s.Value != "xxxxx" {
+			t.Errorf("Settings() shows the token as %q; want it redacted", s.Value)
+		}
+	}
+}
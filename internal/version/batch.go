@@ -0,0 +1,220 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxParallelInstalls bounds how many releases dl install installs at
+// once when given several.
+const maxParallelInstalls = 3
+
+// installBatch installs the releases versions concurrently, at most
+// maxParallelInstalls at a time, as dl install does when given several,
+// reporting their progress to bp and recording each install in the
+// journal. A release that locked has a Lockfile for is installed from the
+// archive it pins, as dl sync installs them. It returns the error of
+// each, in the order of versions.
+func installBatch(ctx context.Context, opts DownloaderOptions, versions []string, locked map[string]*Lockfile, bp *batchProgress) []error {
+	errs := make([]error, len(versions))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxParallelInstalls)
+	for i, v := range versions {
+		wg.Add(1)
+		go func(i int, v string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			errs[i] = batchRelease(ctx, opts, v, locked[v], bp)
+			bp.finish(v, errs[i])
+		}(i, v)
+	}
+	wg.Wait()
+	return errs
+}
+
+// batchRelease installs the release version for installBatch, from the
+// archive l pins unless l is nil. Its events go both to bp and to the
+// journal.
+func batchRelease(ctx context.Context, opts DownloaderOptions, version string, l *Lockfile, bp *batchProgress) error {
+	root, err := goroot(version)
+	if err != nil {
+		return err
+	}
+	events := make(chan Event, 64)
+	opts.Events = events
+	opts.Quiet = true
+	d, err := NewDownloader(opts)
+	if err != nil {
+		return err
+	}
+	rec := newJournalRecorder(version)
+	forwarded := make(chan struct{})
+	go func() {
+		defer close(forwarded)
+		for ev := range events {
+			bp.update(version, ev)
+			rec.events <- ev
+		}
+	}()
+	if l != nil {
+		err = d.installLocked(ctx, root, l)
+	} else {
+		err = d.install(ctx, root, version)
+	}
+	close(events)
+	<-forwarded
+	if e, ok := rec.wait(); ok {
+		arch, _ := d.arch()
+		e.Platform = getOS() + "/" + arch
+		recordInstall(e)
+	}
+	return err
+}
+
+// A batchProgress shows the progress of several installs at once. On a
+// terminal, a status line shows the state of each, such as
+//
+//	go1.20.14 done, go1.21.13 downloading 45%, go1.22.5 waiting
+//
+// otherwise a line is logged whenever nothing has been logged for a
+// while. The installs' own log lines, such as the one each logs when it
+// succeeds, go through w, which keeps them clear of the status line.
+type batchProgress struct {
+	w         io.Writer // where log lines go
+	status    io.Writer // the terminal for the status line, or nil
+	keepalive time.Duration
+
+	mu         sync.Mutex
+	names      []string
+	state      map[string]string
+	last       time.Time // of the last line logged
+	drawn      bool      // whether the status line is on the terminal
+	stop, done chan struct{}
+}
+
+// newBatchProgress starts reporting the installs of names, logging to w.
+// status, if non-nil, is the terminal to draw the status line on. The
+// caller should make bp the log's output while the installs run. close
+// must be called when they end.
+func newBatchProgress(names []string, w, status io.Writer, keepalive time.Duration) *batchProgress {
+	bp := &batchProgress{
+		w:         w,
+		status:    status,
+		keepalive: keepalive,
+		names:     names,
+		state:     make(map[string]string),
+		last:      time.Now(),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	for _, name := range names {
+		bp.state[name] = "waiting"
+	}
+	tick := time.Second
+	if keepalive < tick {
+		tick = keepalive
+	}
+	go func() {
+		defer close(bp.done)
+		t := time.NewTicker(tick)
+		defer t.Stop()
+		for {
+			select {
+			case <-bp.stop:
+				return
+			case <-t.C:
+				bp.tick()
+			}
+		}
+	}()
+	return bp
+}
+
+func (bp *batchProgress) tick() {
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	if bp.status != nil {
+		fmt.Fprintf(bp.status, "\r\x1b[K%s", bp.describe())
+		bp.drawn = true
+		return
+	}
+	if now := time.Now(); now.Sub(bp.last) >= bp.keepalive {
+		fmt.Fprintf(bp.w, "Installing: %s\n", bp.describe())
+		bp.last = now
+	}
+}
+
+// describe returns the state of every install.
+func (bp *batchProgress) describe() string {
+	var parts []string
+	for _, name := range bp.names {
+		parts = append(parts, name+" "+bp.state[name])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// update notes the event ev of the install of name.
+func (bp *batchProgress) update(name string, ev Event) {
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	switch ev := ev.(type) {
+	case ResolutionDone:
+		bp.state[name] = "downloading"
+	case DownloadProgress:
+		if ev.Total > 0 {
+			bp.state[name] = fmt.Sprintf("downloading %d%%", 100*ev.Bytes/ev.Total)
+		}
+	case VerificationResult:
+		bp.state[name] = "verifying"
+	case UnpackProgress:
+		bp.state[name] = fmt.Sprintf("unpacking (%d files)", ev.Files)
+	case Completed:
+		bp.state[name] = "done"
+	case Failed:
+		bp.state[name] = "failed"
+	}
+}
+
+// finish notes that the install of name ended, failing with err if it
+// isn't nil, in case its last event was dropped.
+func (bp *batchProgress) finish(name string, err error) {
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	bp.state[name] = "done"
+	if err != nil {
+		bp.state[name] = "failed"
+	}
+}
+
+// Write writes the log line p to bp's log, clearing the status line first.
+func (bp *batchProgress) Write(p []byte) (int, error) {
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	if bp.drawn {
+		io.WriteString(bp.status, "\r\x1b[K")
+		bp.drawn = false
+	}
+	bp.last = time.Now()
+	return bp.w.Write(p)
+}
+
+// close stops reporting, and removes the status line.
+func (bp *batchProgress) close() {
+	close(bp.stop)
+	<-bp.done
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	if bp.drawn {
+		io.WriteString(bp.status, "\r\x1b[K")
+		bp.drawn = false
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInstallBatch(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "config"))
+	t.Setenv("AppData", filepath.Join(home, "config"))
+	sdk := t.TempDir()
+	old := defaultLocator
+	defaultLocator = &Locator{Root: sdk}
+	t.Cleanup(func() { defaultLocator = old })
+
+	ts := newTestServer(t)
+	versions := []string{"go1.97", "go1.98", "go1.99"}
+	var out bytes.Buffer
+	bp := newBatchProgress(versions, &out, nil, time.Hour)
+	errs := installBatch(context.Background(), DownloaderOptions{Client: ts.client()}, versions, nil, bp)
+	bp.close()
+	for i, v := range versions {
+		if errs[i] != nil {
+			t.Errorf("installing %s: %v", v, errs[i])
+		}
+		if _, err := os.Stat(filepath.Join(sdk, v, unpackedOkay)); err != nil {
+			t.Errorf("%s isn't unpacked: %v", v, err)
+		}
+	}
+	if got, want := bp.describe(), "go1.97 done, go1.98 done, go1.99 done"; got != want {
+		t.Errorf("describe() = %q; want %q", got, want)
+	}
+
+	file, err := JournalFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	journal, err := ReadJournal(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range journal {
+		got = append(got, e.Toolchain)
+	}
+	if len(got) != len(versions) {
+		t.Errorf("journal records %v; want an install of each of %v", got, versions)
+	}
+}
+
+func TestBatchProgress(t *testing.T) {
+	var out, status bytes.Buffer
+	bp := newBatchProgress([]string{"go1.20.14", "go1.21.13", "go1.22.5"}, &out, &status, time.Hour)
+	bp.update("go1.20.14", Completed{})
+	bp.update("go1.21.13", ResolutionDone{})
+	bp.update("go1.21.13", DownloadProgress{Bytes: 45, Total: 100})
+	if got, want := bp.describe(), "go1.20.14 done, go1.21.13 downloading 45%, go1.22.5 waiting"; got != want {
+		t.Errorf("describe() = %q; want %q", got, want)
+	}
+	bp.finish("go1.22.5", errors.New("no binary release"))
+	if got := bp.describe(); !strings.HasSuffix(got, "go1.22.5 failed") {
+		t.Errorf("describe() = %q after go1.22.5 failed", got)
+	}
+
+	// A log line clears the status line before it is written.
+	bp.tick()
+	if !strings.Contains(status.String(), "go1.21.13 downloading 45%") {
+		t.Errorf("status line = %q; want the state of each install", status.String())
+	}
+	status.Reset()
+	bp.Write([]byte("Success. You may now run 'go1.20.14'\n"))
+	if status.String() != "\r\x1b[K" || out.String() != "Success. You may now run 'go1.20.14'\n" {
+		t.Errorf("after Write, status = %q, log = %q; want the status line cleared and the line logged", status.String(), out.String())
+	}
+	bp.close()
+}
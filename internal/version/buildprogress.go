@@ -0,0 +1,162 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// bannerRE matches the lines make.bash, make.bat and cmd/dist print as
+// they start each phase of the build, such as
+//
+//	Building Go cmd/dist using /usr/lib/go. (go1.22.1 linux/amd64)
+//	Building Go toolchain2 using go_bootstrap and Go toolchain1.
+//	Building packages and commands for linux/amd64.
+var bannerRE = regexp.MustCompile(`^Building (.+?)(?: using .*| for .*)?\.(?: \(.*\))?$`)
+
+// buildBanner returns the phase that a line of build output announces,
+// such as "Go toolchain2", or "" if the line is not a banner. Should the
+// banners change, the build is merely reported in less detail.
+func buildBanner(line string) string {
+	m := bannerRE.FindStringSubmatch(line)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
+// keepaliveInterval is how long a build may be silent, when not on a
+// terminal, before a line is logged to show that it is still going.
+const keepaliveInterval = 30 * time.Second
+
+// A buildProgress reports the phases of a gotip build, which it learns
+// from the build's output, while the output passes through it untouched.
+// Each phase is sent as a BuildProgress event and to the BuildPhase hook.
+// On a terminal, a status line under the output shows the phase and how
+// long it has taken; otherwise a line is logged whenever the build has
+// been silent for a while.
+type buildProgress struct {
+	em        *emitter
+	m         *Metrics
+	status    io.Writer // the terminal for the status line, or nil
+	keepalive time.Duration
+
+	mu         sync.Mutex
+	start      time.Time
+	phase      string
+	phaseStart time.Time
+	last       time.Time // of the last output or keepalive line
+	drawn      bool      // whether the status line is on the terminal
+	midLine    bool      // whether the output ended without a newline
+	stop, done chan struct{}
+}
+
+// newBuildProgress starts reporting a build. status, if non-nil, is the
+// terminal to draw the status line on. close must be called when the
+// build ends.
+func newBuildProgress(em *emitter, m *Metrics, status io.Writer, keepalive time.Duration) *buildProgress {
+	now := time.Now()
+	bp := &buildProgress{
+		em:        em,
+		m:         m,
+		status:    status,
+		keepalive: keepalive,
+		start:     now,
+		last:      now,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	tick := time.Second
+	if keepalive < tick {
+		tick = keepalive
+	}
+	go func() {
+		defer close(bp.done)
+		t := time.NewTicker(tick)
+		defer t.Stop()
+		for {
+			select {
+			case <-bp.stop:
+				return
+			case <-t.C:
+				bp.tick()
+			}
+		}
+	}()
+	return bp
+}
+
+func (bp *buildProgress) tick() {
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	now := time.Now()
+	if bp.status != nil {
+		if !bp.midLine {
+			fmt.Fprintf(bp.status, "\r\x1b[K%s", bp.describe(now))
+			bp.drawn = true
+		}
+		return
+	}
+	if now.Sub(bp.last) >= bp.keepalive {
+		log.Printf("Still building: %s.", bp.describe(now))
+		bp.last = now
+	}
+}
+
+// describe returns the phase and the time taken, as of now.
+func (bp *buildProgress) describe(now time.Time) string {
+	total := now.Sub(bp.start).Round(time.Second)
+	if bp.phase == "" {
+		return fmt.Sprintf("%v so far", total)
+	}
+	return fmt.Sprintf("%s for %v, %v so far", bp.phase, now.Sub(bp.phaseStart).Round(time.Second), total)
+}
+
+// write passes build output p through to w.
+func (bp *buildProgress) write(w io.Writer, p []byte) (int, error) {
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	if bp.drawn {
+		io.WriteString(bp.status, "\r\x1b[K")
+		bp.drawn = false
+	}
+	if len(p) > 0 {
+		bp.midLine = p[len(p)-1] != '\n'
+	}
+	bp.last = time.Now()
+	return w.Write(p)
+}
+
+// line notes a complete line of build output.
+func (bp *buildProgress) line(l string) {
+	name := buildBanner(l)
+	if name == "" {
+		return
+	}
+	bp.mu.Lock()
+	now := time.Now()
+	bp.phase, bp.phaseStart = name, now
+	elapsed := now.Sub(bp.start)
+	bp.mu.Unlock()
+	bp.em.emit(BuildProgress{Phase: name, Elapsed: elapsed})
+	bp.m.buildPhase(name, elapsed)
+}
+
+// close stops reporting, and removes the status line.
+func (bp *buildProgress) close() {
+	close(bp.stop)
+	<-bp.done
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	if bp.drawn {
+		io.WriteString(bp.status, "\r\x1b[K")
+		bp.drawn = false
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBuildBanner(t *testing.T) {
+	tests := []struct {
+		line, want string
+	}{
+		{"Building Go cmd/dist using /usr/lib/go. (go1.22.1 linux/amd64)", "Go cmd/dist"},
+		{"Building Go toolchain1 using /usr/lib/go.", "Go toolchain1"},
+		{"Building Go bootstrap cmd/go (go_bootstrap) using Go toolchain1.", "Go bootstrap cmd/go (go_bootstrap)"},
+		{"Building Go toolchain2 using go_bootstrap and Go toolchain1.", "Go toolchain2"},
+		{"Building packages and commands for linux/amd64.", "packages and commands"},
+		{"Building packages and commands for host, linux/amd64.", "packages and commands"},
+		{"Installed Go for linux/amd64 in /home/gopher/sdk/gotip", ""},
+		{"Building the toolchain", ""}, // reworded
+		{"cmd/compile: Building Go toolchain2 using go_bootstrap.", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := buildBanner(tt.line); got != tt.want {
+			t.Errorf("buildBanner(%q) = %q; want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu sync.Mutex
+	b  bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.String()
+}
+
+func TestBuildProgressKeepalive(t *testing.T) {
+	var logged syncBuffer
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+
+	ch := make(chan Event, 10)
+	var phases []string
+	m := &Metrics{BuildPhase: func(phase string, elapsed time.Duration) { phases = append(phases, phase) }}
+	bp := newBuildProgress(newEmitter(ch), m, nil, 10*time.Millisecond)
+	var out bytes.Buffer
+	lw := &lineWriter{w: &out, em: newEmitter(ch), bp: bp}
+	const text = "Building Go toolchain2 using go_bootstrap and Go toolchain1.\nsome output\n"
+	if _, err := lw.Write([]byte(text)); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	bp.close()
+	close(ch)
+
+	if out.String() != text {
+		t.Errorf("passed through %q; want %q", out.String(), text)
+	}
+	var got []string
+	for e := range ch {
+		if p, ok := e.(BuildProgress); ok {
+			got = append(got, p.Phase)
+		}
+	}
+	if len(got) != 1 || got[0] != "Go toolchain2" || len(phases) != 1 || phases[0] != "Go toolchain2" {
+		t.Errorf("reported phases %q to events and %q to metrics; want Go toolchain2", got, phases)
+	}
+	if !strings.Contains(logged.String(), "Still building: Go toolchain2 for ") {
+		t.Errorf("logged %q; want a keepalive line", logged.String())
+	}
+}
+
+func TestBuildProgressStatusLine(t *testing.T) {
+	var status syncBuffer
+	bp := newBuildProgress(nil, nil, &status, time.Hour)
+	var out bytes.Buffer
+	lw := &lineWriter{w: &out, bp: bp}
+	lw.Write([]byte("Building Go toolchain1 using /usr/lib/go.\n"))
+	bp.tick()
+	if s := status.String(); !strings.HasPrefix(s, "\r\x1b[K") || !strings.Contains(s, "Go toolchain1 for ") {
+		t.Errorf("status line %q; want the phase drawn", s)
+	}
+
+	// Output erases the status line first, and a partial line isn't
+	// drawn over.
+	lw.Write([]byte("partial"))
+	n := len(status.String())
+	bp.tick()
+	if s := status.String(); !strings.HasSuffix(s, "\r\x1b[K") || len(s) != n {
+		t.Errorf("status line after partial output %q; want it erased and not redrawn", s)
+	}
+	bp.close()
+	if out.String() != "Building Go toolchain1 using /usr/lib/go.\npartial" {
+		t.Errorf("passed through %q", out.String())
+	}
+}
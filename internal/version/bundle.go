@@ -0,0 +1,242 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// BundleFormat is the version of the bundle format that dl bundle writes,
+// and the newest that dl install -bundle reads.
+const BundleFormat = 1
+
+// bundleMetaName is the name of the first file of a bundle.
+const bundleMetaName = "bundle.json"
+
+// A bundleMeta describes the release archive a bundle carries to a
+// machine without network access. A bundle is an uncompressed tar file
+// of bundle.json, holding the bundleMeta, followed by the archive.
+type bundleMeta struct {
+	Format   int       `json:"format"`
+	Go       string    `json:"go"`
+	OS       string    `json:"os"` // GOOS and GOARCH of the machine it is for
+	Arch     string    `json:"arch"`
+	Filename string    `json:"filename"`
+	Size     int64     `json:"size"`
+	SHA256   string    `json:"sha256"`
+	URL      string    `json:"url"` // where the archive was downloaded from
+	Created  time.Time `json:"created"`
+	Tool     string    `json:"tool,omitempty"` // version of dl that made it
+}
+
+func runBundle(cfg *Config, args []string) {
+	flags := flag.NewFlagSet("dl bundle", flag.ExitOnError)
+	out := flags.String("o", "", "write the bundle to `file`; by default release.os-arch.bundle in the current directory")
+	goos := flags.String("os", getOS(), "bundle the archive for this operating system, that of the machine it is for")
+	goarch := flags.String("arch", "", "bundle the archive for this architecture; by default that of this machine")
+	flags.Bool("offline", false, "don't use the network; bundle only an archive in the cache")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		usagef("usage: dl bundle [-o file] [-os os] [-arch arch] <release>")
+	}
+	cfg.setFlags(flags)
+
+	opts, err := cfg.Options()
+	if err != nil {
+		fatal("dl bundle", err)
+	}
+	d, err := NewDownloader(opts)
+	if err != nil {
+		fatal("dl bundle", err)
+	}
+	if *goarch == "" {
+		*goarch, _ = d.arch()
+	}
+	ctx, stop := interruptContext()
+	defer stop()
+	meta, err := d.bundle(ctx, cfg.resolveAlias(flags.Arg(0)), *goos, *goarch, out)
+	if err != nil {
+		fatal("dl bundle", err)
+	}
+	log.Printf("Wrote %s, with %s (%s) for %s/%s", *out, meta.Filename, formatByteSize(meta.Size), meta.OS, meta.Arch)
+}
+
+// bundle writes a bundle of the archive of the release alias resolves to,
+// for goos/goarch, to *out, setting it to the default name if empty. The
+// archive is taken from the cache, if it is there, and otherwise
+// downloaded, and verified against the checksum the release listing has
+// for it.
+func (d *Downloader) bundle(ctx context.Context, alias, goos, goarch string, out *string) (bundleMeta, error) {
+	r, err := d.Catalog().Resolve(ctx, alias)
+	if err != nil {
+		return bundleMeta{}, err
+	}
+	f, ok := releaseArchive(r, goos, goarch)
+	if !ok {
+		return bundleMeta{}, fmt.Errorf("no binary release of %s for %s/%s", r.Version, goos, goarch)
+	}
+	dir := d.opts.CacheDir
+	if dir == "" {
+		if dir, err = ioutil.TempDir("", "godl-"); err != nil {
+			return bundleMeta{}, err
+		}
+		defer os.RemoveAll(dir)
+	}
+	defer d.holdCache()()
+	archive := filepath.Join(dir, f.Filename)
+	url := d.baseURL + f.Filename
+	if fi, err := os.Stat(archive); err != nil || fi.Size() != f.Size {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return bundleMeta{}, err
+		}
+		if _, _, err := d.download(ctx, archive, url, f.Size, 0); err != nil {
+			return bundleMeta{}, fmt.Errorf("error downloading %v: %w", url, err)
+		}
+	}
+	if err := VerifyFileSHA256(archive, f.SHA256, nil); err != nil {
+		return bundleMeta{}, fmt.Errorf("error verifying SHA256 of %v: %w", archive, err)
+	}
+	meta := bundleMeta{
+		Format:   BundleFormat,
+		Go:       r.Version.String(),
+		OS:       goos,
+		Arch:     goarch,
+		Filename: f.Filename,
+		Size:     f.Size,
+		SHA256:   f.SHA256,
+		URL:      url,
+		Created:  time.Now().UTC().Truncate(time.Second),
+		Tool:     toolBuildInfo().Version,
+	}
+	if *out == "" {
+		*out = fmt.Sprintf("%s.%s-%s.bundle", meta.Go, goos, goarch)
+	}
+	return meta, writeBundle(*out, meta, archive)
+}
+
+// writeBundle writes a bundle of meta and the archive file to file.
+func writeBundle(file string, meta bundleMeta, archive string) (err error) {
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	src, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	tmp, err := ioutil.TempFile(filepath.Dir(file), filepath.Base(file)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+	tw := tar.NewWriter(tmp)
+	hdr := &tar.Header{Name: bundleMetaName, Mode: 0644, Size: int64(len(data)), ModTime: meta.Created}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	if _, err := tw.Write(data); err != nil {
+		return err
+	}
+	hdr = &tar.Header{Name: meta.Filename, Mode: 0644, Size: meta.Size, ModTime: meta.Created}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	if _, err := io.Copy(tw, src); err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+// readBundle reads the bundle file, writing the archive it carries into
+// dir, and returns its metadata and the archive's path. The archive is
+// not verified; installBundle does that.
+func readBundle(file, dir string) (bundleMeta, string, error) {
+	var meta bundleMeta
+	f, err := os.Open(file)
+	if err != nil {
+		return meta, "", err
+	}
+	defer f.Close()
+	tr := tar.NewReader(f)
+	hdr, err := tr.Next()
+	if err != nil || hdr.Name != bundleMetaName {
+		return meta, "", fmt.Errorf("%s is not a bundle: it doesn't start with %s", file, bundleMetaName)
+	}
+	if err := json.NewDecoder(io.LimitReader(tr, 1<<20)).Decode(&meta); err != nil {
+		return meta, "", fmt.Errorf("%s: reading %s: %v", file, bundleMetaName, err)
+	}
+	switch {
+	case meta.Format < 1 || meta.Format > BundleFormat:
+		return meta, "", fmt.Errorf("%s is in bundle format %d, but this dl reads format %d at most; update dl", file, meta.Format, BundleFormat)
+	case meta.Filename == "" || filepath.Base(meta.Filename) != meta.Filename || !validRelPath(meta.Filename):
+		return meta, "", fmt.Errorf("%s: invalid archive name %q", file, meta.Filename)
+	}
+	if _, err := ParseVersion(meta.Go); err != nil {
+		return meta, "", fmt.Errorf("%s: %v", file, err)
+	}
+	hdr, err = tr.Next()
+	if err != nil || hdr.Name != meta.Filename {
+		return meta, "", fmt.Errorf("%s doesn't hold the archive %s", file, meta.Filename)
+	}
+	archive := filepath.Join(dir, meta.Filename)
+	w, err := os.Create(archive)
+	if err != nil {
+		return meta, "", err
+	}
+	n, err := io.Copy(w, tr)
+	if closeErr := w.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return meta, "", err
+	}
+	if n != meta.Size {
+		return meta, "", fmt.Errorf("%s: the archive %s is %d bytes; want %d", file, meta.Filename, n, meta.Size)
+	}
+	return meta, archive, nil
+}
+
+// installBundle installs the release of meta into targetDir from archive,
+// read from its bundle, after checking that the bundle is for this
+// platform, and verifying the archive against the bundle's checksum.
+func (d *Downloader) installBundle(ctx context.Context, targetDir string, meta bundleMeta, archive string) error {
+	arch, _ := d.arch()
+	if meta.OS != getOS() || meta.Arch != arch {
+		err := fmt.Errorf("the bundle of %s is for %s/%s, not this %s/%s; make one with dl bundle -os %s -arch %s", meta.Go, meta.OS, meta.Arch, getOS(), arch, getOS(), arch)
+		d.emitter().emit(Failed{Err: err, Phase: PhaseResolve})
+		return err
+	}
+	// installFile wants the archive named as for this platform, which
+	// the release listing may name otherwise, as for illumos.
+	name := filepath.Join(filepath.Dir(archive), archiveName(meta.Go, getOS(), arch))
+	if name != archive {
+		if err := os.Rename(archive, name); err != nil {
+			return err
+		}
+	}
+	return d.installFile(ctx, targetDir, meta.Go, name, meta.SHA256)
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBundle(t *testing.T) {
+	ts := newTestServer(t)
+	ctx := context.Background()
+	arch, _ := (&Downloader{}).arch()
+	name := archiveName("go1.99", getOS(), arch)
+	archive := ts.tar
+	if strings.HasSuffix(name, ".zip") {
+		archive = ts.zip
+	}
+	listing := fmt.Sprintf(`[{"version": "go1.99", "stable": true, "files": [
+		{"filename": %q, "os": %q, "arch": %q, "sha256": "%x", "size": %d, "kind": "archive"},
+		{"filename": "go1.99.plan9-386.tar.gz", "os": "plan9", "arch": "386", "sha256": "%x", "size": %d, "kind": "archive"}]}]`,
+		name, getOS(), releaseArch(getOS(), arch), sha256.Sum256(archive), len(archive), sha256.Sum256(ts.tar), len(ts.tar))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/dl/" {
+			w.Write([]byte(listing))
+			return
+		}
+		ts.serve(w, r)
+	}))
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+	// dl bundle resolves releases with the cached listing of its URL.
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	d, err := NewDownloader(DownloaderOptions{
+		Client:     &http.Client{Transport: redirectTransport{u, http.DefaultTransport}},
+		CatalogURL: srv.URL + "/dl/?mode=json&include=all",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.catalog.CacheDir = t.TempDir()
+
+	dir := t.TempDir()
+	out := filepath.Join(dir, "go1.99.bundle")
+	meta, err := d.bundle(ctx, "go1.99", getOS(), arch, &out)
+	if err != nil {
+		t.Fatalf("bundle: %v", err)
+	}
+	if meta.Go != "go1.99" || meta.Filename != name || meta.Size != int64(len(archive)) || meta.URL != DefaultBaseURL+name {
+		t.Errorf("bundle wrote %+v; want go1.99's archive %s", meta, name)
+	}
+
+	// The bundle installs without the network.
+	offline := ts.downloader(t, DownloaderOptions{Offline: true})
+	got, file, err := readBundle(out, t.TempDir())
+	if err != nil {
+		t.Fatalf("readBundle: %v", err)
+	}
+	if got != meta {
+		t.Errorf("readBundle = %+v; want %+v", got, meta)
+	}
+	root := t.TempDir()
+	if err := offline.installBundle(ctx, root, got, file); err != nil {
+		t.Fatalf("installBundle: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, unpackedOkay)); err != nil {
+		t.Errorf("installBundle didn't complete the install: %v", err)
+	}
+
+	// A bundle's checksum is checked.
+	got, file, err = readBundle(out, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got.SHA256 = strings.Repeat("0", 64)
+	var ce *ChecksumError
+	if err := offline.installBundle(ctx, t.TempDir(), got, file); !errors.As(err, &ce) {
+		t.Errorf("installBundle with the wrong checksum = %v; want a checksum error", err)
+	}
+
+	// A bundle for another platform is refused.
+	if getOS() != "plan9" {
+		other := filepath.Join(dir, "other.bundle")
+		if _, err := d.bundle(ctx, "go1.99", "plan9", "386", &other); err != nil {
+			t.Fatalf("bundle for plan9/386: %v", err)
+		}
+		got, file, err := readBundle(other, t.TempDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := offline.installBundle(ctx, t.TempDir(), got, file); err == nil || !strings.Contains(err.Error(), "plan9/386") {
+			t.Errorf("installBundle of a plan9/386 bundle = %v; want an error naming its platform", err)
+		}
+	}
+
+	// Anything else isn't a bundle.
+	notBundle := filepath.Join(dir, name)
+	writeTestFile(t, notBundle, archive)
+	if _, _, err := readBundle(notBundle, t.TempDir()); err == nil {
+		t.Errorf("readBundle of an archive succeeded")
+	}
+	if reqs := ts.requests(); len(reqs) != 2 {
+		t.Errorf("requests = %q; want only the two archives bundled", reqs)
+	}
+}
@@ -0,0 +1,348 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Files the archive cache keeps beside the archives: the lock that
+// installs hold shared while they use the cache and dl cache clean holds
+// exclusively, and the record of recent cache hits and misses.
+const (
+	cacheLockName  = ".lock"
+	cacheStatsName = ".stats.jsonl"
+)
+
+// maxCacheStatsSize is the size past which the record of cache hits and
+// misses is rotated, as the journal is.
+const maxCacheStatsSize = 64 << 10
+
+// errLocked reports that another process holds a conflicting lock.
+var errLocked = errors.New("locked by another process")
+
+// lockCache locks the archive cache dir, shared or exclusively, and
+// returns the function that unlocks it. If wait is false and another
+// process holds a conflicting lock, it returns errLocked.
+func lockCache(dir string, exclusive, wait bool) (unlock func(), err error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(filepath.Join(dir, cacheLockName), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(f, exclusive, wait); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return func() {
+		_ = unlockFile(f)
+		_ = f.Close()
+	}, nil
+}
+
+// holdCache locks d's archive cache, if it has one, shared, for an
+// install, and returns the function that unlocks it. The lock is only
+// advisory: a cache that can't be locked, such as one mounted read-only,
+// is used anyway.
+func (d *Downloader) holdCache() (unlock func()) {
+	if d.opts.CacheDir == "" {
+		return func() {}
+	}
+	release, err := lockCache(d.opts.CacheDir, false, true)
+	if err != nil {
+		return func() {}
+	}
+	return func() {
+		release()
+		d.trimCache()
+	}
+}
+
+// trimCache keeps d's archive cache within CacheMaxSize, if it is set.
+// It needs the cache to itself, so while other installs hold it, the
+// last of them to finish trims it instead.
+func (d *Downloader) trimCache() {
+	if d.opts.CacheMaxSize <= 0 {
+		return
+	}
+	unlock, err := lockCache(d.opts.CacheDir, true, false)
+	if err != nil {
+		return
+	}
+	defer unlock()
+	removed, err := trimCache(d.opts.CacheDir, d.opts.CacheMaxSize)
+	for _, e := range removed {
+		verbosef("Removed %s (%s) from the archive cache, to keep it within %s", e.Name, formatByteSize(e.Size), formatByteSize(d.opts.CacheMaxSize))
+	}
+	if err != nil {
+		log.Printf("Warning: trimming the archive cache: %v", err)
+	}
+}
+
+// A cacheUse records whether an install found its archive in the cache.
+type cacheUse struct {
+	Time    time.Time `json:"time"`
+	Archive string    `json:"archive"`
+	Hit     bool      `json:"hit"`
+}
+
+// recordCacheUse notes, in the cache dir, whether an install found
+// archive there, for dl cache stats. On a hit, the archive's modification
+// time is also updated, so that dl cache clean -older-than spares the
+// archives still in use. Failures are ignored.
+func recordCacheUse(dir, archive string, hit bool, now time.Time) {
+	if hit {
+		_ = os.Chtimes(filepath.Join(dir, archive), now, now)
+	}
+	_ = appendJSONLine(filepath.Join(dir, cacheStatsName), cacheUse{now, archive, hit}, maxCacheStatsSize)
+}
+
+// A CacheEntry is an archive in the archive cache, with the checksum
+// saved beside it.
+type CacheEntry struct {
+	Name    string    `json:"name"`    // of the archive, such as go1.22.7.linux-amd64.tar.gz
+	Version string    `json:"version"` // such as go1.22.7
+	Size    int64     `json:"size"`    // of the archive and its checksum
+	ModTime time.Time `json:"mod_time"`
+}
+
+// archiveVersion returns the release an archive name is of, such as
+// go1.22.7 for go1.22.7.linux-amd64.tar.gz or the module zip
+// go1.22.7.linux-amd64.module.zip, or "" if it is not a release archive.
+func archiveVersion(name string) string {
+	name = strings.TrimSuffix(strings.TrimSuffix(strings.TrimSuffix(name, ".tar.gz"), ".zip"), ".module")
+	i := strings.LastIndex(name, ".")
+	if i < 0 {
+		return ""
+	}
+	if _, err := ParseVersion(name[:i]); err != nil {
+		return ""
+	}
+	return name[:i]
+}
+
+// cacheEntries returns the archives in the cache dir, oldest release
+// first. A saved checksum whose archive is gone is an entry of its own,
+// so that it can be cleaned. A missing cache has no entries.
+func cacheEntries(dir string) ([]CacheEntry, error) {
+	fis, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*CacheEntry)
+	var entries []*CacheEntry
+	for _, fi := range fis {
+		if !fi.Mode().IsRegular() || !isArchiveName(fi.Name()) {
+			continue
+		}
+		name := strings.TrimSuffix(fi.Name(), ".sha256")
+		v := archiveVersion(name)
+		if v == "" {
+			continue
+		}
+		e := byName[name]
+		if e == nil {
+			e = &CacheEntry{Name: name, Version: v}
+			byName[name] = e
+			entries = append(entries, e)
+		}
+		e.Size += fi.Size()
+		if fi.Name() == name || e.ModTime.IsZero() {
+			e.ModTime = fi.ModTime()
+		}
+	}
+	out := make([]CacheEntry, len(entries))
+	for i, e := range entries {
+		out[i] = *e
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if c := compareEntries(out[i].Version, out[j].Version); c != 0 {
+			return c < 0
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out, nil
+}
+
+// CacheStats describes the archive cache, for dl cache stats.
+type CacheStats struct {
+	Dir     string      `json:"dir"`
+	Entries int         `json:"entries"`
+	Size    int64       `json:"size"`
+	MaxSize int64       `json:"max_size,omitempty"` // as set by cache_max_size
+	Oldest  *CacheEntry `json:"oldest,omitempty"`   // least recently downloaded or used
+	Newest  *CacheEntry `json:"newest,omitempty"`
+
+	// Hits and Misses count the installs that did and didn't find their
+	// archive in the cache, of those recorded since Since.
+	Hits   int        `json:"hits"`
+	Misses int        `json:"misses"`
+	Since  *time.Time `json:"since,omitempty"`
+}
+
+// readCacheStats returns the statistics of the archive cache dir.
+func readCacheStats(dir string) (CacheStats, error) {
+	s := CacheStats{Dir: dir}
+	entries, err := cacheEntries(dir)
+	if err != nil {
+		return s, err
+	}
+	for i, e := range entries {
+		s.Entries++
+		s.Size += e.Size
+		if s.Oldest == nil || e.ModTime.Before(s.Oldest.ModTime) {
+			s.Oldest = &entries[i]
+		}
+		if s.Newest == nil || e.ModTime.After(s.Newest.ModTime) {
+			s.Newest = &entries[i]
+		}
+	}
+	file := filepath.Join(dir, cacheStatsName)
+	for _, name := range []string{file + ".1", file} {
+		f, err := os.Open(name)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return s, err
+		}
+		sc := bufio.NewScanner(f)
+		for sc.Scan() {
+			var u cacheUse
+			if json.Unmarshal(sc.Bytes(), &u) != nil {
+				continue
+			}
+			if u.Hit {
+				s.Hits++
+			} else {
+				s.Misses++
+			}
+			if s.Since == nil {
+				t := u.Time
+				s.Since = &t
+			}
+		}
+		err = sc.Err()
+		_ = f.Close()
+		if err != nil {
+			return s, fmt.Errorf("reading %s: %v", name, err)
+		}
+	}
+	return s, nil
+}
+
+// A cacheSelector selects the entries dl cache clean removes: all of
+// them, or those last downloaded or used longer ago than olderThan, if
+// set, and of version, if set.
+type cacheSelector struct {
+	all       bool
+	olderThan time.Duration
+	version   *Version
+}
+
+func (sel cacheSelector) match(e CacheEntry, now time.Time) bool {
+	if sel.all {
+		return true
+	}
+	if sel.olderThan > 0 && now.Sub(e.ModTime) < sel.olderThan {
+		return false
+	}
+	if sel.version != nil {
+		v, err := ParseVersion(e.Version)
+		if err != nil || v.Compare(*sel.version) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// cleanCache removes the entries of the cache dir that sel selects, and
+// returns them. The caller must hold the cache's lock exclusively. If
+// removing one fails, the ones removed so far are returned with the
+// error.
+func cleanCache(dir string, sel cacheSelector, now time.Time) ([]CacheEntry, error) {
+	entries, err := cacheEntries(dir)
+	if err != nil {
+		return nil, err
+	}
+	var removed []CacheEntry
+	for _, e := range entries {
+		if !sel.match(e, now) {
+			continue
+		}
+		if err := removeCacheEntry(dir, e); err != nil {
+			return removed, err
+		}
+		removed = append(removed, e)
+	}
+	return removed, nil
+}
+
+// trimCache removes the entries of the cache dir least recently
+// downloaded or used until the rest take at most max bytes, and returns
+// them. The most recent entry is kept, however large. The caller must
+// hold the cache's lock exclusively.
+func trimCache(dir string, max int64) ([]CacheEntry, error) {
+	entries, err := cacheEntries(dir)
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].ModTime.Before(entries[j].ModTime) })
+	var size int64
+	for _, e := range entries {
+		size += e.Size
+	}
+	var removed []CacheEntry
+	for _, e := range entries[:len(entries)-1] {
+		if size <= max {
+			break
+		}
+		if err := removeCacheEntry(dir, e); err != nil {
+			return removed, err
+		}
+		size -= e.Size
+		removed = append(removed, e)
+	}
+	return removed, nil
+}
+
+// removeCacheEntry removes e's archive and checksum from the cache dir.
+func removeCacheEntry(dir string, e CacheEntry) error {
+	for _, name := range []string{e.Name, e.Name + ".sha256"} {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseAge parses a duration for -older-than: as time.ParseDuration does,
+// or as a number of days, such as 30d.
+func parseAge(s string) (time.Duration, error) {
+	if n := strings.TrimSuffix(s, "d"); n != s {
+		days, err := strconv.Atoi(n)
+		if err != nil || days < 0 {
+			return 0, fmt.Errorf("invalid number of days %q", s)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
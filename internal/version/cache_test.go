@@ -0,0 +1,278 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestArchiveVersion(t *testing.T) {
+	for name, want := range map[string]string{
+		"go1.22.7.linux-amd64.tar.gz":   "go1.22.7",
+		"go1.23rc1.windows-arm64.zip":   "go1.23rc1",
+		"go1.9.darwin-amd64.tar.gz":     "go1.9",
+		"go1.22.7.src.tar.gz":           "go1.22.7",
+		"gotip.linux-amd64.tar.gz":      "",
+		"go.linux-amd64.tar.gz":         "",
+		"releases.json":                 "",
+		"go1.22.7.linux-amd64.tar.gz.1": "",
+	} {
+		if got := archiveVersion(name); got != want {
+			t.Errorf("archiveVersion(%q) = %q; want %q", name, got, want)
+		}
+	}
+}
+
+// makeCache fills dir with archives, each with its checksum saved, and
+// each last used the given number of days before now, plus a checksum
+// left 20 days ago without its archive, and a release listing.
+func makeCache(t *testing.T, dir string, now time.Time, archives map[string]int) {
+	t.Helper()
+	files := map[string]string{
+		"releases.json":                       "[]",
+		"go1.20.14.linux-amd64.tar.gz.sha256": "orphan",
+	}
+	for name := range archives {
+		files[name] = "archive"
+		files[name+".sha256"] = "sum"
+	}
+	makeTree(t, dir, files)
+	old := now.Add(-20 * 24 * time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "go1.20.14.linux-amd64.tar.gz.sha256"), old, old); err != nil {
+		t.Fatal(err)
+	}
+	for name, days := range archives {
+		when := now.Add(-time.Duration(days) * 24 * time.Hour)
+		for _, f := range []string{name, name + ".sha256"} {
+			if err := os.Chtimes(filepath.Join(dir, f), when, when); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+func entryNames(entries []CacheEntry) []string {
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name)
+	}
+	return names
+}
+
+func TestCacheStats(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	makeCache(t, dir, now, map[string]int{
+		"go1.10.linux-amd64.tar.gz":   40,
+		"go1.9.linux-amd64.tar.gz":    10,
+		"go1.22.7.linux-amd64.tar.gz": 1,
+		"go1.22.7.windows-amd64.zip":  5,
+	})
+	recordCacheUse(dir, "go1.22.7.linux-amd64.tar.gz", false, now.Add(-time.Hour))
+	recordCacheUse(dir, "go1.22.7.linux-amd64.tar.gz", true, now)
+
+	entries, err := cacheEntries(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"go1.9.linux-amd64.tar.gz", "go1.10.linux-amd64.tar.gz", "go1.20.14.linux-amd64.tar.gz",
+		"go1.22.7.linux-amd64.tar.gz", "go1.22.7.windows-amd64.zip",
+	}
+	if got := entryNames(entries); !reflect.DeepEqual(got, want) {
+		t.Errorf("cacheEntries = %q; want %q", got, want)
+	}
+	if e := entries[0]; e.Version != "go1.9" || e.Size != int64(len("archive")+len("sum")) {
+		t.Errorf("entry %+v; want go1.9 with the archive's and checksum's size", e)
+	}
+
+	s, err := readCacheStats(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Entries != 5 || s.Hits != 1 || s.Misses != 1 || s.Since == nil {
+		t.Errorf("stats = %+v; want 5 entries, 1 hit and 1 miss", s)
+	}
+	if s.Oldest == nil || s.Oldest.Name != "go1.10.linux-amd64.tar.gz" {
+		t.Errorf("oldest = %+v; want go1.10, unused for 40 days", s.Oldest)
+	}
+	// The hit touched the archive it used.
+	if s.Newest == nil || s.Newest.Name != "go1.22.7.linux-amd64.tar.gz" || now.Sub(s.Newest.ModTime) > time.Minute {
+		t.Errorf("newest = %+v; want go1.22.7, used just now", s.Newest)
+	}
+
+	s, err = readCacheStats(filepath.Join(dir, "missing"))
+	if err != nil || s.Entries != 0 || s.Since != nil {
+		t.Errorf("stats of a missing cache = %+v, %v; want nothing", s, err)
+	}
+}
+
+func TestCleanCache(t *testing.T) {
+	now := time.Now()
+	go122, _ := ParseVersion("go1.22.7")
+	tests := []struct {
+		sel  cacheSelector
+		want []string
+	}{
+		{cacheSelector{all: true}, []string{"go1.9.linux-amd64.tar.gz", "go1.20.14.linux-amd64.tar.gz", "go1.22.7.linux-amd64.tar.gz", "go1.22.7.windows-amd64.zip"}},
+		{cacheSelector{olderThan: 7 * 24 * time.Hour}, []string{"go1.9.linux-amd64.tar.gz", "go1.20.14.linux-amd64.tar.gz"}},
+		{cacheSelector{version: &go122}, []string{"go1.22.7.linux-amd64.tar.gz", "go1.22.7.windows-amd64.zip"}},
+		{cacheSelector{version: &go122, olderThan: 3 * 24 * time.Hour}, []string{"go1.22.7.windows-amd64.zip"}},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		makeCache(t, dir, now, map[string]int{
+			"go1.9.linux-amd64.tar.gz":    10,
+			"go1.22.7.linux-amd64.tar.gz": 1,
+			"go1.22.7.windows-amd64.zip":  5,
+		})
+		removed, err := cleanCache(dir, tt.sel, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := entryNames(removed); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("cleanCache(%+v) removed %q; want %q", tt.sel, got, tt.want)
+		}
+		left, _ := cacheEntries(dir)
+		if len(left)+len(removed) != 4 {
+			t.Errorf("cleanCache(%+v) left %q", tt.sel, entryNames(left))
+		}
+		for _, e := range removed {
+			if _, err := os.Stat(filepath.Join(dir, e.Name+".sha256")); !os.IsNotExist(err) {
+				t.Errorf("cleanCache(%+v) left the checksum of %s", tt.sel, e.Name)
+			}
+		}
+		if _, err := os.Stat(filepath.Join(dir, "releases.json")); err != nil {
+			t.Errorf("cleanCache(%+v) removed the release listing", tt.sel)
+		}
+	}
+}
+
+func TestTrimCache(t *testing.T) {
+	now := time.Now()
+	entry := int64(len("archive") + len("sum"))
+	tests := []struct {
+		max  int64
+		want []string
+	}{
+		{100 * entry, nil},
+		{3 * entry, []string{"go1.10.linux-amd64.tar.gz", "go1.20.14.linux-amd64.tar.gz"}},
+		{entry + 1, []string{"go1.10.linux-amd64.tar.gz", "go1.20.14.linux-amd64.tar.gz", "go1.9.linux-amd64.tar.gz", "go1.22.7.windows-amd64.zip"}},
+		// The most recent archive is kept, however large.
+		{1, []string{"go1.10.linux-amd64.tar.gz", "go1.20.14.linux-amd64.tar.gz", "go1.9.linux-amd64.tar.gz", "go1.22.7.windows-amd64.zip"}},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		makeCache(t, dir, now, map[string]int{
+			"go1.10.linux-amd64.tar.gz":   40,
+			"go1.9.linux-amd64.tar.gz":    10,
+			"go1.22.7.linux-amd64.tar.gz": 1,
+			"go1.22.7.windows-amd64.zip":  5,
+		})
+		removed, err := trimCache(dir, tt.max)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := entryNames(removed); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("trimCache(%d) removed %q; want %q", tt.max, got, tt.want)
+		}
+		left, _ := cacheEntries(dir)
+		if len(left)+len(removed) != 5 {
+			t.Errorf("trimCache(%d) left %q", tt.max, entryNames(left))
+		}
+		if _, err := os.Stat(filepath.Join(dir, "releases.json")); err != nil {
+			t.Errorf("trimCache(%d) removed the release listing", tt.max)
+		}
+	}
+}
+
+func TestInstallTrimsCache(t *testing.T) {
+	ts := newTestServer(t)
+	cache := t.TempDir()
+	makeCache(t, cache, time.Now(), map[string]int{"go1.9.linux-amd64.tar.gz": 10})
+	d := ts.downloader(t, DownloaderOptions{CacheDir: cache, CacheMaxSize: 1})
+	if err := d.install(context.Background(), t.TempDir(), "go1.99"); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := cacheEntries(cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Version != "go1.99" {
+		t.Errorf("after an install over the cache's limit, it holds %q; want only go1.99's archive", entryNames(entries))
+	}
+}
+
+func TestCacheLock(t *testing.T) {
+	switch runtime.GOOS {
+	case "darwin", "dragonfly", "freebsd", "linux", "netbsd", "openbsd", "windows":
+	default:
+		t.Skipf("no file locking on %s", runtime.GOOS)
+	}
+	dir := t.TempDir()
+	unlock, err := lockCache(dir, false, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shared, err := lockCache(dir, false, false)
+	if err != nil {
+		t.Fatalf("second shared lock: %v", err)
+	}
+	if _, err := lockCache(dir, true, false); err != errLocked {
+		t.Errorf("exclusive lock while installs hold the cache = %v; want errLocked", err)
+	}
+	unlock()
+	shared()
+	excl, err := lockCache(dir, true, false)
+	if err != nil {
+		t.Fatalf("exclusive lock of an unused cache: %v", err)
+	}
+	if _, err := lockCache(dir, false, false); err != errLocked {
+		t.Errorf("shared lock while cleaning = %v; want errLocked", err)
+	}
+	excl()
+}
+
+func TestInstallRecordsCacheUse(t *testing.T) {
+	ts := newTestServer(t)
+	cache := t.TempDir()
+	d := ts.downloader(t, DownloaderOptions{CacheDir: cache})
+	for i := 0; i < 2; i++ {
+		if err := d.install(context.Background(), t.TempDir(), "go1.99"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s, err := readCacheStats(cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Hits != 1 || s.Misses != 1 || s.Entries != 1 {
+		t.Errorf("after two installs, stats = %+v; want 1 entry, 1 miss and 1 hit", s)
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	for s, want := range map[string]time.Duration{
+		"30d":  30 * 24 * time.Hour,
+		"0d":   0,
+		"720h": 720 * time.Hour,
+		"90m":  90 * time.Minute,
+	} {
+		if got, err := parseAge(s); err != nil || got != want {
+			t.Errorf("parseAge(%q) = %v, %v; want %v", s, got, err, want)
+		}
+	}
+	for _, s := range []string{"d", "-1d", "1.5d", "soon"} {
+		if _, err := parseAge(s); err == nil {
+			t.Errorf("parseAge(%q) succeeded; want an error", s)
+		}
+	}
+}
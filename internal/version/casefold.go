@@ -0,0 +1,66 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// caseInsensitive reports whether the file system holding dir, which must
+// exist, treats names differing only in case as the same file, as macOS
+// and Windows do by default. It finds out by creating a probe file.
+func caseInsensitive(dir string) (bool, error) {
+	f, err := ioutil.TempFile(dir, ".case-probe-")
+	if err != nil {
+		return false, err
+	}
+	name := f.Name()
+	_ = f.Close()
+	defer func() {
+		_ = os.Remove(name)
+	}()
+	base := filepath.Base(name)
+	_, err = os.Stat(filepath.Join(dir, strings.ToUpper(base)))
+	return err == nil, nil
+}
+
+// An entrySet tracks the archive entries extracted so far, to catch
+// entries that would overwrite each other.
+type entrySet struct {
+	fold  bool              // the target file system is case-insensitive
+	names map[string]string // folded name -> entry name
+}
+
+// newEntrySet returns an entrySet for extracting into dir.
+func newEntrySet(dir string) (*entrySet, error) {
+	fold, err := caseInsensitive(dir)
+	if err != nil {
+		return nil, fmt.Errorf("checking whether %s is case-sensitive: %v", dir, err)
+	}
+	return &entrySet{fold: fold}, nil
+}
+
+// add records the archive entry name. On a case-insensitive file system,
+// it reports an error if an earlier entry differs from name only in case,
+// as extracting both would silently leave only one of them.
+func (s *entrySet) add(name string) error {
+	if s == nil || !s.fold {
+		return nil
+	}
+	if s.names == nil {
+		s.names = make(map[string]string)
+	}
+	name = strings.TrimSuffix(name, "/")
+	key := strings.ToLower(name)
+	if prev, ok := s.names[key]; ok && prev != name {
+		return fmt.Errorf("archive entries %q and %q differ only in case and would overwrite each other on this case-insensitive file system", prev, name)
+	}
+	s.names[key] = name
+	return nil
+}
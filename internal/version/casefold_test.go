@@ -0,0 +1,61 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestCaseInsensitiveProbe(t *testing.T) {
+	dir := t.TempDir()
+	fold, err := caseInsensitive(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("%s is case-insensitive: %v", dir, fold)
+	if runtime.GOOS == "linux" && fold {
+		t.Errorf("caseInsensitive(%s) = true on linux", dir)
+	}
+	if fis, _ := ioutil.ReadDir(dir); len(fis) != 0 {
+		t.Errorf("probe left %d files behind", len(fis))
+	}
+}
+
+func TestUnpackCaseCollision(t *testing.T) {
+	for _, tt := range []struct {
+		file   string
+		unpack func(context.Context, string, string, *emitter, *meter, *entrySet, func(string) bool) (UnpackProgress, error)
+	}{
+		{"case-collision.tar.gz", unpackTarGz},
+		{"case-collision.zip", unpackZip},
+	} {
+		archive := filepath.Join("testdata", "unpack", tt.file)
+
+		// Pretend the target is case-insensitive, as on macOS or Windows.
+		_, err := tt.unpack(context.Background(), t.TempDir(), archive, nil, nil, &entrySet{fold: true}, nil)
+		if err == nil || !strings.Contains(err.Error(), `"go/src/strings/Builder.go" and "go/src/strings/builder.go"`) {
+			t.Errorf("unpacking %s case-insensitively = %v; want error naming both entries", tt.file, err)
+		}
+
+		// A case-sensitive target holds both files.
+		dir := t.TempDir()
+		if _, err := tt.unpack(context.Background(), dir, archive, nil, nil, &entrySet{}, nil); err != nil {
+			t.Errorf("unpacking %s case-sensitively: %v", tt.file, err)
+		}
+	}
+
+	// Entries that merely repeat, such as directories, are fine.
+	s := &entrySet{fold: true}
+	for _, name := range []string{"go/src/", "go/src", "go/src/a.go", "go/src/a.go"} {
+		if err := s.add(name); err != nil {
+			t.Errorf("add(%q): %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,516 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultCatalogURL is the listing of all published Go releases.
+const DefaultCatalogURL = "https://go.dev/dl/?mode=json&include=all"
+
+// A Release is a published Go release, as listed by the Catalog.
+type Release struct {
+	Version Version
+	Stable  bool
+
+	// ReleaseDate is when the release was published, or the zero time
+	// if the listing doesn't say.
+	ReleaseDate time.Time
+
+	Files []File
+}
+
+// A File is a downloadable artifact of a Release.
+type File struct {
+	Filename string
+	OS       string // empty for source archives
+	Arch     string // empty for source archives
+	Kind     string // "archive", "installer" or "source"
+	Size     int64
+	SHA256   string
+}
+
+// Archive returns the binary archive of r for goos/goarch, if there is one.
+func (r Release) Archive(goos, goarch string) (File, bool) {
+	for _, f := range r.Files {
+		if f.Kind == "archive" && f.OS == goos && f.Arch == goarch {
+			return f, true
+		}
+	}
+	return File{}, false
+}
+
+// A Filter selects releases from the Catalog. The zero Filter selects all.
+type Filter struct {
+	// StableOnly excludes betas and release candidates.
+	StableOnly bool
+
+	// Since, if non-zero, excludes releases older than it.
+	Since Version
+
+	// OS and Arch, if set, exclude releases that have no binary archive
+	// for that platform.
+	OS, Arch string
+}
+
+func (f Filter) match(r Release) bool {
+	if f.StableOnly && !r.Stable {
+		return false
+	}
+	if f.Since != (Version{}) && r.Version.Less(f.Since) {
+		return false
+	}
+	if f.OS != "" || f.Arch != "" {
+		found := false
+		for _, file := range r.Files {
+			if file.Kind == "archive" && (f.OS == "" || file.OS == f.OS) && (f.Arch == "" || file.Arch == f.Arch) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// A Catalog answers questions about published Go releases, such as the
+// latest patch release of a minor version. It fetches the release listing
+// at most once per Catalog, and caches it on disk, where it is shared with
+// the command-line tools, revalidating it with conditional requests.
+//
+// The zero Catalog is ready to use. A Catalog is safe for concurrent use
+// once its fields are set.
+type Catalog struct {
+	// Client performs the listing request. If nil, a client with the
+	// package's default timeouts is used.
+	Client Doer
+
+	// URL is the listing to fetch. If empty, DefaultCatalogURL is used.
+	URL string
+
+	// CacheDir is where the listing is cached. If empty, a directory in
+	// the user's cache directory is used; see DefaultCacheDir.
+	CacheDir string
+
+	// MaxAge is how long a cached listing is used without revalidating
+	// it. If zero, one hour is used.
+	MaxAge time.Duration
+
+	// Offline serves the listing from the cache only, however old,
+	// and never makes a request.
+	Offline bool
+
+	mu       sync.Mutex
+	releases []Release // newest first; nil until loaded
+}
+
+// DefaultCacheDir returns the directory the tools cache downloaded data
+// in by default.
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "godl"), nil
+}
+
+// Catalog returns a Catalog of d's release listing, whose requests are
+// made with d's client, and which is offline if d is.
+func (d *Downloader) Catalog() *Catalog {
+	return &Catalog{Client: d.client, URL: d.catalogURL(), Offline: d.opts.Offline}
+}
+
+// catalogURL returns the listing of d's releases, or "" for the default.
+func (d *Downloader) catalogURL() string {
+	if d.opts.CatalogURL == "" && d.baseURL == ChinaBaseURL {
+		return ChinaCatalogURL
+	}
+	return d.opts.CatalogURL
+}
+
+// All returns the releases selected by f, newest first.
+func (c *Catalog) All(ctx context.Context, f Filter) ([]Release, error) {
+	releases, err := c.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var out []Release
+	for _, r := range releases {
+		if f.match(r) {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+// Latest returns the newest release, or the newest stable release if
+// stable is set.
+func (c *Catalog) Latest(ctx context.Context, stable bool) (Release, error) {
+	rs, err := c.All(ctx, Filter{StableOnly: stable})
+	if err != nil {
+		return Release{}, err
+	}
+	if len(rs) == 0 {
+		return Release{}, errors.New("no releases found")
+	}
+	return rs[0], nil
+}
+
+// LatestPatch returns the newest stable release of the given minor
+// version, such as "go1.22".
+func (c *Catalog) LatestPatch(ctx context.Context, minor string) (Release, error) {
+	m, err := ParseVersion(minor)
+	if err != nil {
+		return Release{}, err
+	}
+	if m.Patch != 0 || m.Pre != "" {
+		return Release{}, fmt.Errorf("%s is not a minor version like go1.22", minor)
+	}
+	rs, err := c.All(ctx, Filter{StableOnly: true, Since: m})
+	if err != nil {
+		return Release{}, err
+	}
+	for _, r := range rs {
+		if r.Version.Major == m.Major && r.Version.Minor == m.Minor {
+			return r, nil
+		}
+	}
+	all, err := c.All(ctx, Filter{StableOnly: true})
+	if err != nil {
+		return Release{}, err
+	}
+	return Release{}, &NotFoundError{Name: minor, Stable: true, Suggestion: nearestRelease(all, m)}
+}
+
+// A NotFoundError reports that no published release matches a name.
+type NotFoundError struct {
+	Name   string // as given, such as go1.22.7 or go1.22
+	Stable bool   // whether only stable releases were considered
+
+	// Suggestion is the published release nearest to Name, or empty if
+	// there are none.
+	Suggestion string
+}
+
+func (e *NotFoundError) Error() string {
+	s := e.Name + ": no such release"
+	if e.Stable {
+		s = fmt.Sprintf("no stable release of %s found", e.Name)
+	}
+	if e.Suggestion != "" {
+		s += fmt.Sprintf(" (did you mean %s?)", e.Suggestion)
+	}
+	return s
+}
+
+// nearestRelease returns the release of rs, which are newest first, that
+// v was most likely meant to be: the newest of the same minor version, or
+// else the newest older one, or else the oldest. It returns "" if rs is
+// empty.
+func nearestRelease(rs []Release, v Version) string {
+	if len(rs) == 0 {
+		return ""
+	}
+	for _, r := range rs {
+		if r.Version.Major == v.Major && r.Version.Minor == v.Minor {
+			return r.Version.String()
+		}
+	}
+	for _, r := range rs {
+		if r.Version.Less(v) {
+			return r.Version.String()
+		}
+	}
+	return rs[len(rs)-1].Version.String()
+}
+
+// Resolve returns the release an alias refers to. An alias may be
+//
+//	"latest" or "stable"  the newest stable release
+//	"unstable"            the newest release, including betas and RCs
+//	go1.N, from go1.21    the newest stable patch release of go1.N
+//	any release name      that release, such as go1.22.7, go1.20 or go1.23rc1
+//
+// Before Go 1.21, go1.N names a release of its own; see partialRelease.
+func (c *Catalog) Resolve(ctx context.Context, alias string) (Release, error) {
+	switch alias {
+	case "latest", "stable":
+		return c.Latest(ctx, true)
+	case "unstable":
+		return c.Latest(ctx, false)
+	}
+	v, err := ParseVersion(alias)
+	if err != nil {
+		return Release{}, err
+	}
+	if partialRelease(v, alias) {
+		return c.LatestPatch(ctx, alias)
+	}
+	rs, err := c.All(ctx, Filter{})
+	if err != nil {
+		return Release{}, err
+	}
+	for _, r := range rs {
+		if r.Version.Compare(v) == 0 {
+			return r, nil
+		}
+	}
+	return Release{}, &NotFoundError{Name: alias, Suggestion: nearestRelease(rs, v)}
+}
+
+// Cache file names within the cache directory.
+const (
+	catalogCacheFile = "releases.json"
+	catalogMetaFile  = "releases.meta.json"
+)
+
+// catalogMeta records how the cached listing was obtained.
+type catalogMeta struct {
+	URL          string
+	ETag         string
+	LastModified string
+	Fetched      time.Time
+}
+
+// jsonRelease is a release in the go.dev/dl JSON listing.
+type jsonRelease struct {
+	Version string `json:"version"`
+	Stable  bool   `json:"stable"`
+	Files   []struct {
+		Filename string `json:"filename"`
+		OS       string `json:"os"`
+		Arch     string `json:"arch"`
+		Version  string `json:"version"`
+		SHA256   string `json:"sha256"`
+		Size     int64  `json:"size"`
+		Kind     string `json:"kind"`
+	} `json:"files"`
+}
+
+func (c *Catalog) url() string {
+	if c.URL == "" {
+		return DefaultCatalogURL
+	}
+	return c.URL
+}
+
+func (c *Catalog) maxAge() time.Duration {
+	if c.MaxAge == 0 {
+		return time.Hour
+	}
+	return c.MaxAge
+}
+
+func (c *Catalog) cacheDir() (string, error) {
+	if c.CacheDir != "" {
+		return c.CacheDir, nil
+	}
+	return DefaultCacheDir()
+}
+
+// load returns the release listing, fetching it if necessary.
+func (c *Catalog) load(ctx context.Context) ([]Release, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.releases != nil {
+		return c.releases, nil
+	}
+	data, err := c.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	releases, err := parseCatalog(data)
+	if err != nil {
+		return nil, err
+	}
+	c.releases = releases
+	return releases, nil
+}
+
+// fetch returns the raw listing, from the cache if it is fresh enough,
+// and otherwise from the network.
+func (c *Catalog) fetch(ctx context.Context) ([]byte, error) {
+	var meta catalogMeta
+	var cached []byte
+	dir, dirErr := c.cacheDir()
+	if dirErr == nil {
+		cached, _ = ioutil.ReadFile(filepath.Join(dir, catalogCacheFile))
+		if m, err := ioutil.ReadFile(filepath.Join(dir, catalogMetaFile)); err == nil {
+			_ = json.Unmarshal(m, &meta)
+		}
+		if meta.URL != c.url() {
+			cached = nil
+		}
+	}
+
+	if c.Offline {
+		if cached == nil {
+			return nil, netGate(true, c.url())
+		}
+		if time.Since(meta.Fetched) >= c.maxAge() {
+			log.Printf("Note: offline mode: using the cached release listing from %v, which may be out of date", meta.Fetched.Format(time.RFC3339))
+		}
+		return cached, nil
+	}
+	if cached != nil && time.Since(meta.Fetched) < c.maxAge() {
+		return cached, nil
+	}
+
+	data, newMeta, err := c.get(ctx, cached, meta)
+	if err != nil {
+		if cached != nil {
+			log.Printf("warning: using cached release listing from %v: %v", meta.Fetched.Format(time.RFC3339), err)
+			return cached, nil
+		}
+		return nil, err
+	}
+	if dirErr == nil {
+		// Caching is best effort; a read-only cache just means more requests.
+		if err := os.MkdirAll(dir, 0755); err == nil {
+			m, _ := json.Marshal(newMeta)
+			if writeFileAtomic(filepath.Join(dir, catalogCacheFile), data) == nil {
+				_ = writeFileAtomic(filepath.Join(dir, catalogMetaFile), m)
+			}
+		}
+	}
+	return data, nil
+}
+
+// get requests the listing, revalidating the cached copy if there is one.
+func (c *Catalog) get(ctx context.Context, cached []byte, meta catalogMeta) ([]byte, catalogMeta, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url(), nil)
+	if err != nil {
+		return nil, meta, err
+	}
+	if cached != nil {
+		if meta.ETag != "" {
+			req.Header.Set("If-None-Match", meta.ETag)
+		}
+		if meta.LastModified != "" {
+			req.Header.Set("If-Modified-Since", meta.LastModified)
+		}
+	}
+	client := c.Client
+	if client == nil {
+		client = defaultCatalogClient()
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, meta, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	switch {
+	case res.StatusCode == http.StatusNotModified && cached != nil:
+		// A 304 may carry new validators for the same listing, as when a
+		// CDN recomputes ETags; the next revalidation must send those.
+		if etag := res.Header.Get("ETag"); etag != "" {
+			meta.ETag = etag
+		}
+		if lm := res.Header.Get("Last-Modified"); lm != "" {
+			meta.LastModified = lm
+		}
+		meta.Fetched = time.Now()
+		return cached, meta, nil
+	case res.StatusCode != http.StatusOK:
+		return nil, meta, &statusError{URL: c.url(), Code: res.StatusCode, Status: res.Status}
+	}
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, meta, fmt.Errorf("reading %s: %w", c.url(), err)
+	}
+	if _, err := parseCatalog(data); err != nil {
+		return nil, meta, err
+	}
+	return data, catalogMeta{
+		URL:          c.url(),
+		ETag:         res.Header.Get("ETag"),
+		LastModified: res.Header.Get("Last-Modified"),
+		Fetched:      time.Now(),
+	}, nil
+}
+
+var (
+	catalogClientOnce sync.Once
+	catalogClient     *http.Client
+)
+
+func defaultCatalogClient() *http.Client {
+	catalogClientOnce.Do(func() {
+		// Without a CA file, building the default client can't fail.
+		catalogClient, _ = newHTTPClient(&DownloaderOptions{})
+	})
+	return catalogClient
+}
+
+// parseCatalog decodes the JSON listing, skipping releases whose names
+// aren't understood, and returns the releases newest first.
+func parseCatalog(data []byte) ([]Release, error) {
+	var list []jsonRelease
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("decoding release listing: %v", err)
+	}
+	releases := make([]Release, 0, len(list))
+	for _, jr := range list {
+		v, err := ParseVersion(jr.Version)
+		if err != nil {
+			continue
+		}
+		r := Release{Version: v, Stable: jr.Stable}
+		for _, f := range jr.Files {
+			r.Files = append(r.Files, File{
+				Filename: f.Filename,
+				OS:       f.OS,
+				Arch:     f.Arch,
+				Kind:     f.Kind,
+				Size:     f.Size,
+				SHA256:   f.SHA256,
+			})
+		}
+		releases = append(releases, r)
+	}
+	sortReleases(releases)
+	return releases, nil
+}
+
+// sortReleases sorts rs newest first.
+func sortReleases(rs []Release) {
+	sort.SliceStable(rs, func(i, j int) bool { return rs[j].Version.Less(rs[i].Version) })
+}
+
+// writeFileAtomic writes data to file by way of a temporary file in the
+// same directory, so that readers never see a partial file.
+func writeFileAtomic(file string, data []byte) error {
+	f, err := ioutil.TempFile(filepath.Dir(file), filepath.Base(file)+".tmp*")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), file)
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+	}
+	return err
+}
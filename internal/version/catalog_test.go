@@ -0,0 +1,230 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testCatalogJSON is a trimmed-down go.dev/dl listing, deliberately out of
+// order and with a release name the parser doesn't know.
+const testCatalogJSON = `[
+ {"version": "go1.21.13", "stable": true, "files": [
+  {"filename": "go1.21.13.linux-amd64.tar.gz", "os": "linux", "arch": "amd64", "sha256": "aa", "size": 10, "kind": "archive"}]},
+ {"version": "go1.23rc1", "stable": false, "files": [
+  {"filename": "go1.23rc1.linux-amd64.tar.gz", "os": "linux", "arch": "amd64", "sha256": "bb", "size": 11, "kind": "archive"}]},
+ {"version": "go1.22.7", "stable": true, "files": [
+  {"filename": "go1.22.7.src.tar.gz", "os": "", "arch": "", "sha256": "cc", "size": 12, "kind": "source"},
+  {"filename": "go1.22.7.linux-amd64.tar.gz", "os": "linux", "arch": "amd64", "sha256": "dd", "size": 13, "kind": "archive"},
+  {"filename": "go1.22.7.windows-arm64.zip", "os": "windows", "arch": "arm64", "sha256": "ee", "size": 14, "kind": "archive"}]},
+ {"version": "go1.22.0", "stable": true, "files": []},
+ {"version": "go1.9", "stable": true, "files": []},
+ {"version": "weekly.2012-03-27", "stable": false, "files": []}
+]`
+
+// catalogServer serves testCatalogJSON with an ETag, counting requests and
+// the ones it could answer with 304 Not Modified.
+type catalogServer struct {
+	*httptest.Server
+	mu          sync.Mutex
+	gets, fresh int
+}
+
+func newCatalogServer(t *testing.T) *catalogServer {
+	cs := &catalogServer{}
+	cs.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cs.mu.Lock()
+		defer cs.mu.Unlock()
+		cs.gets++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			cs.fresh++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(testCatalogJSON))
+	}))
+	t.Cleanup(cs.Close)
+	return cs
+}
+
+func (cs *catalogServer) counts() (gets, fresh int) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	return cs.gets, cs.fresh
+}
+
+func (cs *catalogServer) catalog(dir string) *Catalog {
+	return &Catalog{URL: cs.URL, CacheDir: dir, Client: cs.Client()}
+}
+
+func names(rs []Release) []string {
+	var s []string
+	for _, r := range rs {
+		s = append(s, r.Version.String())
+	}
+	return s
+}
+
+func TestCatalogQueries(t *testing.T) {
+	ctx := context.Background()
+	c := newCatalogServer(t).catalog(t.TempDir())
+
+	all, err := c.All(ctx, Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := names(all), []string{"go1.23rc1", "go1.22.7", "go1.22.0", "go1.21.13", "go1.9"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("All = %v; want %v", got, want)
+	}
+
+	filters := []struct {
+		f    Filter
+		want []string
+	}{
+		{Filter{StableOnly: true}, []string{"go1.22.7", "go1.22.0", "go1.21.13", "go1.9"}},
+		{Filter{Since: Version{Major: 1, Minor: 22}}, []string{"go1.23rc1", "go1.22.7", "go1.22.0"}},
+		{Filter{OS: "windows", Arch: "arm64"}, []string{"go1.22.7"}},
+		{Filter{StableOnly: true, OS: "linux"}, []string{"go1.22.7", "go1.21.13"}},
+	}
+	for _, tt := range filters {
+		rs, err := c.All(ctx, tt.f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := names(rs); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("All(%+v) = %v; want %v", tt.f, got, tt.want)
+		}
+	}
+
+	resolve := []struct {
+		alias, want string
+	}{
+		{"latest", "go1.22.7"},
+		{"stable", "go1.22.7"},
+		{"unstable", "go1.23rc1"},
+		{"go1.22", "go1.22.7"},
+		{"go1.21", "go1.21.13"},
+		{"go1.9", "go1.9"},
+		{"go1.22.0", "go1.22.0"},
+		{"go1.23rc1", "go1.23rc1"},
+		{"go1.20", ""},
+		{"go1.22.8", ""},
+		{"newest", ""},
+	}
+	for _, tt := range resolve {
+		r, err := c.Resolve(ctx, tt.alias)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("Resolve(%q) = %v; want error", tt.alias, r.Version)
+			}
+			continue
+		}
+		if err != nil || r.Version.String() != tt.want {
+			t.Errorf("Resolve(%q) = %v, %v; want %s", tt.alias, r.Version, err, tt.want)
+		}
+	}
+
+	r, _ := c.Resolve(ctx, "go1.22.7")
+	if f, ok := r.Archive("linux", "amd64"); !ok || f.SHA256 != "dd" || f.Size != 13 {
+		t.Errorf("Archive(linux, amd64) = %+v, %v; want the linux-amd64 file", f, ok)
+	}
+	if _, ok := r.Archive("plan9", "386"); ok {
+		t.Errorf("Archive(plan9, 386) found a file")
+	}
+}
+
+func TestCatalogCache(t *testing.T) {
+	ctx := context.Background()
+	cs := newCatalogServer(t)
+	dir := t.TempDir()
+
+	if _, err := cs.catalog(dir).Latest(ctx, true); err != nil {
+		t.Fatal(err)
+	}
+	// A new Catalog within MaxAge uses the disk cache without a request.
+	if _, err := cs.catalog(dir).Latest(ctx, true); err != nil {
+		t.Fatal(err)
+	}
+	if gets, _ := cs.counts(); gets != 1 {
+		t.Errorf("made %d requests; want 1", gets)
+	}
+
+	// Once stale, the cache is revalidated with the ETag.
+	c := cs.catalog(dir)
+	c.MaxAge = -1
+	if r, err := c.Latest(ctx, true); err != nil || r.Version.String() != "go1.22.7" {
+		t.Fatalf("Latest after revalidation = %v, %v", r.Version, err)
+	}
+	if gets, fresh := cs.counts(); gets != 2 || fresh != 1 {
+		t.Errorf("made %d requests, %d revalidated; want 2, 1", gets, fresh)
+	}
+
+	// Offline mode serves the stale cache without asking.
+	c = cs.catalog(dir)
+	c.MaxAge = -1
+	c.Offline = true
+	if _, err := c.Latest(ctx, true); err != nil {
+		t.Errorf("offline Latest = %v", err)
+	}
+	if gets, _ := cs.counts(); gets != 2 {
+		t.Errorf("offline catalog made a request")
+	}
+
+	// And fails clearly without a cache.
+	c = cs.catalog(t.TempDir())
+	c.Offline = true
+	if _, err := c.Latest(ctx, true); err == nil || !strings.Contains(err.Error(), "offline mode") {
+		t.Errorf("offline Latest without cache = %v; want offline mode error", err)
+	}
+}
+
+func TestCatalogFallsBackToCache(t *testing.T) {
+	ctx := context.Background()
+	cs := newCatalogServer(t)
+	dir := t.TempDir()
+	if _, err := cs.catalog(dir).Latest(ctx, true); err != nil {
+		t.Fatal(err)
+	}
+	c := cs.catalog(dir)
+	c.MaxAge = -1
+	cs.Close()
+	if _, err := c.Latest(ctx, true); err != nil {
+		t.Errorf("Latest with server down and a cached listing = %v", err)
+	}
+}
+
+func TestCatalogRevalidationUpdatesValidators(t *testing.T) {
+	ctx := context.Background()
+	var sent []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = append(sent, r.Header.Get("If-None-Match"))
+		if r.Header.Get("If-None-Match") != "" {
+			// Not modified, but known by a new ETag from now on.
+			w.Header().Set("ETag", `"v2"`)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(testCatalogJSON))
+	}))
+	defer srv.Close()
+	dir := t.TempDir()
+	for i := 0; i < 3; i++ {
+		c := &Catalog{URL: srv.URL, CacheDir: dir, Client: srv.Client(), MaxAge: -1}
+		if _, err := c.Latest(ctx, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := []string{"", `"v1"`, `"v2"`}; !reflect.DeepEqual(sent, want) {
+		t.Errorf("If-None-Match sent = %q; want %q", sent, want)
+	}
+}
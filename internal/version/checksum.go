@@ -0,0 +1,106 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// A ChecksumError reports content whose SHA-256 digest, or other hash,
+// is not the expected one.
+type ChecksumError struct {
+	File string // empty if not verifying a file
+	Want string // expected digest, in lower-case hex unless Hash is set
+	Got  string // actual digest, written the same way
+
+	// Hash names the hash, if it isn't SHA-256, such as the go.sum hash
+	// of a module zip.
+	Hash string
+}
+
+func (e *ChecksumError) Error() string {
+	hash := e.Hash
+	if hash == "" {
+		hash = "SHA-256"
+	}
+	if e.File != "" {
+		return fmt.Sprintf("%s corrupt? has %s %s, want %s", e.File, hash, e.Got, e.Want)
+	}
+	return fmt.Sprintf("%s mismatch: got %s, want %s", hash, e.Got, e.Want)
+}
+
+// parseSHA256 decodes a SHA-256 digest written in hex, in either case,
+// optionally with a "sha256:" prefix.
+func parseSHA256(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if len(s) > len("sha256:") && strings.EqualFold(s[:len("sha256:")], "sha256:") {
+		s = s[len("sha256:"):]
+	}
+	sum, err := hex.DecodeString(s)
+	if err != nil || len(sum) != sha256.Size {
+		return nil, fmt.Errorf("invalid SHA-256 digest %q", s)
+	}
+	return sum, nil
+}
+
+// VerifySHA256 reads r to EOF and checks that its SHA-256 digest is want,
+// which is in hex and may have a "sha256:" prefix. If progress is non-nil,
+// it is called with the number of bytes read so far as reading proceeds.
+// A mismatch is reported as a *ChecksumError.
+func VerifySHA256(r io.Reader, want string, progress func(n int64)) error {
+	wantSum, err := parseSHA256(want)
+	if err != nil {
+		return err
+	}
+	h := sha256.New()
+	var w io.Writer = h
+	if progress != nil {
+		w = &progressHash{w: h, progress: progress}
+	}
+	if _, err := io.Copy(w, r); err != nil {
+		return err
+	}
+	if gotSum := h.Sum(nil); subtle.ConstantTimeCompare(gotSum, wantSum) != 1 {
+		return &ChecksumError{Want: hex.EncodeToString(wantSum), Got: hex.EncodeToString(gotSum)}
+	}
+	return nil
+}
+
+// VerifyFileSHA256 is like VerifySHA256, but verifies the contents of the
+// named file.
+func VerifyFileSHA256(file, want string, progress func(n int64)) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	err = VerifySHA256(f, want, progress)
+	if ce, ok := err.(*ChecksumError); ok {
+		ce.File = file
+	}
+	return err
+}
+
+// progressHash counts the bytes written through it.
+type progressHash struct {
+	w        io.Writer
+	n        int64
+	progress func(int64)
+}
+
+func (p *progressHash) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.n += int64(n)
+	p.progress(p.n)
+	return n, err
+}
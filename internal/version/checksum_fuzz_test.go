@@ -0,0 +1,43 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.18
+// +build go1.18
+
+package version
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func FuzzVerifySHA256(f *testing.F) {
+	f.Add([]byte(""), "")
+	f.Add([]byte("hello"), "sha256:")
+	f.Add([]byte("hello"), "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824")
+	f.Fuzz(func(t *testing.T, data []byte, want string) {
+		sum := fmt.Sprintf("%x", sha256.Sum256(data))
+		for _, w := range []string{sum, strings.ToUpper(sum), "sha256:" + sum} {
+			if err := VerifySHA256(bytes.NewReader(data), w, nil); err != nil {
+				t.Fatalf("VerifySHA256(%q, %q) = %v; want nil", data, w, err)
+			}
+		}
+		// Arbitrary expectations must never panic, and may only match
+		// if they name the true digest.
+		err := VerifySHA256(bytes.NewReader(data), want, nil)
+		if err == nil {
+			if got, _ := parseSHA256(want); fmt.Sprintf("%x", got) != sum {
+				t.Fatalf("VerifySHA256(%q, %q) matched a different digest", data, want)
+			}
+		}
+		var ce *ChecksumError
+		if errors.As(err, &ce) && ce.Got != sum {
+			t.Fatalf("ChecksumError.Got = %s; want %s", ce.Got, sum)
+		}
+	})
+}
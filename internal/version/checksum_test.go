@@ -0,0 +1,100 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestVerifySHA256(t *testing.T) {
+	data := "hello, gopher\n"
+	sum := fmt.Sprintf("%x", sha256.Sum256([]byte(data)))
+	empty := fmt.Sprintf("%x", sha256.Sum256(nil))
+	tests := []struct {
+		name     string
+		r        io.Reader
+		want     string
+		mismatch bool
+		errText  string
+	}{
+		{"match", strings.NewReader(data), sum, false, ""},
+		{"upper hex", strings.NewReader(data), strings.ToUpper(sum), false, ""},
+		{"mixed hex", strings.NewReader(data), sum[:10] + strings.ToUpper(sum[10:]), false, ""},
+		{"prefixed", strings.NewReader(data), "sha256:" + sum, false, ""},
+		{"prefix upper", strings.NewReader(data), "SHA256:" + sum, false, ""},
+		{"surrounding space", strings.NewReader(data), " " + sum + "\n", false, ""},
+		{"empty", strings.NewReader(""), empty, false, ""},
+		{"empty mismatch", strings.NewReader(""), sum, true, ""},
+		{"mismatch", strings.NewReader(data + "!"), sum, true, ""},
+		{"one-byte reads", iotest.OneByteReader(strings.NewReader(data)), sum, false, ""},
+		{"short read", io.MultiReader(strings.NewReader(data[:3]), iotest.ErrReader(io.ErrUnexpectedEOF)), sum, false, "unexpected EOF"},
+		{"bad hex", strings.NewReader(data), "zz" + sum[2:], false, "invalid SHA-256"},
+		{"truncated", strings.NewReader(data), sum[:62], false, "invalid SHA-256"},
+		{"other algorithm", strings.NewReader(data), "sha512:" + sum, false, "invalid SHA-256"},
+		{"no digest", strings.NewReader(data), "", false, "invalid SHA-256"},
+	}
+	for _, tt := range tests {
+		err := VerifySHA256(tt.r, tt.want, nil)
+		var ce *ChecksumError
+		switch {
+		case tt.mismatch:
+			if !errors.As(err, &ce) {
+				t.Errorf("%s: VerifySHA256 = %v; want *ChecksumError", tt.name, err)
+			} else if ce.Want != strings.ToLower(strings.TrimSpace(tt.want)) || ce.Got == "" {
+				t.Errorf("%s: ChecksumError = %+v; want got/want filled in", tt.name, ce)
+			}
+		case tt.errText != "":
+			if err == nil || !strings.Contains(err.Error(), tt.errText) {
+				t.Errorf("%s: VerifySHA256 = %v; want error containing %q", tt.name, err, tt.errText)
+			}
+		default:
+			if err != nil {
+				t.Errorf("%s: VerifySHA256 = %v; want nil", tt.name, err)
+			}
+		}
+	}
+}
+
+func TestVerifySHA256Progress(t *testing.T) {
+	data := strings.Repeat("x", 100)
+	var last int64
+	calls := 0
+	err := VerifySHA256(iotest.OneByteReader(strings.NewReader(data)), fmt.Sprintf("%x", sha256.Sum256([]byte(data))), func(n int64) {
+		if n < last {
+			t.Errorf("progress went backwards: %d after %d", n, last)
+		}
+		last = n
+		calls++
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last != int64(len(data)) || calls < 2 {
+		t.Errorf("progress ended at %d after %d calls; want %d after several", last, calls, len(data))
+	}
+}
+
+func TestVerifyFileSHA256(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "archive.tar.gz")
+	if err := ioutil.WriteFile(file, []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err := VerifyFileSHA256(file, fmt.Sprintf("%x", sha256.Sum256([]byte("other"))), nil)
+	var ce *ChecksumError
+	if !errors.As(err, &ce) || ce.File != file || !strings.Contains(err.Error(), file) {
+		t.Errorf("VerifyFileSHA256 = %v; want *ChecksumError naming %s", err, file)
+	}
+	if err := VerifyFileSHA256(filepath.Join(t.TempDir(), "missing"), strings.Repeat("0", 64), nil); err == nil {
+		t.Errorf("VerifyFileSHA256 of a missing file succeeded")
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// A ciReport is what dl ci tells the CI system about an install.
+type ciReport struct {
+	GoVersion string // such as go1.22.7
+	GOROOT    string
+	SDKDir    string // to cache, with CacheDir
+	CacheDir  string // the archive cache, or ""
+	CacheKey  string
+}
+
+// ciCacheKey returns the key to cache the SDK and archive cache
+// directories under: the same tool version, Go release and platform
+// always give the same key.
+func ciCacheKey(tool, goVersion, goos, goarch string) string {
+	tool = strings.Map(func(r rune) rune {
+		switch {
+		case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9', r == '.', r == '-', r == '+':
+			return r
+		}
+		return -1
+	}, tool)
+	return fmt.Sprintf("godl-%s-%s-%s-%s", tool, goVersion, goos, goarch)
+}
+
+// githubFiles returns the files GitHub Actions reads commands from: the
+// ones to add to PATH, to set environment variables for later steps, and
+// to set step outputs. ok reports whether all are set, as in a workflow.
+func githubFiles(getenv func(string) string) (path, env, output string, ok bool) {
+	path, env, output = getenv("GITHUB_PATH"), getenv("GITHUB_ENV"), getenv("GITHUB_OUTPUT")
+	return path, env, output, path != "" && env != "" && output != ""
+}
+
+// writeGitHub adds r's bin directory to PATH and sets GOROOT for the later
+// steps of a GitHub Actions job, and sets r as the step's outputs:
+// go-version, goroot, sdk-dir, cache-dir and cache-key. Its paths stay
+// native, since the runner reads them, whatever shell a step uses.
+func writeGitHub(r ciReport, path, env, output string) error {
+	for _, w := range []struct {
+		file  string
+		lines []string
+	}{
+		{path, []string{filepath.Join(r.GOROOT, "bin")}},
+		{env, []string{"GOROOT=" + r.GOROOT}},
+		{output, []string{
+			"go-version=" + r.GoVersion,
+			"goroot=" + r.GOROOT,
+			"sdk-dir=" + r.SDKDir,
+			"cache-dir=" + r.CacheDir,
+			"cache-key=" + r.CacheKey,
+		}},
+	} {
+		if err := appendLines(w.file, w.lines); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func appendLines(file string, lines []string) error {
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(f, strings.Join(lines, "\n")+"\n"); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writePlain prints r for CI systems other than GitHub Actions, one
+// NAME=value line each, with paths in style, in this order:
+//
+//	GO_VERSION  the installed release
+//	GOROOT      its GOROOT
+//	PATH_ADD    the directory to add to PATH
+//	SDK_DIR     the SDK directory, to cache
+//	CACHE_DIR   the archive cache, to cache, or empty
+//	CACHE_KEY   the key to cache them under
+func writePlain(w io.Writer, r ciReport, style PathStyle) {
+	fmt.Fprintf(w, "GO_VERSION=%s\n", r.GoVersion)
+	fmt.Fprintf(w, "GOROOT=%s\n", style.Format(r.GOROOT))
+	fmt.Fprintf(w, "PATH_ADD=%s\n", style.Format(filepath.Join(r.GOROOT, "bin")))
+	fmt.Fprintf(w, "SDK_DIR=%s\n", style.Format(r.SDKDir))
+	fmt.Fprintf(w, "CACHE_DIR=%s\n", style.Format(r.CacheDir))
+	fmt.Fprintf(w, "CACHE_KEY=%s\n", r.CacheKey)
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCICacheKey(t *testing.T) {
+	tests := []struct {
+		tool, want string
+	}{
+		{"v0.4.0", "godl-v0.4.0-go1.22.7-linux-amd64"},
+		{"(devel)", "godl-devel-go1.22.7-linux-amd64"},
+		{"v0.0.0-20260101000000-abcdef012345+dirty", "godl-v0.0.0-20260101000000-abcdef012345+dirty-go1.22.7-linux-amd64"},
+	}
+	for _, tt := range tests {
+		if got := ciCacheKey(tt.tool, "go1.22.7", "linux", "amd64"); got != tt.want {
+			t.Errorf("ciCacheKey(%q, ...) = %q; want %q", tt.tool, got, tt.want)
+		}
+	}
+}
+
+func TestWriteGitHub(t *testing.T) {
+	dir := t.TempDir()
+	env := map[string]string{
+		"GITHUB_PATH":   filepath.Join(dir, "path"),
+		"GITHUB_ENV":    filepath.Join(dir, "env"),
+		"GITHUB_OUTPUT": filepath.Join(dir, "output"),
+	}
+	path, envFile, output, ok := githubFiles(func(k string) string { return env[k] })
+	if !ok {
+		t.Fatal("githubFiles didn't find the files")
+	}
+	if _, _, _, ok := githubFiles(func(string) string { return "" }); ok {
+		t.Error("githubFiles found files outside GitHub Actions")
+	}
+	if err := ioutil.WriteFile(envFile, []byte("EARLIER=1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	goroot := filepath.Join(dir, "sdk", "go1.22.7")
+	r := ciReport{GoVersion: "go1.22.7", GOROOT: goroot, SDKDir: filepath.Join(dir, "sdk"), CacheKey: "godl-v0.4.0-go1.22.7-linux-amd64"}
+	if err := writeGitHub(r, path, envFile, output); err != nil {
+		t.Fatal(err)
+	}
+	for file, want := range map[string]string{
+		path:    filepath.Join(goroot, "bin") + "\n",
+		envFile: "EARLIER=1\nGOROOT=" + goroot + "\n",
+	} {
+		if b, err := ioutil.ReadFile(file); err != nil || string(b) != want {
+			t.Errorf("%s holds %q, %v; want %q", filepath.Base(file), b, err, want)
+		}
+	}
+	b, err := ioutil.ReadFile(output)
+	if err != nil || !strings.Contains(string(b), "\ncache-key=godl-v0.4.0-go1.22.7-linux-amd64\n") {
+		t.Errorf("output holds %q, %v; want the cache key", b, err)
+	}
+
+	var buf bytes.Buffer
+	writePlain(&buf, r, PathStyleAuto)
+	if !strings.Contains(buf.String(), "\nPATH_ADD="+filepath.Join(goroot, "bin")+"\n") || !strings.HasPrefix(buf.String(), "GO_VERSION=go1.22.7\n") {
+		t.Errorf("writePlain printed\n%s", buf.String())
+	}
+}
@@ -0,0 +1,353 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Doer is the subset of *http.Client used by the downloader. Embedders may
+// supply their own implementation to add tracing, custom TLS, retries, or
+// record/replay for hermetic tests.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Default timeouts for the client built by NewDownloader.
+const (
+	defaultConnectTimeout  = 30 * time.Second
+	defaultResponseTimeout = 30 * time.Second
+	defaultReadTimeout     = time.Minute
+)
+
+// newHTTPClient returns the client used when DownloaderOptions.Client is
+// nil. There is no overall request timeout unless RequestTimeout sets one,
+// since archives are large and links can be slow; instead each phase of
+// the connection is bounded, and so is each wait for more of a response.
+func newHTTPClient(opts *DownloaderOptions) (*http.Client, error) {
+	connect := opts.ConnectTimeout
+	if connect == 0 {
+		connect = defaultConnectTimeout
+	}
+	response := opts.ResponseTimeout
+	if response == 0 {
+		response = defaultResponseTimeout
+	}
+	read := opts.ReadTimeout
+	if read == 0 {
+		read = defaultReadTimeout
+	}
+	network, err := ipNetwork(opts.IP)
+	if err != nil {
+		return nil, err
+	}
+	dialer := &net.Dialer{
+		Timeout:   connect,
+		KeepAlive: 30 * time.Second,
+	}
+	tlsConfig, err := loadTLSConfig(opts.CAFile, opts.CADir)
+	if err != nil {
+		return nil, err
+	}
+	proxy := http.ProxyFromEnvironment
+	if opts.Proxy != "" {
+		u, err := parseProxy(opts.Proxy)
+		if err != nil {
+			return nil, err
+		}
+		proxy = http.ProxyURL(u)
+	}
+	return &http.Client{
+		Timeout: opts.RequestTimeout,
+		Transport: &userAgentTransport{opts.UserAgent, opts.Header, &stallTransport{read, &http.Transport{
+			Proxy: proxy,
+			DialContext: func(ctx context.Context, _, addr string) (net.Conn, error) {
+				return dialer.DialContext(ctx, network, addr)
+			},
+			TLSClientConfig:       tlsConfig,
+			TLSHandshakeTimeout:   connect,
+			ResponseHeaderTimeout: response,
+			ExpectContinueTimeout: 1 * time.Second,
+			IdleConnTimeout:       90 * time.Second,
+			// Archives are already compressed. Prefer accurate ContentLength.
+			DisableCompression: true,
+		}}},
+	}, nil
+}
+
+// A stallTransport fails the reading of a response body, with a
+// stallError, once a read has waited timeout for any of it, by canceling
+// the request.
+type stallTransport struct {
+	timeout time.Duration
+	rt      http.RoundTripper
+}
+
+func (t *stallTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithCancel(req.Context())
+	res, err := t.rt.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	sr := &stallReader{body: res.Body, timeout: t.timeout, cancel: cancel}
+	sr.timer = time.AfterFunc(t.timeout, sr.stall)
+	sr.timer.Stop()
+	res.Body = sr
+	return res, nil
+}
+
+// A stallReader is a response body that stallTransport times reads of.
+type stallReader struct {
+	body    io.ReadCloser
+	timeout time.Duration
+	cancel  context.CancelFunc
+	timer   *time.Timer
+	stalled int32 // atomic
+}
+
+func (r *stallReader) stall() {
+	atomic.StoreInt32(&r.stalled, 1)
+	r.cancel()
+}
+
+func (r *stallReader) Read(p []byte) (int, error) {
+	r.timer.Reset(r.timeout)
+	n, err := r.body.Read(p)
+	r.timer.Stop()
+	if err != nil && atomic.LoadInt32(&r.stalled) != 0 {
+		err = &stallError{r.timeout}
+	}
+	return n, err
+}
+
+func (r *stallReader) Close() error {
+	r.timer.Stop()
+	r.cancel()
+	return r.body.Close()
+}
+
+// A stallError reports that a response stopped arriving. It is a timeout,
+// so the request is retried.
+type stallError struct {
+	timeout time.Duration
+}
+
+func (e *stallError) Error() string {
+	return fmt.Sprintf("no data received for %v (GODL_READ_TIMEOUT)", e.timeout)
+}
+
+func (e *stallError) Timeout() bool   { return true }
+func (e *stallError) Temporary() bool { return true }
+
+// ipNetwork returns the network to dial for the IP option ip: tcp4 or
+// tcp6 for "4" or "6", or tcp for either.
+func ipNetwork(ip string) (string, error) {
+	switch ip {
+	case "", "auto":
+		return "tcp", nil
+	case "4":
+		return "tcp4", nil
+	case "6":
+		return "tcp6", nil
+	}
+	return "", fmt.Errorf("invalid IP version %q: must be 4, 6 or auto", ip)
+}
+
+// parseProxy parses and checks the URL of a proxy.
+func parseProxy(s string) (*url.URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy: %v", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("invalid proxy %q: must be an http, https or socks5 URL", redactURL(s))
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy %q: no host", redactURL(s))
+	}
+	return u, nil
+}
+
+// gitProxyArgs returns the git options that make git fetch through proxy,
+// or none if it is empty, leaving git to its own configuration. git's
+// socks5 resolves names itself, unlike Go's, so socks5h is used to match.
+func gitProxyArgs(proxy string) []string {
+	u, err := parseProxy(proxy)
+	if proxy == "" || err != nil {
+		return nil
+	}
+	if u.Scheme == "socks5" {
+		u.Scheme = "socks5h"
+	}
+	return []string{"-c", "http.proxy=" + u.String()}
+}
+
+// gitHeaderEnv returns the environment variables that make git send
+// userAgent, if set, and header with its HTTP requests. They go in the
+// environment rather than in -c options so that header values, which may
+// be credentials, don't show in the command line or the verbose log.
+// git older than 2.31 ignores them.
+func gitHeaderEnv(userAgent string, header http.Header) []string {
+	var config []string
+	if userAgent != "" {
+		config = append(config, "http.userAgent", userAgent)
+	}
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	for _, name := range names {
+		for _, v := range header[name] {
+			config = append(config, "http.extraHeader", name+": "+v)
+		}
+	}
+	if len(config) == 0 {
+		return nil
+	}
+	n := len(config) / 2
+	env := []string{"GIT_CONFIG_COUNT=" + strconv.Itoa(n)}
+	for i := 0; i < n; i++ {
+		env = append(env,
+			fmt.Sprintf("GIT_CONFIG_KEY_%d=%s", i, config[2*i]),
+			fmt.Sprintf("GIT_CONFIG_VALUE_%d=%s", i, config[2*i+1]))
+	}
+	return env
+}
+
+// splitHeaders splits the headers setting into its "Name: value"
+// entries, which are separated by semicolons or newlines.
+func splitHeaders(s string) []string {
+	var entries []string
+	for _, kv := range strings.FieldsFunc(s, func(r rune) bool { return r == ';' || r == '\n' }) {
+		if kv = strings.TrimSpace(kv); kv != "" {
+			entries = append(entries, kv)
+		}
+	}
+	return entries
+}
+
+// parseHeaders parses the headers setting: "Name: value" entries
+// separated by semicolons or newlines. It returns nil if there are none.
+func parseHeaders(s string) (http.Header, error) {
+	var h http.Header
+	for _, kv := range splitHeaders(s) {
+		i := strings.Index(kv, ":")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid header %q: want Name: value", kv)
+		}
+		name, value := strings.TrimSpace(kv[:i]), strings.TrimSpace(kv[i+1:])
+		if name == "" || strings.ContainsAny(name, " \t\r") {
+			return nil, fmt.Errorf("invalid header name %q", name)
+		}
+		if h == nil {
+			h = http.Header{}
+		}
+		h.Add(name, value)
+	}
+	return h, nil
+}
+
+// loadTLSConfig returns a TLS configuration trusting the system roots plus
+// the PEM certificates in caFile and in the files in caDir. It returns nil
+// if both are empty.
+func loadTLSConfig(caFile, caDir string) (*tls.Config, error) {
+	if caFile == "" && caDir == "" {
+		return nil, nil
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if caFile != "" {
+		pem, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA file: %v", err)
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA file %s contains no PEM certificates", caFile)
+		}
+	}
+	if caDir != "" {
+		if err := appendCertsFromDir(pool, caDir); err != nil {
+			return nil, err
+		}
+	}
+	return &tls.Config{RootCAs: pool}, nil
+}
+
+// appendCertsFromDir adds the PEM certificates in the files in dir to
+// pool. Other files, such as the index an OpenSSL certificate directory
+// may keep, are skipped, but a directory without any certificates is an
+// error, as it is most likely the wrong one.
+func appendCertsFromDir(pool *x509.CertPool, dir string) error {
+	fis, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("reading CA directory: %v", err)
+	}
+	found := false
+	for _, fi := range fis {
+		if fi.IsDir() {
+			continue
+		}
+		// Certificate directories are often symbolic links to the
+		// certificates, named for their hashes, so they are followed.
+		pem, err := ioutil.ReadFile(filepath.Join(dir, fi.Name()))
+		if err != nil {
+			continue
+		}
+		if pool.AppendCertsFromPEM(pem) {
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("CA directory %s contains no PEM certificates", dir)
+	}
+	return nil
+}
+
+// A statusError reports an unexpected HTTP response status.
+type statusError struct {
+	URL    string
+	Code   int
+	Status string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("%s: %v", e.URL, e.Status)
+}
+
+// isNotFound reports whether err is a 404 response.
+func isNotFound(err error) bool {
+	var se *statusError
+	return errors.As(err, &se) && se.Code == http.StatusNotFound
+}
+
+// do issues a body-less request for url with the downloader's client.
+func (d *Downloader) do(ctx context.Context, method, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return d.client.Do(req)
+}
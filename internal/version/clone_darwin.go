@@ -0,0 +1,43 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	// sysClonefileat is SYS_CLONEFILEAT, which the syscall package
+	// doesn't name.
+	sysClonefileat = 462
+
+	// atFDCWD is AT_FDCWD: paths relative to the working directory.
+	atFDCWD = -2
+
+	// cloneNoFollow is CLONE_NOFOLLOW: clone symbolic links themselves.
+	cloneNoFollow = 0x0001
+)
+
+// cloneFile creates dst as a copy-on-write clone of src, which APFS
+// supports.
+func cloneFile(src, dst string) error {
+	s, err := syscall.BytePtrFromString(src)
+	if err != nil {
+		return err
+	}
+	d, err := syscall.BytePtrFromString(dst)
+	if err != nil {
+		return err
+	}
+	fd := atFDCWD
+	_, _, errno := syscall.Syscall6(sysClonefileat,
+		uintptr(fd), uintptr(unsafe.Pointer(s)), uintptr(fd), uintptr(unsafe.Pointer(d)), cloneNoFollow, 0)
+	if errno != 0 {
+		return &os.PathError{Op: "clonefile", Path: dst, Err: errno}
+	}
+	return nil
+}
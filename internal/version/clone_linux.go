@@ -0,0 +1,36 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"os"
+	"syscall"
+)
+
+// ficlone is the FICLONE ioctl, which makes a file share the extents of
+// another on btrfs, XFS and other file systems with copy-on-write.
+const ficlone = 0x40049409
+
+// cloneFile creates dst as a copy-on-write clone of src.
+func cloneFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, out.Fd(), ficlone, in.Fd())
+	err = out.Close()
+	if errno != 0 {
+		_ = os.Remove(dst)
+		return &os.PathError{Op: "clone", Path: dst, Err: errno}
+	}
+	return err
+}
@@ -0,0 +1,16 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !darwin && !linux
+// +build !darwin,!linux
+
+package version
+
+import "errors"
+
+// cloneFile creates dst as a copy-on-write clone of src. It is only
+// implemented on Linux and macOS.
+func cloneFile(src, dst string) error {
+	return errors.New("copy-on-write clones are not supported on this system")
+}
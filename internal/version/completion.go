@@ -0,0 +1,157 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+func init() {
+	// dl completion lists dlCommands, so it can't be named in their
+	// initializer.
+	for i := range dlCommands {
+		if dlCommands[i].name == "completion" {
+			dlCommands[i].run = runCompletion
+		}
+	}
+}
+
+// completionShells are the shells dl completion writes scripts for.
+var completionShells = []string{"bash", "zsh", "fish", "powershell"}
+
+// releaseCommands are the dl commands whose arguments are releases, which
+// the completion scripts complete.
+var releaseCommands = []string{"default", "direnv", "exec", "info", "install", "lock", "uninstall", "which"}
+
+// writeCompletion writes the completion script for shell to w. It
+// completes the commands, with their descriptions where the shell shows
+// them, and the releases that dl completion -releases lists, asking for
+// those in the cached release listing too if remote is set.
+func writeCompletion(w io.Writer, shell string, remote bool) error {
+	list := "dl completion -releases"
+	if remote {
+		list += " -remote"
+	}
+	var names []string
+	for _, c := range dlCommands {
+		names = append(names, c.name)
+	}
+	switch shell {
+	case "bash":
+		fmt.Fprintf(w, `# bash completion for dl, generated by 'dl completion bash'.
+_dl() {
+	local cur=${COMP_WORDS[COMP_CWORD]} words=
+	if [[ $COMP_CWORD -eq 1 ]]; then
+		words="help %s $(%s 2>/dev/null)"
+	else
+		case ${COMP_WORDS[1]} in
+		%s) words=$(%s 2>/dev/null) ;;
+		esac
+	fi
+	COMPREPLY=($(compgen -W "$words" -- "$cur"))
+}
+complete -F _dl dl
+`, strings.Join(names, " "), list, strings.Join(releaseCommands, "|"), list)
+	case "zsh":
+		fmt.Fprintf(w, "#compdef dl\n# zsh completion for dl, generated by 'dl completion zsh'.\n_dl() {\n\tlocal -a commands releases\n\tcommands=(\n")
+		for _, c := range dlCommands {
+			fmt.Fprintf(w, "\t\t%s\n", shellQuote(c.name+":"+c.short))
+		}
+		fmt.Fprintf(w, `	)
+	releases=(${(f)"$(%s 2>/dev/null)"})
+	if (( CURRENT == 2 )); then
+		_describe command commands
+		compadd -a releases
+	else
+		case $words[2] in
+		%s) compadd -a releases ;;
+		esac
+	fi
+}
+compdef _dl dl
+`, list, strings.Join(releaseCommands, "|"))
+	case "fish":
+		fmt.Fprintf(w, "# fish completion for dl, generated by 'dl completion fish'.\ncomplete -c dl -f\n")
+		for _, c := range dlCommands {
+			fmt.Fprintf(w, "complete -c dl -n __fish_use_subcommand -a %s -d %s\n", c.name, fishQuote(c.short))
+		}
+		fmt.Fprintf(w, "complete -c dl -n __fish_use_subcommand -a '(%s 2>/dev/null)'\n", list)
+		fmt.Fprintf(w, "complete -c dl -n '__fish_seen_subcommand_from %s' -a '(%s 2>/dev/null)'\n", strings.Join(releaseCommands, " "), list)
+	case "powershell":
+		fmt.Fprintf(w, `# PowerShell completion for dl, generated by 'dl completion powershell'.
+Register-ArgumentCompleter -Native -CommandName dl -ScriptBlock {
+	param($wordToComplete, $commandAst, $cursorPosition)
+	$words = @($commandAst.CommandElements | ForEach-Object { $_.ToString() })
+	$n = $words.Count
+	if ($wordToComplete -eq '') { $n++ }
+	$candidates = @()
+	if ($n -eq 2) {
+		$candidates = @(%s) + @(%s 2>$null)
+	} elseif ($n -gt 2 -and @(%s) -contains $words[1]) {
+		$candidates = @(%s 2>$null)
+	}
+	$candidates | Where-Object { $_ -like "$wordToComplete*" } | ForEach-Object {
+		[System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)
+	}
+}
+`, psList(append([]string{"help"}, names...)), list, psList(releaseCommands), list)
+	default:
+		return fmt.Errorf("no completion for the shell %q; dl completion supports %s", shell, strings.Join(completionShells, ", "))
+	}
+	return nil
+}
+
+// completionReleases returns the names the completion scripts offer where
+// a release goes: the installed releases, and, if remote is set, those in
+// the cached release listing, which c serves without the network, oldest
+// first, followed by gotip if it is installed, latest, and the aliases.
+func completionReleases(ctx context.Context, cfg *Config, c *Catalog, remote bool) []string {
+	l := cfg.Locator()
+	vs, _ := installedReleases(l)
+	if remote {
+		if rs, err := c.All(ctx, Filter{}); err == nil {
+			for _, r := range rs {
+				vs = append(vs, r.Version)
+			}
+		}
+	}
+	SortVersions(vs)
+	var names []string
+	for i, v := range vs {
+		if i == 0 || v.String() != vs[i-1].String() {
+			names = append(names, v.String())
+		}
+	}
+	if _, err := whichGoroot(l, "gotip"); err == nil {
+		names = append(names, "gotip")
+	}
+	names = append(names, "latest")
+	for _, a := range cfg.Aliases() {
+		names = append(names, a.Name)
+	}
+	return names
+}
+
+// shellQuote quotes s in single quotes, as bash and zsh read them.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// fishQuote quotes s in single quotes, as fish reads them.
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
+
+// psList returns names as a PowerShell list of strings.
+func psList(names []string) string {
+	var quoted []string
+	for _, name := range names {
+		quoted = append(quoted, "'"+strings.ReplaceAll(name, "'", "''")+"'")
+	}
+	return strings.Join(quoted, ", ")
+}
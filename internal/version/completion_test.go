@@ -0,0 +1,68 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWriteCompletion(t *testing.T) {
+	for _, shell := range completionShells {
+		var buf bytes.Buffer
+		if err := writeCompletion(&buf, shell, true); err != nil {
+			t.Errorf("writeCompletion(%s): %v", shell, err)
+			continue
+		}
+		script := buf.String()
+		for _, c := range dlCommands {
+			if !strings.Contains(script, c.name) {
+				t.Errorf("the %s script doesn't complete dl %s", shell, c.name)
+			}
+		}
+		if !strings.Contains(script, "dl completion -releases -remote") {
+			t.Errorf("the %s script doesn't ask for remote releases:\n%s", shell, script)
+		}
+	}
+	if err := writeCompletion(new(bytes.Buffer), "tcsh", false); err == nil {
+		t.Error("writeCompletion(tcsh) succeeded; want an error")
+	}
+
+	// The bash script must at least parse.
+	if _, err := exec.LookPath("bash"); err == nil {
+		var buf bytes.Buffer
+		writeCompletion(&buf, "bash", false)
+		cmd := exec.Command("bash", "-n")
+		cmd.Stdin = &buf
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Errorf("bash -n: %v\n%s", err, out)
+		}
+	}
+}
+
+func TestCompletionReleases(t *testing.T) {
+	sdk := t.TempDir()
+	makeTree(t, sdk, map[string]string{
+		"go1.21.13/" + unpackedOkay: "",
+		"go1.22.7/" + unpackedOkay:  "",
+	})
+	cfg, err := LoadConfig(writeConfig(t, "sdk_dir = '"+sdk+"'\nalias.work = go1.21.13\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newCatalogServer(t).catalog(t.TempDir())
+	got := completionReleases(context.Background(), cfg, c, false)
+	if want := []string{"go1.21.13", "go1.22.7", "latest", "work"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("completionReleases = %q; want %q", got, want)
+	}
+	got = completionReleases(context.Background(), cfg, c, true)
+	if want := "go1.9 go1.21.13 go1.22.0 go1.22.7 go1.23rc1 latest work"; strings.Join(got, " ") != want {
+		t.Errorf("completionReleases with remote = %q; want %s", got, want)
+	}
+}
@@ -0,0 +1,758 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// envConfig names the config file to read instead of the default one.
+const envConfig = "GODL_CONFIG"
+
+// Environment variables of the settings that are not downloader options,
+// and so are not read by FromEnvironment: the SDK directory, and the
+// directory of container mode (see dl install -dir).
+const (
+	envSDKDir = "GODL_SDK_DIR"
+	envPrefix = "GODL_PREFIX"
+)
+
+// A setting is one configurable value, settable by its key in the config
+// file, its environment variable and, for commands that have it, the flag
+// named like the key with dashes for underscores.
+type setting struct {
+	key   string
+	env   string
+	apply func(opts *DownloaderOptions, loc *Locator, s string) error
+}
+
+// settings lists every setting, in the order they are documented.
+var settings = []setting{
+	{"base_url", envBaseURL, func(opts *DownloaderOptions, _ *Locator, s string) error {
+		opts.BaseURL = s
+		return nil
+	}},
+	{"catalog_url", envCatalogURL, func(opts *DownloaderOptions, _ *Locator, s string) error {
+		opts.CatalogURL = s
+		return nil
+	}},
+	{"mirrors", envMirrors, func(opts *DownloaderOptions, _ *Locator, s string) error {
+		opts.Mirrors = parseMirrors(s)
+		return nil
+	}},
+	{"module_proxy", envModuleProxy, func(opts *DownloaderOptions, _ *Locator, s string) error {
+		opts.ModuleProxy = s
+		return nil
+	}},
+	{"sumdb", envSumDB, func(opts *DownloaderOptions, _ *Locator, s string) error {
+		opts.SumDB = s
+		return nil
+	}},
+	{"checksum", envChecksum, func(opts *DownloaderOptions, _ *Locator, s string) error {
+		opts.Checksum = ChecksumPolicy(s)
+		return nil
+	}},
+	{"cache_dir", envCacheDir, func(opts *DownloaderOptions, _ *Locator, s string) error {
+		opts.CacheDir = s
+		return nil
+	}},
+	{"cache_max_size", envCacheMaxSize, func(opts *DownloaderOptions, _ *Locator, s string) error {
+		n, err := parseByteSize(s)
+		opts.CacheMaxSize = n
+		return err
+	}},
+	{"resume", envResume, boolSetting(func(opts *DownloaderOptions) *bool { return &opts.Resume })},
+	{"fallback", envFallback, boolSetting(func(opts *DownloaderOptions) *bool { return &opts.Fallback })},
+	{"max_rate", envMaxRate, func(opts *DownloaderOptions, _ *Locator, s string) error {
+		n, err := parseByteSize(s)
+		opts.MaxRate = n
+		return err
+	}},
+	{"segments", envSegments, func(opts *DownloaderOptions, _ *Locator, s string) error {
+		n, err := strconv.Atoi(s)
+		opts.Segments = n
+		return err
+	}},
+	{"stream", envStream, boolSetting(func(opts *DownloaderOptions) *bool { return &opts.Stream })},
+	{"slim", envSlim, boolSetting(func(opts *DownloaderOptions) *bool { return &opts.Slim })},
+	{"dedupe", envDedupe, func(opts *DownloaderOptions, _ *Locator, s string) error {
+		opts.Dedupe = s
+		return checkDedupe(s)
+	}},
+	{"retries", envRetries, func(opts *DownloaderOptions, _ *Locator, s string) error {
+		n, err := strconv.Atoi(s)
+		opts.Retries = n
+		return err
+	}},
+	{"retry_backoff", envRetryBackoff, durationSetting(func(opts *DownloaderOptions) *time.Duration { return &opts.RetryBackoff })},
+	{"retry_jitter", envRetryJitter, func(opts *DownloaderOptions, _ *Locator, s string) error {
+		f, err := strconv.ParseFloat(s, 64)
+		opts.RetryJitter = f
+		return err
+	}},
+	{"connect_timeout", envConnectTimeout, durationSetting(func(opts *DownloaderOptions) *time.Duration { return &opts.ConnectTimeout })},
+	{"response_timeout", envResponseTimeout, durationSetting(func(opts *DownloaderOptions) *time.Duration { return &opts.ResponseTimeout })},
+	{"read_timeout", envReadTimeout, durationSetting(func(opts *DownloaderOptions) *time.Duration { return &opts.ReadTimeout })},
+	{"request_timeout", envRequestTimeout, durationSetting(func(opts *DownloaderOptions) *time.Duration { return &opts.RequestTimeout })},
+	{"ip", envIP, func(opts *DownloaderOptions, _ *Locator, s string) error {
+		opts.IP = s
+		_, err := ipNetwork(s)
+		return err
+	}},
+	{"ca_file", envCAFile, func(opts *DownloaderOptions, _ *Locator, s string) error {
+		opts.CAFile = s
+		return nil
+	}},
+	{"ca_dir", envCADir, func(opts *DownloaderOptions, _ *Locator, s string) error {
+		opts.CADir = s
+		return nil
+	}},
+	{"proxy", envProxy, func(opts *DownloaderOptions, _ *Locator, s string) error {
+		opts.Proxy = s
+		return nil
+	}},
+	{"token", envToken, func(opts *DownloaderOptions, _ *Locator, s string) error {
+		opts.Token = s
+		return nil
+	}},
+	{"netrc", envNetrc, func(opts *DownloaderOptions, _ *Locator, s string) error {
+		opts.Netrc = s
+		return nil
+	}},
+	{"user_agent", envUserAgent, func(opts *DownloaderOptions, _ *Locator, s string) error {
+		opts.UserAgent = s
+		return nil
+	}},
+	{"headers", envHeaders, func(opts *DownloaderOptions, _ *Locator, s string) error {
+		h, err := parseHeaders(s)
+		opts.Header = h
+		return err
+	}},
+	{"goarch", envGOARCH, func(opts *DownloaderOptions, _ *Locator, s string) error {
+		opts.GOARCH = s
+		return nil
+	}},
+	{"offline", envOffline, boolSetting(func(opts *DownloaderOptions) *bool { return &opts.Offline })},
+	{"quiet", envQuiet, boolSetting(func(opts *DownloaderOptions) *bool { return &opts.Quiet })},
+	{"progress", envProgress, func(opts *DownloaderOptions, _ *Locator, s string) error {
+		switch s {
+		case "text":
+			opts.ProgressJSON = nil
+		case "json":
+			opts.ProgressJSON = os.Stderr
+		default:
+			return fmt.Errorf("unknown progress format %q: must be text or json", s)
+		}
+		return nil
+	}},
+	{"sdk_dir", envSDKDir, func(_ *DownloaderOptions, loc *Locator, s string) error {
+		loc.Root = s
+		return nil
+	}},
+	{"prefix", envPrefix, func(_ *DownloaderOptions, _ *Locator, s string) error {
+		if !filepath.IsAbs(s) {
+			return errors.New("must be an absolute path")
+		}
+		return nil
+	}},
+}
+
+func boolSetting(field func(*DownloaderOptions) *bool) func(*DownloaderOptions, *Locator, string) error {
+	return func(opts *DownloaderOptions, _ *Locator, s string) error {
+		b, err := strconv.ParseBool(s)
+		*field(opts) = b
+		return err
+	}
+}
+
+func durationSetting(field func(*DownloaderOptions) *time.Duration) func(*DownloaderOptions, *Locator, string) error {
+	return func(opts *DownloaderOptions, _ *Locator, s string) error {
+		d, err := time.ParseDuration(s)
+		*field(opts) = d
+		return err
+	}
+}
+
+func lookupSetting(key string) (setting, bool) {
+	for _, s := range settings {
+		if s.key == key {
+			return s, true
+		}
+	}
+	return setting{}, false
+}
+
+// A ConfigSource says where the value of a setting came from.
+type ConfigSource string
+
+const (
+	SourceDefault ConfigSource = "default"
+	SourceFile    ConfigSource = "config-file"
+	SourceEnv     ConfigSource = "env"
+	SourceFlag    ConfigSource = "flag"
+)
+
+// A Config is the resolved configuration of the commands: the value of
+// each setting and the source it came from. Later sources take precedence:
+// the defaults, then the config file, then the environment, then
+// command-line flags. The installers and the run path all take their
+// settings from one Config, so that they agree.
+type Config struct {
+	// File is the config file that was read, or "" if there was none.
+	File string
+
+	values  map[string]configValue
+	flagged map[string]configValue // the values flags took precedence over
+	aliases map[string]string      // alias name to release, from the file
+
+	defaultRelease string // the release the go shim runs, from the file
+}
+
+type configValue struct {
+	value  string
+	source ConfigSource
+}
+
+// DefaultConfigFile returns the config file read when none is named:
+// godl/config in the user's configuration directory, such as
+// ~/.config/godl/config on Linux.
+func DefaultConfigFile() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "godl", "config"), nil
+}
+
+// LoadConfig resolves the configuration from the config file and the
+// environment. The config file is file if non-empty, else the one named by
+// GODL_CONFIG, else DefaultConfigFile, which need not exist.
+//
+// The config file holds one "key = value" setting per line, such as
+//
+//	# Fetch from the company mirror.
+//	base_url = "https://mirror.example.com/go/"
+//	catalog_url = "https://mirror.example.com/go/releases.json"
+//	mirrors = "https://backup.example.com/go/ https://dl.google.com/go/"
+//	max_rate = 2MiB
+//	offline = false
+//
+// where the keys are those of FromEnvironment's variables, lower-cased and
+// without the GODL_ prefix, plus sdk_dir for GODL_SDK_DIR, the directory
+// toolchains are installed in, and prefix for GODL_PREFIX. Values may be
+// quoted as in TOML. Keys such as alias.work define aliases for releases;
+// see Aliases. The key default names the release the go shim runs; see
+// DefaultRelease. Unknown keys are reported with a warning, so that a config
+// file may be shared with newer versions of the tool; malformed lines and
+// invalid aliases are an error. Values are checked by Options.
+func LoadConfig(file string) (*Config, error) {
+	c := &Config{values: map[string]configValue{}, aliases: map[string]string{}}
+	explicit := true
+	if file == "" {
+		file = os.Getenv(envConfig)
+	}
+	if file == "" {
+		explicit = false
+		var err error
+		if file, err = DefaultConfigFile(); err != nil {
+			file = "" // no configuration directory, so no config file
+		}
+	}
+	if file != "" {
+		err := c.readFile(file)
+		if os.IsNotExist(err) && !explicit {
+			err = nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, s := range settings {
+		if v := os.Getenv(s.env); v != "" {
+			c.values[s.key] = configValue{v, SourceEnv}
+		}
+	}
+	return c, nil
+}
+
+// readFile reads settings from the config file named file.
+func (c *Config) readFile(file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	c.File = file
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		i := strings.Index(line, "=")
+		if i < 0 {
+			return fmt.Errorf("%s:%d: expected key = value", file, n)
+		}
+		key := strings.TrimSpace(line[:i])
+		value, err := configString(strings.TrimSpace(line[i+1:]))
+		if err != nil {
+			return fmt.Errorf("%s:%d: %s: %v", file, n, key, err)
+		}
+		if strings.HasPrefix(key, aliasPrefix) {
+			name := strings.TrimPrefix(key, aliasPrefix)
+			if err := checkAlias(name, value); err != nil {
+				return fmt.Errorf("%s:%d: %v", file, n, err)
+			}
+			c.aliases[name] = value
+			continue
+		}
+		if key == defaultKey {
+			if err := checkDefault(value); err != nil {
+				return fmt.Errorf("%s:%d: %v", file, n, err)
+			}
+			c.defaultRelease = value
+			continue
+		}
+		if _, ok := lookupSetting(key); !ok {
+			log.Printf("Warning: %s:%d: unknown setting %q is ignored", file, n, key)
+			continue
+		}
+		c.values[key] = configValue{value, SourceFile}
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("reading %s: %v", file, err)
+	}
+	return nil
+}
+
+// configString returns the value s of a config file line: a TOML basic
+// string in double quotes, a literal string in single quotes, or a bare
+// word such as a number or boolean, which may be followed by a comment.
+func configString(s string) (string, error) {
+	switch {
+	case strings.HasPrefix(s, `"`):
+		end := strings.LastIndex(s, `"`)
+		if end == 0 || !isComment(s[end+1:]) {
+			return "", fmt.Errorf("malformed string %s", s)
+		}
+		return strconv.Unquote(s[:end+1])
+	case strings.HasPrefix(s, "'"):
+		end := strings.LastIndex(s, "'")
+		if end == 0 || !isComment(s[end+1:]) {
+			return "", fmt.Errorf("malformed string %s", s)
+		}
+		return s[1:end], nil
+	}
+	if i := strings.Index(s, "#"); i >= 0 {
+		s = strings.TrimSpace(s[:i])
+	}
+	return s, nil
+}
+
+// isComment reports whether s, the rest of a line, is blank or a comment.
+func isComment(s string) bool {
+	s = strings.TrimSpace(s)
+	return s == "" || strings.HasPrefix(s, "#")
+}
+
+// Set sets the setting key to value, as if by a command-line flag.
+func (c *Config) Set(key, value string) error {
+	if _, ok := lookupSetting(key); !ok {
+		return fmt.Errorf("unknown setting %q", key)
+	}
+	c.values[key] = configValue{value, SourceFlag}
+	return nil
+}
+
+// save sets the setting key to value in the config file, creating the
+// file if need be, for later runs to use. It takes effect in c too,
+// unless the environment or a flag sets key.
+func (c *Config) save(key, value string) error {
+	if err := c.editFile(key, fmt.Sprintf("%s = %s", key, strconv.Quote(value))); err != nil {
+		return err
+	}
+	if _, ok := lookupSetting(key); !ok {
+		return nil
+	}
+	if s := c.values[key].source; s != SourceEnv && s != SourceFlag {
+		c.values[key] = configValue{value, SourceFile}
+	}
+	return nil
+}
+
+// unsave removes the setting key from the config file, if it is there.
+func (c *Config) unsave(key string) error {
+	return c.editFile(key, "")
+}
+
+// editFile replaces the line setting key in the config file, creating the
+// file if need be, with line, or if line is empty, removes it.
+func (c *Config) editFile(key, line string) error {
+	file := c.File
+	if file == "" {
+		var err error
+		if file, err = DefaultConfigFile(); err != nil {
+			return err
+		}
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	var lines []string
+	found := false
+	for _, l := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+		if i := strings.Index(l, "="); i >= 0 && !strings.HasPrefix(strings.TrimSpace(l), "#") && strings.TrimSpace(l[:i]) == key {
+			if found || line == "" {
+				found = true
+				continue
+			}
+			l, found = line, true
+		}
+		lines = append(lines, l)
+	}
+	if !found && line == "" {
+		return nil
+	}
+	if len(data) == 0 {
+		lines = nil
+	}
+	if !found {
+		lines = append(lines, line)
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(file, []byte(strings.Join(lines, "\n")+"\n")); err != nil {
+		return err
+	}
+	c.File = file
+	return nil
+}
+
+// setFlags sets the settings named like the flags that were given on the
+// command line, such as -offline.
+func (c *Config) setFlags(flags *flag.FlagSet) {
+	flags.Visit(func(f *flag.Flag) {
+		key := strings.Replace(f.Name, "-", "_", -1)
+		if _, ok := lookupSetting(key); ok {
+			if old, ok := c.values[key]; ok {
+				if c.flagged == nil {
+					c.flagged = map[string]configValue{}
+				}
+				c.flagged[key] = old
+			}
+			c.values[key] = configValue{f.Value.String(), SourceFlag}
+		}
+	})
+}
+
+// loadConfig resolves the configuration of a command, as LoadConfig does,
+// with the given flags, if non-nil, taking precedence, and makes its SDK
+// directory that of defaultLocator.
+func loadConfig(file string, flags *flag.FlagSet) (*Config, error) {
+	c, err := LoadConfig(file)
+	if err != nil {
+		return nil, err
+	}
+	if flags != nil {
+		c.setFlags(flags)
+	}
+	defaultLocator = c.Locator()
+	return c, nil
+}
+
+// Options returns the downloader options the configuration sets. It
+// reports an error, naming where it was set, for any value that can't be
+// parsed. The options are otherwise validated by NewDownloader.
+func (c *Config) Options() (DownloaderOptions, error) {
+	// The commands resume interrupted downloads, retry failed requests
+	// and fall back to the mirror for China, unless told not to.
+	opts := DownloaderOptions{Resume: true, Fallback: true, Retries: defaultRetries, RetryJitter: defaultRetryJitter, Netrc: defaultNetrc()}
+	var loc Locator
+	for _, s := range settings {
+		v, ok := c.values[s.key]
+		if !ok {
+			continue
+		}
+		if err := s.apply(&opts, &loc, v.value); err != nil {
+			return opts, fmt.Errorf("%s: %v", c.describe(s), err)
+		}
+	}
+	if outputLevel == outputQuiet {
+		opts.Quiet = true
+	}
+	return opts, nil
+}
+
+// describe says where the value of s came from, for error messages.
+func (c *Config) describe(s setting) string {
+	switch c.values[s.key].source {
+	case SourceFile:
+		return fmt.Sprintf("%s in %s", s.key, c.File)
+	case SourceFlag:
+		return "-" + strings.Replace(s.key, "_", "-", -1)
+	default:
+		return s.env
+	}
+}
+
+// prefix returns the directory of container mode, or "" if it is off.
+func (c *Config) prefix() string {
+	return c.values["prefix"].value
+}
+
+// Locator returns the Locator for the configured SDK directory.
+func (c *Config) Locator() *Locator {
+	loc := &Locator{}
+	if v, ok := c.values["sdk_dir"]; ok {
+		loc.Root = v.value
+	}
+	return loc
+}
+
+// A ConfigSetting reports the effective value of a setting, as shown by
+// dl config show.
+type ConfigSetting struct {
+	Key    string       `json:"key"`
+	Env    string       `json:"env"`
+	Value  string       `json:"value"` // with any password redacted
+	Source ConfigSource `json:"source"`
+
+	// Problem, if non-empty, says what is wrong with the value.
+	Problem string `json:"problem,omitempty"`
+}
+
+// Settings returns the effective value of every setting, and checks each:
+// that it parses, that URLs are well-formed, and that files and
+// directories are usable. Passwords in URLs are redacted.
+func (c *Config) Settings() []ConfigSetting {
+	var list []ConfigSetting
+	for _, s := range settings {
+		cs := ConfigSetting{Key: s.key, Env: s.env, Source: SourceDefault}
+		v, ok := c.values[s.key]
+		if ok {
+			cs.Value, cs.Source = v.value, v.source
+			var opts DownloaderOptions
+			var loc Locator
+			if err := s.apply(&opts, &loc, v.value); err != nil {
+				cs.Problem = err.Error()
+			} else {
+				cs.Problem = checkSetting(s.key, v.value)
+			}
+		} else {
+			cs.Value = settingDefault(s.key)
+		}
+		switch s.key {
+		case "base_url", "catalog_url", "proxy":
+			cs.Value = redactURL(cs.Value)
+		case "module_proxy":
+			// It may be a GOPROXY list.
+			cs.Value = urlPasswordRE.ReplaceAllString(cs.Value, "$1:xxxxx@")
+		case "token":
+			if cs.Value != "" {
+				cs.Value = "xxxxx"
+			}
+		case "headers":
+			// Header values are often credentials; keep just the names.
+			var redacted []string
+			for _, kv := range splitHeaders(cs.Value) {
+				name := strings.TrimSpace(strings.SplitN(kv, ":", 2)[0])
+				redacted = append(redacted, name+": xxxxx")
+			}
+			cs.Value = strings.Join(redacted, "; ")
+		case "mirrors":
+			mirrors := parseMirrors(cs.Value)
+			for i, m := range mirrors {
+				mirrors[i] = redactURL(m)
+			}
+			cs.Value = strings.Join(mirrors, " ")
+		}
+		list = append(list, cs)
+	}
+	return list
+}
+
+// settingDefault returns the value a setting has when it isn't set, or ""
+// if there is none.
+func settingDefault(key string) string {
+	switch key {
+	case "base_url":
+		return DefaultBaseURL
+	case "catalog_url":
+		return DefaultCatalogURL
+	case "checksum":
+		return string(ChecksumRequire)
+	case "resume", "fallback":
+		return "true"
+	case "offline", "quiet", "stream", "slim":
+		return "false"
+	case "progress":
+		return "text"
+	case "segments":
+		return "1"
+	case "retries":
+		return strconv.Itoa(defaultRetries)
+	case "retry_backoff":
+		return defaultRetryBackoff.String()
+	case "retry_jitter":
+		return strconv.FormatFloat(defaultRetryJitter, 'g', -1, 64)
+	case "netrc":
+		return defaultNetrc()
+	case "connect_timeout":
+		return defaultConnectTimeout.String()
+	case "response_timeout":
+		return defaultResponseTimeout.String()
+	case "read_timeout":
+		return defaultReadTimeout.String()
+	case "ip":
+		return "auto"
+	case "dedupe":
+		return dedupeOff
+	case "sumdb":
+		return defaultSumDB
+	case "user_agent":
+		return defaultUserAgent()
+	case "goarch":
+		arch, _ := hostArch()
+		return arch
+	case "sdk_dir":
+		root, _ := (&Locator{}).SDKRoot()
+		return root
+	}
+	return ""
+}
+
+// checkSetting returns what is wrong with value, which parses, as the
+// setting key, or "" if nothing is.
+func checkSetting(key, value string) string {
+	switch key {
+	case "base_url":
+		if _, err := checkBaseURL(value); err != nil {
+			return "must be an absolute http, https, s3 or gs URL"
+		}
+	case "catalog_url":
+		if err := checkCatalogURL(value); err != nil {
+			return "must be an absolute http or https URL"
+		}
+	case "mirrors":
+		if _, err := checkMirrors(parseMirrors(value)); err != nil {
+			return err.Error()
+		}
+	case "module_proxy":
+		if _, err := moduleProxy(value); value != "" && err != nil {
+			return "must be an http or https URL, or a GOPROXY list with one"
+		}
+	case "sumdb":
+		if _, err := parseSumDB(value); err != nil {
+			return err.Error()
+		}
+	case "proxy":
+		if _, err := parseProxy(value); err != nil {
+			return "must be an http, https or socks5 URL"
+		}
+	case "checksum":
+		switch ChecksumPolicy(value) {
+		case "", ChecksumRequire, ChecksumIfPublished, ChecksumSkip:
+		default:
+			return fmt.Sprintf("unknown checksum policy %q", value)
+		}
+	case "max_rate", "cache_max_size", "connect_timeout", "response_timeout", "read_timeout", "request_timeout":
+		if strings.HasPrefix(value, "-") {
+			return "must not be negative"
+		}
+	case "retries", "retry_backoff":
+		if strings.HasPrefix(value, "-") {
+			return "must not be negative"
+		}
+	case "ip":
+		if _, err := ipNetwork(value); err != nil {
+			return "must be 4, 6 or auto"
+		}
+	case "dedupe":
+		if err := checkDedupe(value); err != nil {
+			return "must be reflink, hardlink or off"
+		}
+	case "retry_jitter":
+		if f, _ := strconv.ParseFloat(value, 64); f < 0 || f > 1 {
+			return "must be from 0 to 1"
+		}
+	case "segments":
+		if n, _ := strconv.Atoi(value); n < 0 || n > maxSegments {
+			return fmt.Sprintf("must be from 0 to %d", maxSegments)
+		}
+	case "ca_file":
+		if value == "" {
+			break
+		}
+		if _, err := loadTLSConfig(value, ""); err != nil {
+			return err.Error()
+		}
+	case "ca_dir":
+		if value == "" {
+			break
+		}
+		if _, err := loadTLSConfig("", value); err != nil {
+			return err.Error()
+		}
+	case "cache_dir", "sdk_dir", "prefix":
+		if value == "" {
+			break
+		}
+		if !filepath.IsAbs(value) {
+			return "is relative, so it depends on the current directory"
+		}
+		if err := checkCreatable(value); err != nil {
+			return err.Error()
+		}
+	}
+	return ""
+}
+
+// checkCreatable checks that dir is a directory that can be written to,
+// or can be created: that its nearest existing parent can be written to.
+func checkCreatable(dir string) error {
+	if fi, err := os.Stat(dir); err == nil && !fi.IsDir() {
+		return errors.New("is not a directory")
+	}
+	for {
+		fi, err := os.Stat(dir)
+		if err == nil {
+			if !fi.IsDir() {
+				return fmt.Errorf("%s is not a directory", dir)
+			}
+			return checkWritable(dir)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return errors.New("no part of the path exists")
+		}
+		dir = parent
+	}
+}
+
+// redactURL returns s with the password of its user information, if any,
+// replaced by "xxxxx".
+func redactURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || u.User == nil {
+		return s
+	}
+	if _, ok := u.User.Password(); !ok {
+		return s
+	}
+	return u.Redacted()
+}
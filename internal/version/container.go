@@ -0,0 +1,92 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Container mode, set by dl install -dir or GODL_PREFIX, installs a single
+// toolchain to <prefix>/go, the layout images already put in PATH, and
+// needs no home directory: downloads go to a temporary directory unless a
+// cache directory is configured, nothing is journaled, and the installed
+// tree's modification times are normalized so that an image layer holding
+// it is the same whenever it is built.
+
+// prefixGoroot returns the GOROOT of a container mode install.
+func prefixGoroot(prefix string) string {
+	return filepath.Join(prefix, "go")
+}
+
+// checkPrefixTarget refuses to install version over a tree at root that
+// this tool didn't install, such as a base image's Go, or that holds
+// another release.
+func checkPrefixTarget(root, version string) error {
+	if isFile(filepath.Join(root, unpackedOkay)) {
+		b, err := ioutil.ReadFile(filepath.Join(root, "VERSION"))
+		if err != nil {
+			return err
+		}
+		if have := strings.SplitN(string(b), "\n", 2)[0]; have != version {
+			return fmt.Errorf("%s holds %s; remove it first to install %s", root, have, version)
+		}
+		return nil
+	}
+	fis, err := ioutil.ReadDir(root)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(fis) > 0 {
+		return fmt.Errorf("%s already exists and wasn't installed by this tool; remove it first", root)
+	}
+	return nil
+}
+
+// layerTime returns the time to normalize an installed tree at root to:
+// SOURCE_DATE_EPOCH, the convention of reproducible builds, if set, and
+// otherwise the time its archive gives its VERSION file.
+func layerTime(root string, getenv func(string) string) (time.Time, error) {
+	if s := getenv("SOURCE_DATE_EPOCH"); s != "" {
+		sec, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("SOURCE_DATE_EPOCH=%s: not a number of seconds", s)
+		}
+		return time.Unix(sec, 0), nil
+	}
+	fi, err := os.Stat(filepath.Join(root, "VERSION"))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return fi.ModTime(), nil
+}
+
+// normalizeTimes sets the modification time of root and of everything in
+// it to t. Symbolic links are left alone.
+func normalizeTimes(root string, t time.Time) error {
+	return filepath.Walk(root, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if fi.Mode()&os.ModeSymlink != 0 {
+			return nil
+		}
+		return os.Chtimes(path, t, t)
+	})
+}
+
+// isTerminal reports whether f is a terminal, or a console on Windows.
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
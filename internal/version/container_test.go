@@ -0,0 +1,74 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckPrefixTarget(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		err   string
+	}{
+		{"missing", nil, ""},
+		{"empty", map[string]string{}, ""},
+		{"same release", map[string]string{"VERSION": "go1.22.7\ntime 2024-09-04\n", unpackedOkay: ""}, ""},
+		{"other release", map[string]string{"VERSION": "go1.21.0\n", unpackedOkay: ""}, "holds go1.21.0"},
+		{"foreign", map[string]string{"VERSION": "go1.22.7\n", "bin/go": ""}, "wasn't installed by this tool"},
+	}
+	for _, tt := range tests {
+		root := filepath.Join(t.TempDir(), "go")
+		if tt.files != nil {
+			if err := os.Mkdir(root, 0755); err != nil {
+				t.Fatal(err)
+			}
+			makeTree(t, root, tt.files)
+		}
+		err := checkPrefixTarget(root, "go1.22.7")
+		if tt.err == "" && err != nil || tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+			t.Errorf("%s: checkPrefixTarget = %v; want error containing %q", tt.name, err, tt.err)
+		}
+	}
+}
+
+func TestNormalizeTimes(t *testing.T) {
+	root := t.TempDir()
+	makeTree(t, root, map[string]string{"VERSION": "go1.22.7\n", "bin/go": "", "src/a/a.go": "package a\n"})
+	versionTime := time.Date(2024, 9, 4, 17, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(filepath.Join(root, "VERSION"), versionTime, versionTime); err != nil {
+		t.Fatal(err)
+	}
+
+	lt, err := layerTime(root, func(string) string { return "" })
+	if err != nil || !lt.Equal(versionTime) {
+		t.Errorf("layerTime without SOURCE_DATE_EPOCH = %v, %v; want %v", lt, err, versionTime)
+	}
+	lt, err = layerTime(root, func(string) string { return "1700000000" })
+	if err != nil || lt.Unix() != 1700000000 {
+		t.Errorf("layerTime with SOURCE_DATE_EPOCH = %v, %v; want 1700000000", lt, err)
+	}
+	if _, err := layerTime(root, func(string) string { return "yesterday" }); err == nil {
+		t.Error("layerTime accepted SOURCE_DATE_EPOCH=yesterday")
+	}
+
+	if err := normalizeTimes(root, versionTime); err != nil {
+		t.Fatal(err)
+	}
+	err = filepath.Walk(root, func(path string, fi os.FileInfo, err error) error {
+		if err == nil && !fi.ModTime().Equal(versionTime) {
+			t.Errorf("%s has modification time %v; want %v", path, fi.ModTime(), versionTime)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import "strings"
+
+// A platform is the GOOS and GOARCH of another machine, that download
+// -goos and -goarch install a release for, as for a container image. The
+// zero platform is this machine's.
+type platform struct {
+	goos, goarch string
+}
+
+// crossPlatform returns the platform download -goos and -goarch name,
+// either of which may be empty for this machine's. It returns the zero
+// platform if that is this machine's after all.
+func crossPlatform(goos, goarch string) platform {
+	host, _ := hostArch()
+	if goos == "" {
+		goos = getOS()
+	}
+	if goarch == "" {
+		goarch = host
+	}
+	if goos == getOS() && goarch == host {
+		return platform{}
+	}
+	return platform{goos, goarch}
+}
+
+func (p platform) String() string {
+	return p.goos + "/" + p.goarch
+}
+
+// cross reports whether p is another machine's platform.
+func (p platform) cross() bool {
+	return p != platform{}
+}
+
+// goroot returns the GOROOT of version for p. That of another machine's
+// platform is beside this machine's, named by crossName, so that the
+// wrappers, which run only the toolchain named for their release, never
+// run it.
+func (p platform) goroot(version string) (string, error) {
+	if !p.cross() {
+		return goroot(version)
+	}
+	return goroot(crossName(version, p.goos, p.goarch))
+}
+
+// options sets opts to install releases for p.
+func (p platform) options(opts *DownloaderOptions) {
+	if p.cross() {
+		opts.GOOS, opts.GOARCH, opts.Cross = p.goos, p.goarch, true
+	}
+}
+
+// crossName returns the name of the directory in the SDK root holding
+// version for the platform goos/goarch of another machine, such as
+// go1.22.7.linux-arm64.
+func crossName(version, goos, goarch string) string {
+	return version + "." + goos + "-" + goarch
+}
+
+// isCrossName reports whether name is a crossName.
+func isCrossName(name string) bool {
+	i := strings.LastIndex(name, ".")
+	if i < 0 || strings.Contains(name, asideSuffix) {
+		return false
+	}
+	if _, err := ParseVersion(name[:i]); err != nil {
+		return false
+	}
+	plat := strings.Split(name[i+1:], "-")
+	return len(plat) == 2 && plat[0] != "" && plat[1] != ""
+}
@@ -0,0 +1,89 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCrossName(t *testing.T) {
+	for name, want := range map[string]bool{
+		"go1.22.7.linux-arm64":         true,
+		"go1.21rc2.darwin-amd64":       true,
+		"go1.22.7":                     false,
+		"go1.22":                       false,
+		"gotip":                        false,
+		"go1.22.7.linux":               false,
+		"go1.22.7.linux-":              false,
+		"go1.22.7.linux-arm-v6":        false,
+		"go1.22.7.old-abc":             false,
+		"go1.22.7.linux-arm64.old-abc": false,
+		"other.linux-arm64":            false,
+	} {
+		if got := isCrossName(name); got != want {
+			t.Errorf("isCrossName(%q) = %v; want %v", name, got, want)
+		}
+	}
+	if name := crossName("go1.22.7", "linux", "arm64"); !isCrossName(name) {
+		t.Errorf("isCrossName(crossName(...)) = false for %s", name)
+	}
+	if got := toolchainEntry("go1.22.7.linux-arm64.old-abc"); got != "old copy of go1.22.7.linux-arm64" {
+		t.Errorf("toolchainEntry of an old cross toolchain = %q", got)
+	}
+
+	host, _ := hostArch()
+	if p := crossPlatform("", ""); p.cross() {
+		t.Errorf("crossPlatform with no flags = %v; want this machine's", p)
+	}
+	if p := crossPlatform(getOS(), host); p.cross() {
+		t.Errorf("crossPlatform(%s, %s) = %v; want this machine's", getOS(), host, p)
+	}
+	if p := crossPlatform("plan9", ""); p != (platform{"plan9", host}) {
+		t.Errorf("crossPlatform(plan9, \"\") = %v; want plan9/%s", p, host)
+	}
+}
+
+func TestCrossInstall(t *testing.T) {
+	goos := "freebsd"
+	if getOS() == goos {
+		goos = "netbsd"
+	}
+	files := map[string]string{
+		"go/VERSION": "go1.99",
+		"go/bin/go":  "\x7fELF not for this machine",
+	}
+	ts := newTestServer(t)
+	ts.tar = makeTestArchive(t, files, false)
+	d := ts.downloader(t, DownloaderOptions{GOOS: goos, GOARCH: "riscv64", Cross: true})
+	sdk := t.TempDir()
+	root := filepath.Join(sdk, crossName("go1.99", goos, "riscv64"))
+	p, err := d.plan(context.Background(), root, "go1.99")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := archiveName("go1.99", goos, "riscv64"); !strings.HasSuffix(p.URL, "/"+want) {
+		t.Errorf("plan URL = %s; want the archive %s", p.URL, want)
+	}
+	// The go command can't be run here, so a smoke test would fail.
+	if err := d.Execute(context.Background(), p); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, unpackedOkay)); err != nil {
+		t.Errorf("the install wasn't marked complete: %v", err)
+	}
+
+	loc := &Locator{Root: sdk}
+	if installed, err := loc.Installed(); err != nil || len(installed) != 0 {
+		t.Errorf("Installed = %v, %v; want the toolchain for %s/riscv64 left out", installed, err, goos)
+	}
+	entries, err := loc.List()
+	if err != nil || len(entries) != 1 || entries[0].Path != root {
+		t.Errorf("List = %v, %v; want the toolchain in %s", entries, err, root)
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// The values of DownloaderOptions.Dedupe.
+const (
+	dedupeOff      = "off"
+	dedupeReflink  = "reflink"
+	dedupeHardlink = "hardlink"
+)
+
+// checkDedupe reports an error if mode isn't a DownloaderOptions.Dedupe
+// value.
+func checkDedupe(mode string) error {
+	switch mode {
+	case "", dedupeOff, dedupeReflink, dedupeHardlink:
+		return nil
+	}
+	return fmt.Errorf("invalid dedupe mode %q: must be reflink, hardlink or off", mode)
+}
+
+// dedupe carries out the dedupe step s. Files that can't be shared are
+// left as they are: the release is installed either way, so only a
+// cancelled ctx is an error.
+func (d *Downloader) dedupe(ctx context.Context, s Step) error {
+	ref := dedupeSource(s.Target)
+	if ref == "" {
+		verbosef("No other installed release to share the files of %v with", s.Target)
+		return nil
+	}
+	n, size, err := dedupeFiles(ctx, s.Target, ref, s.Mode)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil {
+		log.Printf("Note: could not share files with %v: %v", ref, err)
+	}
+	if n > 0 {
+		log.Printf("Shared %d files, %s, with %v", n, formatByteSize(size), filepath.Base(ref))
+	}
+	return nil
+}
+
+// dedupeSource returns the GOROOT of the release installed beside target
+// that is nearest to the one target is for: the newest older one, or
+// else the oldest newer one. It returns "" if there is none, or target
+// isn't named for a release.
+func dedupeSource(target string) string {
+	v, err := ParseVersion(filepath.Base(target))
+	if err != nil {
+		return ""
+	}
+	loc := &Locator{Root: filepath.Dir(target)}
+	installed, err := loc.Installed()
+	if err != nil {
+		return ""
+	}
+	var older, newer *Version
+	for i, w := range installed { // oldest first
+		switch {
+		case w.Less(v):
+			older = &installed[i]
+		case v.Less(w) && newer == nil:
+			newer = &installed[i]
+		}
+	}
+	ref := older
+	if ref == nil {
+		ref = newer
+	}
+	if ref == nil {
+		return ""
+	}
+	root, err := loc.Goroot(ref.String())
+	if err != nil {
+		return ""
+	}
+	return root
+}
+
+// dedupeFiles replaces each regular file under root that has the same
+// contents and permissions as the file at the same path under ref with a
+// reflink clone or a hard link of it, as mode says. It returns the number
+// of files replaced and their size, stopping at the first error.
+func dedupeFiles(ctx context.Context, root, ref, mode string) (n int, size int64, err error) {
+	err = filepath.Walk(root, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !fi.Mode().IsRegular() || fi.Size() == 0 {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		other := filepath.Join(ref, rel)
+		ofi, err := os.Lstat(other)
+		if err != nil || !ofi.Mode().IsRegular() || ofi.Size() != fi.Size() || ofi.Mode().Perm() != fi.Mode().Perm() || os.SameFile(fi, ofi) {
+			return nil
+		}
+		if same, err := sameContents(path, other); err != nil || !same {
+			return err
+		}
+		if err := shareFile(other, path, fi, mode); err != nil {
+			return err
+		}
+		n++
+		size += fi.Size()
+		return nil
+	})
+	return n, size, err
+}
+
+// sameContents reports whether files a and b, of the same size, have the
+// same contents.
+func sameContents(a, b string) (bool, error) {
+	fa, err := os.Open(a)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		_ = fa.Close()
+	}()
+	fb, err := os.Open(b)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		_ = fb.Close()
+	}()
+	bufa, bufb := make([]byte, 64<<10), make([]byte, 64<<10)
+	for {
+		na, erra := io.ReadFull(fa, bufa)
+		nb, errb := io.ReadFull(fb, bufb)
+		if !bytes.Equal(bufa[:na], bufb[:nb]) {
+			return false, nil
+		}
+		if erra == io.EOF || erra == io.ErrUnexpectedEOF {
+			return errb == erra, nil
+		}
+		if erra != nil {
+			return false, erra
+		}
+		if errb != nil {
+			return false, errb
+		}
+	}
+}
+
+// shareFile replaces dst, described by fi, with a reflink clone or a hard
+// link of src, as mode says. The replacement is put in place by renaming
+// it over dst, so that dst is whole throughout.
+func shareFile(src, dst string, fi os.FileInfo, mode string) error {
+	tmp := dst + ".dedupe"
+	_ = os.Remove(tmp)
+	var err error
+	if mode == dedupeHardlink {
+		err = os.Link(src, tmp)
+	} else if err = cloneFile(src, tmp); err == nil {
+		// A clone is a file of its own, with the times of dst.
+		if err = os.Chmod(tmp, fi.Mode().Perm()); err == nil {
+			err = os.Chtimes(tmp, fi.ModTime(), fi.ModTime())
+		}
+	}
+	if err == nil {
+		err = os.Rename(tmp, dst)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+	}
+	return err
+}
@@ -0,0 +1,114 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDedupeSource(t *testing.T) {
+	root := t.TempDir()
+	makeTree(t, root, map[string]string{
+		"go1.21.0/" + unpackedOkay:     "",
+		"go1.22.5/" + unpackedOkay:     "",
+		"go1.22.6/VERSION":             "go1.22.6", // not completely installed
+		"go1.23.0/" + unpackedOkay:     "",
+		"go1.24.0/" + unpackedOkay:     "",
+		"gotip/" + unpackedOkay:        "",
+		"go1.22.7.old-abc/VERSION":     "go1.22.7",
+		"elsewhere/go/" + unpackedOkay: "",
+	})
+	for _, tt := range []struct{ target, want string }{
+		{"go1.22.7", "go1.22.5"},
+		{"go1.20.0", "go1.21.0"},
+		{"go1.23.0", "go1.22.5"}, // itself, when reinstalled, isn't a candidate
+		{"gotip", ""},
+	} {
+		got := dedupeSource(filepath.Join(root, tt.target))
+		if want := filepath.Join(root, tt.want); tt.want == "" && got != "" || tt.want != "" && got != want {
+			t.Errorf("dedupeSource(%s) = %q; want %s", tt.target, got, tt.want)
+		}
+	}
+	if got := dedupeSource(filepath.Join(root, "elsewhere", "go")); got != "" {
+		t.Errorf("dedupeSource of a -dir install = %q; want none", got)
+	}
+}
+
+func TestDedupeFiles(t *testing.T) {
+	for _, mode := range []string{dedupeHardlink, dedupeReflink} {
+		t.Run(mode, func(t *testing.T) {
+			sdk := t.TempDir()
+			files := map[string]string{
+				"go1.22.5/src/same.go":     "package same\n",
+				"go1.22.5/src/changed.go":  "package old\n",
+				"go1.22.5/src/empty.go":    "",
+				"go1.22.5/" + unpackedOkay: "",
+				"go1.22.7/src/same.go":     "package same\n",
+				"go1.22.7/src/changed.go":  "package new\n",
+				"go1.22.7/src/empty.go":    "",
+				"go1.22.7/src/added.go":    "package added\n",
+			}
+			makeTree(t, sdk, files)
+			ref, root := filepath.Join(sdk, "go1.22.5"), filepath.Join(sdk, "go1.22.7")
+			n, size, err := dedupeFiles(context.Background(), root, ref, mode)
+			if mode == dedupeReflink && err != nil {
+				t.Skipf("reflinks aren't supported here: %v", err)
+			}
+			if err != nil || n != 1 || size != int64(len(files["go1.22.7/src/same.go"])) {
+				t.Errorf("dedupeFiles = %d, %d, %v; want the one identical file shared", n, size, err)
+			}
+			for name, body := range files {
+				if b, err := ioutil.ReadFile(filepath.Join(sdk, filepath.FromSlash(name))); err != nil || string(b) != body {
+					t.Errorf("%s = %q, %v; want %q", name, b, err, body)
+				}
+			}
+			fi, _ := os.Stat(filepath.Join(root, "src", "same.go"))
+			ofi, _ := os.Stat(filepath.Join(ref, "src", "same.go"))
+			if linked := os.SameFile(fi, ofi); linked != (mode == dedupeHardlink) {
+				t.Errorf("same.go hard linked = %v; want %v", linked, !linked)
+			}
+			if fis, _ := ioutil.ReadDir(filepath.Join(root, "src")); len(fis) != 4 {
+				t.Errorf("src holds %d files after deduplicating; want 4, with no temporary files left", len(fis))
+			}
+		})
+	}
+}
+
+func TestDedupeInstall(t *testing.T) {
+	ts := newTestServer(t)
+	sdk := t.TempDir()
+	makeTree(t, sdk, map[string]string{
+		"go1.98/bin/go":          testFiles["go/bin/go"],
+		"go1.98/" + unpackedOkay: "",
+	})
+	if err := os.Chmod(filepath.Join(sdk, "go1.98", "bin", "go"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	d := ts.downloader(t, DownloaderOptions{Dedupe: dedupeHardlink})
+	root := filepath.Join(sdk, "go1.99")
+	p, err := d.plan(context.Background(), root, "go1.99")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hasStep(p, StepDedupe) {
+		t.Fatalf("plan = %v; want a dedupe step", p)
+	}
+	if err := d.Execute(context.Background(), p); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	fi, err := os.Stat(filepath.Join(root, "bin", "go"))
+	ofi, oerr := os.Stat(filepath.Join(sdk, "go1.98", "bin", "go"))
+	if err != nil || oerr != nil || !os.SameFile(fi, ofi) {
+		t.Errorf("bin/go of go1.99 isn't linked to go1.98's: %v, %v", err, oerr)
+	}
+
+	if _, err := NewDownloader(DownloaderOptions{Dedupe: "copy"}); err == nil {
+		t.Errorf("NewDownloader with an unknown dedupe mode succeeded")
+	}
+}
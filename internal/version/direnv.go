@@ -0,0 +1,143 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// The lines delimiting the block dl direnv -w manages in a .envrc file.
+const (
+	envrcBegin = "# >>> dl direnv >>>"
+	envrcEnd   = "# <<< dl direnv <<<"
+)
+
+// envrcSnippet returns a .envrc fragment that puts the toolchain named
+// name on PATH. It looks the toolchain up with dl which whenever direnv
+// loads it, rather than naming a directory, so that it works on any
+// machine; if the toolchain isn't installed it does nothing but say how
+// to install it. dl which prints the directory in style.
+func envrcSnippet(name string, style PathStyle) string {
+	return fmt.Sprintf(`%s
+if goroot=$(%[5]s %[2]s 2>/dev/null); then
+  export GOROOT="$goroot"
+  PATH_add "$goroot/bin"
+else
+  log_status "%[2]s is not installed; run '%[3]s'"
+fi
+%[4]s
+`, envrcBegin, name, installCommand(name), envrcEnd, whichCommand(style))
+}
+
+// direnvHook returns the use_godl function printed by dl direnv -hook,
+// for direnv's direnvrc, which lets a .envrc say just "use godl go1.22.7".
+// dl which prints the directory in style.
+func direnvHook(style PathStyle) string {
+	return fmt.Sprintf(`# use godl <toolchain>: put a toolchain installed by dl or its wrappers
+# on PATH, or say how to install it.
+use_godl() {
+  local goroot
+  if goroot=$(%s "$1" 2>/dev/null); then
+    export GOROOT="$goroot"
+    PATH_add "$goroot/bin"
+  elif [ "$1" = gotip ]; then
+    log_status "gotip is not installed; run 'gotip download'"
+  else
+    log_status "$1 is not installed; run 'dl install $1'"
+  fi
+}
+`, whichCommand(style))
+}
+
+// whichCommand returns the dl which command the .envrc fragments run,
+// passing style on unless it is auto, which dl which works out itself
+// where direnv runs it.
+func whichCommand(style PathStyle) string {
+	if style == PathStyleAuto {
+		return "dl which"
+	}
+	return "dl which -path-style=" + string(style)
+}
+
+// installCommand returns the command that installs the toolchain name.
+func installCommand(name string) string {
+	if name == "gotip" {
+		return "gotip download"
+	}
+	return "dl install " + name
+}
+
+// writeEnvrc adds snippet to the .envrc file, replacing the block an
+// earlier dl direnv -w wrote, if any.
+func writeEnvrc(file, snippet string) error {
+	b, err := ioutil.ReadFile(file)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	old := string(b)
+	var content string
+	i := strings.Index(old, envrcBegin)
+	j := strings.Index(old, envrcEnd)
+	switch {
+	case i >= 0 && j > i:
+		content = old[:i] + snippet + strings.TrimPrefix(old[j+len(envrcEnd):], "\n")
+	case old == "" || strings.HasSuffix(old, "\n"):
+		content = old + snippet
+	default:
+		content = old + "\n" + snippet
+	}
+	return ioutil.WriteFile(file, []byte(content), 0644)
+}
+
+// whichGoroot returns the GOROOT of the installed toolchain name, such as
+// go1.22.7 or gotip, as dl which prints it and the run path runs it. If l
+// has its own SDK directory, the default one is searched too, for
+// toolchains installed before the SDK directory was moved.
+func whichGoroot(l *Locator, name string) (string, error) {
+	locs := []*Locator{l}
+	if l != nil && l.Root != "" {
+		locs = append(locs, &Locator{})
+	}
+	for _, l := range locs {
+		var t Toolchain = l.Tip()
+		if name != "gotip" {
+			v, err := l.Parse(name)
+			if err != nil {
+				return "", err
+			}
+			t = v
+		}
+		if t.Installed() {
+			return t.GorootPath()
+		}
+	}
+	return "", &notInstalledError{name}
+}
+
+// resolveInstalled returns the installed release that name stands for, as
+// dl which looks it up: for a minor version, such as go1.22, its newest
+// stable patch release installed under l, and for latest, the newest
+// stable release installed. It only looks at the file system. Other
+// names, and those with no match installed, are returned as they are.
+func resolveInstalled(l *Locator, name string) string {
+	match := func(Version) bool { return true }
+	if name != "latest" {
+		v, err := ParseVersion(name)
+		if err != nil || !partialRelease(v, name) {
+			return name
+		}
+		match = func(u Version) bool { return u.Major == v.Major && u.Minor == v.Minor }
+	}
+	installed, err := installedReleases(l)
+	for i := len(installed) - 1; err == nil && i >= 0; i-- {
+		if u := installed[i]; u.Pre == "" && match(u) {
+			return u.String()
+		}
+	}
+	return name
+}
@@ -0,0 +1,111 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteEnvrc(t *testing.T) {
+	tests := []struct {
+		name, old, want string
+	}{
+		{"new file", "", envrcSnippet("go1.22.7", PathStyleAuto)},
+		{"appended", "dotenv", "dotenv\n" + envrcSnippet("go1.22.7", PathStyleAuto)},
+		{"replaced", "dotenv\n" + envrcSnippet("go1.21.0", PathStyleAuto) + "export FOO=1\n", "dotenv\n" + envrcSnippet("go1.22.7", PathStyleAuto) + "export FOO=1\n"},
+	}
+	for _, tt := range tests {
+		file := filepath.Join(t.TempDir(), ".envrc")
+		if tt.old != "" {
+			if err := ioutil.WriteFile(file, []byte(tt.old), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := writeEnvrc(file, envrcSnippet("go1.22.7", PathStyleAuto)); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		got, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("%s: .envrc is\n%s\nwant\n%s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDirenvPathStyle(t *testing.T) {
+	if s := envrcSnippet("go1.22.7", PathStyleAuto); !strings.Contains(s, "$(dl which go1.22.7 2>/dev/null)") {
+		t.Errorf("envrcSnippet with auto paths is\n%s", s)
+	}
+	if s := envrcSnippet("go1.22.7", PathStyleUnix); !strings.Contains(s, "$(dl which -path-style=unix go1.22.7 2>/dev/null)") {
+		t.Errorf("envrcSnippet with Unix paths is\n%s", s)
+	}
+	if s := direnvHook(PathStyleWindows); !strings.Contains(s, `$(dl which -path-style=windows "$1" 2>/dev/null)`) {
+		t.Errorf("direnvHook with Windows paths is\n%s", s)
+	}
+}
+
+func TestWhichGoroot(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	def, err := (&Locator{}).SDKRoot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Installed before the SDK directory was moved.
+	makeTree(t, def, map[string]string{"go1.20.14/" + unpackedOkay: ""})
+	dir := t.TempDir()
+	makeTree(t, dir, map[string]string{
+		"go1.22.7/" + unpackedOkay: "",
+		"go1.21.0/bin/go":          "", // not completely installed
+	})
+	l := &Locator{Root: dir}
+	for name, want := range map[string]string{
+		"go1.22.7":  filepath.Join(dir, "go1.22.7"),
+		"go1.20.14": filepath.Join(def, "go1.20.14"),
+	} {
+		if got, err := whichGoroot(l, name); err != nil || got != want {
+			t.Errorf("whichGoroot(%s) = %q, %v; want %q", name, got, err, want)
+		}
+	}
+	for _, name := range []string{"go1.21.0", "gotip"} {
+		if _, err := whichGoroot(l, name); err == nil || !strings.Contains(err.Error(), "not installed") {
+			t.Errorf("whichGoroot(%s) = %v; want a not installed error", name, err)
+		}
+	}
+}
+
+func TestResolveInstalled(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	dir := t.TempDir()
+	makeTree(t, dir, map[string]string{
+		"go1.22.5/" + unpackedOkay:  "",
+		"go1.22.7/" + unpackedOkay:  "",
+		"go1.23rc1/" + unpackedOkay: "",
+		"go1.21.0/bin/go":           "", // not completely installed
+	})
+	l := &Locator{Root: dir}
+	for name, want := range map[string]string{
+		"go1.22":    "go1.22.7",
+		"latest":    "go1.22.7",
+		"go1.22.5":  "go1.22.5",
+		"go1.23":    "go1.23", // only a release candidate
+		"go1.21":    "go1.21",
+		"go1.20":    "go1.20", // a release of its own
+		"gotip":     "gotip",
+		"go1.23rc1": "go1.23rc1",
+	} {
+		if got := resolveInstalled(l, name); got != want {
+			t.Errorf("resolveInstalled(%s) = %s; want %s", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// unpackedSize estimates the disk space a release archive of size bytes
+// takes once unpacked: Go's archives unpack to about three and a half
+// times their size.
+func unpackedSize(size int64) int64 {
+	return size * 7 / 2
+}
+
+// spaceNeeded returns the disk space that the steps of p that write files
+// need in each directory they write to: room for the rest of the archive
+// where it is downloaded, and for the unpacked tree.
+func spaceNeeded(p *Plan) map[string]int64 {
+	need := map[string]int64{}
+	for _, s := range p.Steps {
+		switch s.Kind {
+		case StepDownload:
+			need[filepath.Dir(s.File)] += s.Size - s.Offset
+		case StepUnpack, StepStream:
+			if p.Size > 0 {
+				need[s.Target] += unpackedSize(p.Size)
+			}
+		}
+	}
+	return need
+}
+
+// A spaceError reports that a directory lacks the disk space an install
+// needs.
+type spaceError struct {
+	Dir        string
+	Need, Free int64
+}
+
+func (e *spaceError) Error() string {
+	return fmt.Sprintf("not enough disk space in %s: installing needs about %s there, but only %s is free; free some, for example with 'dl du' and 'dl purge'",
+		e.Dir, formatByteSize(e.Need), formatByteSize(e.Free))
+}
+
+// checkSpace checks that each directory in need has at least the space
+// it needs free, according to free, before an install starts, rather than
+// letting it fail part way through unpacking. Directories whose free
+// space can't be told are let be. Each is checked on its own, so that a
+// cache directory on the same file system as the SDK directory may pass
+// when only their total doesn't fit.
+func checkSpace(need map[string]int64, free func(dir string) (int64, error)) error {
+	dirs := make([]string, 0, len(need))
+	for dir := range need {
+		dirs = append(dirs, dir)
+	}
+	sort.Slice(dirs, func(i, j int) bool { return dirs[i] < dirs[j] })
+	for _, dir := range dirs {
+		n, err := free(existingDir(dir))
+		if err != nil {
+			continue
+		}
+		if n < need[dir] {
+			return &spaceError{Dir: dir, Need: need[dir], Free: n}
+		}
+	}
+	return nil
+}
+
+// existingDir returns dir, or the nearest of its parents that exists if
+// it doesn't yet.
+func existingDir(dir string) string {
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,16 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !darwin && !freebsd && !linux && !windows
+// +build !darwin,!freebsd,!linux,!windows
+
+package version
+
+import "errors"
+
+// freeSpace returns the bytes available on the file system holding dir.
+// It is only implemented on some systems.
+func freeSpace(dir string) (int64, error) {
+	return 0, errors.New("not supported on this system")
+}
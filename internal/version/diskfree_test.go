@@ -0,0 +1,58 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSpaceNeeded(t *testing.T) {
+	root := filepath.Join("sdk", "go1.99")
+	cache := filepath.Join("cache")
+	p := &Plan{Size: 100, Steps: []Step{
+		{Kind: StepDownload, File: filepath.Join(root, "go1.99.linux-amd64.tar.gz"), Size: 100, Offset: 40},
+		{Kind: StepVerify},
+		{Kind: StepUnpack, Target: root},
+		{Kind: StepMarkInstalled},
+	}}
+	if got, want := spaceNeeded(p), map[string]int64{root: 60 + 350}; !reflect.DeepEqual(got, want) {
+		t.Errorf("spaceNeeded with the archive in the target = %v; want %v", got, want)
+	}
+	p.Steps[0].File, p.Steps[0].Offset = filepath.Join(cache, "go1.99.linux-amd64.tar.gz"), 0
+	if got, want := spaceNeeded(p), map[string]int64{cache: 100, root: 350}; !reflect.DeepEqual(got, want) {
+		t.Errorf("spaceNeeded with a cache = %v; want %v", got, want)
+	}
+	p.Steps = []Step{{Kind: StepStream, Target: root}, {Kind: StepMarkInstalled}}
+	if got, want := spaceNeeded(p), map[string]int64{root: 350}; !reflect.DeepEqual(got, want) {
+		t.Errorf("spaceNeeded streaming = %v; want %v", got, want)
+	}
+}
+
+func TestCheckSpace(t *testing.T) {
+	dir := t.TempDir()
+	missing := filepath.Join(dir, "a", "b")
+	free := func(d string) (int64, error) {
+		if d != dir {
+			t.Errorf("free(%s); want the nearest existing directory, %s", d, dir)
+		}
+		return 1000, nil
+	}
+	if err := checkSpace(map[string]int64{missing: 1000}, free); err != nil {
+		t.Errorf("checkSpace with just enough = %v", err)
+	}
+	err := checkSpace(map[string]int64{missing: 1001}, free)
+	var se *spaceError
+	if !errors.As(err, &se) || se.Dir != missing || !strings.Contains(err.Error(), "not enough disk space") {
+		t.Errorf("checkSpace with too little = %v; want a spaceError for %s", err, missing)
+	}
+	unknown := func(string) (int64, error) { return 0, errors.New("not supported on this system") }
+	if err := checkSpace(map[string]int64{dir: 1 << 40}, unknown); err != nil {
+		t.Errorf("checkSpace without free space known = %v; want it let be", err)
+	}
+}
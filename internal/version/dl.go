@@ -0,0 +1,1920 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// A dlCommand is a subcommand of the dl command.
+type dlCommand struct {
+	name  string
+	short string // one-line description for "dl help"
+	run   func(cfg *Config, args []string)
+}
+
+var dlCommands = []dlCommand{
+	{"alias", "define, remove or list names for releases, such as work for go1.21.13", runAlias},
+	{"bundle", "package a release archive for installing on a machine without network access", runBundle},
+	{"cache", "show or clean the archive cache: dl cache path, stats or clean", runCache},
+	{"ci", "install a release in a CI job and tell the CI system about it", runCI},
+	{"completion", "print a shell completion script: dl completion bash, zsh, fish or powershell", nil}, // see completion.go
+	{"config", "show the effective configuration and where it comes from", runConfig},
+	{"default", "set the release that go runs, installing a go shim in GOBIN", runDefault},
+	{"direnv", "print or write a .envrc fragment that puts a release on PATH", runDirenv},
+	{"doctor", "diagnose common problems with the environment", runDoctor},
+	{"du", "report the disk space used by toolchains and caches", runDU},
+	{"env", "describe the SDK directory, the installed toolchains and the default release", runEnv},
+	{"exec", "run a command with GOROOT and PATH set for a release", runExec},
+	{"export-manifest", "print a manifest of the installed toolchains, for import-manifest", runExportManifest},
+	{"gc", "remove old cached archives, failed installs and other leftovers", runGC},
+	{"history", "show the install journal", runHistory},
+	{"import-manifest", "install every toolchain a manifest lists", runImportManifest},
+	{"info", "show a release's files, requirements and local state", runInfo},
+	{"install", "install a release, or the one pinned by godl.lock with -locked", runInstall},
+	{"list", "list the toolchains under the SDK directory, with their size", runList},
+	{"list-remote", "list the published releases, from the go.dev release listing", runListRemote},
+	{"lock", "pin a release and its archive checksums in godl.lock, or several in versions.lock", runLock},
+	{"outdated", "report the installed minor versions that have a newer patch release", runOutdated},
+	{"pick", "choose a release from a list to install, set as the default or remove", runPick},
+	{"prune", "remove installed releases superseded by newer patches, by a retention policy", runPrune},
+	{"purge", "remove every toolchain, the gotip tree and the archive cache", runPurge},
+	{"run-all", "run a command under each of several releases and summarize", runRunAll},
+	{"self-update", "update the dl command to its latest release", runSelfUpdate},
+	{"sync", "install every release versions.lock pins, or -check that they are", runSync},
+	{"uninstall", "remove an installed release and its cached archives", runUninstall},
+	{"version", "print the version of the dl command", runVersion},
+	{"which", "print the GOROOT of an installed release, alias or minor version", runWhich},
+}
+
+// RunDL runs the dl command, which manages the toolchains that the
+// go1.N.M and gotip commands install.
+func RunDL() {
+	log.SetFlags(0)
+	removeOldExecutable()
+	if isShimName(os.Args[0]) {
+		runShim(os.Args[1:])
+	}
+
+	flags := flag.NewFlagSet("dl", flag.ExitOnError)
+	flags.Usage = dlUsage
+	configFile := flags.String("config", "", "read settings from this file instead of the default config file")
+	flags.BoolVar(&nonInteractive, "non-interactive", false, "never ask questions; also the default when not at a terminal or when CI=true")
+	addOutputFlags(flags)
+	addNetworkFlags(flags)
+	flags.Parse(os.Args[1:])
+	if flags.NArg() < 1 {
+		// On a terminal, offer the picker; it only runs commands that
+		// could have been given instead.
+		if interactive() && isTerminal(os.Stdout) {
+			cfg, err := loadConfig(*configFile, flags)
+			if err != nil {
+				fatal("dl", err)
+			}
+			if runPicker(cfg) {
+				os.Exit(0)
+			}
+		}
+		dlUsage()
+		os.Exit(2)
+	}
+	name := flags.Arg(0)
+	if name == "help" {
+		dlUsage()
+		os.Exit(0)
+	}
+	for _, c := range dlCommands {
+		if c.name == name {
+			cfg, err := loadConfig(*configFile, flags)
+			if err != nil {
+				fatal("dl", err)
+			}
+			c.run(cfg, flags.Args()[1:])
+			os.Exit(0)
+		}
+	}
+	// dl go1.N.M runs that release's go command, installing it first if
+	// need be, as its go1.N.M wrapper would; so does an alias of it, and
+	// dl latest runs the newest stable release's, as golatest does.
+	cfg, err := loadConfig(*configFile, flags)
+	if err != nil {
+		fatal("dl", err)
+	}
+	release := cfg.resolveAlias(name)
+	if release == "latest" {
+		runLatest(flags.Args()[1:], *configFile)
+	}
+	_, err = ParseVersion(release)
+	if err == nil {
+		runRelease(release, flags.Args()[1:], *configFile, true)
+	}
+	var ve *VersionError
+	if errors.As(err, &ve) && ve.Suggestion != "" {
+		usagef("dl: unknown command %q (did you mean dl %s?)", name, ve.Suggestion)
+	}
+	log.Printf("dl: unknown command %q", name)
+	dlUsage()
+	os.Exit(2)
+}
+
+// addNetworkFlags adds the flags setting the HTTP timeouts and the IP
+// version, which go before the command to dl.
+func addNetworkFlags(flags *flag.FlagSet) {
+	flags.String("ip", "auto", "connect over IP `version` 4 or 6 only, or auto for either (also GODL_IP)")
+	flags.Duration("connect-timeout", defaultConnectTimeout, "give up connecting, TLS handshake included, after `duration` (also GODL_CONNECT_TIMEOUT)")
+	flags.Duration("response-timeout", defaultResponseTimeout, "give up waiting for a server to start responding after `duration` (also GODL_RESPONSE_TIMEOUT)")
+	flags.Duration("read-timeout", defaultReadTimeout, "give up on a download that has received nothing for `duration`, and retry it (also GODL_READ_TIMEOUT)")
+	flags.Duration("request-timeout", 0, "give up on any request, download included, after `duration`; 0 for no limit (also GODL_REQUEST_TIMEOUT)")
+}
+
+// addInstallFlags adds the flags setting how a release is downloaded
+// and installed, shared by the commands that install releases.
+func addInstallFlags(flags *flag.FlagSet) {
+	flags.Bool("offline", false, "don't use the network; install only from the archive cache (also GODL_OFFLINE=1)")
+	flags.String("base-url", "", "download archives from `url`, such as "+ChinaBaseURL+" in mainland China (also GODL_BASE_URL)")
+	flags.Int("segments", 1, "download archives in up to `n` parts at once, over as many connections (also GODL_SEGMENTS)")
+	flags.Bool("stream", false, "unpack tar.gz archives as they download, without keeping them (also GODL_STREAM)")
+	flags.Bool("slim", false, "leave out the tests, docs and API data, which building and running programs doesn't need (also GODL_SLIM)")
+	flags.String("dedupe", "off", "share the files identical to another installed release's as `mode` reflink (copy-on-write clones) or hardlink (also GODL_DEDUPE)")
+	flags.String("max-rate", "", "limit downloads to `rate` bytes per second, such as 2MiB (also GODL_MAX_RATE)")
+	flags.String("progress", "text", "report progress as `format` text, or json: one JSON object per line on standard error (also GODL_PROGRESS)")
+	flags.String("sdk-dir", "", "install toolchains in `dir`, remembering it in the config file if the usual SDK directory can't be written to")
+}
+
+func dlUsage() {
+	fmt.Fprintf(os.Stderr, "usage: dl [-config file] [-non-interactive] [-q | -v] [-ip 4|6|auto] [timeouts] <command> [arguments]\n")
+	fmt.Fprintf(os.Stderr, "       dl [-config file] <release, such as go1.22.7, or latest> [go command arguments]\n\nThe commands are:\n\n")
+	for _, c := range dlCommands {
+		fmt.Fprintf(os.Stderr, "\t%-10s %s\n", c.name, c.short)
+	}
+	fmt.Fprintf(os.Stderr, "\nThe timeouts, each a duration such as 30s, are -connect-timeout, -response-timeout,\n-read-timeout and -request-timeout.\n")
+	fmt.Fprintf(os.Stderr, "\nRun 'dl <command> -h' for the flags of a command.\n")
+}
+
+func runAlias(cfg *Config, args []string) {
+	flags := flag.NewFlagSet("dl alias", flag.ExitOnError)
+	remove := flags.Bool("d", false, "remove the aliases named")
+	jsonOut := flags.Bool("json", false, "print the aliases as JSON")
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		if *remove {
+			usagef("usage: dl alias -d <name>...")
+		}
+		list := cfg.Aliases()
+		if *jsonOut {
+			if list == nil {
+				list = []Alias{}
+			}
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(list); err != nil {
+				fatal("dl alias", err)
+			}
+			return
+		}
+		for _, a := range list {
+			fmt.Printf("%s=%s\n", a.Name, a.Release)
+		}
+		return
+	}
+	for _, arg := range flags.Args() {
+		if *remove {
+			if err := cfg.saveAlias(arg, ""); err != nil {
+				fatal("dl alias", err)
+			}
+			continue
+		}
+		i := strings.Index(arg, "=")
+		if i < 0 {
+			usagef("usage: dl alias <name>=<release>... | dl alias -d <name>... | dl alias [-json]")
+		}
+		if err := cfg.saveAlias(arg[:i], arg[i+1:]); err != nil {
+			fatal("dl alias", err)
+		}
+	}
+}
+
+func runCache(cfg *Config, args []string) {
+	const usage = "usage: dl cache path | dl cache stats | dl cache clean -all | -older-than=age | -version=release"
+	if len(args) == 0 {
+		usagef(usage)
+	}
+	flags := flag.NewFlagSet("dl cache "+args[0], flag.ExitOnError)
+	jsonOut := flags.Bool("json", false, "print the result as JSON")
+	var all *bool
+	var olderThan, version *string
+	switch args[0] {
+	case "path", "stats":
+	case "clean":
+		all = flags.Bool("all", false, "remove every cached archive")
+		olderThan = flags.String("older-than", "", "remove the archives last downloaded or used longer ago than this, such as 720h or 30d")
+		version = flags.String("version", "", "remove the archives of this release, such as go1.22.7")
+	default:
+		usagef(usage)
+	}
+	flags.Parse(args[1:])
+	if flags.NArg() > 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	opts, err := cfg.Options()
+	if err != nil {
+		fatal("dl cache", err)
+	}
+	dir := opts.CacheDir
+	if dir == "" {
+		log.Fatalf("dl cache: there is no archive cache: archives are kept in each GOROOT unless cache_dir or %s is set", envCacheDir)
+	}
+	encode := func(v interface{}) {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(v); err != nil {
+			fatal("dl cache", err)
+		}
+	}
+
+	switch args[0] {
+	case "path":
+		if *jsonOut {
+			encode(struct {
+				Dir string `json:"dir"`
+			}{dir})
+			return
+		}
+		fmt.Println(dir)
+
+	case "stats":
+		s, err := readCacheStats(dir)
+		if err != nil {
+			fatal("dl cache", err)
+		}
+		s.MaxSize = opts.CacheMaxSize
+		if *jsonOut {
+			encode(s)
+			return
+		}
+		fmt.Printf("Archive cache: %s\n", dir)
+		if s.MaxSize > 0 {
+			fmt.Printf("Entries:       %d, %s of at most %s\n", s.Entries, formatByteSize(s.Size), formatByteSize(s.MaxSize))
+		} else {
+			fmt.Printf("Entries:       %d, %s\n", s.Entries, formatByteSize(s.Size))
+		}
+		if s.Oldest != nil {
+			fmt.Printf("Oldest:        %s, %s\n", s.Oldest.Name, s.Oldest.ModTime.Format("2006-01-02 15:04"))
+			fmt.Printf("Newest:        %s, %s\n", s.Newest.Name, s.Newest.ModTime.Format("2006-01-02 15:04"))
+		}
+		if s.Since != nil {
+			fmt.Printf("Installs:      %d hits, %d misses since %s\n", s.Hits, s.Misses, s.Since.Format("2006-01-02"))
+		} else {
+			fmt.Printf("Installs:      none recorded\n")
+		}
+
+	case "clean":
+		var sel cacheSelector
+		sel.all = *all
+		if *olderThan != "" {
+			if sel.olderThan, err = parseAge(*olderThan); err != nil {
+				usagef("dl cache clean: -older-than=%s: %v", *olderThan, err)
+			}
+		}
+		if *version != "" {
+			v, err := ParseVersion(*version)
+			if err != nil {
+				fatal("dl cache clean", err)
+			}
+			sel.version = &v
+		}
+		if sel.all == (*olderThan != "" || *version != "") {
+			usagef("dl cache clean: give -all, or select archives with -older-than, -version or both")
+		}
+		unlock, err := lockCache(dir, true, false)
+		if err == errLocked {
+			log.Printf("Waiting for the installs using %s to finish...", dir)
+			unlock, err = lockCache(dir, true, true)
+		}
+		if err != nil {
+			fatal("dl cache clean", err)
+		}
+		removed, err := cleanCache(dir, sel, time.Now())
+		unlock()
+		var freed int64
+		for _, e := range removed {
+			freed += e.Size
+		}
+		if removed == nil {
+			removed = []CacheEntry{}
+		}
+		if *jsonOut {
+			encode(struct {
+				Removed []CacheEntry `json:"removed"`
+				Freed   int64        `json:"freed"`
+			}{removed, freed})
+		} else {
+			for _, e := range removed {
+				fmt.Printf("%10s  %s\n", formatByteSize(e.Size), e.Name)
+			}
+			fmt.Printf("%10s  freed, by removing %d archives\n", formatByteSize(freed), len(removed))
+		}
+		if err != nil {
+			fatal("dl cache clean", err)
+		}
+	}
+}
+
+func runCI(cfg *Config, args []string) {
+	if len(args) == 0 || args[0] != "github" {
+		usagef("usage: dl ci github <release> | dl ci github -locked [release]")
+	}
+	flags := flag.NewFlagSet("dl ci github", flag.ExitOnError)
+	inst := newInstallFlags(flags)
+	style := addPathStyleFlag(flags)
+	flags.Parse(args[1:])
+	cfg.setFlags(flags)
+	if flags.NArg() > 1 || !inst.pinned() && flags.NArg() != 1 {
+		usagef("usage: dl ci github <release> | dl ci github -locked [release] | dl ci github -bundle file [release]")
+	}
+
+	path, env, output, github := githubFiles(os.Getenv)
+	if github {
+		fmt.Println(strings.TrimSpace("::group::Installing Go " + flags.Arg(0)))
+	}
+	ctx, stop := interruptContext()
+	defer stop()
+	version, root, err := inst.install(ctx, cfg, flags.Arg(0))
+	if github {
+		fmt.Println("::endgroup::")
+	}
+	if err != nil {
+		fatal("dl ci", err)
+	}
+
+	opts, err := cfg.Options()
+	if err != nil {
+		fatal("dl ci", err)
+	}
+	sdkDir, err := defaultLocator.SDKRoot()
+	if err != nil {
+		fatal("dl ci", err)
+	}
+	arch, _ := hostArch()
+	if opts.GOARCH != "" {
+		arch = opts.GOARCH
+	}
+	r := ciReport{
+		GoVersion: version,
+		GOROOT:    root,
+		SDKDir:    sdkDir,
+		CacheDir:  opts.CacheDir,
+		CacheKey:  ciCacheKey(toolBuildInfo().Version, version, getOS(), arch),
+	}
+	if !github {
+		writePlain(os.Stdout, r, *style)
+		return
+	}
+	if err := writeGitHub(r, path, env, output); err != nil {
+		fatal("dl ci", err)
+	}
+	log.Printf("Added %s to PATH for the next steps; cache key %s.", filepath.Join(root, "bin"), r.CacheKey)
+}
+
+func runConfig(cfg *Config, args []string) {
+	if len(args) == 0 || args[0] != "show" {
+		usagef("usage: dl config show [-json]")
+	}
+	flags := flag.NewFlagSet("dl config show", flag.ExitOnError)
+	jsonOut := flags.Bool("json", false, "print the configuration as JSON")
+	flags.Parse(args[1:])
+	if flags.NArg() > 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	list := cfg.Settings()
+	bad := false
+	for _, s := range list {
+		if s.Problem != "" {
+			bad = true
+		}
+	}
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err := enc.Encode(struct {
+			File     string          `json:"file,omitempty"`
+			Settings []ConfigSetting `json:"settings"`
+			Aliases  []Alias         `json:"aliases,omitempty"`
+		}{cfg.File, list, cfg.Aliases()})
+		if err != nil {
+			fatal("dl config", err)
+		}
+	} else {
+		if cfg.File != "" {
+			fmt.Printf("Config file: %s\n\n", cfg.File)
+		} else if file, err := DefaultConfigFile(); err == nil {
+			fmt.Printf("Config file: %s (not found)\n\n", file)
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		for _, s := range list {
+			value := s.Value
+			if value == "" {
+				value = "(none)"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s", s.Key, value, s.Source)
+			if s.Problem != "" {
+				fmt.Fprintf(tw, "\t! %s", s.Problem)
+			}
+			fmt.Fprintln(tw)
+		}
+		for _, a := range cfg.Aliases() {
+			fmt.Fprintf(tw, "%s%s\t%s\t%s\n", aliasPrefix, a.Name, a.Release, SourceFile)
+		}
+		tw.Flush()
+	}
+	if bad {
+		os.Exit(1)
+	}
+}
+
+func runLock(cfg *Config, args []string) {
+	flags := flag.NewFlagSet("dl lock", flag.ExitOnError)
+	file := flags.String("file", "", "the lockfile to write (default "+LockfileName+", or "+VersionsLockName+" given several releases)")
+	flags.Parse(args)
+	if flags.NArg() < 1 {
+		usagef("usage: dl lock <release, such as go1.22.7 or latest>...")
+	}
+	opts, err := cfg.Options()
+	if err != nil {
+		fatal("dl lock", err)
+	}
+	d, err := NewDownloader(opts)
+	if err != nil {
+		fatal("dl lock", err)
+	}
+	var rs []Release
+	seen := make(map[Version]bool)
+	for _, arg := range flags.Args() {
+		r, err := d.Catalog().Resolve(context.Background(), arg)
+		if err != nil {
+			fatal("dl lock", err)
+		}
+		if seen[r.Version] {
+			continue
+		}
+		seen[r.Version] = true
+		if len(NewLockfile(r).Archives) == 0 {
+			log.Fatalf("dl lock: the release listing has no checksummed archives of %s", r.Version)
+		}
+		rs = append(rs, r)
+	}
+
+	if len(rs) == 1 {
+		if *file == "" {
+			*file = LockfileName
+		}
+		l := NewLockfile(rs[0])
+		if err := writeFileAtomic(*file, l.Bytes()); err != nil {
+			fatal("dl lock", err)
+		}
+		log.Printf("Pinned %s for %d platforms in %s.", l.Go, len(l.Archives), *file)
+		return
+	}
+	if *file == "" {
+		*file = VersionsLockName
+	}
+	vl := NewVersionsLock(rs)
+	if err := writeFileAtomic(*file, vl.Bytes()); err != nil {
+		fatal("dl lock", err)
+	}
+	log.Printf("Pinned %d releases in %s.", len(rs), *file)
+}
+
+// runSync installs the releases of versions.lock that aren't installed,
+// from the archives it pins, or with -check only reports them.
+func runSync(cfg *Config, args []string) {
+	flags := flag.NewFlagSet("dl sync", flag.ExitOnError)
+	file := flags.String("file", VersionsLockName, "the lockfile to read")
+	check := flags.Bool("check", false, "install nothing; exit 1 if a release is missing or was installed from another archive than the pinned one")
+	addInstallFlags(flags)
+	addOutputFlags(flags)
+	flags.Parse(args)
+	if flags.NArg() > 0 {
+		usagef("usage: dl sync [-check] [-file versions.lock]")
+	}
+	cfg.setFlags(flags)
+
+	data, err := ioutil.ReadFile(*file)
+	if err != nil {
+		fatal("dl sync", err)
+	}
+	vl, err := ParseVersionsLock(data)
+	if err != nil {
+		fatal("dl sync", err)
+	}
+	opts, err := cfg.Options()
+	if err != nil {
+		fatal("dl sync", err)
+	}
+	d, err := NewDownloader(opts)
+	if err != nil {
+		fatal("dl sync", err)
+	}
+	var journal []JournalEntry
+	if file, err := JournalFile(); err == nil {
+		journal, _ = ReadJournal(file)
+	}
+	arch, _ := d.arch()
+	results := checkSync(cfg.Locator(), vl, journal, getOS(), arch)
+
+	var missing []string
+	var index []int // of each missing release in results
+	locked := make(map[string]*Lockfile)
+	for i, r := range results {
+		if r.Status == SyncMissing {
+			missing = append(missing, r.Toolchain)
+			index = append(index, i)
+			locked[r.Toolchain] = vl.Releases[i]
+		}
+	}
+	var first error
+	if !*check && len(missing) > 0 {
+		if err := ensureSDKRoot(cfg); err != nil {
+			fatal("dl sync", err)
+		}
+		ctx, stop := interruptContext()
+		errs := runBatch(ctx, opts, missing, locked)
+		stop()
+		for j, err := range errs {
+			r := &results[index[j]]
+			r.Status = SyncInstalled
+			if err != nil {
+				r.Status, r.Note = SyncFailed, err.Error()
+				if first == nil {
+					first = err
+				}
+			}
+		}
+	}
+	writeSync(os.Stdout, results)
+
+	var bad int
+	for _, r := range results {
+		switch r.Status {
+		case SyncMissing, SyncMismatched, SyncFailed:
+			bad++
+		}
+	}
+	if bad == 0 {
+		return
+	}
+	if *check {
+		log.Printf("dl sync: %d of %d releases in %s are missing or mismatched; run 'dl sync' to install them", bad, len(results), *file)
+		os.Exit(ExitFailure)
+	}
+	log.Printf("dl sync: %d of %d releases in %s aren't installed as pinned; remove a mismatched one with 'dl uninstall' and run 'dl sync' again", bad, len(results), *file)
+	if first != nil {
+		os.Exit(ExitCode(first))
+	}
+	os.Exit(ExitFailure)
+}
+
+func runInstall(cfg *Config, args []string) {
+	flags := flag.NewFlagSet("dl install", flag.ExitOnError)
+	inst := newInstallFlags(flags)
+	flags.Parse(args)
+	cfg.setFlags(flags)
+	if flags.NArg() > 1 && !inst.pinned() && *inst.dir == "" && cfg.prefix() == "" {
+		installMany(cfg, flags.Args())
+		return
+	}
+	if flags.NArg() > 1 || !inst.pinned() && flags.NArg() != 1 {
+		usagef("usage: dl install <release>... | dl install -locked [release] | dl install -bundle file [release] | dl install -dir dir <release>")
+	}
+	ctx, stop := interruptContext()
+	defer stop()
+	version, root, err := inst.install(ctx, cfg, flags.Arg(0))
+	if err != nil {
+		fatal("dl install", err)
+	}
+	if cfg.prefix() == "" {
+		warnShadowing(version, root, managedRoots(defaultLocator))
+	}
+}
+
+// installMany installs the releases args name at once, for dl install
+// given several, showing their progress together.
+func installMany(cfg *Config, args []string) {
+	opts, err := cfg.Options()
+	if err != nil {
+		fatal("dl install", err)
+	}
+	d, err := NewDownloader(opts)
+	if err != nil {
+		fatal("dl install", err)
+	}
+	ctx, stop := interruptContext()
+	defer stop()
+	var versions []string
+	seen := make(map[string]bool)
+	for _, arg := range args {
+		r, err := d.Catalog().Resolve(ctx, cfg.resolveAlias(arg))
+		if err != nil {
+			fatal("dl install", err)
+		}
+		if v := r.Version.String(); !seen[v] {
+			seen[v] = true
+			versions = append(versions, v)
+		}
+	}
+	if err := ensureSDKRoot(cfg); err != nil {
+		fatal("dl install", err)
+	}
+
+	errs := runBatch(ctx, opts, versions, nil)
+	var first error
+	failed := 0
+	for i, err := range errs {
+		if err != nil {
+			log.Printf("%s: install failed: %v", versions[i], err)
+			if first == nil {
+				first = err
+			}
+			failed++
+		}
+	}
+	if first != nil {
+		log.Printf("dl install: %d of %d releases failed to install", failed, len(versions))
+		os.Exit(ExitCode(first))
+	}
+}
+
+// runBatch runs installBatch, showing its progress on standard error.
+func runBatch(ctx context.Context, opts DownloaderOptions, versions []string, locked map[string]*Lockfile) []error {
+	var status io.Writer
+	if isTerminal(os.Stderr) {
+		status = os.Stderr
+	}
+	bp := newBatchProgress(versions, os.Stderr, status, keepaliveInterval)
+	log.SetOutput(bp)
+	errs := installBatch(ctx, opts, versions, locked, bp)
+	bp.close()
+	log.SetOutput(os.Stderr)
+	return errs
+}
+
+// installFlags are the flags of the commands that install a release.
+type installFlags struct {
+	locked   *bool
+	lockfile *string
+	bundle   *string
+	dir      *string
+}
+
+// pinned reports whether the flags name the release to install, so that
+// the command needs no argument.
+func (f *installFlags) pinned() bool {
+	return *f.locked || *f.bundle != ""
+}
+
+func newInstallFlags(flags *flag.FlagSet) *installFlags {
+	f := &installFlags{
+		locked:   flags.Bool("locked", false, "install the release pinned by the lockfile, verifying its archive against the pinned checksum"),
+		lockfile: flags.String("lockfile", LockfileName, "the lockfile -locked reads"),
+		bundle:   flags.String("bundle", "", "install the release in the bundle `file` that dl bundle wrote, without the network"),
+		dir:      flags.String("dir", "", "install to `dir`/go, for container images, without using the home directory (also GODL_PREFIX)"),
+	}
+	addInstallFlags(flags)
+	addOutputFlags(flags)
+	return f
+}
+
+// install installs the release arg names, or with -locked the one the
+// lockfile pins, which arg may repeat, and records the install in the
+// journal. It returns the release and its GOROOT.
+func (f *installFlags) install(ctx context.Context, cfg *Config, arg string) (version, root string, err error) {
+	arg = cfg.resolveAlias(arg)
+	if *f.dir != "" {
+		if err := cfg.Set("prefix", *f.dir); err != nil {
+			return "", "", err
+		}
+	}
+	opts, err := cfg.Options()
+	if err != nil {
+		return "", "", err
+	}
+	prefix := cfg.prefix()
+	if prefix != "" {
+		if opts.CacheDir == "" {
+			tmp, err := ioutil.TempDir("", "godl-")
+			if err != nil {
+				return "", "", err
+			}
+			defer os.RemoveAll(tmp)
+			opts.CacheDir = tmp
+		}
+		opts.Quiet = opts.Quiet || !isTerminal(os.Stderr)
+	}
+
+	var l *Lockfile
+	var bundle bundleMeta
+	var bundleArchive string
+	if *f.locked && *f.bundle != "" {
+		return "", "", errors.New("-locked and -bundle can't be given together")
+	}
+	if *f.bundle != "" {
+		tmp, err := ioutil.TempDir("", "godl-")
+		if err != nil {
+			return "", "", err
+		}
+		defer os.RemoveAll(tmp)
+		if bundle, bundleArchive, err = readBundle(*f.bundle, tmp); err != nil {
+			return "", "", err
+		}
+		version = bundle.Go
+		if arg != "" && arg != version {
+			return "", "", fmt.Errorf("%s holds %s, not %s", *f.bundle, version, arg)
+		}
+	} else if *f.locked {
+		data, err := ioutil.ReadFile(*f.lockfile)
+		if err != nil {
+			return "", "", err
+		}
+		if l, err = ParseLockfile(data); err != nil {
+			return "", "", err
+		}
+		version = l.Go.String()
+		if arg != "" && arg != version {
+			return "", "", fmt.Errorf("-locked installs %s, as pinned by %s, not %s", version, *f.lockfile, arg)
+		}
+	} else {
+		d, err := NewDownloader(opts)
+		if err != nil {
+			return "", "", err
+		}
+		c := d.Catalog()
+		if prefix != "" {
+			c.CacheDir = opts.CacheDir
+		}
+		r, err := c.Resolve(ctx, arg)
+		if err != nil {
+			return "", "", err
+		}
+		version = r.Version.String()
+	}
+	if prefix != "" {
+		root = prefixGoroot(prefix)
+		err = checkPrefixTarget(root, version)
+	} else if err = ensureSDKRoot(cfg); err == nil {
+		root, err = goroot(version)
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	rec := newJournalRecorder(version)
+	opts.Events = rec.events
+	d, err := NewDownloader(opts)
+	if err != nil {
+		return "", "", err
+	}
+	if prefix != "" {
+		d.catalog.CacheDir = opts.CacheDir
+	}
+	if l != nil {
+		err = d.installLocked(ctx, root, l)
+	} else if bundleArchive != "" {
+		err = d.installBundle(ctx, root, bundle, bundleArchive)
+	} else {
+		err = d.install(ctx, root, version)
+	}
+	if e, ok := rec.wait(); ok && prefix == "" {
+		arch, _ := d.arch()
+		e.Platform = getOS() + "/" + arch
+		recordInstall(e)
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("%s: %w", version, err)
+	}
+	if prefix != "" {
+		t, err := layerTime(root, os.Getenv)
+		if err == nil {
+			err = normalizeTimes(root, t)
+		}
+		if err != nil {
+			return "", "", fmt.Errorf("normalizing modification times: %v", err)
+		}
+	}
+	return version, root, nil
+}
+
+func runExportManifest(cfg *Config, args []string) {
+	flags := flag.NewFlagSet("dl export-manifest", flag.ExitOnError)
+	flags.Bool("offline", false, "take the checksums from the cached release listing, without the network")
+	flags.Parse(args)
+	if flags.NArg() > 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	cfg.setFlags(flags)
+
+	opts, err := cfg.Options()
+	if err != nil {
+		fatal("dl export-manifest", err)
+	}
+	d, err := NewDownloader(opts)
+	if err != nil {
+		fatal("dl export-manifest", err)
+	}
+	l := cfg.Locator()
+	vs, err := installedReleases(l)
+	if err != nil {
+		fatal("dl export-manifest", err)
+	}
+	var commit string
+	if root, err := whichGoroot(l, "gotip"); err == nil {
+		if commit = gitHead(root); commit == "" {
+			log.Printf("Warning: can't tell which commit gotip is built at, so the manifest leaves it out.")
+		}
+	}
+	m, unpinned, err := NewManifest(context.Background(), d.Catalog(), vs, commit)
+	if err != nil {
+		fatal("dl export-manifest", err)
+	}
+	for _, v := range unpinned {
+		log.Printf("Warning: the release listing has no %s, so the manifest pins no archives of it.", v)
+	}
+	if _, err := os.Stdout.Write(m.Bytes()); err != nil {
+		fatal("dl export-manifest", err)
+	}
+}
+
+func runImportManifest(cfg *Config, args []string) {
+	flags := flag.NewFlagSet("dl import-manifest", flag.ExitOnError)
+	strict := flags.Bool("strict", false, "install nothing unless the manifest pins an archive of every release for this platform")
+	jsonOut := flags.Bool("json", false, "print the results as JSON")
+	addInstallFlags(flags)
+	addOutputFlags(flags)
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		usagef("usage: dl import-manifest [-strict] [-json] <manifest file, or - for standard input>")
+	}
+	cfg.setFlags(flags)
+
+	var data []byte
+	var err error
+	if flags.Arg(0) == "-" {
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(flags.Arg(0))
+	}
+	if err != nil {
+		fatal("dl import-manifest", err)
+	}
+	m, err := ParseManifest(data)
+	if err != nil {
+		fatal("dl import-manifest", err)
+	}
+	opts, err := cfg.Options()
+	if err != nil {
+		fatal("dl import-manifest", err)
+	}
+	if err := ensureSDKRoot(cfg); err != nil {
+		fatal("dl import-manifest", err)
+	}
+	d, err := NewDownloader(opts)
+	if err != nil {
+		fatal("dl import-manifest", err)
+	}
+	arch, _ := d.arch()
+	im := &importer{
+		goos:   getOS(),
+		goarch: arch,
+		strict: *strict,
+		present: func(name string) bool {
+			_, err := whichGoroot(defaultLocator, name)
+			return err == nil
+		},
+		install: func(ctx context.Context, v Version, l *Lockfile) error {
+			return importRelease(ctx, opts, v, l)
+		},
+		installTip: func(ctx context.Context, commit string) error {
+			root, err := goroot("gotip")
+			if err != nil {
+				return err
+			}
+			return downloadTip(ctx, root, commit, &opts)
+		},
+	}
+	if err := im.check(m); err != nil {
+		fatal("dl import-manifest", err)
+	}
+
+	ctx, stop := interruptContext()
+	defer stop()
+	results := im.run(ctx, m)
+	var failed []ImportResult
+	for _, r := range results {
+		if r.Status == ImportFailed {
+			failed = append(failed, r)
+		}
+	}
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			fatal("dl import-manifest", err)
+		}
+	} else {
+		for _, r := range results {
+			fmt.Printf("%-9s  %s", r.Status, r.Toolchain)
+			if r.Error != "" {
+				fmt.Printf(": %s", r.Error)
+			}
+			if r.Note != "" {
+				fmt.Printf("  (%s)", r.Note)
+			}
+			fmt.Println()
+		}
+	}
+	if len(failed) > 0 {
+		log.Printf("dl import-manifest: %d of %d toolchains failed to install", len(failed), len(results))
+		os.Exit(ExitCode(failed[0].err))
+	}
+}
+
+// importRelease installs v for dl import-manifest, from the archive l
+// pins for this platform or, if l is nil, as its wrapper would, and
+// records the install in the journal. Imports run concurrently, so
+// progress isn't printed.
+func importRelease(ctx context.Context, opts DownloaderOptions, v Version, l *Lockfile) error {
+	root, err := goroot(v.String())
+	if err != nil {
+		return err
+	}
+	rec := newJournalRecorder(v.String())
+	opts.Events = rec.events
+	opts.Quiet = true
+	d, err := NewDownloader(opts)
+	if err != nil {
+		return err
+	}
+	if l != nil {
+		err = d.installLocked(ctx, root, l)
+	} else {
+		err = d.install(ctx, root, v.String())
+	}
+	if e, ok := rec.wait(); ok {
+		arch, _ := d.arch()
+		e.Platform = getOS() + "/" + arch
+		recordInstall(e)
+	}
+	return err
+}
+
+func runPrune(cfg *Config, args []string) {
+	flags := flag.NewFlagSet("dl prune", flag.ExitOnError)
+	var p prunePolicy
+	flags.IntVar(&p.keepPerMinor, "keep-per-minor", defaultPrunePolicy.keepPerMinor, "keep the newest `n` installed releases of each minor version, such as go1.22")
+	flags.IntVar(&p.keepLatest, "keep-latest", defaultPrunePolicy.keepLatest, "also keep the newest `n` installed releases overall")
+	dryRun := flags.Bool("n", false, "only show what would be removed")
+	yes := flags.Bool("y", false, "don't ask for confirmation")
+	force := flags.Bool("force", false, "also remove toolchains with files changed or added since they were installed")
+	flags.Parse(args)
+	if flags.NArg() > 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	if err := p.check(); err != nil {
+		usagef("dl prune: %v", err)
+	}
+
+	opts, err := cfg.Options()
+	if err != nil {
+		fatal("dl prune", err)
+	}
+	l := cfg.Locator()
+	installed, err := installedReleases(l)
+	if err != nil {
+		fatal("dl prune", err)
+	}
+	prune := pruneCandidates(installed, p)
+	if len(prune) == 0 {
+		fmt.Println("Nothing to prune.")
+		return
+	}
+	var total int64
+	for _, v := range prune {
+		dirs, _ := uninstallDirs(l, v.String())
+		for _, dir := range dirs {
+			size := diskUsage(dir)
+			fmt.Printf("%10s  %s  %s\n", formatByteSize(size), v, dir)
+			total += size
+		}
+	}
+	fmt.Printf("%10s  total\n", formatByteSize(total))
+	if *dryRun {
+		return
+	}
+
+	if !*yes {
+		answer, err := ask("Type yes to remove the releases above: ", "-y")
+		if err != nil {
+			fatal("dl prune", err)
+		}
+		if answer != "yes" {
+			log.Printf("dl prune: canceled; nothing was removed")
+			os.Exit(ExitInterrupted)
+		}
+	}
+	failed := 0
+	for _, v := range prune {
+		if _, err := uninstall(l, v.String(), opts.CacheDir, *force); err != nil {
+			log.Printf("dl prune: %v", err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		log.Fatalf("dl prune: %d of %d releases could not be removed", failed, len(prune))
+	}
+	log.Printf("Removed %d releases.", len(prune))
+}
+
+func runPurge(cfg *Config, args []string) {
+	flags := flag.NewFlagSet("dl purge", flag.ExitOnError)
+	yes := flags.Bool("y", false, "don't ask for confirmation")
+	force := flags.Bool("force", false, "purge even if the SDK directory holds entries this tool didn't create, leaving those alone")
+	flags.Parse(args)
+	if flags.NArg() > 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	opts, err := cfg.Options()
+	if err != nil {
+		fatal("dl purge", err)
+	}
+	items, err := defaultLocator.PurgeItems(opts.CacheDir, *force)
+	var uerr *UnknownEntriesError
+	if errors.As(err, &uerr) {
+		log.Fatalf("dl purge: %v\nIs that the right directory? Run 'dl purge -force' to remove the rest anyway.", err)
+	}
+	if err != nil {
+		fatal("dl purge", err)
+	}
+	var files toolFiles
+	if file, err := DefaultConfigFile(); err == nil {
+		files.Config = file
+	}
+	if file, err := JournalFile(); err == nil {
+		files.Journal = file
+	}
+	if dir, err := DefaultCacheDir(); err == nil {
+		files.Listing = dir
+	}
+	if dir, err := goBinDir(); err == nil {
+		files.GOBIN = dir
+	}
+	items = append(items, files.purgeItems()...)
+
+	var total int64
+	n := 0
+	for _, it := range items {
+		if it.IfEmpty {
+			continue
+		}
+		fmt.Printf("%10s  %s  (%s)\n", formatByteSize(it.Size), it.Path, it.What)
+		total += it.Size
+		n++
+	}
+	if n == 0 {
+		_ = Purge(items)
+		fmt.Println("Nothing to purge.")
+		return
+	}
+	fmt.Printf("%10s  total\n", formatByteSize(total))
+
+	if !*yes {
+		answer, err := ask("Type yes to remove all of the above: ", "-y")
+		if err != nil {
+			fatal("dl purge", err)
+		}
+		if answer != "yes" {
+			log.Printf("dl purge: canceled; nothing was removed")
+			os.Exit(ExitInterrupted)
+		}
+	}
+	if errs := Purge(items); len(errs) > 0 {
+		for _, err := range errs {
+			log.Printf("dl purge: %v", err)
+		}
+		log.Fatalf("dl purge: %d of %d items could not be removed", len(errs), len(items))
+	}
+	log.Printf("Removed everything.")
+}
+
+func runGC(cfg *Config, args []string) {
+	flags := flag.NewFlagSet("dl gc", flag.ExitOnError)
+	olderThan := flags.String("older-than", "30d", "only remove what was last downloaded, used or written longer ago than this, such as 720h or 7d")
+	dryRun := flags.Bool("n", false, "only show what would be removed")
+	flags.Parse(args)
+	if flags.NArg() > 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	age, err := parseAge(*olderThan)
+	if err != nil {
+		usagef("dl gc: -older-than=%s: %v", *olderThan, err)
+	}
+
+	opts, err := cfg.Options()
+	if err != nil {
+		fatal("dl gc", err)
+	}
+	root, err := cfg.Locator().SDKRoot()
+	if err != nil {
+		fatal("dl gc", err)
+	}
+	dirs := gcDirs{SDKRoot: root, CacheDir: opts.CacheDir, TempDir: os.TempDir()}
+	if dir, err := DefaultCacheDir(); err == nil {
+		dirs.Listing = dir
+	}
+	if dir, err := goBinDir(); err == nil {
+		dirs.GOBIN = dir
+	}
+	if exe, err := os.Executable(); err == nil {
+		dirs.ExeDir = filepath.Dir(exe)
+	}
+	unlock := func() {}
+	if dirs.CacheDir != "" && isDir(dirs.CacheDir) {
+		if unlock, err = lockCache(dirs.CacheDir, true, false); err != nil {
+			log.Printf("Note: the archive cache is in use, so its archives were left alone: %v", err)
+			dirs.CacheDir, unlock = "", func() {}
+		}
+	}
+	defer unlock()
+	items, err := gcItems(dirs, age, time.Now())
+	if err != nil {
+		fatal("dl gc", err)
+	}
+	if len(items) == 0 {
+		fmt.Printf("Nothing older than %s to remove.\n", *olderThan)
+		return
+	}
+	var total int64
+	for _, it := range items {
+		fmt.Printf("%10s  %s  (%s)\n", formatByteSize(it.Size), it.Path, it.What)
+		total += it.Size
+	}
+	if *dryRun {
+		fmt.Printf("%10s  would be freed\n", formatByteSize(total))
+		return
+	}
+
+	var freed int64
+	failed := 0
+	for _, it := range items {
+		if err := purgePath(it.Path); err != nil {
+			log.Printf("dl gc: %v", err)
+			failed++
+			continue
+		}
+		freed += it.Size
+	}
+	fmt.Printf("%10s  freed, by removing %d of %d items\n", formatByteSize(freed), len(items)-failed, len(items))
+	if failed > 0 {
+		unlock()
+		os.Exit(ExitFailure)
+	}
+}
+
+func runList(cfg *Config, args []string) {
+	flags := flag.NewFlagSet("dl list", flag.ExitOnError)
+	jsonOut := flags.Bool("json", false, "print the list as JSON")
+	flags.Parse(args)
+	if flags.NArg() > 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	entries, err := cfg.Locator().List()
+	if err != nil {
+		fatal("dl list", err)
+	}
+	if *jsonOut {
+		if entries == nil {
+			entries = []ListEntry{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(entries); err != nil {
+			fatal("dl list", err)
+		}
+		return
+	}
+	writeList(os.Stdout, entries)
+}
+
+func runListRemote(cfg *Config, args []string) {
+	flags := flag.NewFlagSet("dl list-remote", flag.ExitOnError)
+	stableOnly := flags.Bool("stable-only", false, "leave out betas and release candidates")
+	since := flags.String("since", "", "leave out releases older than this one, such as go1.20")
+	goos := flags.String("os", "", "list only releases with an archive for this GOOS, and show its size")
+	goarch := flags.String("arch", "", "list only releases with an archive for this GOARCH, and show its size")
+	files := flags.Bool("files", false, "print a line per file instead of per release")
+	jsonOut := flags.Bool("json", false, "print the releases, with all their files, as JSON")
+	flags.Bool("offline", false, "use the cached release listing, without the network")
+	flags.Parse(args)
+	if flags.NArg() > 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	cfg.setFlags(flags)
+
+	f := Filter{StableOnly: *stableOnly, OS: *goos, Arch: *goarch}
+	if *since != "" {
+		v, err := ParseVersion(*since)
+		if err != nil {
+			usagef("dl list-remote: -since: %v", err)
+		}
+		f.Since = v
+	}
+	opts, err := cfg.Options()
+	if err != nil {
+		fatal("dl list-remote", err)
+	}
+	d, err := NewDownloader(opts)
+	if err != nil {
+		fatal("dl list-remote", err)
+	}
+	rs, err := d.Catalog().All(context.Background(), f)
+	if err != nil {
+		fatal("dl list-remote", err)
+	}
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(remoteReleases(rs)); err != nil {
+			fatal("dl list-remote", err)
+		}
+		return
+	}
+	if *goos == "" {
+		*goos = getOS()
+	}
+	if *goarch == "" {
+		*goarch, _ = d.arch()
+	}
+	writeRemoteReleases(os.Stdout, remoteReleases(rs), *goos, *goarch, *files)
+}
+
+func runOutdated(cfg *Config, args []string) {
+	flags := flag.NewFlagSet("dl outdated", flag.ExitOnError)
+	jsonOut := flags.Bool("json", false, "print every installed minor version, outdated or not, as JSON")
+	flags.Bool("offline", false, "use the cached release listing, without the network")
+	flags.Parse(args)
+	if flags.NArg() > 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	cfg.setFlags(flags)
+
+	installed, err := installedReleases(cfg.Locator())
+	if err != nil {
+		fatal("dl outdated", err)
+	}
+	opts, err := cfg.Options()
+	if err != nil {
+		fatal("dl outdated", err)
+	}
+	d, err := NewDownloader(opts)
+	if err != nil {
+		fatal("dl outdated", err)
+	}
+	rs, err := d.Catalog().All(context.Background(), Filter{})
+	if err != nil {
+		fatal("dl outdated", err)
+	}
+	entries := outdated(installed, rs)
+	if *jsonOut {
+		if entries == nil {
+			entries = []OutdatedEntry{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(entries); err != nil {
+			fatal("dl outdated", err)
+		}
+	} else {
+		writeOutdated(os.Stdout, entries)
+	}
+	for _, e := range entries {
+		if e.Outdated {
+			os.Exit(ExitFailure)
+		}
+	}
+}
+
+func runDU(cfg *Config, args []string) {
+	flags := flag.NewFlagSet("dl du", flag.ExitOnError)
+	jsonOut := flags.Bool("json", false, "print the report as JSON")
+	flags.Parse(args)
+	if flags.NArg() > 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	opts, err := cfg.Options()
+	if err != nil {
+		fatal("dl du", err)
+	}
+	entries, err := defaultLocator.DiskUsage(context.Background(), opts.CacheDir)
+	if err != nil {
+		fatal("dl du", err)
+	}
+	var total int64
+	for _, e := range entries {
+		total += e.Size
+	}
+
+	if *jsonOut {
+		if entries == nil {
+			entries = []UsageEntry{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err := enc.Encode(struct {
+			Entries []UsageEntry `json:"entries"`
+			Total   int64        `json:"total"`
+		}{entries, total})
+		if err != nil {
+			fatal("dl du", err)
+		}
+		return
+	}
+	var prunable int64
+	for _, e := range entries {
+		switch {
+		case e.Err != "":
+			fmt.Printf("%10s  %s: %s\n", "?", e.Name, e.Err)
+		case len(e.Users) > 0:
+			fmt.Printf("%10s  %s  %s (used by %s)\n", formatByteSize(e.Size), e.Name, e.Path, strings.Join(e.Users, ", "))
+		case e.Prunable:
+			fmt.Printf("%10s  %s  %s (superseded; dl prune removes it)\n", formatByteSize(e.Size), e.Name, e.Path)
+			prunable += e.Size
+		default:
+			fmt.Printf("%10s  %s  %s\n", formatByteSize(e.Size), e.Name, e.Path)
+		}
+	}
+	fmt.Printf("%10s  total\n", formatByteSize(total))
+	if prunable > 0 {
+		fmt.Printf("%10s  of it freed by dl prune\n", formatByteSize(prunable))
+	}
+}
+
+func runHistory(cfg *Config, args []string) {
+	flags := flag.NewFlagSet("dl history", flag.ExitOnError)
+	toolchain := flags.String("toolchain", "", "show only installs of this toolchain, such as go1.22.7 or gotip")
+	since := flags.String("since", "", "show only installs since this date (2006-01-02) or this long ago (such as 720h)")
+	failed := flags.Bool("failed", false, "show only failed installs")
+	jsonOut := flags.Bool("json", false, "print the entries as JSON lines, in the journal's format")
+	flags.Parse(args)
+	if flags.NArg() > 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	var after time.Time
+	if *since != "" {
+		if d, err := time.ParseDuration(*since); err == nil {
+			after = time.Now().Add(-d)
+		} else if t, err := time.ParseInLocation("2006-01-02", *since, time.Local); err == nil {
+			after = t
+		} else {
+			usagef("dl history: -since=%s is neither a date nor a duration", *since)
+		}
+	}
+
+	file, err := JournalFile()
+	if err != nil {
+		fatal("dl history", err)
+	}
+	entries, err := ReadJournal(file)
+	if err != nil {
+		fatal("dl history", err)
+	}
+	entries = filterJournal(entries, *toolchain, after, *failed)
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		for _, e := range entries {
+			if err := enc.Encode(e); err != nil {
+				fatal("dl history", err)
+			}
+		}
+		return
+	}
+	writeJournal(os.Stdout, entries)
+}
+
+func runInfo(cfg *Config, args []string) {
+	flags := flag.NewFlagSet("dl info", flag.ExitOnError)
+	jsonOut := flags.Bool("json", false, "print the information as JSON")
+	flags.Bool("offline", false, "use the cached release listing, without the network")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		usagef("usage: dl info [-json] [-offline] <release, such as go1.22.7>")
+	}
+	cfg.setFlags(flags)
+
+	opts, err := cfg.Options()
+	if err != nil {
+		fatal("dl info", err)
+	}
+	d, err := NewDownloader(opts)
+	if err != nil {
+		fatal("dl info", err)
+	}
+	var journal []JournalEntry
+	if file, err := JournalFile(); err == nil {
+		journal, _ = ReadJournal(file)
+	}
+	info, err := releaseInfo(context.Background(), d.Catalog(), cfg.Locator(), cfg.resolveAlias(flags.Arg(0)), journal, opts.CacheDir)
+	if err != nil {
+		fatal("dl info", err)
+	}
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(info); err != nil {
+			fatal("dl info", err)
+		}
+		return
+	}
+	writeReleaseInfo(os.Stdout, info)
+}
+
+func runEnv(cfg *Config, args []string) {
+	flags := flag.NewFlagSet("dl env", flag.ExitOnError)
+	jsonOut := flags.Bool("json", false, "print the report as JSON")
+	style := addPathStyleFlag(flags)
+	flags.Parse(args)
+	if flags.NArg() > 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	var journal []JournalEntry
+	if file, err := JournalFile(); err == nil {
+		journal, _ = ReadJournal(file)
+	}
+	env, err := environment(cfg, journal)
+	if err != nil {
+		fatal("dl env", err)
+	}
+	env.formatPaths(*style)
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(env); err != nil {
+			fatal("dl env", err)
+		}
+		return
+	}
+	writeEnvironment(os.Stdout, env)
+}
+
+func runUninstall(cfg *Config, args []string) {
+	flags := flag.NewFlagSet("dl uninstall", flag.ExitOnError)
+	force := flags.Bool("force", false, "remove the toolchain even if files in it were changed or added since it was installed")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		usagef("usage: dl uninstall [-force] <release, such as go1.22.7>")
+	}
+	if err := removeRelease(cfg, cfg.resolveAlias(flags.Arg(0)), *force); err != nil {
+		fatal("dl uninstall", err)
+	}
+}
+
+func runExec(cfg *Config, args []string) {
+	flags := flag.NewFlagSet("dl exec", flag.ExitOnError)
+	flags.Parse(args)
+	rest := flags.Args()
+	if len(rest) > 1 && rest[1] == "--" {
+		rest = append(rest[:1:1], rest[2:]...)
+	}
+	if len(rest) < 2 {
+		usagef("usage: dl exec <release, such as go1.22.7, go1.22 or latest, or gotip> [--] <command> [arguments]")
+	}
+	_, root, err := toolchainRoot(cfg, rest[0])
+	if err != nil {
+		fatal("dl exec", err)
+	}
+	checkQuarantine(root)
+	cmd, err := toolchainCommand(context.Background(), root, rest[1], rest[2:]...)
+	if err != nil {
+		fatal("dl exec", err)
+	}
+	runCommand(cmd)
+}
+
+// toolchainRoot returns the toolchain name stands for, as dl exec looks
+// it up, and its GOROOT: gotip, which must be installed, or a release, an
+// alias, a minor version or latest, as the go shim resolves its default,
+// which is installed first if need be.
+func toolchainRoot(cfg *Config, name string) (version, root string, err error) {
+	if name == "gotip" {
+		root, err := whichGoroot(defaultLocator, name)
+		return name, root, err
+	}
+	version, err = resolveDefault(cfg, name)
+	if err != nil {
+		return "", "", err
+	}
+	if root, err := whichGoroot(defaultLocator, version); err == nil {
+		return version, root, nil
+	}
+	log.Printf("%s is not installed; downloading it first.", version)
+	root, err = installRelease(cfg, version)
+	return version, root, err
+}
+
+func runRunAll(cfg *Config, args []string) {
+	flags := flag.NewFlagSet("dl run-all", flag.ExitOnError)
+	parallel := flags.Int("p", 0, "run the command under at most `n` toolchains at once (default all of them)")
+	flags.Parse(args)
+	rest := flags.Args()
+	if len(rest) > 1 && rest[1] == "--" {
+		rest = append(rest[:1:1], rest[2:]...)
+	}
+	if len(rest) < 2 {
+		usagef("usage: dl run-all [-p n] <toolchains, such as go1.21,go1.22,gotip> [--] <command> [arguments]")
+	}
+	var names []string
+	var cmds []*exec.Cmd
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(rest[0], ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		version, root, err := toolchainRoot(cfg, name)
+		if err != nil {
+			fatal("dl run-all", err)
+		}
+		if seen[version] {
+			continue
+		}
+		seen[version] = true
+		checkQuarantine(root)
+		cmd, err := toolchainCommand(context.Background(), root, rest[1], rest[2:]...)
+		if err != nil {
+			fatal("dl run-all", err)
+		}
+		names = append(names, version)
+		cmds = append(cmds, cmd)
+	}
+	if len(cmds) == 0 {
+		usagef("dl run-all: no toolchains named")
+	}
+	if *parallel <= 0 {
+		*parallel = len(cmds)
+	}
+
+	handleSignals()
+	results := runMatrix(names, cmds, os.Stdout, os.Stderr, *parallel)
+	fmt.Println()
+	if failed := writeMatrixSummary(os.Stdout, results); failed > 0 {
+		log.Printf("dl run-all: failed under %d of %d toolchains", failed, len(results))
+		os.Exit(ExitFailure)
+	}
+}
+
+func runPick(cfg *Config, args []string) {
+	flags := flag.NewFlagSet("dl pick", flag.ExitOnError)
+	flags.Parse(args)
+	if flags.NArg() > 0 {
+		usagef("usage: dl pick")
+	}
+	if !isTerminal(os.Stdin) || !isTerminal(os.Stdout) {
+		log.Fatalf("dl pick: standard input and output must be a terminal")
+	}
+	if !runPicker(cfg) {
+		log.Fatalf("dl pick: the terminal can't be put in raw mode on %s", runtime.GOOS)
+	}
+}
+
+func runCompletion(cfg *Config, args []string) {
+	flags := flag.NewFlagSet("dl completion", flag.ExitOnError)
+	remote := flags.Bool("remote", false, "also complete the releases in the cached release listing, without using the network")
+	releases := flags.Bool("releases", false, "list the names the scripts complete as releases, one per line, instead of writing a script")
+	flags.Parse(args)
+	if *releases {
+		if flags.NArg() > 0 {
+			usagef("usage: dl completion -releases [-remote]")
+		}
+		opts, err := cfg.Options()
+		if err != nil {
+			fatal("dl completion", err)
+		}
+		opts.Offline = true
+		d, err := NewDownloader(opts)
+		if err != nil {
+			fatal("dl completion", err)
+		}
+		for _, name := range completionReleases(context.Background(), cfg, d.Catalog(), *remote) {
+			fmt.Println(name)
+		}
+		return
+	}
+	if flags.NArg() != 1 {
+		usagef("usage: dl completion [-remote] <%s>", strings.Join(completionShells, " | "))
+	}
+	if err := writeCompletion(os.Stdout, flags.Arg(0), *remote); err != nil {
+		usagef("dl completion: %v", err)
+	}
+}
+
+func runWhich(cfg *Config, args []string) {
+	flags := flag.NewFlagSet("dl which", flag.ExitOnError)
+	style := addPathStyleFlag(flags)
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		usagef("usage: dl which [-path-style style] <release, such as go1.22.7, go1.22 or latest, or gotip>")
+	}
+	l := cfg.Locator()
+	root, err := whichGoroot(l, resolveInstalled(l, cfg.resolveAlias(flags.Arg(0))))
+	if err != nil {
+		fatal("dl which", err)
+	}
+	fmt.Println(style.Format(root))
+}
+
+func runDefault(cfg *Config, args []string) {
+	flags := flag.NewFlagSet("dl default", flag.ExitOnError)
+	remove := flags.Bool("d", false, "unset the default release and remove the go shim")
+	force := flags.Bool("force", false, "replace a go command in GOBIN that isn't a shim installed by dl default")
+	flags.Parse(args)
+	if flags.NArg() > 1 || *remove && flags.NArg() > 0 {
+		usagef("usage: dl default [-force] <release, such as go1.22.7, go1.22 or latest> | dl default -d | dl default")
+	}
+	dir, err := goBinDir()
+	if err != nil {
+		fatal("dl default", err)
+	}
+	shim := filepath.Join(dir, "go"+exe())
+	if *remove {
+		if err := cfg.saveDefault(""); err != nil {
+			fatal("dl default", err)
+		}
+		removed, err := removeShim(shim)
+		if err != nil {
+			fatal("dl default", err)
+		}
+		if removed {
+			log.Printf("Removed %s", shim)
+		}
+		return
+	}
+	if flags.NArg() == 0 {
+		name := cfg.DefaultRelease()
+		if name == "" {
+			fatal("dl default", errNoDefault)
+		}
+		fmt.Println(name)
+		return
+	}
+	name := flags.Arg(0)
+	if err := checkDefault(name); err != nil {
+		fatal("dl default", err)
+	}
+	version, err := resolveDefault(cfg, name)
+	if err != nil {
+		fatal("dl default", err)
+	}
+	root, err := whichGoroot(cfg.Locator(), version)
+	if err != nil {
+		if root, err = installRelease(cfg, version); err != nil {
+			fatal(version+": download failed", err)
+		}
+	}
+	self, err := os.Executable()
+	if err != nil {
+		fatal("dl default", err)
+	}
+	if err := installShim(shim, self, *force); err != nil {
+		fatal("dl default", err)
+	}
+	if err := cfg.saveDefault(name); err != nil {
+		fatal("dl default", err)
+	}
+	if name == version {
+		log.Printf("%s now runs %s, in %s", shim, version, root)
+	} else {
+		log.Printf("%s now runs %s, currently %s, in %s", shim, name, version, root)
+	}
+	if os.Getenv(envNoPathCheck) == "1" {
+		return
+	}
+	switch found := lookGo(os.Getenv(pathVar())); {
+	case found == "":
+		log.Printf("Warning: %s is not in PATH. Add it to PATH to use the shim as go. (Set %s=1 to silence this.)", dir, envNoPathCheck)
+	case !sameFile(found, shim):
+		log.Printf("Warning: go in PATH is %s, not the shim. Put %s first in PATH to use it. (Set %s=1 to silence this.)", found, dir, envNoPathCheck)
+	}
+}
+
+func runDirenv(cfg *Config, args []string) {
+	flags := flag.NewFlagSet("dl direnv", flag.ExitOnError)
+	write := flags.Bool("w", false, "add the fragment to .envrc in the current directory instead of printing it")
+	hook := flags.Bool("hook", false, "print a use_godl function for direnv's direnvrc instead")
+	style := addPathStyleFlag(flags)
+	flags.Parse(args)
+	if *hook {
+		if flags.NArg() != 0 || *write {
+			usagef("usage: dl direnv [-path-style style] -hook")
+		}
+		fmt.Print(direnvHook(*style))
+		return
+	}
+	if flags.NArg() != 1 {
+		usagef("usage: dl direnv [-w] [-path-style style] <release, such as go1.22.7, or gotip> | dl direnv [-path-style style] -hook")
+	}
+	name := flags.Arg(0)
+	if name != "gotip" {
+		if _, err := ParseVersion(name); err != nil {
+			fatal("dl direnv", err)
+		}
+	}
+	snippet := envrcSnippet(name, *style)
+	if !*write {
+		fmt.Print(snippet)
+		return
+	}
+	if err := writeEnvrc(".envrc", snippet); err != nil {
+		fatal("dl direnv", err)
+	}
+	log.Printf("Wrote .envrc. Run 'direnv allow' to use %s here.", name)
+}
+
+func runVersion(cfg *Config, args []string) {
+	bi := toolBuildInfo()
+	fmt.Printf("dl %s %s/%s\n", bi.Version, runtime.GOOS, runtime.GOARCH)
+}
+
+func runSelfUpdate(cfg *Config, args []string) {
+	flags := flag.NewFlagSet("dl self-update", flag.ExitOnError)
+	check := flags.Bool("check", false, "only report whether an update is available")
+	wrappers := flags.Bool("wrappers", true, "also update the release wrappers, such as go1.22.7 and gotip, and the go shim in GOBIN")
+	flags.Parse(args)
+	if flags.NArg() > 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	ctx := context.Background()
+
+	exe, err := os.Executable()
+	if err == nil {
+		exe, err = filepath.EvalSymlinks(exe)
+	}
+	if err != nil {
+		log.Fatalf("dl self-update: locating this program: %v", err)
+	}
+	bi := toolBuildInfo()
+	if err := checkOwnership(exe, bi); err != nil {
+		fatal("dl self-update", err)
+	}
+	proxy, err := moduleProxy(os.Getenv("GOPROXY"))
+	if err != nil {
+		fatal("dl self-update", err)
+	}
+	opts, err := cfg.Options()
+	if err != nil {
+		fatal("dl self-update", err)
+	}
+	d, err := NewDownloader(opts)
+	if err != nil {
+		fatal("dl self-update", err)
+	}
+	latest, err := d.latestToolVersion(ctx, proxy)
+	if err != nil {
+		fatal("dl self-update", err)
+	}
+	gobin, goErr := findGo()
+
+	// The wrappers are rebuilt at the release of dl, to keep them
+	// in step with it.
+	var outdated []wrapper
+	if dir, err := goBinDir(); *wrappers && err == nil && goErr == nil {
+		ws, err := installedWrappers(ctx, gobin, dir, exe)
+		if err != nil {
+			log.Printf("Note: can't tell which wrappers in %s need updating: %v", dir, err)
+		}
+		for _, w := range ws {
+			if semverLess(w.Version, latest) {
+				outdated = append(outdated, w)
+			}
+		}
+	}
+	update := semverLess(bi.Version, latest)
+	if !update && len(outdated) == 0 {
+		log.Printf("dl %s is up to date.", bi.Version)
+		return
+	}
+	if *check {
+		if update {
+			log.Printf("dl %s can be updated to %s; run 'dl self-update'.", bi.Version, latest)
+		}
+		for _, w := range outdated {
+			log.Printf("%s %s can be updated to %s; run 'dl self-update'.", w.File, w.Version, latest)
+		}
+		return
+	}
+	if goErr != nil {
+		fatal("dl self-update", goErr)
+	}
+
+	if update {
+		dir := filepath.Dir(exe)
+		if err := checkWritable(dir); err != nil {
+			fatal("dl self-update", err)
+		}
+		tmp, err := ioutil.TempDir(dir, ".dl-update-")
+		if err != nil {
+			fatal("dl self-update", err)
+		}
+		log.Printf("Building dl %s with %s ...", latest, gobin)
+		built, err := buildTool(ctx, gobin, bi.Package, latest, tmp)
+		if err != nil {
+			os.RemoveAll(tmp)
+			fatal("dl self-update", err)
+		}
+		err = replaceExecutable(exe, built)
+		os.RemoveAll(tmp)
+		if err != nil {
+			log.Fatalf("dl self-update: replacing %s: %v", exe, err)
+		}
+		log.Printf("Updated dl from %s to %s.", bi.Version, latest)
+	}
+	failed := 0
+	for _, w := range outdated {
+		if !w.isShim() {
+			log.Printf("Building %s %s ...", filepath.Base(w.File), latest)
+		}
+		if err := updateWrapper(ctx, gobin, exe, latest, w); err != nil {
+			log.Printf("dl self-update: updating %s: %v", w.File, err)
+			failed++
+			continue
+		}
+		log.Printf("Updated %s from %s to %s.", w.File, w.Version, latest)
+	}
+	if failed > 0 {
+		log.Fatalf("dl self-update: %d of %d wrappers could not be updated", failed, len(outdated))
+	}
+}
+
+func runDoctor(cfg *Config, args []string) {
+	flags := flag.NewFlagSet("dl doctor", flag.ExitOnError)
+	offline := flags.Bool("offline", false, "skip the checks that need the network")
+	jsonOut := flags.Bool("json", false, "print the results as JSON")
+	flags.Parse(args)
+	if flags.NArg() > 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	results := runChecks(context.Background(), cfg, *offline)
+	failed := false
+	for _, r := range results {
+		if r.Status == checkFail {
+			failed = true
+		}
+	}
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			fatal("dl doctor", err)
+		}
+	} else {
+		for _, r := range results {
+			fmt.Printf("%-4s  %s: %s\n", strings.ToUpper(string(r.Status)), r.Name, r.Message)
+			if r.Remedy != "" && r.Status != checkPass {
+				fmt.Printf("      %s\n", r.Remedy)
+			}
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,350 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// A checkStatus is the outcome of one of dl doctor's checks.
+type checkStatus string
+
+const (
+	checkPass checkStatus = "pass"
+	checkWarn checkStatus = "warn" // works, but likely to cause trouble
+	checkFail checkStatus = "fail" // installing or running toolchains won't work
+	checkSkip checkStatus = "skip"
+)
+
+// A checkResult reports the outcome of a check, with what to do about it
+// unless it passed.
+type checkResult struct {
+	Name    string      `json:"name"`
+	Status  checkStatus `json:"status"`
+	Message string      `json:"message"`
+	Remedy  string      `json:"remedy,omitempty"`
+}
+
+// Free space below which the SDK directory check warns or fails: an
+// install needs room for the archive and the unpacked tree.
+const (
+	lowSpace       = 2 << 30
+	tooLittleSpace = 512 << 20
+)
+
+// networkCheckTimeout bounds each reachability check.
+const networkCheckTimeout = 15 * time.Second
+
+// runChecks runs dl doctor's checks of the environment and cfg, skipping
+// those that need the network if offline is set.
+func runChecks(ctx context.Context, cfg *Config, offline bool) []checkResult {
+	var results []checkResult
+	if _, err := homedir(); err != nil {
+		results = append(results, checkResult{"home directory", checkFail, err.Error(), "set HOME (USERPROFILE on Windows) to your home directory"})
+	} else {
+		results = append(results, checkResult{Name: "home directory", Status: checkPass, Message: "found"})
+	}
+
+	var d *Downloader
+	opts, err := cfg.Options()
+	if err == nil {
+		d, err = NewDownloader(opts)
+	}
+	offline = offline || opts.Offline
+	if err != nil {
+		results = append(results, checkResult{"configuration", checkFail, err.Error(), "fix the setting in the environment or the config file; 'dl config show' shows where each comes from"})
+	} else {
+		results = append(results, checkResult{Name: "configuration", Status: checkPass, Message: "settings are valid"})
+	}
+
+	results = append(results, checkGOROOT(os.Getenv("GOROOT")))
+	results = append(results, checkGoOnPath(os.Getenv(pathVar()))...)
+	results = append(results, checkShadowing(os.Getenv(pathVar()), defaultLocator)...)
+	if root, err := defaultLocator.SDKRoot(); err == nil {
+		results = append(results, checkSDKDir(root)...)
+	}
+	results = append(results, checkGOBIN(os.Getenv(pathVar())))
+	results = append(results, checkGit())
+	results = append(results, checkBootstrap())
+
+	switch {
+	case offline:
+		results = append(results,
+			checkResult{Name: "download server", Status: checkSkip, Message: "offline"},
+			checkResult{Name: "release listing", Status: checkSkip, Message: "offline"},
+			checkResult{Name: "gerrit", Status: checkSkip, Message: "offline"})
+	case d != nil:
+		results = append(results, checkProxy(d.baseURL))
+		results = append(results,
+			checkReachable(ctx, d, "download server", d.baseURL, checkFail),
+			checkReachable(ctx, d, "release listing", d.Catalog().url(), checkWarn),
+			checkReachable(ctx, d, "gerrit", gerritURL, checkWarn))
+	}
+
+	results = append(results, checkInstalls(defaultLocator)...)
+	return results
+}
+
+// checkGOROOT checks an exported GOROOT, which overrides the toolchain
+// any go command would otherwise use.
+func checkGOROOT(goroot string) checkResult {
+	const name = "GOROOT"
+	switch {
+	case goroot == "":
+		return checkResult{Name: name, Status: checkPass, Message: "not set"}
+	case !isFile(filepath.Join(goroot, "bin", "go"+exe())):
+		return checkResult{name, checkFail, fmt.Sprintf("GOROOT=%s, which has no go command", goroot), "unset GOROOT; the go1.N.M and gotip commands set it themselves"}
+	default:
+		return checkResult{name, checkWarn, fmt.Sprintf("GOROOT=%s is exported, so a go command from another toolchain uses its standard library", goroot), "unset GOROOT unless you need it"}
+	}
+}
+
+// checkGoOnPath reports which go command the PATH list selects, and
+// warns if it shadows others.
+func checkGoOnPath(list string) []checkResult {
+	const name = "go in PATH"
+	var found []string
+	var seen []os.FileInfo
+Dirs:
+	for _, dir := range filepath.SplitList(list) {
+		if dir == "" {
+			continue
+		}
+		p := filepath.Join(dir, "go"+exe())
+		fi, err := os.Stat(p)
+		if err != nil || !fi.Mode().IsRegular() {
+			continue
+		}
+		for _, s := range seen {
+			if os.SameFile(fi, s) {
+				continue Dirs // listed twice, or linked
+			}
+		}
+		seen = append(seen, fi)
+		found = append(found, p)
+	}
+	switch len(found) {
+	case 0:
+		return []checkResult{{name, checkWarn, "there is no go command in PATH", "run a wrapper's download with -add-to-path on Windows, or add a toolchain's bin directory to PATH"}}
+	case 1:
+		return []checkResult{{Name: name, Status: checkPass, Message: found[0]}}
+	default:
+		return []checkResult{{name, checkWarn, fmt.Sprintf("%s shadows %s", found[0], strings.Join(found[1:], ", ")), "reorder PATH so that the go command you want comes first"}}
+	}
+}
+
+// checkShadowing warns if, while toolchains are installed, the go command
+// that the PATH list selects is none of theirs.
+func checkShadowing(list string, l *Locator) []checkResult {
+	const name = "installed toolchains in PATH"
+	vs, err := l.Installed()
+	if err != nil || len(vs) == 0 {
+		return nil
+	}
+	newest := vs[len(vs)-1]
+	root, err := newest.GorootPath()
+	if err != nil {
+		return nil
+	}
+	if found := shadowingGo(list, root, managedRoots(l)); found != "" {
+		return []checkResult{{name, checkWarn, fmt.Sprintf("go is %s, not a toolchain dl installed, such as %s", found, newest), shadowFix(newest.String(), root)}}
+	}
+	return []checkResult{{Name: name, Status: checkPass, Message: "go is not shadowed by another toolchain"}}
+}
+
+// checkSDKDir checks that toolchains can be installed and run in root.
+func checkSDKDir(root string) []checkResult {
+	const name = "SDK directory"
+	dir := root
+	for !isDir(dir) {
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return []checkResult{{name, checkFail, fmt.Sprintf("no part of %s exists", root), "check that the home directory is mounted"}}
+		}
+		dir = parent
+	}
+	var results []checkResult
+	if err := checkWritable(dir); err != nil {
+		results = append(results, checkResult{name, checkFail, err.Error(), "make " + dir + " writable by you"})
+	} else if err := checkExecAllowed(dir); err != nil {
+		results = append(results, checkResult{name, checkFail, err.Error(), "move the SDK directory to a file system that allows running programs"})
+	} else {
+		results = append(results, checkResult{Name: name, Status: checkPass, Message: root})
+	}
+
+	const spaceName = "free space"
+	free, err := freeSpace(dir)
+	switch {
+	case err != nil:
+		results = append(results, checkResult{Name: spaceName, Status: checkSkip, Message: err.Error()})
+	case free < tooLittleSpace:
+		results = append(results, checkResult{spaceName, checkFail, fmt.Sprintf("only %s free in %s", formatByteSize(free), dir), "free some space, for example with 'dl du' and 'dl purge'"})
+	case free < lowSpace:
+		results = append(results, checkResult{spaceName, checkWarn, fmt.Sprintf("only %s free in %s", formatByteSize(free), dir), "free some space, for example with 'dl du' and 'dl purge'"})
+	default:
+		results = append(results, checkResult{Name: spaceName, Status: checkPass, Message: formatByteSize(free) + " free"})
+	}
+	return results
+}
+
+// checkGit checks for the git command, which gotip needs.
+func checkGit() checkResult {
+	const name = "git"
+	out, err := exec.Command("git", "--version").Output()
+	if err != nil {
+		return checkResult{name, checkWarn, "git is not installed or doesn't run", "install git if you want to use gotip"}
+	}
+	return checkResult{Name: name, Status: checkPass, Message: strings.TrimSpace(string(out))}
+}
+
+// checkGOBIN checks that the directory go install and dl default put
+// commands in, such as the go1.N.M wrappers and the go shim, is in the
+// PATH list, if it exists.
+func checkGOBIN(list string) checkResult {
+	const name = "GOBIN in PATH"
+	dir, err := goBinDir()
+	if err != nil {
+		return checkResult{Name: name, Status: checkSkip, Message: err.Error()}
+	}
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return checkResult{Name: name, Status: checkPass, Message: dir + " doesn't exist yet"}
+	}
+	for _, d := range filepath.SplitList(list) {
+		if di, err := os.Stat(d); err == nil && os.SameFile(fi, di) {
+			return checkResult{Name: name, Status: checkPass, Message: dir}
+		}
+	}
+	return checkResult{name, checkWarn, dir + " is not in PATH, so the go1.N.M wrappers and the go shim installed there aren't found", "add " + dir + " to PATH in your shell's profile"}
+}
+
+// checkBootstrap checks for the go command that gotip download builds
+// gotip with: that of GOROOT_BOOTSTRAP, or else the one in PATH.
+func checkBootstrap() checkResult {
+	const name = "bootstrap go"
+	gobin := "go"
+	if root := os.Getenv("GOROOT_BOOTSTRAP"); root != "" {
+		gobin = filepath.Join(root, "bin", "go"+exe())
+	}
+	out, err := exec.Command(gobin, "env", "GOROOT", "GOVERSION").Output()
+	if err != nil {
+		return checkResult{name, checkWarn, fmt.Sprintf("no go command to build gotip with: %v", err), "install a Go release and put it in PATH, or point GOROOT_BOOTSTRAP at one, if you want to use gotip"}
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	for len(lines) < 2 {
+		lines = append(lines, "")
+	}
+	goroot, goversion := strings.TrimSpace(lines[0]), strings.TrimSpace(lines[1])
+	arch, _ := hostArch()
+	if err := checkBootstrapVersion(runtime.GOOS, arch, goroot, goversion); err != nil {
+		return checkResult{name, checkWarn, err.Error(), "point GOROOT_BOOTSTRAP at a newer release if you want to use gotip"}
+	}
+	return checkResult{Name: name, Status: checkPass, Message: strings.TrimSpace(goversion + " in " + goroot)}
+}
+
+// checkProxy reports the proxy, if any, that requests to url go through,
+// as the environment sets it.
+func checkProxy(url string) checkResult {
+	const name = "proxy"
+	req, err := http.NewRequest(http.MethodHead, url, nil)
+	if err != nil {
+		return checkResult{Name: name, Status: checkSkip, Message: err.Error()}
+	}
+	proxy, err := http.ProxyFromEnvironment(req)
+	switch {
+	case err != nil:
+		return checkResult{name, checkFail, err.Error(), "fix HTTPS_PROXY, or unset it"}
+	case proxy == nil:
+		return checkResult{Name: name, Status: checkPass, Message: "none"}
+	}
+	proxy.User = nil // keep credentials out of the report
+	return checkResult{Name: name, Status: checkPass, Message: fmt.Sprintf("requests to %s go through %s", req.URL.Host, proxy)}
+}
+
+// checkReachable checks that url can be fetched, reporting status on
+// failure.
+func checkReachable(ctx context.Context, d *Downloader, name, url string, status checkStatus) checkResult {
+	ctx, cancel := context.WithTimeout(ctx, networkCheckTimeout)
+	defer cancel()
+	res, err := d.do(ctx, http.MethodHead, url)
+	if err == nil {
+		_ = res.Body.Close()
+		return checkResult{Name: name, Status: checkPass, Message: url}
+	}
+	if isTLSError(err) {
+		return checkResult{name, status, fmt.Sprintf("%s: %v", url, err), "if your network intercepts TLS, set GODL_CA_FILE to its certificate authority"}
+	}
+	return checkResult{name, status, fmt.Sprintf("%s: %v", url, err), "check your network connection and proxy settings (HTTPS_PROXY)"}
+}
+
+// isTLSError reports whether err is from a certificate that couldn't be
+// verified, as when a proxy intercepts TLS.
+func isTLSError(err error) bool {
+	var (
+		unknown   x509.UnknownAuthorityError
+		invalid   x509.CertificateInvalidError
+		hostname  x509.HostnameError
+		untrusted x509.SystemRootsError
+	)
+	return errors.As(err, &unknown) || errors.As(err, &invalid) || errors.As(err, &hostname) || errors.As(err, &untrusted)
+}
+
+// checkInstalls checks each toolchain under l's SDK root, but not those
+// downloaded for other machines, which this one can't run.
+func checkInstalls(l *Locator) []checkResult {
+	root, err := l.SDKRoot()
+	if err != nil {
+		return nil
+	}
+	fis, err := ioutil.ReadDir(root)
+	if err != nil {
+		return nil
+	}
+	sortEntries(fis)
+	var results []checkResult
+	for _, fi := range fis {
+		name := fi.Name()
+		if !fi.IsDir() || toolchainEntry(name) == "" || strings.Contains(name, asideSuffix) || isCrossName(name) {
+			continue
+		}
+		dir := filepath.Join(root, name)
+		gobin := isFile(filepath.Join(dir, "bin", "go"+exe()))
+		marker := isFile(filepath.Join(dir, unpackedOkay))
+		r := checkResult{Name: name, Status: checkPass, Message: dir}
+		switch {
+		case name == "gotip":
+			if !gobin {
+				r = checkResult{name, checkWarn, "the gotip tree isn't built", "run 'gotip download'"}
+			}
+		case !marker:
+			r = checkResult{name, checkWarn, "the install was not completed", fmt.Sprintf("run '%s download'", name)}
+		case !gobin:
+			r = checkResult{name, checkFail, "marked installed, but has no go command", fmt.Sprintf("remove %s and run '%s download'", dir, name)}
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+func isFile(name string) bool {
+	fi, err := os.Stat(name)
+	return err == nil && fi.Mode().IsRegular()
+}
+
+func isDir(name string) bool {
+	fi, err := os.Stat(name)
+	return err == nil && fi.IsDir()
+}
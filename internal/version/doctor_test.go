@@ -0,0 +1,153 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestCheckGOROOT(t *testing.T) {
+	good := t.TempDir()
+	makeTree(t, good, map[string]string{"bin/go" + exe(): ""})
+	tests := []struct {
+		goroot string
+		want   checkStatus
+	}{
+		{"", checkPass},
+		{good, checkWarn},
+		{filepath.Join(good, "stale"), checkFail},
+	}
+	for _, tt := range tests {
+		if r := checkGOROOT(tt.goroot); r.Status != tt.want {
+			t.Errorf("checkGOROOT(%q) = %+v; want %s", tt.goroot, r, tt.want)
+		}
+	}
+}
+
+func TestCheckGoOnPath(t *testing.T) {
+	a, b, empty := t.TempDir(), t.TempDir(), t.TempDir()
+	makeTree(t, a, map[string]string{"go" + exe(): ""})
+	makeTree(t, b, map[string]string{"go" + exe(): ""})
+	join := func(dirs ...string) string { return strings.Join(dirs, string(filepath.ListSeparator)) }
+	tests := []struct {
+		list string
+		want checkStatus
+	}{
+		{"", checkWarn},
+		{join(empty), checkWarn},
+		{join(empty, a), checkPass},
+		{join(a, a), checkPass},
+		{join(a, empty, b), checkWarn},
+	}
+	for _, tt := range tests {
+		rs := checkGoOnPath(tt.list)
+		if len(rs) != 1 || rs[0].Status != tt.want {
+			t.Errorf("checkGoOnPath(%q) = %+v; want %s", tt.list, rs, tt.want)
+		}
+	}
+	if rs := checkGoOnPath(join(a, b)); !strings.Contains(rs[0].Message, a) || rs[0].Remedy == "" {
+		t.Errorf("checkGoOnPath with two go commands = %+v; want the first named, with a remedy", rs[0])
+	}
+}
+
+func TestCheckShadowing(t *testing.T) {
+	sys, sdk := t.TempDir(), t.TempDir()
+	makeTree(t, sys, map[string]string{"go" + exe(): ""})
+	l := &Locator{Root: sdk}
+	if rs := checkShadowing(sys, l); len(rs) != 0 {
+		t.Errorf("checkShadowing with nothing installed = %+v; want no results", rs)
+	}
+	makeTree(t, sdk, map[string]string{"go1.22.7/bin/go" + exe(): "", "go1.22.7/" + unpackedOkay: ""})
+	bin := filepath.Join(sdk, "go1.22.7", "bin")
+	join := func(dirs ...string) string { return strings.Join(dirs, string(filepath.ListSeparator)) }
+	if rs := checkShadowing(join(sys, bin), l); len(rs) != 1 || rs[0].Status != checkWarn || !strings.Contains(rs[0].Remedy, bin) {
+		t.Errorf("checkShadowing with a shadowing go = %+v; want a warning with a remedy naming %s", rs, bin)
+	}
+	if rs := checkShadowing(join(bin, sys), l); len(rs) != 1 || rs[0].Status != checkPass {
+		t.Errorf("checkShadowing with the toolchain first = %+v; want a pass", rs)
+	}
+}
+
+func TestCheckInstalls(t *testing.T) {
+	root := t.TempDir()
+	makeTree(t, root, map[string]string{
+		"go1.22.7/bin/go" + exe():  "",
+		"go1.22.7/" + unpackedOkay: "",
+		"go1.21.0/bin/go" + exe():  "", // interrupted
+		"go1.20.1/" + unpackedOkay: "", // damaged
+		"gotip/src/make.bash":      "", // not built
+		"go1.19.old-abc/VERSION":   "",
+		"notes/readme.txt":         "",
+	})
+	got := map[string]checkStatus{}
+	for _, r := range checkInstalls(&Locator{Root: root}) {
+		got[r.Name] = r.Status
+		if r.Status != checkPass && r.Remedy == "" {
+			t.Errorf("%s: %s without a remedy", r.Name, r.Status)
+		}
+	}
+	want := map[string]checkStatus{
+		"go1.22.7": checkPass,
+		"go1.21.0": checkWarn,
+		"go1.20.1": checkFail,
+		"gotip":    checkWarn,
+	}
+	if len(got) != len(want) {
+		t.Errorf("checkInstalls checked %v; want %v", got, want)
+	}
+	for name, status := range want {
+		if got[name] != status {
+			t.Errorf("checkInstalls: %s = %q; want %q", name, got[name], status)
+		}
+	}
+}
+
+func TestCheckSDKDir(t *testing.T) {
+	// The SDK root need not exist yet; its nearest existing parent is
+	// checked instead.
+	root := filepath.Join(t.TempDir(), "Cache", "go_sdk")
+	rs := checkSDKDir(root)
+	if len(rs) == 0 || rs[0].Status != checkPass {
+		t.Errorf("checkSDKDir(%s) = %+v; want a pass first", root, rs)
+	}
+}
+
+func TestCheckGOBIN(t *testing.T) {
+	gobin, other := t.TempDir(), t.TempDir()
+	t.Setenv("GOBIN", gobin)
+	join := func(dirs ...string) string { return strings.Join(dirs, string(filepath.ListSeparator)) }
+	if r := checkGOBIN(join(other, gobin)); r.Status != checkPass {
+		t.Errorf("checkGOBIN with GOBIN in PATH = %+v; want a pass", r)
+	}
+	if r := checkGOBIN(other); r.Status != checkWarn || !strings.Contains(r.Remedy, gobin) {
+		t.Errorf("checkGOBIN without GOBIN in PATH = %+v; want a warning with a remedy naming %s", r, gobin)
+	}
+	t.Setenv("GOBIN", filepath.Join(gobin, "missing"))
+	if r := checkGOBIN(other); r.Status != checkPass {
+		t.Errorf("checkGOBIN with no GOBIN directory = %+v; want a pass", r)
+	}
+}
+
+func TestCheckBootstrap(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+		t.Skip("runs a shell script as go")
+	}
+	root := t.TempDir()
+	t.Setenv("GOROOT_BOOTSTRAP", root)
+	if r := checkBootstrap(); r.Status != checkWarn || r.Remedy == "" {
+		t.Errorf("checkBootstrap with no go in GOROOT_BOOTSTRAP = %+v; want a warning with a remedy", r)
+	}
+	makeTree(t, root, map[string]string{"bin/go": "#!/bin/sh\necho " + root + "\necho go1.22.7\n"})
+	if err := os.Chmod(filepath.Join(root, "bin", "go"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if r := checkBootstrap(); r.Status != checkPass || !strings.Contains(r.Message, "go1.22.7") {
+		t.Errorf("checkBootstrap = %+v; want a pass naming go1.22.7", r)
+	}
+}
@@ -0,0 +1,562 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is where release archives and their checksums are
+// published.
+const DefaultBaseURL = "https://dl.google.com/go/"
+
+// A ChecksumPolicy says how downloaded archives are verified.
+type ChecksumPolicy string
+
+const (
+	// ChecksumRequire fails the install unless the archive matches its
+	// published SHA-256. It is the default.
+	ChecksumRequire ChecksumPolicy = "require"
+
+	// ChecksumIfPublished verifies the archive if the server publishes a
+	// checksum for it, and otherwise installs it with a warning. It is
+	// meant for mirrors that don't carry the .sha256 files.
+	ChecksumIfPublished ChecksumPolicy = "if-published"
+
+	// ChecksumSkip installs archives without verifying them.
+	ChecksumSkip ChecksumPolicy = "skip"
+)
+
+// DownloaderOptions configures how Go releases are fetched.
+//
+// The zero value downloads from DefaultBaseURL with default timeouts.
+// Commands build their options from a Config: the defaults, then the
+// config file, then the environment (see FromEnvironment), then their
+// command-line flags, with later sources taking precedence.
+type DownloaderOptions struct {
+	// Client performs every outbound HTTP request made by the downloader:
+	// archives, checksums and release metadata. If nil, a client with the
+	// package's default timeouts is used. It may not be combined with the
+	// options that configure the default client: CAFile, CADir, Proxy and
+	// the timeouts.
+	Client Doer
+
+	// Events, if non-nil, receives an Event at each step of an install.
+	// It may be consumed from another goroutine. The installer never
+	// closes it, and never waits on it for long: progress events are
+	// dropped while the consumer is busy, and any other event is dropped
+	// if it cannot be delivered within a few seconds.
+	Events chan<- Event
+
+	// BaseURL is the http or https URL that archives and checksums are
+	// fetched from, such as a mirror of DefaultBaseURL. If empty,
+	// DefaultBaseURL is used.
+	BaseURL string
+
+	// CatalogURL is the JSON release listing, in the format of
+	// DefaultCatalogURL, that release names are resolved with, such as a
+	// mirror of it kept beside a mirror of the archives. If empty,
+	// DefaultCatalogURL is used, or ChinaCatalogURL with ChinaBaseURL.
+	CatalogURL string
+
+	// Mirrors are base URLs, like BaseURL, that each file is fetched from
+	// in turn, after BaseURL, when it can't be reached, is overloaded or
+	// down, or doesn't have the file. One that fails is tried after those
+	// that haven't for the rest of the Downloader's requests. Checksums
+	// are fetched the same way, so a mirror is trusted no more than
+	// BaseURL is; a lockfile's checksums are verified all the same.
+	Mirrors []string
+
+	// ModuleProxy, if set, is a module proxy, or a GOPROXY list naming
+	// one, that releases are fetched from instead of BaseURL, as zips of
+	// the golang.org/toolchain module, which the go command switches
+	// toolchains with and which holds the releases from go1.21.0 on. It
+	// spares a second mirror where the module proxy is mirrored already.
+	// The zips are verified against the checksum database SumDB,
+	// according to the Checksum policy, rather than a published SHA-256.
+	ModuleProxy string
+
+	// SumDB is the checksum database module zips are verified with,
+	// written as GOSUMDB is: the name of a known one, such as
+	// sum.golang.org, the default, or a verifier key, either followed by
+	// the URL it is served at, or "off" for none. The module proxy is
+	// asked first, in case it mirrors the database.
+	SumDB string
+
+	// Checksum is the verification policy. If empty, ChecksumRequire
+	// is used.
+	Checksum ChecksumPolicy
+
+	// CacheDir, if non-empty, is where archives are downloaded and
+	// kept for reuse. Otherwise they are kept in the GOROOT they were
+	// unpacked into.
+	CacheDir string
+
+	// CacheMaxSize, if positive, bounds the bytes of archives CacheDir
+	// keeps: when an install is done with the cache, the archives least
+	// recently downloaded or used are removed until the rest fit. The
+	// most recent one is always kept.
+	CacheMaxSize int64
+
+	// Resume continues a partially downloaded archive, as left by an
+	// interrupted install, rather than starting it over, with a Range
+	// request, as long as the server still has the same file. Partial
+	// downloads are kept on failure so that they can be resumed. The
+	// archive is verified as a whole either way. Commands resume by
+	// default.
+	Resume bool
+
+	// Fallback, when downloading from DefaultBaseURL or with it among
+	// Mirrors, switches to ChinaBaseURL, the official mirror for mainland
+	// China, for the rest of the Downloader's requests once dl.google.com
+	// or go.dev can't be connected to at all, after any retries. Archives
+	// are verified just the same. Commands fall back by default.
+	Fallback bool
+
+	// MaxRate, if positive, limits download bandwidth in bytes per second.
+	// It limits gotip's git fetches too, unless git has a proxy to use.
+	MaxRate int64
+
+	// Segments, if more than 1, downloads archives in up to that many
+	// parts at once, each on a connection of its own, which is faster on
+	// links with high latency. Archives are only split into parts of at
+	// least a few megabytes, and are downloaded whole from servers that
+	// don't serve byte ranges. At most 16 parts are allowed.
+	Segments int
+
+	// Stream unpacks a tar.gz archive as it downloads, hashing it on the
+	// way, rather than unpacking it once it has downloaded, so that an
+	// install takes about as long as the slower of the two, and the
+	// archive is never kept on disk. It is checked against its checksum
+	// once it has all arrived, and what was unpacked is removed if it
+	// doesn't match. Archives are streamed only when they are downloaded
+	// whole and afresh, without CacheDir, which keeps them, and not as
+	// zip files, which can't be read before they have arrived; other
+	// installs are unaffected.
+	Stream bool
+
+	// Slim leaves out of an installed release what building and running
+	// Go programs never reads: the test and doc directories, the api
+	// data, and the tests and testdata of the standard library and
+	// commands, about a third of the release. go test std doesn't work in
+	// a slim release, which is otherwise complete.
+	Slim bool
+
+	// Dedupe, if "reflink" or "hardlink", makes the files of a newly
+	// installed release that are identical to those of the installed
+	// release nearest to it share their storage: as copy-on-write clones,
+	// on file systems that support them, such as btrfs, XFS and APFS, or
+	// as hard links, which any file system supports, but which share any
+	// change made to one of the files too. Consecutive releases have most
+	// of their files in common. "" and "off" copy nothing.
+	Dedupe string
+
+	// Retries, if positive, is how many times a request that fails for a
+	// reason that may pass is retried: a network error, a timeout, a
+	// connection reset while downloading, or a 5xx or 429 response. The
+	// first retry waits RetryBackoff, or 1 second if it is zero, and each
+	// later one twice as long as the one before, up to 30 seconds or as
+	// long as the server asks with Retry-After. Each wait is shortened by
+	// up to the fraction RetryJitter, from 0 to 1, at random. Commands
+	// retry 3 times, with a jitter of 0.5, by default.
+	Retries      int
+	RetryBackoff time.Duration
+	RetryJitter  float64
+
+	// ConnectTimeout bounds establishing a connection, including the TLS
+	// handshake, and ResponseTimeout bounds waiting for the server to
+	// start responding. Zero means 30 seconds.
+	ConnectTimeout  time.Duration
+	ResponseTimeout time.Duration
+
+	// ReadTimeout bounds how long reading a response may wait for more
+	// of it, so that a connection that dies without closing fails, and is
+	// retried, rather than hanging. Zero means 1 minute. Time spent
+	// waiting for the MaxRate limit doesn't count.
+	ReadTimeout time.Duration
+
+	// IP, if "4" or "6", makes connections over IPv4 or IPv6 only, for
+	// networks where the other is broken in ways that only show as
+	// timeouts. "" and "auto" use either, as the system resolves names.
+	IP string
+
+	// RequestTimeout, if positive, bounds each request as a whole,
+	// reading its response included. There is none by default, since
+	// archives are large and links can be slow.
+	RequestTimeout time.Duration
+
+	// CAFile names a PEM file of certificate authorities to trust in
+	// addition to the system roots, for networks that intercept TLS.
+	// CADir names a directory of such files, such as one kept by
+	// c_rehash or shared with other tools; files in it without
+	// certificates are skipped.
+	CAFile string
+	CADir  string
+
+	// Proxy is the URL of the proxy to make every request through,
+	// instead of those named by HTTPS_PROXY, HTTP_PROXY and NO_PROXY: an
+	// http, https or socks5 URL, such as socks5://localhost:1080, with
+	// user:password@ before the host for a proxy that requires them. Names
+	// are resolved by a socks5 proxy. gotip's git commands use it too.
+	Proxy string
+
+	// Token, if set, is sent as a bearer token with the requests over
+	// https to the hosts of BaseURL, Mirrors and CatalogURL, for a
+	// private mirror that requires it, but never to the public sites.
+	// Other https requests carry the login and password that the .netrc
+	// file Netrc, if set, has for their host, if any; the commands read
+	// the file NETRC names, or ~/.netrc. Credentials are dropped when a
+	// request is redirected to another host.
+	Token string
+	Netrc string
+
+	// UserAgent, if set, replaces the User-Agent every request is sent
+	// with, and Header adds to every request the headers it holds, for
+	// egress proxies that filter on them. gotip's git commands send them
+	// too, with git 2.31 or later.
+	UserAgent string
+	Header    http.Header
+
+	// Metrics, if non-nil, is called at each step of an install to report
+	// telemetry.
+	Metrics *Metrics
+
+	// GOARCH, if non-empty, is the architecture of the releases to
+	// install. By default it is that of the machine, which is the
+	// architecture of the running program unless the program is being
+	// emulated, as under Rosetta.
+	GOARCH string
+
+	// GOOS, if non-empty, is the operating system of the releases to
+	// install. By default it is that of the machine.
+	GOOS string
+
+	// Cross marks the releases installed as being for another machine,
+	// as GOOS and GOARCH say: they are neither checked to be allowed to
+	// run here nor run once unpacked.
+	Cross bool
+
+	// Offline forbids all network access. Installs then succeed only from
+	// archives already in CacheDir, verified against the checksums saved
+	// with them, and anything that would need the network fails at once
+	// with an *OfflineError instead. The release listing is served from
+	// its cache, however old.
+	Offline bool
+
+	// Quiet suppresses the download progress lines, which are only noise
+	// in logs that nobody watches live.
+	Quiet bool
+
+	// ProgressJSON, if non-nil, receives the progress of each install as
+	// JSON objects, one per line, with its phase, the bytes done and to
+	// do, the percentage done and the download speed, for programs that
+	// show progress their own way. It replaces the progress lines.
+	// Commands write it to standard error with -progress=json.
+	ProgressJSON io.Writer
+}
+
+// A Downloader fetches and installs Go releases.
+// It is safe for concurrent use.
+type Downloader struct {
+	opts     DownloaderOptions
+	baseURL  string
+	client   Doer
+	catalog  *Catalog // describes what DefaultBaseURL publishes, or its mirror
+	progress *jsonProgress
+
+	hostArch func() (arch string, emulated bool) // if nil, the package's hostArch; set by tests
+}
+
+// NewDownloader validates opts and returns a Downloader using them.
+func NewDownloader(opts DownloaderOptions) (*Downloader, error) {
+	if opts.Client != nil && (opts.CAFile != "" || opts.CADir != "" || opts.Proxy != "" || opts.ConnectTimeout != 0 || opts.ResponseTimeout != 0 || opts.ReadTimeout != 0 || opts.RequestTimeout != 0 || opts.IP != "" || opts.UserAgent != "" || opts.Header != nil) {
+		return nil, errors.New("CAFile, CADir, Proxy, IP, UserAgent, Header and timeouts configure the default HTTP client and can't be used with a custom Client")
+	}
+	switch opts.Checksum {
+	case "":
+		opts.Checksum = ChecksumRequire
+	case ChecksumRequire, ChecksumIfPublished, ChecksumSkip:
+	default:
+		return nil, fmt.Errorf("unknown checksum policy %q", opts.Checksum)
+	}
+	if opts.MaxRate < 0 {
+		return nil, fmt.Errorf("invalid maximum download rate %d", opts.MaxRate)
+	}
+	if opts.Segments < 0 || opts.Segments > maxSegments {
+		return nil, fmt.Errorf("invalid number of download segments %d: must be from 0 to %d", opts.Segments, maxSegments)
+	}
+	if opts.ConnectTimeout < 0 || opts.ResponseTimeout < 0 || opts.ReadTimeout < 0 || opts.RequestTimeout < 0 {
+		return nil, errors.New("timeouts must not be negative")
+	}
+	if _, err := ipNetwork(opts.IP); err != nil {
+		return nil, err
+	}
+	if opts.Retries < 0 || opts.RetryBackoff < 0 {
+		return nil, errors.New("retries and their backoff must not be negative")
+	}
+	if opts.RetryJitter < 0 || opts.RetryJitter > 1 {
+		return nil, fmt.Errorf("invalid retry jitter %v: must be from 0 to 1", opts.RetryJitter)
+	}
+	if opts.RetryBackoff == 0 {
+		opts.RetryBackoff = defaultRetryBackoff
+	}
+	baseURL, err := checkBaseURL(opts.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCatalogURL(opts.CatalogURL); err != nil {
+		return nil, err
+	}
+	mirrors, err := checkMirrors(opts.Mirrors)
+	if err != nil {
+		return nil, err
+	}
+	if opts.ModuleProxy != "" {
+		if _, err := moduleProxy(opts.ModuleProxy); err != nil {
+			return nil, fmt.Errorf("invalid module proxy %q: must be an http or https URL, or a GOPROXY list with one", opts.ModuleProxy)
+		}
+	}
+	if _, err := parseSumDB(opts.SumDB); err != nil {
+		return nil, err
+	}
+	if err := checkDedupe(opts.Dedupe); err != nil {
+		return nil, err
+	}
+	d := &Downloader{opts: opts, baseURL: baseURL, client: opts.Client, progress: newJSONProgress(opts.ProgressJSON)}
+	if d.client == nil {
+		c, err := newHTTPClient(&opts)
+		if err != nil {
+			return nil, err
+		}
+		d.client = c
+	}
+	if isObjectStoreURL(baseURL) || anyObjectStoreURL(mirrors) {
+		d.client = newObjectStoreDoer(d.client)
+	}
+	if d.client, err = newAuthDoer(d.client, &opts); err != nil {
+		return nil, err
+	}
+	if opts.Retries > 0 {
+		d.client = &retryDoer{d.client, d.retryPolicy(), d.opts.Metrics}
+	}
+	if opts.Fallback {
+		d.client = &fallbackDoer{d: d.client, rewrites: chinaFallback}
+	}
+	if len(mirrors) > 0 {
+		d.client = newMirrorDoer(d.client, append([]string{baseURL}, mirrors...))
+	}
+	if opts.Offline {
+		d.client = offlineDoer{}
+	}
+	d.catalog = d.Catalog()
+	return d, nil
+}
+
+// newDownloaderFrom is NewDownloader for optional options.
+func newDownloaderFrom(opts *DownloaderOptions) (*Downloader, error) {
+	if opts == nil {
+		return NewDownloader(DownloaderOptions{})
+	}
+	return NewDownloader(*opts)
+}
+
+// checkBaseURL validates a download base URL, which may be an s3:// or
+// gs:// bucket URL, and returns it with a trailing slash.
+func checkBaseURL(s string) (string, error) {
+	if s == "" {
+		return DefaultBaseURL, nil
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid base URL: %v", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "s3", "gs":
+	default:
+		u.Host = ""
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("invalid base URL %q: must be an absolute http, https, s3 or gs URL", s)
+	}
+	if !strings.HasSuffix(s, "/") {
+		s += "/"
+	}
+	return s, nil
+}
+
+// checkCatalogURL validates a release listing URL, which may be empty.
+func checkCatalogURL(s string) error {
+	if s == "" {
+		return nil
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return fmt.Errorf("invalid catalog URL: %v", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("invalid catalog URL %q: must be an absolute http or https URL", s)
+	}
+	return nil
+}
+
+// Install downloads and unpacks release v into its GOROOT. It does nothing
+// if v is already installed.
+func (d *Downloader) Install(ctx context.Context, v Version) error {
+	root, err := v.GorootPath()
+	if err != nil {
+		return err
+	}
+	return d.install(ctx, root, v.String())
+}
+
+// Reinstall removes any existing installation of release v, complete or
+// not, and installs it afresh. On Windows, a toolchain whose programs are
+// still running is moved aside rather than deleted, or, if even that is
+// impossible, the error names the programs to stop.
+func (d *Downloader) Reinstall(ctx context.Context, v Version) error {
+	root, err := v.GorootPath()
+	if err != nil {
+		return err
+	}
+	if err := removeInstall(root); err != nil {
+		return err
+	}
+	return d.install(ctx, root, v.String())
+}
+
+// arch returns the architecture of the releases d installs, and whether
+// that was chosen because this program is running emulated.
+func (d *Downloader) arch() (arch string, emulated bool) {
+	if d.opts.GOARCH != "" {
+		return d.opts.GOARCH, false
+	}
+	if d.hostArch != nil {
+		return d.hostArch()
+	}
+	return hostArch()
+}
+
+// goos returns the operating system of the releases d installs.
+func (d *Downloader) goos() string {
+	if d.opts.GOOS != "" {
+		return d.opts.GOOS
+	}
+	return getOS()
+}
+
+func (d *Downloader) retryPolicy() retryPolicy {
+	return retryPolicy{retries: d.opts.Retries, backoff: d.opts.RetryBackoff, jitter: d.opts.RetryJitter}
+}
+
+func (d *Downloader) emitter() *emitter {
+	return newProgressEmitter(d.opts.Events, d.progress)
+}
+
+// quiet reports whether d prints no progress lines of its own.
+func (d *Downloader) quiet() bool {
+	return d.opts.Quiet || d.opts.ProgressJSON != nil
+}
+
+// Environment variables read by FromEnvironment.
+const (
+	envBaseURL         = "GODL_BASE_URL"
+	envCatalogURL      = "GODL_CATALOG_URL"
+	envMirrors         = "GODL_MIRRORS"
+	envModuleProxy     = "GODL_MODULE_PROXY"
+	envSumDB           = "GODL_SUMDB"
+	envChecksum        = "GODL_CHECKSUM"
+	envCacheDir        = "GODL_CACHE_DIR"
+	envCacheMaxSize    = "GODL_CACHE_MAX_SIZE"
+	envResume          = "GODL_RESUME"
+	envFallback        = "GODL_FALLBACK"
+	envMaxRate         = "GODL_MAX_RATE"
+	envSegments        = "GODL_SEGMENTS"
+	envStream          = "GODL_STREAM"
+	envSlim            = "GODL_SLIM"
+	envDedupe          = "GODL_DEDUPE"
+	envRetries         = "GODL_RETRIES"
+	envRetryBackoff    = "GODL_RETRY_BACKOFF"
+	envRetryJitter     = "GODL_RETRY_JITTER"
+	envConnectTimeout  = "GODL_CONNECT_TIMEOUT"
+	envResponseTimeout = "GODL_RESPONSE_TIMEOUT"
+	envReadTimeout     = "GODL_READ_TIMEOUT"
+	envRequestTimeout  = "GODL_REQUEST_TIMEOUT"
+	envIP              = "GODL_IP"
+	envCAFile          = "GODL_CA_FILE"
+	envCADir           = "GODL_CA_DIR"
+	envProxy           = "GODL_PROXY"
+	envToken           = "GODL_TOKEN"
+	envNetrc           = "GODL_NETRC"
+	envUserAgent       = "GODL_USER_AGENT"
+	envHeaders         = "GODL_HEADERS"
+	envGOARCH          = "GODL_GOARCH"
+	envOffline         = "GODL_OFFLINE"
+	envQuiet           = "GODL_QUIET"
+	envProgress        = "GODL_PROGRESS"
+)
+
+// FromEnvironment returns downloader options set from these environment
+// variables, leaving the rest at their defaults:
+//
+//	GODL_BASE_URL          BaseURL
+//	GODL_CATALOG_URL       CatalogURL
+//	GODL_MIRRORS           Mirrors: URLs separated by commas or spaces
+//	GODL_MODULE_PROXY      ModuleProxy
+//	GODL_SUMDB             SumDB
+//	GODL_CHECKSUM          Checksum: require, if-published or skip
+//	GODL_CACHE_DIR         CacheDir
+//	GODL_CACHE_MAX_SIZE    CacheMaxSize: bytes, with an optional unit
+//	                       such as 500M or 10GiB
+//	GODL_RESUME            Resume: a boolean such as 1 or false
+//	GODL_FALLBACK          Fallback: a boolean such as 1 or false
+//	GODL_MAX_RATE          MaxRate: bytes per second, with an optional
+//	                       unit such as 500K, 2MiB or 1G
+//	GODL_SEGMENTS          Segments: a number such as 4
+//	GODL_STREAM            Stream: a boolean such as 1 or false
+//	GODL_SLIM              Slim: a boolean such as 1 or false
+//	GODL_DEDUPE            Dedupe: reflink, hardlink or off
+//	GODL_RETRIES           Retries: a number such as 5, or 0 not to retry
+//	GODL_RETRY_BACKOFF     RetryBackoff: a duration such as 2s
+//	GODL_RETRY_JITTER      RetryJitter: a fraction such as 0.5
+//	GODL_CONNECT_TIMEOUT   ConnectTimeout: a duration such as 10s
+//	GODL_RESPONSE_TIMEOUT  ResponseTimeout: a duration such as 1m
+//	GODL_READ_TIMEOUT      ReadTimeout: a duration such as 2m
+//	GODL_REQUEST_TIMEOUT   RequestTimeout: a duration such as 30m
+//	GODL_IP                IP: 4, 6 or auto
+//	GODL_CA_FILE           CAFile
+//	GODL_CA_DIR            CADir
+//	GODL_PROXY             Proxy
+//	GODL_TOKEN             Token
+//	GODL_NETRC             Netrc
+//	GODL_USER_AGENT        UserAgent
+//	GODL_HEADERS           Header: headers such as "X-Team: infra",
+//	                       separated by semicolons
+//	GODL_GOARCH            GOARCH
+//	GODL_OFFLINE           Offline: a boolean such as 1 or false
+//	GODL_QUIET             Quiet: a boolean such as 1 or false
+//	GODL_PROGRESS          ProgressJSON: json for standard error, or text
+//
+// It reports an error for values that can't be parsed. The options are
+// otherwise validated by NewDownloader.
+func FromEnvironment() (DownloaderOptions, error) {
+	var opts DownloaderOptions
+	var loc Locator // GODL_SDK_DIR is not a downloader option
+	for _, s := range settings {
+		if v := os.Getenv(s.env); v != "" {
+			if err := s.apply(&opts, &loc, v); err != nil {
+				return opts, fmt.Errorf("%s: %v", s.env, err)
+			}
+		}
+	}
+	return opts, nil
+}
@@ -0,0 +1,189 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A UsageEntry reports the disk space used by one thing the tool manages,
+// or that the toolchains it installs create.
+type UsageEntry struct {
+	Name string    `json:"name"` // such as "go1.22.7" or "archive cache"
+	Kind UsageKind `json:"kind"`
+	Path string    `json:"path,omitempty"`
+	Size int64     `json:"size"`
+
+	// Users lists the toolchains sharing a build cache.
+	Users []string `json:"users,omitempty"`
+
+	// Prunable marks a release that dl prune removes by default, as a
+	// newer patch release of its minor version is installed.
+	Prunable bool `json:"prunable,omitempty"`
+
+	// Err says why the entry couldn't be measured, or located.
+	Err string `json:"error,omitempty"`
+}
+
+// A UsageKind classifies a UsageEntry.
+type UsageKind string
+
+const (
+	UsageToolchain    UsageKind = "toolchain"     // an installed release, or the gotip tree
+	UsageAside        UsageKind = "aside"         // an old toolchain moved aside by a reinstall
+	UsageArchiveCache UsageKind = "archive-cache" // DownloaderOptions.CacheDir
+	UsageBuildCache   UsageKind = "build-cache"   // a GOCACHE used by installed toolchains
+)
+
+// maxParallelWalks bounds how many directory trees DiskUsage walks at once.
+// A few concurrent walks keep a spinning disk's queue busy without making
+// it seek back and forth between them.
+const maxParallelWalks = 4
+
+// goEnvTimeout bounds asking a toolchain for its GOCACHE.
+const goEnvTimeout = 10 * time.Second
+
+// DiskUsage reports the space used by each toolchain under l's SDK root,
+// by the archive cache in cacheDir, if non-empty, and by the build caches
+// the toolchains use, largest first. Build caches are usually shared by
+// all toolchains, so their size can't be attributed to any one of them;
+// each distinct cache is reported once, with the toolchains using it.
+// Toolchains that can't be run to locate their cache are reported with an
+// error entry, and releases dl prune would remove are marked Prunable.
+// Sizes are of file contents, as walked, without hashing.
+func (l *Locator) DiskUsage(ctx context.Context, cacheDir string) ([]UsageEntry, error) {
+	root, err := l.SDKRoot()
+	if err != nil {
+		return nil, err
+	}
+	fis, err := ioutil.ReadDir(root)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	sortEntries(fis)
+
+	var entries []UsageEntry
+	var toolchains []string
+	for _, fi := range fis {
+		name := fi.Name()
+		if !fi.IsDir() || toolchainEntry(name) == "" {
+			continue
+		}
+		kind := UsageToolchain
+		if strings.Contains(name, asideSuffix) {
+			kind = UsageAside
+		} else {
+			toolchains = append(toolchains, name)
+		}
+		entries = append(entries, UsageEntry{Name: name, Kind: kind, Path: filepath.Join(root, name)})
+	}
+	if cacheDir != "" && !inToolchain(cacheDir, root) {
+		entries = append(entries, UsageEntry{Name: "archive cache", Kind: UsageArchiveCache, Path: cacheDir})
+	}
+	var installed []Version
+	for _, name := range toolchains {
+		if v, err := ParseVersion(name); err == nil && isFile(filepath.Join(root, name, unpackedOkay)) {
+			installed = append(installed, v)
+		}
+	}
+	for _, v := range pruneCandidates(installed, defaultPrunePolicy) {
+		for i := range entries {
+			if entries[i].Kind == UsageToolchain && entries[i].Name == v.String() {
+				entries[i].Prunable = true
+			}
+		}
+	}
+	entries = append(entries, buildCaches(ctx, root, toolchains)...)
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxParallelWalks)
+	for i := range entries {
+		if entries[i].Path == "" {
+			continue
+		}
+		wg.Add(1)
+		go func(e *UsageEntry) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			e.Size = diskUsage(e.Path)
+		}(&entries[i])
+	}
+	wg.Wait()
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Size > entries[j].Size })
+	return entries, nil
+}
+
+// inToolchain reports whether dir is the SDK root or inside one of the
+// toolchains under it, whose size is reported already.
+func inToolchain(dir, root string) bool {
+	rel, err := filepath.Rel(root, dir)
+	if err != nil || !within(dir, root) {
+		return false
+	}
+	return rel == "." || toolchainEntry(strings.SplitN(filepath.ToSlash(rel), "/", 2)[0]) != ""
+}
+
+// buildCaches asks each of the toolchains under root for its GOCACHE,
+// concurrently, and returns an entry for each distinct cache, plus one for
+// each toolchain that couldn't say. Toolchains with no go command yet,
+// such as an unbuilt gotip tree, are skipped.
+func buildCaches(ctx context.Context, root string, toolchains []string) []UsageEntry {
+	dirs := make([]string, len(toolchains))
+	errs := make([]error, len(toolchains))
+	var wg sync.WaitGroup
+	for i, name := range toolchains {
+		wg.Add(1)
+		go func(i int, goroot string) {
+			defer wg.Done()
+			if _, err := os.Stat(filepath.Join(goroot, "bin", "go"+exe())); os.IsNotExist(err) {
+				return // not built or unpacked yet
+			}
+			dirs[i], errs[i] = goEnv(ctx, goroot, "GOCACHE")
+		}(i, filepath.Join(root, name))
+	}
+	wg.Wait()
+
+	var entries []UsageEntry
+	index := map[string]int{}
+	for i, name := range toolchains {
+		dir := dirs[i]
+		switch {
+		case errs[i] != nil:
+			entries = append(entries, UsageEntry{Name: "build cache of " + name, Kind: UsageBuildCache, Users: []string{name}, Err: errs[i].Error()})
+		case dir == "" || dir == "off":
+			// The toolchain has no build cache.
+		default:
+			j, ok := index[dir]
+			if !ok {
+				j = len(entries)
+				index[dir] = j
+				entries = append(entries, UsageEntry{Name: "build cache", Kind: UsageBuildCache, Path: dir})
+			}
+			entries[j].Users = append(entries[j].Users, name)
+		}
+	}
+	return entries
+}
+
+// goEnv returns the value of the go environment variable key, as reported
+// by the toolchain in goroot.
+func goEnv(ctx context.Context, goroot, key string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, goEnvTimeout)
+	defer cancel()
+	out, err := goCommand(ctx, goroot, "env", key).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDiskUsage(t *testing.T) {
+	root := t.TempDir()
+	cache := t.TempDir()
+	makeTree(t, root, map[string]string{
+		"go1.22.7/bin/go" + exe():  "not a program",
+		"go1.22.7/src/fmt.go":      "0123456789",
+		"go1.22.7/" + unpackedOkay: "",
+		"go1.22.3/" + unpackedOkay: "",
+		"go1.22.3/VERSION":         "go1.22.3\ntime",
+		"go1.21.0.old-abc/VERSION": "go1.21.0",
+		"gotip/src/make.bash":      "#!/bin/sh",
+		"Documents/notes.txt":      "not ours",
+	})
+	makeTree(t, cache, map[string]string{
+		"go1.22.7.linux-amd64.tar.gz": "0123456789012345678901234567890123456789",
+	})
+
+	l := &Locator{Root: root}
+	entries, err := l.DiskUsage(context.Background(), cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type summary struct {
+		Name     string
+		Kind     UsageKind
+		Size     int64
+		Failed   bool
+		Prunable bool
+	}
+	var got []summary
+	for _, e := range entries {
+		got = append(got, summary{e.Name, e.Kind, e.Size, e.Err != "", e.Prunable})
+	}
+	want := []summary{
+		{"archive cache", UsageArchiveCache, 40, false, false},
+		{"go1.22.7", UsageToolchain, int64(len("not a program") + 10), false, false},
+		{"go1.22.3", UsageToolchain, 13, false, true},
+		{"gotip", UsageToolchain, 9, false, false},
+		{"go1.21.0.old-abc", UsageAside, 8, false, false},
+		// The go command of go1.22.7 can't run, so its build cache is
+		// unknown. gotip isn't built, so it has none.
+		{"build cache of go1.22.7", UsageBuildCache, 0, true, false},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiskUsage:\n%+v\nwant:\n%+v", got, want)
+	}
+	if p := entries[0].Path; p != cache {
+		t.Errorf("archive cache path = %s; want %s", p, cache)
+	}
+	if p := entries[1].Path; p != filepath.Join(root, "go1.22.7") {
+		t.Errorf("go1.22.7 path = %s", p)
+	}
+}
+
+func TestInToolchain(t *testing.T) {
+	root := filepath.FromSlash("/home/gopher/sdk")
+	for dir, want := range map[string]bool{
+		"/home/gopher/sdk":                   true,
+		"/home/gopher/sdk/go1.22.7/archives": true,
+		"/home/gopher/sdk/gotip/cache":       true,
+		"/home/gopher/sdk/archives":          false,
+		"/home/gopher/.cache/godl":           false,
+	} {
+		if got := inToolchain(filepath.FromSlash(dir), root); got != want {
+			t.Errorf("inToolchain(%s, %s) = %v; want %v", dir, root, got, want)
+		}
+	}
+}
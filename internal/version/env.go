@@ -0,0 +1,198 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+)
+
+// An Environment describes where dl keeps things and what it has
+// installed, as dl env reports it, so that other tools need not parse
+// the output of the other commands. Its JSON form is stable: fields may
+// be added, but not renamed or removed.
+type Environment struct {
+	Tool       string `json:"tool"`                  // version of the dl command
+	ConfigFile string `json:"config_file,omitempty"` // read, if there was one
+	SDKRoot    string `json:"sdk_root"`
+	CacheDir   string `json:"cache_dir,omitempty"` // the archive cache, if there is one
+	GOBIN      string `json:"gobin,omitempty"`     // where dl default puts the go shim
+
+	Default    *EnvDefault    `json:"default,omitempty"`
+	Toolchains []EnvToolchain `json:"toolchains"`
+	Gotip      *EnvTip        `json:"gotip,omitempty"`
+}
+
+// EnvDefault is the default release set by dl default, for dl env.
+type EnvDefault struct {
+	Name string `json:"name"` // as given to dl default, such as go1.22 or latest
+
+	// Release is the installed release Name stands for, as dl which
+	// finds it, or "" if none is.
+	Release string `json:"release,omitempty"`
+	// Shim is the go shim, if it is installed.
+	Shim string `json:"shim,omitempty"`
+}
+
+// An EnvToolchain is an installed release, for dl env: its local state,
+// as dl info reports it.
+type EnvToolchain struct {
+	Version string `json:"version"`
+	LocalInfo
+}
+
+// EnvTip is the installed gotip tree, for dl env.
+type EnvTip struct {
+	GOROOT string `json:"goroot"`
+	Commit string `json:"commit,omitempty"` // checked out, if git can tell
+
+	// Branch or CL is what the last install fetched, according to the
+	// journal: a branch, master unless another was named, or a CL number.
+	// Both are empty for a tree fetched at a commit hash, or installed
+	// before the journal was kept.
+	Branch      string     `json:"branch,omitempty"`
+	CL          string     `json:"cl,omitempty"`
+	InstalledAt *time.Time `json:"installed_at,omitempty"`
+}
+
+// environment returns the report of dl env for cfg, looking installs up
+// in journal. It only looks at the file system.
+func environment(cfg *Config, journal []JournalEntry) (*Environment, error) {
+	l := cfg.Locator()
+	root, err := l.SDKRoot()
+	if err != nil {
+		return nil, err
+	}
+	opts, err := cfg.Options()
+	if err != nil {
+		return nil, err
+	}
+	env := &Environment{
+		Tool:       toolBuildInfo().Version,
+		ConfigFile: cfg.File,
+		SDKRoot:    root,
+		CacheDir:   opts.CacheDir,
+		Toolchains: []EnvToolchain{},
+	}
+	if dir, err := goBinDir(); err == nil {
+		env.GOBIN = dir
+	}
+
+	if name := cfg.DefaultRelease(); name != "" {
+		def := &EnvDefault{Name: name}
+		release := resolveInstalled(l, cfg.resolveAlias(name))
+		if _, err := whichGoroot(l, release); err == nil {
+			def.Release = release
+		}
+		if shim := filepath.Join(env.GOBIN, "go"+exe()); env.GOBIN != "" && isShim(shim) {
+			def.Shim = shim
+		}
+		env.Default = def
+	}
+
+	vs, err := installedReleases(l)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range vs {
+		env.Toolchains = append(env.Toolchains, EnvToolchain{v.String(), localInfo(l, v.String(), journal, opts.CacheDir)})
+	}
+
+	if tip, err := whichGoroot(l, "gotip"); err == nil {
+		t := &EnvTip{GOROOT: tip, Commit: gitHead(tip)}
+		for i := len(journal) - 1; i >= 0; i-- {
+			e := journal[i]
+			if e.Toolchain != "gotip" || e.Error != "" {
+				continue
+			}
+			switch {
+			case e.Target == "":
+				t.Branch = "master"
+			case isCLNumber(e.Target):
+				t.CL = e.Target
+			case !isCommitHash(e.Target):
+				t.Branch = e.Target
+			}
+			at := e.Time
+			t.InstalledAt = &at
+			break
+		}
+		env.Gotip = t
+	}
+	return env, nil
+}
+
+// formatPaths spells the paths in env in style s.
+func (env *Environment) formatPaths(s PathStyle) {
+	for _, p := range []*string{&env.ConfigFile, &env.SDKRoot, &env.CacheDir, &env.GOBIN} {
+		*p = s.Format(*p)
+	}
+	if env.Default != nil {
+		env.Default.Shim = s.Format(env.Default.Shim)
+	}
+	for i := range env.Toolchains {
+		env.Toolchains[i].GOROOT = s.Format(env.Toolchains[i].GOROOT)
+	}
+	if env.Gotip != nil {
+		env.Gotip.GOROOT = s.Format(env.Gotip.GOROOT)
+	}
+}
+
+// writeEnvironment writes env to w in the form dl env prints it.
+func writeEnvironment(w io.Writer, env *Environment) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "dl:\t%s\n", env.Tool)
+	if env.ConfigFile != "" {
+		fmt.Fprintf(tw, "Config file:\t%s\n", env.ConfigFile)
+	}
+	fmt.Fprintf(tw, "SDK directory:\t%s\n", env.SDKRoot)
+	if env.CacheDir != "" {
+		fmt.Fprintf(tw, "Archive cache:\t%s\n", env.CacheDir)
+	}
+	if def := env.Default; def != nil {
+		s := def.Name
+		switch {
+		case def.Release == "":
+			s += " (not installed)"
+		case def.Release != def.Name:
+			s += " (" + def.Release + ")"
+		}
+		if def.Shim == "" {
+			s += ", with no go shim in " + env.GOBIN
+		} else {
+			s += ", run by " + def.Shim
+		}
+		fmt.Fprintf(tw, "Default:\t%s\n", s)
+	}
+	if t := env.Gotip; t != nil {
+		s := t.GOROOT
+		switch {
+		case t.Branch != "":
+			s += ", branch " + t.Branch
+		case t.CL != "":
+			s += ", CL " + t.CL
+		}
+		if t.Commit != "" {
+			s += ", at " + t.Commit
+		}
+		fmt.Fprintf(tw, "gotip:\t%s\n", s)
+	}
+	tw.Flush()
+
+	if len(env.Toolchains) == 0 {
+		fmt.Fprintln(w, "\nNo releases are installed.")
+		return
+	}
+	fmt.Fprintln(w)
+	tw = tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERSION\tVERIFIED\tGOROOT")
+	for _, t := range env.Toolchains {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", t.Version, t.Verified, t.GOROOT)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEnvironment(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	gobin := t.TempDir()
+	t.Setenv("GOBIN", gobin)
+	sdk := t.TempDir()
+	makeTree(t, sdk, map[string]string{
+		"go1.21.13/" + unpackedOkay: "",
+		"go1.22.7/" + unpackedOkay:  "",
+		"gotip/bin/go" + exe():      "",
+	})
+	cfg, err := LoadConfig(writeConfig(t, "sdk_dir = '"+sdk+"'\ndefault = go1.22\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	journal := []JournalEntry{
+		{Time: at, Toolchain: "go1.22.7", SHA256: "4a7b", Platform: "linux/amd64"},
+		{Time: at, Toolchain: "gotip", Target: "dev.boringcrypto"},
+		{Time: at.Add(time.Hour), Toolchain: "gotip", Error: "failed to build go"},
+	}
+	env, err := environment(cfg, journal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if env.SDKRoot != sdk || env.GOBIN != gobin || env.ConfigFile != cfg.File {
+		t.Errorf("environment() = %+v; want SDK directory %s, GOBIN %s and config file %s", env, sdk, gobin, cfg.File)
+	}
+	if d := env.Default; d == nil || d.Name != "go1.22" || d.Release != "go1.22.7" || d.Shim != "" {
+		t.Errorf("Default = %+v; want go1.22, installed as go1.22.7, with no shim", d)
+	}
+	var got []string
+	for _, tc := range env.Toolchains {
+		got = append(got, tc.Version+" "+tc.Verified)
+	}
+	if want := "go1.21.13 unknown,go1.22.7 verified"; strings.Join(got, ",") != want {
+		t.Errorf("Toolchains = %v; want %s", got, want)
+	}
+	if tip := env.Gotip; tip == nil || tip.GOROOT != filepath.Join(sdk, "gotip") || tip.Branch != "dev.boringcrypto" || tip.InstalledAt == nil || !tip.InstalledAt.Equal(at) {
+		t.Errorf("Gotip = %+v; want the tree in the SDK directory, on dev.boringcrypto", tip)
+	}
+
+	// The JSON form flattens each toolchain's local state.
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(env); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"sdk_root":`, `"version":"go1.22.7","installed":true,`, `"sha256":"4a7b"`, `"branch":"dev.boringcrypto"`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("JSON form lacks %s:\n%s", want, buf.String())
+		}
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"bytes"
+	"io"
+	"time"
+)
+
+// An Event reports the progress of an install operation. It is one of
+// ResolutionDone, DownloadProgress, VerificationResult, UnpackProgress,
+// BuildOutputLine, Completed or Failed.
+//
+// Every install ends with exactly one Completed or Failed event, unless
+// the consumer falls so far behind that it is dropped (see
+// DownloaderOptions.Events).
+type Event interface {
+	isEvent()
+}
+
+// A Phase names a stage of an install.
+type Phase string
+
+const (
+	PhaseResolve  Phase = "resolve"
+	PhaseDownload Phase = "download"
+	PhaseVerify   Phase = "verify"
+	PhaseUnpack   Phase = "unpack"
+	PhaseBuild    Phase = "build"
+)
+
+// ResolutionDone is sent once the archive to download has been located.
+type ResolutionDone struct {
+	Version string
+	URL     string
+	Size    int64 // archive size in bytes, or -1 if unknown
+}
+
+// DownloadProgress is sent periodically while an archive downloads.
+// Under backpressure intermediate values are dropped, but the final
+// value, with Bytes == Total, is always attempted.
+type DownloadProgress struct {
+	URL   string
+	Bytes int64
+	Total int64 // -1 if unknown
+}
+
+// VerificationResult reports the outcome of checking an archive's SHA-256.
+type VerificationResult struct {
+	File   string
+	SHA256 string // expected digest
+	Err    error  // nil if the archive matched
+}
+
+// UnpackProgress is sent as files are extracted from the archive.
+// Like DownloadProgress, it is coalesced under backpressure.
+type UnpackProgress struct {
+	Files int   // files extracted so far
+	Bytes int64 // bytes written so far
+}
+
+// BuildOutputLine is a line of output from building the gotip toolchain.
+type BuildOutputLine struct {
+	Line string
+}
+
+// BuildProgress is sent when building the gotip toolchain starts a phase
+// that the build announces, such as "Go toolchain2". A build whose output
+// isn't recognized sends none.
+type BuildProgress struct {
+	Phase   string
+	Elapsed time.Duration // since the build started
+}
+
+// Completed is sent when a toolchain is ready to use, including when it
+// was already installed.
+type Completed struct {
+	GOROOT   string
+	Duration time.Duration
+}
+
+// Failed is sent when an install fails.
+type Failed struct {
+	Err   error
+	Phase Phase
+}
+
+func (ResolutionDone) isEvent()     {}
+func (DownloadProgress) isEvent()   {}
+func (VerificationResult) isEvent() {}
+func (UnpackProgress) isEvent()     {}
+func (BuildOutputLine) isEvent()    {}
+func (BuildProgress) isEvent()      {}
+func (Completed) isEvent()          {}
+func (Failed) isEvent()             {}
+
+// eventTimeout bounds how long the installer waits on a slow consumer
+// before dropping an event that cannot be coalesced.
+var eventTimeout = 5 * time.Second
+
+// An emitter delivers events to a consumer's channel without letting the
+// consumer stall the install, and records each to json, if non-nil. A nil
+// *emitter discards everything.
+type emitter struct {
+	ch   chan<- Event
+	json *jsonProgress
+}
+
+// newEmitter returns an emitter for ch, which may be nil.
+func newEmitter(ch chan<- Event) *emitter {
+	return newProgressEmitter(ch, nil)
+}
+
+// newProgressEmitter returns an emitter for ch that also records every
+// event to p. Either may be nil.
+func newProgressEmitter(ch chan<- Event, p *jsonProgress) *emitter {
+	if ch == nil && p == nil {
+		return nil
+	}
+	return &emitter{ch, p}
+}
+
+// emit sends e, waiting at most eventTimeout for the consumer.
+func (em *emitter) emit(e Event) {
+	if em == nil {
+		return
+	}
+	em.json.record(e)
+	if em.ch == nil {
+		return
+	}
+	select {
+	case em.ch <- e:
+		return
+	default:
+	}
+	t := time.NewTimer(eventTimeout)
+	defer t.Stop()
+	select {
+	case em.ch <- e:
+	case <-t.C:
+	}
+}
+
+// progress sends e only if the consumer is ready for it. Progress events
+// supersede each other, so a dropped one is replaced by the next.
+func (em *emitter) progress(e Event) {
+	if em == nil {
+		return
+	}
+	em.json.record(e)
+	if em.ch == nil {
+		return
+	}
+	select {
+	case em.ch <- e:
+	default:
+	}
+}
+
+// lineWriter is an io.Writer that passes writes through to w and
+// reports each complete line as a BuildOutputLine event, and to bp, if
+// non-nil.
+type lineWriter struct {
+	w   io.Writer
+	em  *emitter
+	bp  *buildProgress
+	buf []byte
+}
+
+func (lw *lineWriter) Write(p []byte) (int, error) {
+	lw.buf = append(lw.buf, p...)
+	for {
+		i := bytes.IndexByte(lw.buf, '\n')
+		if i < 0 {
+			break
+		}
+		line := string(bytes.TrimRight(lw.buf[:i], "\r"))
+		lw.em.emit(BuildOutputLine{Line: line})
+		if lw.bp != nil {
+			lw.bp.line(line)
+		}
+		lw.buf = lw.buf[i+1:]
+	}
+	if lw.bp != nil {
+		return lw.bp.write(lw.w, p)
+	}
+	return lw.w.Write(p)
+}
+
+// flush reports any final unterminated line.
+func (lw *lineWriter) flush() {
+	if len(lw.buf) > 0 {
+		lw.em.emit(BuildOutputLine{Line: string(lw.buf)})
+		lw.buf = nil
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEmitterNeverBlocks(t *testing.T) {
+	defer func(d time.Duration) { eventTimeout = d }(eventTimeout)
+	eventTimeout = 10 * time.Millisecond
+
+	em := newEmitter(make(chan Event)) // nobody is listening
+	start := time.Now()
+	for i := 0; i < 100; i++ {
+		em.progress(DownloadProgress{Bytes: int64(i)})
+	}
+	em.emit(Completed{})
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("emitting to a stalled consumer took %v", d)
+	}
+
+	var nilEm *emitter
+	nilEm.emit(Completed{})
+	nilEm.progress(UnpackProgress{})
+}
+
+func TestEmitterDelivers(t *testing.T) {
+	ch := make(chan Event)
+	em := newEmitter(ch)
+	done := make(chan Event)
+	go func() { done <- <-ch }()
+	em.emit(Completed{GOROOT: "x"})
+	if e := <-done; e != (Completed{GOROOT: "x"}) {
+		t.Errorf("received %#v; want Completed{GOROOT: x}", e)
+	}
+}
+
+func TestLineWriter(t *testing.T) {
+	ch := make(chan Event, 10)
+	var out bytes.Buffer
+	lw := &lineWriter{w: &out, em: newEmitter(ch)}
+	for _, s := range []string{"Building Go cmd/dist", " using go1.22\r\nBuilding", " toolchain1\n", "partial"} {
+		if _, err := lw.Write([]byte(s)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lw.flush()
+	close(ch)
+
+	var lines []string
+	for e := range ch {
+		lines = append(lines, e.(BuildOutputLine).Line)
+	}
+	want := []string{"Building Go cmd/dist using go1.22", "Building toolchain1", "partial"}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("lines = %q; want %q", lines, want)
+	}
+	if got := out.String(); got != "Building Go cmd/dist using go1.22\r\nBuilding toolchain1\npartial" {
+		t.Errorf("passed through %q", got)
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+)
+
+// Exit codes of the go1.N.M, gotip and dl commands, so that scripts can
+// tell failures apart: whether to give up, retry, or raise an alarm. They
+// are part of the commands' compatibility surface and will not change.
+// The run path, where a wrapper runs the go command, exits with whatever
+// code the go command does instead.
+const (
+	ExitOK           = 0
+	ExitFailure      = 1   // any failure not listed below
+	ExitUsage        = 2   // the command line is wrong, or lacks a flag to answer a question
+	ExitNotInstalled = 3   // the toolchain to run isn't installed
+	ExitNotFound     = 4   // no published release matches the name given
+	ExitNetwork      = 5   // a download or fetch failed, or needed the network in offline mode
+	ExitVerify       = 6   // an archive didn't have the expected checksum
+	ExitBuild        = 7   // building the gotip toolchain failed
+	ExitInterrupted  = 130 // interrupted, or the user declined to go on
+)
+
+// ExitCode returns the exit code for err, as returned by the installers.
+func ExitCode(err error) int {
+	var (
+		verr  *VersionError
+		nferr *NotFoundError
+		cerr  *ChecksumError
+		oerr  *OfflineError
+		serr  *statusError
+		nerr  net.Error
+		berr  *buildError
+		ferr  *fetchError
+		ierr  *nonInteractiveError
+		nierr *notInstalledError
+	)
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, context.Canceled), errors.Is(err, errInterrupted):
+		return ExitInterrupted
+	case errors.As(err, &ierr), errors.As(err, &verr):
+		return ExitUsage
+	case errors.As(err, &nierr):
+		return ExitNotInstalled
+	case errors.As(err, &nferr):
+		return ExitNotFound
+	case errors.As(err, &serr):
+		if isNotFound(serr) {
+			return ExitNotFound
+		}
+		return ExitNetwork
+	case errors.As(err, &cerr):
+		return ExitVerify
+	case errors.As(err, &oerr), errors.As(err, &nerr), errors.As(err, &ferr):
+		return ExitNetwork
+	case errors.As(err, &berr):
+		return ExitBuild
+	}
+	return ExitFailure
+}
+
+// errInterrupted reports that the user declined to go on.
+var errInterrupted = errors.New("interrupted")
+
+// A buildError reports that building the gotip toolchain failed.
+type buildError struct {
+	err error
+}
+
+func (e *buildError) Error() string { return fmt.Sprintf("failed to build go: %v", e.err) }
+func (e *buildError) Unwrap() error { return e.err }
+
+// A fetchError reports that fetching the gotip tree with git failed,
+// which is usually the network's fault.
+type fetchError struct {
+	what string
+	err  error
+}
+
+func (e *fetchError) Error() string { return fmt.Sprintf("failed to %s: %v", e.what, e.err) }
+func (e *fetchError) Unwrap() error { return e.err }
+
+// A notInstalledError reports that a toolchain isn't installed.
+type notInstalledError struct {
+	name string
+}
+
+func (e *notInstalledError) Error() string {
+	return fmt.Sprintf("%s is not installed; run '%s'", e.name, installCommand(e.name))
+}
+
+// fatal logs err, after prefix, and exits with the code ExitCode gives.
+func fatal(prefix string, err error) {
+	errorLog.Printf("%s: %v", prefix, err)
+	os.Exit(ExitCode(err))
+}
+
+// usagef logs a usage message and exits with ExitUsage.
+func usagef(format string, args ...interface{}) {
+	errorLog.Printf(format, args...)
+	os.Exit(ExitUsage)
+}
+
+// interruptContext returns a context that is canceled by an interrupt,
+// so that an install in progress stops cleanly and exits with
+// ExitInterrupted. A second interrupt, should cleaning up hang, kills
+// the process at once.
+func interruptContext() (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	return ctx, stop
+}
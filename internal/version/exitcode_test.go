@@ -0,0 +1,71 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"testing"
+)
+
+func TestExitCodeValues(t *testing.T) {
+	// The codes are part of the commands' compatibility surface.
+	for code, want := range map[int]int{
+		ExitOK:           0,
+		ExitFailure:      1,
+		ExitUsage:        2,
+		ExitNotInstalled: 3,
+		ExitNotFound:     4,
+		ExitNetwork:      5,
+		ExitVerify:       6,
+		ExitBuild:        7,
+		ExitInterrupted:  130,
+	} {
+		if code != want {
+			t.Errorf("exit code %d changed to %d", want, code)
+		}
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, ExitOK},
+		{errors.New("disk full"), ExitFailure},
+		{fmt.Errorf("go1.22.7: %w", context.Canceled), ExitInterrupted},
+		{errInterrupted, ExitInterrupted},
+		{&VersionError{Input: "go1.x"}, ExitUsage},
+		{&nonInteractiveError{Question: "Type yes", Flag: "-y"}, ExitUsage},
+		{&notInstalledError{"go1.22.7"}, ExitNotInstalled},
+		{fmt.Errorf("go1.99: %w", &NotFoundError{Name: "go1.99", Stable: true}), ExitNotFound},
+		{fmt.Errorf("error downloading x: %w", &statusError{Code: 404, Status: "404 Not Found"}), ExitNotFound},
+		{fmt.Errorf("reading x: %w", &statusError{Code: 503, Status: "503 Service Unavailable"}), ExitNetwork},
+		{fmt.Errorf("error verifying SHA256 of x: %w", &ChecksumError{Want: "a", Got: "b"}), ExitVerify},
+		{&OfflineError{URL: "https://dl.google.com/go/"}, ExitNetwork},
+		{fmt.Errorf("error downloading x: %w", &url.Error{Op: "Get", URL: "x", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}), ExitNetwork},
+		{&fetchError{"clone git repository", errors.New("exit status 128")}, ExitNetwork},
+		{&buildError{errors.New("exit status 2")}, ExitBuild},
+	}
+	for _, tt := range tests {
+		if got := ExitCode(tt.err); got != tt.want {
+			t.Errorf("ExitCode(%v) = %d; want %d", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestInstallInterruptedExitCode(t *testing.T) {
+	ts := newTestServer(t)
+	d := ts.downloader(t, DownloaderOptions{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := d.install(ctx, t.TempDir(), "go1.99"); ExitCode(err) != ExitInterrupted {
+		t.Errorf("installing with a canceled context = %v, exit code %d; want %d", err, ExitCode(err), ExitInterrupted)
+	}
+}
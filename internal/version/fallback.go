@@ -0,0 +1,117 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+)
+
+// ChinaBaseURL and ChinaCatalogURL are the official mirror of the
+// download site and of the release listing for mainland China, where
+// dl.google.com and go.dev often can't be reached. They serve the same
+// archives, checksums and listing.
+const (
+	ChinaBaseURL    = "https://golang.google.cn/dl/"
+	ChinaCatalogURL = "https://golang.google.cn/dl/?mode=json&include=all"
+)
+
+// A urlRewrite maps URLs starting with from to the same path under to.
+type urlRewrite struct{ from, to string }
+
+// chinaFallback maps the URLs of the download site and the release
+// listing to those of their mirror for mainland China.
+var chinaFallback = []urlRewrite{
+	{DefaultBaseURL, ChinaBaseURL},
+	{"https://go.dev/dl/", ChinaBaseURL},
+}
+
+// A fallbackDoer makes requests with d, but once a request to a URL that
+// rewrites maps can't connect at all, it repeats it against the mirror,
+// and sends every later request for such a URL straight to the mirror.
+type fallbackDoer struct {
+	d        Doer
+	rewrites []urlRewrite
+	switched int32 // atomic; set once the mirror is in use
+}
+
+func (f *fallbackDoer) Do(req *http.Request) (*http.Response, error) {
+	mirrored, ok := f.rewrite(req.URL.String())
+	if !ok {
+		return f.d.Do(req)
+	}
+	if atomic.LoadInt32(&f.switched) == 0 {
+		res, err := f.d.Do(req)
+		if err == nil || !unreachable(req.Context(), err) {
+			return res, err
+		}
+		if atomic.CompareAndSwapInt32(&f.switched, 0, 1) {
+			log.Printf("Note: %s can't be reached (%v); using the mirror %s instead", req.URL.Host, err, hostOf(mirrored))
+		}
+	}
+	mreq, err := withURL(req, mirrored)
+	if err != nil {
+		return nil, err
+	}
+	return f.d.Do(mreq)
+}
+
+// withURL returns a copy of req for the URL s.
+func withURL(req *http.Request, s string) (*http.Request, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	r := req.Clone(req.Context())
+	r.URL = u
+	r.Host = ""
+	return r, nil
+}
+
+// rewrite returns the mirror's copy of s, if there is one.
+func (f *fallbackDoer) rewrite(s string) (string, bool) {
+	for _, r := range f.rewrites {
+		if strings.HasPrefix(s, r.from) {
+			return r.to + strings.TrimPrefix(s, r.from), true
+		}
+	}
+	return "", false
+}
+
+// unreachable reports whether err, of a request made in ctx, means that
+// the server couldn't be connected to or stopped answering, rather than
+// that it answered with an error, and ctx hasn't ended.
+func unreachable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var ne net.Error
+	var oe *net.OpError
+	var de *net.DNSError
+	return errors.As(err, &ne) && ne.Timeout() || errors.As(err, &oe) || errors.As(err, &de)
+}
+
+// hostOf returns the host of the URL s, or s if it doesn't parse.
+func hostOf(s string) string {
+	if u, err := url.Parse(s); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return s
+}
+
+// responseHost returns the host that sent res: that of its request, which
+// a mirror may have answered in place of rawURL, or else rawURL's.
+func responseHost(res *http.Response, rawURL string) string {
+	if res.Request != nil && res.Request.URL != nil {
+		return res.Request.URL.Host
+	}
+	return hostOf(rawURL)
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// blockedDoer fails to connect to the hosts in blocked, and answers
+// every other request with its status, or the one statuses gives its
+// host, recording each URL requested.
+type blockedDoer struct {
+	blocked  map[string]bool
+	status   int
+	statuses map[string]int
+	urls     []string
+}
+
+func (b *blockedDoer) Do(req *http.Request) (*http.Response, error) {
+	b.urls = append(b.urls, req.URL.String())
+	if b.blocked[req.URL.Host] {
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	}
+	status := b.status
+	if code, ok := b.statuses[req.URL.Host]; ok {
+		status = code
+	}
+	return &http.Response{StatusCode: status, Status: http.StatusText(status), Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+}
+
+func TestFallbackDoer(t *testing.T) {
+	archive := DefaultBaseURL + "go1.22.7.linux-amd64.tar.gz"
+	get := func(d Doer, url string) error {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := d.Do(req)
+		if err == nil {
+			res.Body.Close()
+		}
+		return err
+	}
+
+	// A server that answers, even with an error, is kept.
+	b := &blockedDoer{status: http.StatusNotFound}
+	f := &fallbackDoer{d: b, rewrites: chinaFallback}
+	if err := get(f, archive); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{archive}; !reflect.DeepEqual(b.urls, want) {
+		t.Errorf("fallback requested %q; want %q", b.urls, want)
+	}
+
+	// One that can't be connected to is given up on, for the listing too.
+	b = &blockedDoer{blocked: map[string]bool{"dl.google.com": true, "go.dev": true}, status: http.StatusOK}
+	f = &fallbackDoer{d: b, rewrites: chinaFallback}
+	for _, url := range []string{archive, archive + ".sha256", DefaultCatalogURL, "https://example.com/go.tar.gz"} {
+		if err := get(f, url); err != nil {
+			t.Errorf("GET %s: %v", url, err)
+		}
+	}
+	want := []string{
+		archive,
+		ChinaBaseURL + "go1.22.7.linux-amd64.tar.gz",
+		ChinaBaseURL + "go1.22.7.linux-amd64.tar.gz.sha256",
+		ChinaCatalogURL,
+		"https://example.com/go.tar.gz",
+	}
+	if !reflect.DeepEqual(b.urls, want) {
+		t.Errorf("fallback requested\n%q\nwant\n%q", b.urls, want)
+	}
+
+	// Other hosts are never rewritten.
+	b = &blockedDoer{blocked: map[string]bool{"example.com": true}}
+	f = &fallbackDoer{d: b, rewrites: chinaFallback}
+	if err := get(f, "https://example.com/go.tar.gz"); err == nil || len(b.urls) != 1 {
+		t.Errorf("GET from a blocked mirror = %v after %q; want an error after one request", err, b.urls)
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// tempPrefixes begin the names of the temporary files and directories the
+// tool creates beside what it writes, and removes unless it is
+// interrupted.
+var tempPrefixes = []string{".case-probe-", ".dl-update-", ".dl-write-test-", ".go-shim-"}
+
+// gcDirs are the directories dl gc looks for leftovers in. Any may be
+// empty, and several may be the same.
+type gcDirs struct {
+	SDKRoot  string // with the toolchains and the gotip tree
+	CacheDir string // the archive cache
+	Listing  string // where the release listing is cached
+	GOBIN    string // where dl default installs the go shim
+	ExeDir   string // that of the dl command, which dl self-update replaces
+	TempDir  string // where installs with -dir download to
+}
+
+// gcItems returns the leftovers in dirs that nothing uses any more, of
+// those last modified longer ago than olderThan, as of now:
+//
+//   - the archives in the archive cache, with their checksums, and those
+//     kept in the GOROOTs of installed releases, when there is no cache
+//   - toolchains moved aside by a reinstall or uninstall that couldn't
+//     delete them
+//   - releases whose install never completed, with what was downloaded or
+//     extracted of them, and partial downloads in the archive cache
+//   - the objects and bootstrap build cache make.bash leaves in pkg/obj of
+//     a built gotip tree
+//   - temporary files and directories left by interrupted writes,
+//     self-updates and installs with -dir
+//
+// A directory's age is that of the newest file in it, so that one being
+// written to is left alone.
+func gcItems(dirs gcDirs, olderThan time.Duration, now time.Time) ([]PurgeItem, error) {
+	old := func(t time.Time) bool { return now.Sub(t) >= olderThan }
+	var items []PurgeItem
+	add := func(path, what string) {
+		items = append(items, PurgeItem{Path: path, What: what, Size: diskUsage(path)})
+	}
+
+	if dirs.CacheDir != "" {
+		entries, err := cacheEntries(dirs.CacheDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if !old(e.ModTime) {
+				continue
+			}
+			for _, name := range []string{e.Name, e.Name + ".sha256"} {
+				if p := filepath.Join(dirs.CacheDir, name); isFile(p) {
+					add(p, "cached archive")
+				}
+			}
+		}
+	}
+
+	if dirs.SDKRoot != "" {
+		fis, err := ioutil.ReadDir(dirs.SDKRoot)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		sortEntries(fis)
+		for _, fi := range fis {
+			name := fi.Name()
+			p := filepath.Join(dirs.SDKRoot, name)
+			if !fi.IsDir() || toolchainEntry(name) == "" {
+				continue
+			}
+			switch {
+			case strings.Contains(name, asideSuffix):
+				if old(asideTime(name, fi.ModTime())) {
+					add(p, toolchainEntry(name))
+				}
+			case name == "gotip":
+				obj := filepath.Join(p, "pkg", "obj")
+				if isFile(filepath.Join(p, "bin", "go"+exe())) && isDir(obj) && old(lastModified(obj)) {
+					add(obj, "gotip build leftovers")
+				}
+			case !isFile(filepath.Join(p, unpackedOkay)):
+				if old(lastModified(p)) {
+					add(p, "incomplete install of "+name)
+				}
+			default:
+				afis, _ := ioutil.ReadDir(p)
+				for _, afi := range afis {
+					if afi.Mode().IsRegular() && isArchiveName(afi.Name()) && old(afi.ModTime()) {
+						add(filepath.Join(p, afi.Name()), "archive of "+name)
+					}
+				}
+			}
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, dir := range []string{dirs.SDKRoot, dirs.CacheDir, dirs.Listing, dirs.GOBIN, dirs.ExeDir} {
+		if dir == "" || seen[filepath.Clean(dir)] {
+			continue
+		}
+		seen[filepath.Clean(dir)] = true
+		fis, _ := ioutil.ReadDir(dir)
+		for _, fi := range fis {
+			p := filepath.Join(dir, fi.Name())
+			switch {
+			case isTempName(fi.Name()) && old(lastModified(p)):
+				add(p, "temporary file")
+			case isPartialName(fi.Name()) && old(fi.ModTime()):
+				add(p, "partial download")
+			}
+		}
+	}
+	if dirs.TempDir != "" {
+		fis, _ := ioutil.ReadDir(dirs.TempDir)
+		for _, fi := range fis {
+			if p := filepath.Join(dirs.TempDir, fi.Name()); fi.IsDir() && strings.HasPrefix(fi.Name(), "godl-") && old(lastModified(p)) {
+				add(p, "temporary download directory")
+			}
+		}
+	}
+	return items, nil
+}
+
+// isTempName reports whether name is that of a temporary file or
+// directory the tool creates: one of tempPrefixes, or a name with ".tmp"
+// and digits appended, as writeFileAtomic writes to.
+func isTempName(name string) bool {
+	for _, prefix := range tempPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	i := strings.LastIndex(name, ".tmp")
+	if i <= 0 || i+len(".tmp") == len(name) {
+		return false
+	}
+	_, err := strconv.ParseUint(name[i+len(".tmp"):], 10, 64)
+	return err == nil
+}
+
+// isPartialName reports whether name is that of a partial download, or of
+// its record.
+func isPartialName(name string) bool {
+	return strings.HasSuffix(name, partialSuffix) || strings.HasSuffix(name, partialInfoSuffix)
+}
+
+// asideTime returns when removeInstall moved the toolchain name aside, as
+// its suffix records, or modTime if it doesn't.
+func asideTime(name string, modTime time.Time) time.Time {
+	i := strings.LastIndex(name, asideSuffix)
+	if i < 0 {
+		return modTime
+	}
+	ns, err := strconv.ParseInt(name[i+len(asideSuffix):], 36, 64)
+	if err != nil {
+		return modTime
+	}
+	return time.Unix(0, ns)
+}
+
+// lastModified returns the newest modification time of path and anything
+// under it.
+func lastModified(path string) time.Time {
+	var t time.Time
+	_ = filepath.Walk(path, func(_ string, fi os.FileInfo, err error) error {
+		if err == nil && fi.ModTime().After(t) {
+			t = fi.ModTime()
+		}
+		return nil
+	})
+	return t
+}
@@ -0,0 +1,105 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestGCItems(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-40 * 24 * time.Hour)
+	root, cache, gobin, tmp := t.TempDir(), t.TempDir(), t.TempDir(), t.TempDir()
+	aside := func(when time.Time) string {
+		return "go1.21.0" + asideSuffix + strconv.FormatInt(when.UnixNano(), 36)
+	}
+	makeTree(t, root, map[string]string{
+		"go1.22.7/" + unpackedOkay:               "",
+		"go1.22.7/go1.22.7.linux-amd64.tar.gz":   "archive",
+		"go1.21.13/" + unpackedOkay:              "",
+		"go1.21.13/go1.21.13.linux-amd64.tar.gz": "archive",
+		"go1.20.14/go1.20.14.linux-amd64.tar.gz": "partial",
+		"go1.19.13/bin/go":                       "half",
+		aside(old) + "/VERSION":                  "go1.21.0",
+		aside(now) + "/VERSION":                  "go1.21.0",
+		"gotip/bin/go" + exe():                   "built",
+		"gotip/pkg/obj/go-build/00/a":            "object",
+		"releases.json.tmp123":                   "[]",
+		"notes.tmpl":                             "mine",
+		"go1.23rc1/go1.23rc1.linux-amd64.tar.gz": "downloading",
+	})
+	makeCache(t, cache, now, map[string]int{
+		"go1.10.linux-amd64.tar.gz":   40,
+		"go1.22.7.linux-amd64.tar.gz": 1,
+	})
+	makeTree(t, cache, map[string]string{"go1.9.linux-amd64.tar.gz" + partialSuffix: "part"})
+	if err := os.Chtimes(filepath.Join(cache, "go1.9.linux-amd64.tar.gz"+partialSuffix), old, old); err != nil {
+		t.Fatal(err)
+	}
+	makeTree(t, gobin, map[string]string{".go-shim-42": "shim", "go": "shim"})
+	makeTree(t, tmp, map[string]string{"godl-1/a.tar.gz": "archive", "other-1/a": "not ours"})
+
+	// Age everything but the install of go1.23rc1 in progress and the
+	// cache, which makeCache dated, and the go1.21.13 archive.
+	for _, dir := range []string{root, gobin, tmp} {
+		filepath.Walk(dir, func(path string, fi os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if rel, _ := filepath.Rel(root, path); rel == "go1.23rc1" || filepath.Dir(rel) == "go1.23rc1" || rel == "go1.21.13/go1.21.13.linux-amd64.tar.gz" {
+				return nil
+			}
+			return os.Chtimes(path, old, old)
+		})
+	}
+
+	items, err := gcItems(gcDirs{SDKRoot: root, CacheDir: cache, GOBIN: gobin, ExeDir: gobin, TempDir: tmp}, 30*24*time.Hour, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, it := range items {
+		got = append(got, it.What+": "+it.Path)
+	}
+	want := []string{
+		"cached archive: " + filepath.Join(cache, "go1.10.linux-amd64.tar.gz"),
+		"cached archive: " + filepath.Join(cache, "go1.10.linux-amd64.tar.gz.sha256"),
+		"incomplete install of go1.19.13: " + filepath.Join(root, "go1.19.13"),
+		"incomplete install of go1.20.14: " + filepath.Join(root, "go1.20.14"),
+		"old copy of go1.21.0: " + filepath.Join(root, aside(old)),
+		"archive of go1.22.7: " + filepath.Join(root, "go1.22.7", "go1.22.7.linux-amd64.tar.gz"),
+		"gotip build leftovers: " + filepath.Join(root, "gotip", "pkg", "obj"),
+		"temporary file: " + filepath.Join(root, "releases.json.tmp123"),
+		"partial download: " + filepath.Join(cache, "go1.9.linux-amd64.tar.gz"+partialSuffix),
+		"temporary file: " + filepath.Join(gobin, ".go-shim-42"),
+		"temporary download directory: " + filepath.Join(tmp, "godl-1"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("gcItems:\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestIsTempName(t *testing.T) {
+	for name, want := range map[string]bool{
+		"releases.json.tmp123":  true,
+		"godl.toml.tmp4567":     true,
+		".dl-update-123":        true,
+		".case-probe-9":         true,
+		"notes.tmpl":            false,
+		"releases.json.tmp":     false,
+		".tmp123":               false,
+		"go1.22.7.linux-amd64":  false,
+		"releases.json.tmp12x3": false,
+	} {
+		if got := isTempName(name); got != want {
+			t.Errorf("isTempName(%q) = %v; want %v", name, got, want)
+		}
+	}
+}
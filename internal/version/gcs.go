@@ -0,0 +1,259 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// gcsReadScope is the OAuth scope that access tokens are requested with:
+// reading Cloud Storage, and nothing else.
+const gcsReadScope = "https://www.googleapis.com/auth/devstorage.read_only"
+
+// googleTokenURL is where user credentials are exchanged for access
+// tokens.
+const googleTokenURL = "https://oauth2.googleapis.com/token"
+
+// A gcsClient makes requests for gs://bucket/object URLs, with an access
+// token from the application default credentials if there are any, and
+// without, for public buckets, if there are none.
+type gcsClient struct {
+	d    Doer
+	meta Doer // for the metadata server
+
+	mu      sync.Mutex
+	token   string // "" if there are no credentials
+	expires time.Time
+	looked  bool // whether token has been looked up
+}
+
+func (g *gcsClient) do(req *http.Request) (*http.Response, error) {
+	token, err := g.accessToken(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("finding Google Cloud credentials for %s: %w", req.URL, err)
+	}
+	r, err := objectRequest(req, gcsURL(req.URL.Host, req.URL.Path))
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	return g.d.Do(r)
+}
+
+// gcsURL returns the URL of object, which starts with a slash, in bucket,
+// in the XML API of Cloud Storage, or of the emulator that
+// STORAGE_EMULATOR_HOST names.
+func gcsURL(bucket, object string) string {
+	endpoint := "https://storage.googleapis.com"
+	if host := os.Getenv("STORAGE_EMULATOR_HOST"); host != "" {
+		endpoint = strings.TrimSuffix(host, "/")
+		if !strings.Contains(endpoint, "://") {
+			endpoint = "http://" + endpoint
+		}
+	}
+	return endpoint + "/" + bucket + (&url.URL{Path: object}).EscapedPath()
+}
+
+// accessToken returns the access token to send, looked up as the Google
+// Cloud client libraries look up application default credentials: from
+// the file GOOGLE_APPLICATION_CREDENTIALS names, the one that
+// "gcloud auth application-default login" writes, or the metadata server
+// of a Google Cloud machine. It returns "" if there are none anywhere.
+func (g *gcsClient) accessToken(ctx context.Context) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.looked && (g.token == "" || time.Until(g.expires) > 5*time.Minute) {
+		return g.token, nil
+	}
+	token, expires, err := g.lookupToken(ctx)
+	if err != nil {
+		return "", err
+	}
+	g.token, g.expires, g.looked = token, expires, true
+	return token, nil
+}
+
+// A googleToken is a token endpoint's or the metadata server's reply.
+type googleToken struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"` // seconds
+}
+
+func (g *gcsClient) lookupToken(ctx context.Context) (string, time.Time, error) {
+	file := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+	if file == "" {
+		if f := gcloudCredentialsFile(); f != "" {
+			if _, err := os.Stat(f); err == nil {
+				file = f
+			}
+		}
+	}
+	var req *http.Request
+	var err error
+	doer := g.d
+	if file != "" {
+		req, err = credentialsTokenRequest(ctx, file, time.Now())
+	} else {
+		host := os.Getenv("GCE_METADATA_HOST")
+		if host == "" {
+			host = "169.254.169.254"
+		}
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, "http://"+host+"/computeMetadata/v1/instance/service-accounts/default/token?scopes="+url.QueryEscape(gcsReadScope), nil)
+		if err == nil {
+			req.Header.Set("Metadata-Flavor", "Google")
+		}
+		doer = g.meta
+	}
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	start := time.Now()
+	var t googleToken
+	if err := fetchJSON(doer, req, &t); err != nil {
+		if file == "" {
+			// Not on Google Cloud, or without a service account.
+			return "", time.Time{}, nil
+		}
+		return "", time.Time{}, err
+	}
+	if t.AccessToken == "" {
+		return "", time.Time{}, fmt.Errorf("no access token from %s", req.URL.Redacted())
+	}
+	return t.AccessToken, start.Add(time.Duration(t.ExpiresIn) * time.Second), nil
+}
+
+// gcloudCredentialsFile returns the application default credentials file
+// that gcloud writes.
+func gcloudCredentialsFile() string {
+	dir := os.Getenv("CLOUDSDK_CONFIG")
+	if dir == "" {
+		if runtime.GOOS == "windows" {
+			dir = filepath.Join(os.Getenv("APPDATA"), "gcloud")
+		} else if home, err := os.UserHomeDir(); err == nil {
+			dir = filepath.Join(home, ".config", "gcloud")
+		} else {
+			return ""
+		}
+	}
+	return filepath.Join(dir, "application_default_credentials.json")
+}
+
+// googleCredentials is a credentials file: a service account's key, or a
+// user's refresh token.
+type googleCredentials struct {
+	Type string `json:"type"`
+
+	// service_account
+	ClientEmail  string `json:"client_email"`
+	PrivateKey   string `json:"private_key"`
+	PrivateKeyID string `json:"private_key_id"`
+	TokenURI     string `json:"token_uri"`
+
+	// authorized_user
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// credentialsTokenRequest returns the request that exchanges the
+// credentials in file for an access token, as of now.
+func credentialsTokenRequest(ctx context.Context, file string, now time.Time) (*http.Request, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var c googleCredentials
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	form := url.Values{}
+	tokenURL := googleTokenURL
+	switch c.Type {
+	case "service_account":
+		if c.TokenURI != "" {
+			tokenURL = c.TokenURI
+		}
+		assertion, err := signJWT(c, tokenURL, now)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+		form.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+		form.Set("assertion", assertion)
+	case "authorized_user":
+		form.Set("grant_type", "refresh_token")
+		form.Set("client_id", c.ClientID)
+		form.Set("client_secret", c.ClientSecret)
+		form.Set("refresh_token", c.RefreshToken)
+	default:
+		return nil, fmt.Errorf("%s: unsupported credentials type %q", file, c.Type)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req, nil
+}
+
+// signJWT returns the JSON Web Token, signed with the key of the service
+// account c, that asserts its identity to tokenURL for an hour from now.
+func signJWT(c googleCredentials, tokenURL string, now time.Time) (string, error) {
+	block, _ := pem.Decode([]byte(c.PrivateKey))
+	if block == nil {
+		return "", errors.New("no PEM private key")
+	}
+	var key *rsa.PrivateKey
+	if k, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		var ok bool
+		if key, ok = k.(*rsa.PrivateKey); !ok {
+			return "", errors.New("private key is not an RSA key")
+		}
+	} else if key, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+		return "", fmt.Errorf("parsing private key: %v", err)
+	}
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": c.PrivateKeyID})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"iss":   c.ClientEmail,
+		"scope": gcsReadScope,
+		"aud":   tokenURL,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	enc := base64.RawURLEncoding
+	signed := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+	sum := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, sum[:])
+	if err != nil {
+		return "", err
+	}
+	return signed + "." + enc.EncodeToString(sig), nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// A modRequirement is what a go.mod file asks of the toolchain that
+// builds the module, from Go 1.21 on, as release names: the minimum
+// release of its go line, such as go1.22 or go1.22.3, and the release its
+// toolchain line prefers, such as go1.22.7. Either may be empty.
+type modRequirement struct {
+	Go        string
+	Toolchain string
+}
+
+// findGoMod looks for the go.mod file of the module that dir is in, in
+// dir and each of its parents in turn, and returns what it requires, and
+// that file. If there is none, it returns a zero modRequirement and "".
+func findGoMod(dir string) (r modRequirement, file string, err error) {
+	for {
+		file = filepath.Join(dir, "go.mod")
+		data, err := ioutil.ReadFile(file)
+		if err == nil {
+			r, err := parseGoMod(data)
+			if err != nil {
+				return r, "", fmt.Errorf("%s: %v", file, err)
+			}
+			return r, file, nil
+		}
+		if !os.IsNotExist(err) {
+			return r, "", err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return r, "", nil
+		}
+		dir = parent
+	}
+}
+
+// parseGoMod returns the requirement of the go.mod file data. A go line
+// before Go 1.21 asks for nothing, since the go command only switches
+// toolchains from then on, and so does a toolchain line of default or of
+// a release older than the go line's, which the go command ignores. A
+// toolchain's custom suffix, as in go1.22.7-mycorp, is dropped.
+func parseGoMod(data []byte) (modRequirement, error) {
+	var r modRequirement
+	for n, line := range strings.Split(string(data), "\n") {
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		f := strings.Fields(line)
+		if len(f) != 2 || f[0] != "go" && f[0] != "toolchain" {
+			continue
+		}
+		name := f[1]
+		if f[0] == "go" {
+			name = "go" + name
+		} else if name == "default" {
+			continue
+		} else if i := strings.IndexAny(name, "-+"); i >= 0 {
+			name = name[:i]
+		}
+		v, err := ParseVersion(name)
+		if err != nil {
+			return r, fmt.Errorf("%d: %s line: %v", n+1, f[0], err)
+		}
+		switch {
+		case f[0] == "toolchain":
+			r.Toolchain = name
+		case v.Major == 1 && v.Minor >= 21 || v.Major > 1:
+			r.Go = name
+		}
+	}
+	if r.Toolchain != "" && r.Go != "" && olderRelease(r.Toolchain, r.Go) {
+		r.Toolchain = ""
+	}
+	return r, nil
+}
+
+// switchFrom returns the release to run instead of base for r to be met,
+// as the go command switches toolchains under GOTOOLCHAIN=auto: that of
+// the toolchain line if base is older than it, or else that of the go line
+// if base is older than that. It returns "" if base meets r. An empty base
+// is older than any release.
+func (r modRequirement) switchFrom(base string) string {
+	for _, want := range []string{r.Toolchain, r.Go} {
+		if want != "" && (base == "" || olderRelease(base, want)) {
+			return want
+		}
+	}
+	return ""
+}
+
+// olderRelease reports whether the release a orders before b. Names that
+// don't parse are never older.
+func olderRelease(a, b string) bool {
+	v, err := ParseVersion(a)
+	if err != nil {
+		return false
+	}
+	w, err := ParseVersion(b)
+	return err == nil && v.Less(w)
+}
+
+// parseGOTOOLCHAIN interprets the GOTOOLCHAIN setting s as the go command
+// does: it returns the release it names, if any, which the shim runs
+// instead of its default, and whether a go.mod file may still switch to a
+// newer one. local forbids switching, a release name alone forces that
+// release, and auto, path, or a release or local followed by +auto or
+// +path, allow switching.
+func parseGOTOOLCHAIN(s string) (name string, switching bool) {
+	base := s
+	if i := strings.Index(s, "+"); i >= 0 {
+		base = s[:i]
+		switching = true
+	}
+	switch base {
+	case "", "auto", "path":
+		return "", true
+	case "local":
+		return "", switching
+	}
+	if i := strings.Index(base, "-"); i >= 0 {
+		base = base[:i]
+	}
+	return base, switching
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseGoMod(t *testing.T) {
+	tests := []struct {
+		data string
+		want modRequirement
+		err  string
+	}{
+		{"module example.com/m\n\ngo 1.22\n", modRequirement{Go: "go1.22"}, ""},
+		{"module m\ngo 1.22.3 // for range over func\ntoolchain go1.22.7\n", modRequirement{Go: "go1.22.3", Toolchain: "go1.22.7"}, ""},
+		{"module m\ngo 1.21rc1\n", modRequirement{Go: "go1.21rc1"}, ""},
+		{"module m\ngo 1.17\n\nrequire (\n\tgolang.org/x/mod v0.14.0\n)\n", modRequirement{}, ""},
+		{"module m\ngo 1.22.0\ntoolchain default\n", modRequirement{Go: "go1.22.0"}, ""},
+		{"module m\ngo 1.22.0\ntoolchain go1.23.1-mycorp\n", modRequirement{Go: "go1.22.0", Toolchain: "go1.23.1"}, ""},
+		{"module m\ngo 1.23.0\ntoolchain go1.22.7\n", modRequirement{Go: "go1.23.0"}, ""},
+		{"module m\n// go 1.23\n", modRequirement{}, ""},
+		{"module m\ngo 1.x\n", modRequirement{}, "2: go line: "},
+		{"module m\ngo 1.22\ntoolchain local\n", modRequirement{}, "3: toolchain line: "},
+	}
+	for _, tt := range tests {
+		got, err := parseGoMod([]byte(tt.data))
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("parseGoMod(%q) = %+v, %v; want error containing %q", tt.data, got, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("parseGoMod(%q) = %+v, %v; want %+v", tt.data, got, err, tt.want)
+		}
+	}
+}
+
+func TestSwitchFrom(t *testing.T) {
+	tests := []struct {
+		r    modRequirement
+		base string
+		want string
+	}{
+		{modRequirement{}, "go1.22.7", ""},
+		{modRequirement{}, "", ""},
+		{modRequirement{Go: "go1.22"}, "go1.21.13", "go1.22"},
+		{modRequirement{Go: "go1.22"}, "go1.22.7", ""},
+		{modRequirement{Go: "go1.22"}, "go1.23.1", ""},
+		{modRequirement{Go: "go1.22"}, "", "go1.22"},
+		{modRequirement{Go: "go1.22.3", Toolchain: "go1.22.7"}, "go1.22.5", "go1.22.7"},
+		{modRequirement{Go: "go1.22.3", Toolchain: "go1.22.7"}, "go1.23.1", ""},
+		{modRequirement{Go: "go1.22.3", Toolchain: "go1.22.7"}, "", "go1.22.7"},
+	}
+	for _, tt := range tests {
+		if got := tt.r.switchFrom(tt.base); got != tt.want {
+			t.Errorf("%+v.switchFrom(%q) = %q; want %q", tt.r, tt.base, got, tt.want)
+		}
+	}
+}
+
+func TestParseGOTOOLCHAIN(t *testing.T) {
+	tests := []struct {
+		s         string
+		name      string
+		switching bool
+	}{
+		{"", "", true},
+		{"auto", "", true},
+		{"path", "", true},
+		{"local", "", false},
+		{"local+auto", "", true},
+		{"go1.22.7", "go1.22.7", false},
+		{"go1.22.7+auto", "go1.22.7", true},
+		{"go1.22.7-mycorp+path", "go1.22.7", true},
+	}
+	for _, tt := range tests {
+		if name, switching := parseGOTOOLCHAIN(tt.s); name != tt.name || switching != tt.switching {
+			t.Errorf("parseGOTOOLCHAIN(%q) = %q, %v; want %q, %v", tt.s, name, switching, tt.name, tt.switching)
+		}
+	}
+}
+
+func TestFindGoMod(t *testing.T) {
+	root := t.TempDir()
+	makeTree(t, root, map[string]string{
+		"m/go.mod":          "module m\n\ngo 1.22.0\ntoolchain go1.22.7\n",
+		"m/internal/x/x.go": "package x",
+		"m/nested/go.mod":   "module m/nested\n\ngo 1.21.0\n",
+	})
+	r, file, err := findGoMod(filepath.Join(root, "m", "internal", "x"))
+	if want := (modRequirement{Go: "go1.22.0", Toolchain: "go1.22.7"}); err != nil || r != want || file != filepath.Join(root, "m", "go.mod") {
+		t.Errorf("findGoMod(m/internal/x) = %+v, %s, %v; want %+v from m/go.mod", r, file, err, want)
+	}
+	r, file, err = findGoMod(filepath.Join(root, "m", "nested"))
+	if want := (modRequirement{Go: "go1.21.0"}); err != nil || r != want || file != filepath.Join(root, "m", "nested", "go.mod") {
+		t.Errorf("findGoMod(m/nested) = %+v, %s, %v; want %+v from m/nested/go.mod", r, file, err, want)
+	}
+}
@@ -5,7 +5,10 @@
 package version
 
 import (
+	"context"
+	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"os/exec"
@@ -14,75 +17,234 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // RunTip runs the "go" tool from the development tree.
 func RunTip() {
 	log.SetFlags(0)
 
+	cfg, err := loadConfig("", nil)
+	if err != nil {
+		fatal("gotip", err)
+	}
 	root, err := goroot("gotip")
 	if err != nil {
-		log.Fatalf("gotip: %v", err)
+		fatal("gotip", err)
 	}
 
 	if len(os.Args) > 1 && os.Args[1] == "download" {
-		switch len(os.Args) {
-		case 2:
-			if err := installTip(root, ""); err != nil {
-				log.Fatalf("gotip: %v", err)
+		flags := flag.NewFlagSet("gotip download", flag.ExitOnError)
+		yes := flags.Bool("y", false, "build a CL without asking for confirmation")
+		flags.String("max-rate", "", "limit downloads to `rate` bytes per second, such as 2MiB (also GODL_MAX_RATE)")
+		flags.String("progress", "text", "report progress as `format` text, or json: one JSON object per line on standard error (also GODL_PROGRESS)")
+		addOutputFlags(flags)
+		flags.String("sdk-dir", "", "install toolchains in `dir`, remembering it in the config file if the usual SDK directory can't be written to")
+		flags.BoolVar(&nonInteractive, "non-interactive", false, "never ask questions; also the default when not at a terminal or when CI=true")
+		flags.Parse(os.Args[2:])
+		if flags.NArg() > 1 {
+			usagef("gotip: usage: gotip download [-y] [-q | -v] [-max-rate rate] [-progress format] [-sdk-dir dir] [-non-interactive] [CL number | commit | branch name]")
+		}
+		cfg.setFlags(flags)
+		opts, err := cfg.Options()
+		if err != nil {
+			fatal("gotip", err)
+		}
+		if err := ensureSDKRoot(cfg); err != nil {
+			fatal("gotip", err)
+		}
+		if root, err = goroot("gotip"); err != nil {
+			fatal("gotip", err)
+		}
+		target := flags.Arg(0)
+		if isCLNumber(target) && !*yes {
+			answer, err := ask(fmt.Sprintf("This will download and execute code from golang.org/cl/%s, continue? [y/n] ", target), "-y")
+			if err != nil {
+				fatal("gotip", err)
 			}
-		case 3:
-			if err := installTip(root, os.Args[2]); err != nil {
-				log.Fatalf("gotip: %v", err)
+			if answer != "y" {
+				fatal("gotip", errInterrupted)
 			}
-		default:
-			log.Fatalf("gotip: usage: gotip download [CL number | branch name]")
+		}
+		ctx, stop := interruptContext()
+		err = downloadTip(ctx, root, target, &opts)
+		stop()
+		if err != nil {
+			fatal("gotip", err)
 		}
 		log.Printf("Success. You may now run 'gotip'!")
+		warnShadowing("gotip", root, nil)
 		os.Exit(0)
 	}
 
-	gobin := filepath.Join(root, "bin", "go"+exe())
-	if _, err := os.Stat(gobin); err != nil {
-		log.Fatalf("gotip: not downloaded. Run 'gotip download' to install to %v", root)
+	if tip, err := whichGoroot(defaultLocator, "gotip"); err == nil {
+		root = tip
+	} else {
+		log.Printf("gotip: not downloaded. Run 'gotip download' to install to %v", root)
+		os.Exit(ExitNotInstalled)
+	}
+	if len(os.Args) > 1 && os.Args[1] == "shell" {
+		runShell("gotip", root, os.Args[2:])
+	}
+	// Commands gotip runs find its go first in PATH, but the shell doesn't.
+	warnShadowing("gotip", root, nil)
+
+	runGo(root, os.Args[1:])
+}
+
+// downloadTip fetches and builds target in the gotip tree at root, as
+// installTip does, and records the install in the journal.
+func downloadTip(ctx context.Context, root, target string, opts *DownloaderOptions) error {
+	rec := newJournalRecorder("gotip")
+	err := installTip(ctx, root, target, newProgressEmitter(rec.events, newJSONProgress(opts.ProgressJSON)), nil, opts)
+	e, _ := rec.wait()
+	e.Target, e.URL, e.Platform = target, gerritURL, runtime.GOOS+"/"+runtime.GOARCH
+	if err == nil {
+		e.Commit = gitHead(root)
+	}
+	recordInstall(e)
+	return err
+}
+
+// gitHead returns the commit checked out in the git repository at dir, or
+// "" if it can't be determined.
+func gitHead(dir string) string {
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// gitHasProxy reports whether git is configured to use a proxy, in the
+// environment or its own configuration.
+func gitHasProxy() bool {
+	for _, env := range []string{"HTTPS_PROXY", "https_proxy", "ALL_PROXY", "all_proxy"} {
+		if os.Getenv(env) != "" {
+			return true
+		}
 	}
+	out, _ := exec.Command("git", "config", "--get", "http.proxy").Output()
+	return len(strings.TrimSpace(string(out))) > 0
+}
+
+// isCLNumber reports whether the gotip download target is a CL number,
+// a simple decimal number, rather than a branch name.
+func isCLNumber(target string) bool {
+	n, _ := strconv.Atoi(target)
+	return n >= 1 && strconv.Itoa(n) == target
+}
 
-	runGo(root)
+// isCommitHash reports whether the gotip download target is a full
+// commit hash, as a manifest records, rather than a branch name.
+func isCommitHash(target string) bool {
+	if len(target) != 40 {
+		return false
+	}
+	for i := 0; i < len(target); i++ {
+		if c := target[i]; !isDigit(c) && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
 }
 
-func installTip(root, target string) error {
+// gerritURL is the repository the gotip tree is fetched from.
+const gerritURL = "https://go.googlesource.com/go"
+
+// installTip fetches target, a CL number, commit hash or branch name
+// (master if empty), into the gotip tree at root and builds it. Build
+// output is also reported as events to em, and the build's outcome to m.
+// Of opts, which may be nil, only Offline, Proxy, MaxRate, UserAgent and
+// Header apply:
+// fetching needs the network, so in offline mode it fails at once. Once
+// ctx is done, git and the build are killed, and the error is ctx's.
+func installTip(ctx context.Context, root, target string, em *emitter, m *Metrics, opts *DownloaderOptions) (err error) {
+	start := time.Now()
+	phase := PhaseResolve
+	defer func() {
+		if err != nil {
+			em.emit(Failed{Err: err, Phase: phase})
+		} else {
+			em.emit(Completed{GOROOT: root, Duration: time.Since(start)})
+		}
+	}()
+
+	var offline bool
+	var proxy string
+	var rate int64
+	var gitEnv []string
+	if opts != nil {
+		offline, proxy, rate = opts.Offline, opts.Proxy, opts.MaxRate
+		gitEnv = gitHeaderEnv(opts.UserAgent, opts.Header)
+	}
+	if err := netGate(offline, gerritURL); err != nil {
+		return err
+	}
+	// git can't limit its own rate, so it is made to fetch through a
+	// local proxy that does, unless it has a proxy of its own to use.
+	if rate > 0 {
+		if proxy != "" || gitHasProxy() {
+			log.Printf("Note: git fetches through its proxy at full speed; the download rate limit only applies to it without one")
+		} else if u, stop, err := startThrottleProxy(rate); err == nil {
+			defer stop()
+			proxy = u
+		}
+	}
+
+	// With -q, the output of git and of the build is shown only if the
+	// install fails.
+	stdout, stderr, flushQuiet := quietWriters(os.Stdout, os.Stderr)
+	defer func() { flushQuiet(err != nil) }()
 	git := func(args ...string) error {
-		cmd := exec.Command("git", args...)
+		args = append(gitProxyArgs(proxy), args...)
+		verbosef("Running git %s in %s", strings.Join(args, " "), root)
+		cmd := exec.CommandContext(ctx, "git", args...)
 		cmd.Stdin = os.Stdin
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
 		cmd.Dir = root
-		return cmd.Run()
+		if gitEnv != nil {
+			cmd.Env = append(os.Environ(), gitEnv...)
+		}
+		if err := cmd.Run(); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		return nil
 	}
 	gitOutput := func(args ...string) ([]byte, error) {
-		cmd := exec.Command("git", args...)
+		args = append(gitProxyArgs(proxy), args...)
+		verbosef("Running git %s in %s", strings.Join(args, " "), root)
+		cmd := exec.CommandContext(ctx, "git", args...)
 		cmd.Dir = root
-		return cmd.Output()
+		if gitEnv != nil {
+			cmd.Env = append(os.Environ(), gitEnv...)
+		}
+		out, err := cmd.Output()
+		if err != nil && ctx.Err() != nil {
+			return out, ctx.Err()
+		}
+		return out, err
 	}
 
 	if _, err := os.Stat(filepath.Join(root, ".git")); err != nil {
 		if err := os.MkdirAll(root, 0755); err != nil {
 			return fmt.Errorf("failed to create repository: %v", err)
 		}
-		if err := git("clone", "--depth=1", "https://go.googlesource.com/go", root); err != nil {
-			return fmt.Errorf("failed to clone git repository: %v", err)
+		if err := git("clone", "--depth=1", gerritURL, root); err != nil {
+			return &fetchError{"clone git repository", err}
 		}
 	}
 
-	// If the argument is a simple decimal number, consider it a CL number.
-	// Otherwise, consider it a branch name. If it's missing, fetch master.
-	if n, _ := strconv.Atoi(target); n >= 1 && strconv.Itoa(n) == target {
-		fmt.Fprintf(os.Stderr, "This will download and execute code from golang.org/cl/%s, continue? [y/n] ", target)
-		var answer string
-		if fmt.Scanln(&answer); answer != "y" {
-			return fmt.Errorf("interrupted")
-		}
-
+	// If the argument is a simple decimal number, consider it a CL number,
+	// which RunTip has confirmed building. Otherwise, consider it a branch
+	// name. If it's missing, fetch master.
+	if isCLNumber(target) {
 		// ls-remote outputs a number of lines like:
 		// 2621ba2c60d05ec0b9ef37cd71e45047b004cead	refs/changes/37/227037/1
 		// 51f2af2be0878e1541d2769bd9d977a7e99db9ab	refs/changes/37/227037/2
@@ -90,7 +252,7 @@ func installTip(root, target string) error {
 		// 6a10ebae05ce4b01cb93b73c47bef67c0f5c5f2a	refs/changes/37/227037/meta
 		refs, err := gitOutput("ls-remote")
 		if err != nil {
-			return fmt.Errorf("failed to list remotes: %v", err)
+			return &fetchError{"list remotes", err}
 		}
 		r := regexp.MustCompile(`refs/changes/\d\d/` + target + `/(\d+)`)
 		match := r.FindAllStringSubmatch(string(refs), -1)
@@ -108,18 +270,23 @@ func installTip(root, target string) error {
 		}
 		log.Printf("Fetching CL %v, Patch Set %v...", target, patchSet)
 		if err := git("fetch", "origin", ref); err != nil {
-			return fmt.Errorf("failed to fetch %s: %v", ref, err)
+			return &fetchError{"fetch " + ref, err}
+		}
+	} else if isCommitHash(target) {
+		log.Printf("Fetching commit %v...", target)
+		if err := git("fetch", "origin", target); err != nil {
+			return &fetchError{"fetch commit " + target, err}
 		}
 	} else if target != "" {
 		log.Printf("Fetching branch %v...", target)
 		ref := "refs/heads/" + target
 		if err := git("fetch", "origin", ref); err != nil {
-			return fmt.Errorf("failed to fetch %s: %v", ref, err)
+			return &fetchError{"fetch " + ref, err}
 		}
 	} else {
 		log.Printf("Updating the go development tree...")
 		if err := git("fetch", "origin", "master"); err != nil {
-			return fmt.Errorf("failed to fetch git repository updates: %v", err)
+			return &fetchError{"fetch git repository updates", err}
 		}
 	}
 
@@ -134,31 +301,147 @@ func installTip(root, target string) error {
 	//
 	// Ask the user what to do about them if they are not gitignored. They might
 	// be artifacts that used to be ignored in previous versions, or precious
-	// uncommitted source files.
-	if err := git("clean", "-i", "-d"); err != nil {
-		return fmt.Errorf("failed to cleanup git repository: %v", err)
+	// uncommitted source files, so without prompts they are left alone.
+	if interactive() && outputLevel != outputQuiet {
+		if err := git("clean", "-i", "-d"); err != nil {
+			return fmt.Errorf("failed to cleanup git repository: %v", err)
+		}
+	} else if out, _ := gitOutput("clean", "-n", "-d"); len(out) > 0 {
+		log.Printf("Note: not running interactively, so these untracked files in %s were left alone:\n%s", root, strings.TrimSpace(string(out)))
 	}
 	// Wipe away probably boring ignored files without bothering the user.
 	if err := git("clean", "-q", "-f", "-d", "-X"); err != nil {
 		return fmt.Errorf("failed to cleanup git repository: %v", err)
 	}
 
-	cmd := exec.Command(filepath.Join(root, "src", makeScript()))
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	if arch, emulated := hostArch(); emulated && runtime.GOOS != "windows" {
+		// The build inherits this process's emulation.
+		log.Printf("Warning: gotip is a %s program running emulated on %s, so the toolchain it builds will be %s too; reinstall gotip with a native Go for a faster toolchain", runtime.GOARCH, arch, runtime.GOARCH)
+	}
+	phase = PhaseBuild
+	var status io.Writer
+	if isTerminal(os.Stderr) && outputLevel != outputQuiet {
+		status = os.Stderr
+	}
+	// The build progress serializes the writes to stdout and stderr.
+	bp := newBuildProgress(em, m, status, keepaliveInterval)
+	buildOut := &lineWriter{w: stdout, em: em, bp: bp}
+	buildErr := &lineWriter{w: stderr, em: em, bp: bp}
+	defer bp.close()
+	defer buildOut.flush()
+	defer buildErr.flush()
+	cmd := exec.CommandContext(ctx, filepath.Join(root, "src", makeScript()))
+	cmd.Stdout = buildOut
+	cmd.Stderr = buildErr
 	cmd.Dir = filepath.Join(root, "src")
-	if runtime.GOOS == "windows" {
+	verbosef("Running %s in %s", makeScript(), cmd.Dir)
+	env, err := bootstrapEnv()
+	if err != nil {
+		return err
+	}
+	env = append(env, tempEnv()...)
+	if env != nil {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	buildStart := time.Now()
+	err = cmd.Run()
+	m.build(time.Since(buildStart), err)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return &buildError{err}
+	}
+	verbosef("Built %s in %v", root, time.Since(buildStart).Round(time.Second))
+
+	return nil
+}
+
+// bootstrapEnv returns the environment variables that point the make
+// script at the go command in $PATH for bootstrapping, where the script
+// can't find it by itself.
+func bootstrapEnv() ([]string, error) {
+	switch arch, _ := hostArch(); {
+	case runtime.GOOS == "windows",
+		runtime.GOOS == "plan9" && os.Getenv("GOROOT_BOOTSTRAP") == "":
 		// Workaround make.bat not autodetecting GOROOT_BOOTSTRAP. Issue 28641.
-		goroot, err := exec.Command("go", "env", "GOROOT").Output()
-		if err != nil {
-			return fmt.Errorf("failed to detect an existing go installation for bootstrap: %v", err)
+		// Older make.rc scripts only look in $home/go1.4.
+	case minBootstrap[arch] != (Version{}):
+		// The make script finds the bootstrap toolchain itself, but one
+		// predating the port fails deep in the build.
+		return nil, checkPortBootstrap(runtime.GOOS, arch)
+	default:
+		return nil, nil
+	}
+	out, err := exec.Command("go", "env", "GOROOT", "GOARCH", "GOVERSION").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect an existing go installation for bootstrap: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	for len(lines) < 3 {
+		lines = append(lines, "") // GOVERSION is new in Go 1.16
+	}
+	goroot, goarch, goversion := strings.TrimSpace(lines[0]), strings.TrimSpace(lines[1]), strings.TrimSpace(lines[2])
+	env := []string{"GOROOT_BOOTSTRAP=" + goroot}
+
+	// On Windows on Arm, the bootstrap toolchain may well be an emulated
+	// amd64 one. Have it build a native toolchain, which it can do since
+	// Go 1.17 added windows/arm64.
+	if arch, _ := hostArch(); runtime.GOOS == "windows" && arch == "arm64" && goarch != "arm64" {
+		if v, err := ParseVersion(goversion); err != nil || v.Less(Version{Major: 1, Minor: 17}) {
+			return nil, fmt.Errorf("the bootstrap go in %s (%s/%s) can't build for windows/arm64; install Go 1.17 or later", goroot, goversion, goarch)
 		}
-		cmd.Env = append(os.Environ(), "GOROOT_BOOTSTRAP="+strings.TrimSpace(string(goroot)))
+		env = append(env, "GOHOSTARCH=arm64", "GOARCH=arm64")
+	}
+	return env, nil
+}
+
+// minBootstrap lists the ports that need a newer bootstrap toolchain than
+// the make script accepts in general, with the first release that can
+// build for them.
+var minBootstrap = map[string]Version{
+	"riscv64": {Major: 1, Minor: 14},
+	"loong64": {Major: 1, Minor: 19},
+}
+
+// checkPortBootstrap checks that the toolchain the make script will
+// bootstrap with, from $GOROOT_BOOTSTRAP or $PATH, is new enough for
+// goos/goarch. If there is none, the make script reports that itself.
+func checkPortBootstrap(goos, goarch string) error {
+	gobin := "go"
+	if root := os.Getenv("GOROOT_BOOTSTRAP"); root != "" {
+		gobin = filepath.Join(root, "bin", "go"+exe())
 	}
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to build go: %v", err)
+	out, err := exec.Command(gobin, "env", "GOROOT", "GOVERSION").Output()
+	if err != nil {
+		return nil
 	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	for len(lines) < 2 {
+		lines = append(lines, "")
+	}
+	return checkBootstrapVersion(goos, goarch, strings.TrimSpace(lines[0]), strings.TrimSpace(lines[1]))
+}
 
+// checkBootstrapVersion reports whether the go command of version
+// goversion in goroot can bootstrap a toolchain for goos/goarch.
+func checkBootstrapVersion(goos, goarch, goroot, goversion string) error {
+	min, ok := minBootstrap[goarch]
+	if !ok {
+		return nil
+	}
+	v, err := ParseVersion(goversion)
+	if goversion == "" {
+		// GOVERSION is new in Go 1.16.
+		v, err, goversion = Version{Major: 1, Minor: 15}, nil, "before go1.16"
+	}
+	if err != nil {
+		// A development toolchain; let the make script judge it.
+		return nil
+	}
+	if v.Less(min) {
+		return fmt.Errorf("the bootstrap go in %s (%s) can't build for %s/%s; install Go %d.%d or later, or point GOROOT_BOOTSTRAP at it", goroot, goversion, goos, goarch, min.Major, min.Minor)
+	}
 	return nil
 }
 
@@ -0,0 +1,133 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"debug/elf"
+	"encoding/binary"
+	"io"
+	"os"
+	"runtime"
+)
+
+// hostArch returns the GOARCH of the releases to install on this machine.
+// It is the architecture of the running binary, unless that is being
+// emulated on a machine that can run its own architecture natively, in
+// which case the native architecture is returned and emulated is set, or
+// the system's programs are the 32-bit variant of it.
+func hostArch() (arch string, emulated bool) {
+	return selectArch(runtime.GOARCH, nativeArch(), userlandArch(runtime.GOARCH))
+}
+
+// selectArch chooses the architecture to install for a binary built for
+// goarch on a machine whose native architecture, as reported by the
+// operating system, is native, and whose userland is built for userland.
+// Either may be empty if unknown.
+func selectArch(goarch, native, userland string) (arch string, emulated bool) {
+	if native == "arm64" && (goarch == "amd64" || goarch == "386") {
+		// An x86 binary running under emulation, as on Windows on Arm.
+		// Native releases are much faster.
+		return native, true
+	}
+	if userland != "" && userland == arch32(goarch) {
+		// A 32-bit userland on a 64-bit kernel, as on Raspberry Pi OS.
+		// This binary runs, but the system may lack what 64-bit releases
+		// need, so match the rest of the system.
+		return userland, false
+	}
+	return goarch, false
+}
+
+// arch32 returns the 32-bit variant of the 64-bit goarch, or "".
+func arch32(goarch string) string {
+	switch goarch {
+	case "amd64":
+		return "386"
+	case "arm64":
+		return "arm"
+	}
+	return ""
+}
+
+// userlandArch returns the architecture of the system's programs on Linux,
+// if it may be the 32-bit variant of goarch, or else "".
+func userlandArch(goarch string) string {
+	if runtime.GOOS != "linux" || arch32(goarch) == "" {
+		return ""
+	}
+	f, err := os.Open("/bin/sh")
+	if err != nil {
+		return ""
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	hdr := make([]byte, 20)
+	if _, err := io.ReadFull(f, hdr); err != nil {
+		return ""
+	}
+	return elfArch(hdr)
+}
+
+// elfArch returns the GOARCH of the ELF executable whose header starts with
+// hdr, if it is for one of the x86 or Arm architectures, or else "".
+func elfArch(hdr []byte) string {
+	if len(hdr) < 20 || string(hdr[:4]) != elf.ELFMAG {
+		return ""
+	}
+	class := elf.Class(hdr[elf.EI_CLASS])
+	var order binary.ByteOrder = binary.LittleEndian
+	if elf.Data(hdr[elf.EI_DATA]) == elf.ELFDATA2MSB {
+		order = binary.BigEndian
+	}
+	switch m := elf.Machine(order.Uint16(hdr[18:])); {
+	case m == elf.EM_386 && class == elf.ELFCLASS32:
+		return "386"
+	case m == elf.EM_X86_64 && class == elf.ELFCLASS64:
+		return "amd64"
+	case m == elf.EM_ARM && class == elf.ELFCLASS32:
+		return "arm"
+	case m == elf.EM_AARCH64 && class == elf.ELFCLASS64:
+		return "arm64"
+	}
+	return ""
+}
+
+// archiveName returns the conventional name of the release archive of
+// version for goos/goarch. The release listing knows the actual names;
+// see Downloader.listedArchive.
+func archiveName(version, goos, goarch string) string {
+	ext := ".tar.gz"
+	if goos == "windows" {
+		ext = ".zip"
+	}
+	if goos == "android" {
+		// There are no Android releases, but Linux ones run there.
+		goos = "linux"
+	}
+	return version + "." + goos + "-" + releaseArch(goos, goarch) + ext
+}
+
+// releaseArch returns the name releases use for goarch on goos.
+func releaseArch(goos, goarch string) string {
+	if goos == "linux" && goarch == "arm" {
+		return "armv6l"
+	}
+	return goarch
+}
+
+// releaseOSes returns the operating systems whose releases run on goos,
+// best first.
+func releaseOSes(goos string) []string {
+	switch goos {
+	case "illumos":
+		// illumos runs Solaris binaries, and older releases only
+		// had Solaris archives.
+		return []string{"illumos", "solaris"}
+	case "android":
+		return []string{"android", "linux"}
+	}
+	return []string{goos}
+}
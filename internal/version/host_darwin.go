@@ -0,0 +1,24 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"runtime"
+	"syscall"
+)
+
+// nativeArch returns the machine's native GOARCH, if the operating system
+// reports one that may differ from runtime.GOARCH, or else "".
+//
+// On Apple Silicon, an amd64 binary runs translated by Rosetta, which is
+// reported by the sysctl.proc_translated sysctl. Intel Macs don't have it.
+func nativeArch() string {
+	if runtime.GOARCH == "amd64" {
+		if t, err := syscall.SysctlUint32("sysctl.proc_translated"); err == nil && t == 1 {
+			return "arm64"
+		}
+	}
+	return ""
+}
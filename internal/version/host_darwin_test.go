@@ -0,0 +1,22 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestNativeArchDarwin(t *testing.T) {
+	// The probe must work on both Intel and Apple Silicon Macs, translated
+	// or not, and never report an emulated native binary.
+	switch got := nativeArch(); {
+	case got == "":
+	case got == "arm64" && runtime.GOARCH == "amd64":
+		t.Logf("running under Rosetta")
+	default:
+		t.Errorf("nativeArch() = %q for a %s binary", got, runtime.GOARCH)
+	}
+}
@@ -0,0 +1,14 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !darwin && !windows
+// +build !darwin,!windows
+
+package version
+
+// nativeArch returns the machine's native GOARCH, if the operating system
+// reports one that may differ from runtime.GOARCH, or else "".
+func nativeArch() string {
+	return ""
+}
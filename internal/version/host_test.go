@@ -0,0 +1,192 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestArchiveSelection(t *testing.T) {
+	tests := []struct {
+		goos, goarch, native, userland string
+		want                           string
+		emulated                       bool
+	}{
+		{"windows", "amd64", "", "", "go1.22.7.windows-amd64.zip", false},
+		{"windows", "amd64", "amd64", "", "go1.22.7.windows-amd64.zip", false},
+		{"windows", "386", "", "", "go1.22.7.windows-386.zip", false},
+		{"windows", "386", "amd64", "", "go1.22.7.windows-386.zip", false},
+		{"windows", "arm64", "", "", "go1.22.7.windows-arm64.zip", false},
+		{"windows", "arm64", "arm64", "", "go1.22.7.windows-arm64.zip", false},
+		{"windows", "amd64", "arm64", "", "go1.22.7.windows-arm64.zip", true},
+		{"windows", "386", "arm64", "", "go1.22.7.windows-arm64.zip", true},
+		{"linux", "amd64", "", "amd64", "go1.22.7.linux-amd64.tar.gz", false},
+		{"linux", "amd64", "", "386", "go1.22.7.linux-386.tar.gz", false},
+		{"linux", "arm", "", "arm", "go1.22.7.linux-armv6l.tar.gz", false},
+		{"linux", "arm64", "", "arm64", "go1.22.7.linux-arm64.tar.gz", false},
+		{"linux", "arm64", "", "arm", "go1.22.7.linux-armv6l.tar.gz", false}, // Raspberry Pi OS
+		{"linux", "arm64", "", "", "go1.22.7.linux-arm64.tar.gz", false},
+		{"linux", "arm64", "", "amd64", "go1.22.7.linux-arm64.tar.gz", false},
+		{"linux", "arm", "", "arm64", "go1.22.7.linux-armv6l.tar.gz", false},
+		{"darwin", "arm64", "", "", "go1.22.7.darwin-arm64.tar.gz", false},
+		{"darwin", "amd64", "", "", "go1.22.7.darwin-amd64.tar.gz", false},
+		{"darwin", "amd64", "arm64", "", "go1.22.7.darwin-arm64.tar.gz", true}, // Rosetta
+		{"freebsd", "arm", "", "", "go1.22.7.freebsd-arm.tar.gz", false},
+		{"android", "arm64", "", "", "go1.22.7.linux-arm64.tar.gz", false}, // Termux
+	}
+	for _, tt := range tests {
+		arch, emulated := selectArch(tt.goarch, tt.native, tt.userland)
+		if got := archiveName("go1.22.7", tt.goos, arch); got != tt.want || emulated != tt.emulated {
+			t.Errorf("%s/%s on native %q with %q userland: archive %s, emulated=%v; want %s, emulated=%v",
+				tt.goos, tt.goarch, tt.native, tt.userland, got, emulated, tt.want, tt.emulated)
+		}
+	}
+}
+
+func TestElfArch(t *testing.T) {
+	// Headers of /bin/sh on various systems.
+	tests := []struct {
+		file, want string
+	}{
+		{"debian-amd64-sh", "amd64"},
+		{"debian-i386-sh", "386"},
+		{"raspios-armhf-sh", "arm"},
+		{"raspios-arm64-sh", "arm64"},
+		{"alpine-x32-sh", ""},
+		{"debian-s390x-sh", ""},
+		{"script-sh", ""},
+	}
+	for _, tt := range tests {
+		hdr, err := ioutil.ReadFile(filepath.Join("testdata", "elf", tt.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := elfArch(hdr); got != tt.want {
+			t.Errorf("elfArch(%s) = %q; want %q", tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestDownloaderGOARCH(t *testing.T) {
+	ts := newTestServer(t)
+	d := ts.downloader(t, DownloaderOptions{GOARCH: "amd64"})
+	p, err := d.plan(context.Background(), t.TempDir(), "go1.99")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := archiveName("go1.99", getOS(), "amd64"); !strings.HasSuffix(p.URL, "/"+want) {
+		t.Errorf("plan URL = %s; want the explicitly requested %s", p.URL, want)
+	}
+}
+
+func TestSmokeTest(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+		t.Skipf("no shell scripts on %s", runtime.GOOS)
+	}
+	root := t.TempDir()
+	gobin := filepath.Join(root, "bin", "go")
+	if err := os.MkdirAll(filepath.Dir(gobin), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// A program for another machine fails the test.
+	hdr, err := ioutil.ReadFile(filepath.Join("testdata", "elf", "debian-s390x-sh"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(gobin, hdr, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := smokeTest(context.Background(), root, "linux", "s390x"); err == nil || !strings.Contains(err.Error(), envGOARCH) {
+		t.Errorf("smokeTest of foreign binary = %v; want error suggesting %s", err, envGOARCH)
+	}
+
+	// A program that runs but fails only warrants a warning.
+	if err := ioutil.WriteFile(gobin, []byte("#!/bin/sh\nexit 1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := smokeTest(context.Background(), root, "linux", "amd64"); err != nil {
+		t.Errorf("smokeTest of failing go = %v; want nil", err)
+	}
+}
+
+func TestListedArchive(t *testing.T) {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "releases", "ports.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	releases, err := parseCatalog(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := NewDownloader(DownloaderOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.catalog = &Catalog{releases: releases}
+
+	tests := []struct {
+		version, goos, goarch string
+		want                  string // archive name, or "" if unlisted, or "error"
+		oldest                string // for errors, the oldest release the error suggests
+	}{
+		{"go1.22.7", "linux", "amd64", "go1.22.7.linux-amd64.tar.gz", ""},
+		{"go1.22.7", "linux", "arm", "go1.22.7.linux-armv6l.tar.gz", ""},
+		{"go1.22.7", "illumos", "amd64", "go1.22.7.illumos-amd64.tar.gz", ""},
+		{"go1.22.7", "solaris", "amd64", "go1.22.7.solaris-amd64.tar.gz", ""},
+		{"go1.22.7", "netbsd", "amd64", "go1.22.7.netbsd-amd64.tar.gz", ""},
+		{"go1.22.7", "netbsd", "arm64", "go1.22.7.netbsd-arm64.tar.gz", ""},
+		{"go1.22.7", "netbsd", "386", "error", ""},
+		{"go1.22.7", "aix", "ppc64", "go1.22.7.aix-ppc64.tar.gz", ""},
+		{"go1.13", "illumos", "amd64", "go1.13.solaris-amd64.tar.gz", ""},
+		{"go1.13", "netbsd", "amd64", "error", "go1.22.7"},
+		{"go1.13", "windows", "amd64", "go1.13.windows-amd64.zip", ""},
+		{"go1.13", "windows", "arm64", "error", "go1.22.7"},
+		{"go1.22.7", "android", "arm", "go1.22.7.linux-armv6l.tar.gz", ""},
+		{"go1.22.7", "linux", "riscv64", "go1.22.7.linux-riscv64.tar.gz", ""},
+		{"go1.21.0", "linux", "riscv64", "error", "go1.22.7"},
+		{"go1.13", "linux", "riscv64", "error", "go1.22.7"},
+		{"go1.22.7", "linux", "loong64", "go1.22.7.linux-loong64.tar.gz", ""},
+		{"go1.21.0", "linux", "loong64", "go1.21.0.linux-loong64.tar.gz", ""},
+		{"go1.13", "linux", "loong64", "error", "go1.21.0"},
+		{"go1.99", "linux", "amd64", "", ""},
+	}
+	for _, tt := range tests {
+		f, err := d.listedArchive(context.Background(), tt.version, tt.goos, tt.goarch)
+		got := f.Filename
+		if err != nil {
+			got = "error"
+			msg := err.Error()
+			if !strings.Contains(msg, "no binary release for "+tt.goos+"/"+tt.goarch+" in "+tt.version) {
+				t.Errorf("listedArchive(%s, %s/%s) error = %v", tt.version, tt.goos, tt.goarch, err)
+			}
+			if hint := "the oldest release with one is " + tt.oldest; tt.oldest != "" && !strings.Contains(msg, hint) {
+				t.Errorf("listedArchive(%s, %s/%s) error = %v; want it to say %q", tt.version, tt.goos, tt.goarch, err, hint)
+			} else if tt.oldest == "" && strings.Contains(msg, "oldest") {
+				t.Errorf("listedArchive(%s, %s/%s) error = %v; want no oldest release", tt.version, tt.goos, tt.goarch, err)
+			}
+		}
+		if got != tt.want {
+			t.Errorf("listedArchive(%s, %s/%s) = %q; want %q", tt.version, tt.goos, tt.goarch, got, tt.want)
+		}
+	}
+
+	// Mirrors may carry other archives than the listing describes.
+	d.baseURL = "https://mirror.example.com/go/"
+	if f, err := d.listedArchive(context.Background(), "go1.13", "netbsd", "amd64"); err != nil || f.Filename != "" {
+		t.Errorf("listedArchive for a mirror = %+v, %v; want no answer", f, err)
+	}
+	// Unless the listing is mirrored with them.
+	d.opts.CatalogURL = "https://mirror.example.com/go/releases.json"
+	if f, err := d.listedArchive(context.Background(), "go1.13", "windows", "amd64"); err != nil || f.Filename != "go1.13.windows-amd64.zip" {
+		t.Errorf("listedArchive for a mirror with its listing = %+v, %v; want go1.13.windows-amd64.zip", f, err)
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var (
+	modkernel32         = syscall.NewLazyDLL("kernel32.dll")
+	procIsWow64Process2 = modkernel32.NewProc("IsWow64Process2")
+)
+
+// Machine types reported by IsWow64Process2.
+const (
+	imageFileMachineI386  = 0x014c
+	imageFileMachineARMNT = 0x01c4
+	imageFileMachineAMD64 = 0x8664
+	imageFileMachineARM64 = 0xaa64
+)
+
+// nativeArch returns the machine's native GOARCH, if the operating system
+// reports one that may differ from runtime.GOARCH, or else "".
+//
+// It uses IsWow64Process2, which unlike IsWow64Process also reports x64
+// emulation on arm64. It is missing before Windows 10 1511.
+func nativeArch() string {
+	if procIsWow64Process2.Find() != nil {
+		return ""
+	}
+	h, err := syscall.GetCurrentProcess()
+	if err != nil {
+		return ""
+	}
+	var processMachine, nativeMachine uint16
+	r, _, _ := procIsWow64Process2.Call(uintptr(h),
+		uintptr(unsafe.Pointer(&processMachine)),
+		uintptr(unsafe.Pointer(&nativeMachine)))
+	if r == 0 {
+		return ""
+	}
+	switch nativeMachine {
+	case imageFileMachineI386:
+		return "386"
+	case imageFileMachineARMNT:
+		return "arm"
+	case imageFileMachineAMD64:
+		return "amd64"
+	case imageFileMachineARM64:
+		return "arm64"
+	}
+	return ""
+}
@@ -0,0 +1,300 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// ReleaseInfo is what dl info reports about a release: what the release
+// listing says of it and, if it is installed, how it was installed.
+type ReleaseInfo struct {
+	Version string `json:"version"`
+
+	// Listed reports whether the release listing was consulted and has
+	// the release. If the listing couldn't be loaded, such as offline
+	// with nothing cached, Note says why and only Local is filled in.
+	Listed      bool       `json:"listed"`
+	Note        string     `json:"note,omitempty"`
+	Stable      bool       `json:"stable"`
+	ReleaseDate *time.Time `json:"release_date,omitempty"`
+
+	// Requirements are the minimum operating system versions the release
+	// is documented to need, where they differ from earlier releases'.
+	Requirements []string `json:"requirements,omitempty"`
+
+	Files []InfoFile `json:"files"`
+	Local LocalInfo  `json:"local"`
+}
+
+// An InfoFile is a downloadable artifact of a release, for dl info.
+type InfoFile struct {
+	Filename string `json:"filename"`
+	Platform string `json:"platform,omitempty"` // GOOS/GOARCH; empty for source archives
+	Kind     string `json:"kind"`
+	Size     int64  `json:"size"`
+	SHA256   string `json:"sha256"`
+}
+
+// The verification states of an installed release: its archive's
+// checksum was verified, and matches the release listing if the listing
+// was consulted; it was verified, but the listing now has another
+// checksum; it was installed without verifying it; or there's no record
+// of the install, such as one made before the journal was kept.
+const (
+	VerifiedOK       = "verified"
+	VerifiedMismatch = "mismatch"
+	VerifiedNot      = "unverified"
+	VerifiedUnknown  = "unknown"
+)
+
+// LocalInfo is the local state of a release, for dl info.
+type LocalInfo struct {
+	Installed   bool       `json:"installed"`
+	GOROOT      string     `json:"goroot,omitempty"`
+	InstalledAt *time.Time `json:"installed_at,omitempty"`
+	Size        int64      `json:"size,omitempty"`            // on disk
+	Platform    string     `json:"platform,omitempty"`        // as the journal recorded the install
+	SHA256      string     `json:"sha256,omitempty"`          // of the archive installed, if verified
+	Verified    string     `json:"verified,omitempty"`        // one of the Verified constants
+	Cached      []string   `json:"cached_archives,omitempty"` // archives of the release in the archive cache
+}
+
+// minimumOS lists, oldest first, the operating system versions Go
+// releases were documented to require, from the release in which each
+// requirement took effect.
+var minimumOS = []struct {
+	since string
+	goos  string
+	need  string
+}{
+	{"go1.11", "windows", "Windows 7 or Windows Server 2008 R2"},
+	{"go1.15", "darwin", "macOS 10.12 Sierra"},
+	{"go1.18", "darwin", "macOS 10.13 High Sierra"},
+	{"go1.21", "darwin", "macOS 10.15 Catalina"},
+	{"go1.21", "windows", "Windows 10 or Windows Server 2016"},
+	{"go1.23", "darwin", "macOS 11 Big Sur"},
+	{"go1.24", "linux", "Linux kernel 3.2"},
+	{"go1.25", "darwin", "macOS 12 Monterey"},
+}
+
+// requirements returns the minimum operating system versions v needs, as
+// far as minimumOS knows, one per operating system. The betas and release
+// candidates of a minor release have its requirements.
+func requirements(v Version) []string {
+	minor := Version{Major: v.Major, Minor: v.Minor}
+	var order []string
+	need := make(map[string]string)
+	for _, m := range minimumOS {
+		since, err := ParseVersion(m.since)
+		if err != nil {
+			panic(err)
+		}
+		if minor.Less(since) {
+			continue
+		}
+		if _, ok := need[m.goos]; !ok {
+			order = append(order, m.goos)
+		}
+		need[m.goos] = m.need
+	}
+	var out []string
+	for _, goos := range order {
+		out = append(out, need[goos]+" or later")
+	}
+	return out
+}
+
+// releaseInfo returns what dl info reports about the release name. The
+// release listing is taken from c; if it can't be loaded, only the local
+// state is reported. A release neither listed nor installed is an
+// *NotFoundError suggesting the nearest listed one. Its installs are
+// looked up in journal, and its cached archives in cacheDir, if set.
+func releaseInfo(ctx context.Context, c *Catalog, l *Locator, name string, journal []JournalEntry, cacheDir string) (ReleaseInfo, error) {
+	v, err := ParseVersion(name)
+	if err != nil {
+		return ReleaseInfo{}, err
+	}
+	info := ReleaseInfo{Version: v.String(), Requirements: requirements(v), Files: []InfoFile{}}
+	info.Local = localInfo(l, info.Version, journal, cacheDir)
+
+	rs, err := c.All(ctx, Filter{})
+	if err != nil {
+		info.Note = fmt.Sprintf("showing only the local state: %v", err)
+		return info, nil
+	}
+	var r *Release
+	for i := range rs {
+		if rs[i].Version.Compare(v) == 0 {
+			r = &rs[i]
+			break
+		}
+	}
+	if r == nil {
+		if !info.Local.Installed {
+			return ReleaseInfo{}, &NotFoundError{Name: name, Suggestion: nearestRelease(rs, v)}
+		}
+		info.Note = "the release listing doesn't have it"
+		return info, nil
+	}
+	info.Listed = true
+	info.Stable = r.Stable
+	if !r.ReleaseDate.IsZero() {
+		t := r.ReleaseDate
+		info.ReleaseDate = &t
+	}
+	for _, f := range r.Files {
+		platform := ""
+		if f.OS != "" {
+			platform = f.OS + "/" + f.Arch
+		}
+		info.Files = append(info.Files, InfoFile{f.Filename, platform, f.Kind, f.Size, f.SHA256})
+	}
+	if loc := &info.Local; loc.Verified == VerifiedOK {
+		goos, goarch := splitPlatform(loc.Platform)
+		if f, ok := r.Archive(goos, goarch); ok && !strings.EqualFold(f.SHA256, loc.SHA256) {
+			loc.Verified = VerifiedMismatch
+		}
+	}
+	return info, nil
+}
+
+// localInfo returns the local state of the release version: where it is
+// installed, how big it is, and, from the last successful install of it
+// in journal, when it was installed and whether its archive was verified.
+func localInfo(l *Locator, version string, journal []JournalEntry, cacheDir string) LocalInfo {
+	var loc LocalInfo
+	if cacheDir != "" {
+		entries, _ := cacheEntries(cacheDir)
+		for _, e := range entries {
+			if e.Version == version {
+				loc.Cached = append(loc.Cached, e.Name)
+			}
+		}
+	}
+	root, err := whichGoroot(l, version)
+	if err != nil {
+		return loc
+	}
+	loc.Installed = true
+	loc.GOROOT = root
+	loc.Size = diskUsage(root)
+	loc.Verified = VerifiedUnknown
+	for i := len(journal) - 1; i >= 0; i-- {
+		e := journal[i]
+		if e.Toolchain != version || e.Error != "" {
+			continue
+		}
+		t := e.Time
+		loc.InstalledAt = &t
+		loc.Platform = e.Platform
+		loc.SHA256 = e.SHA256
+		loc.Verified = VerifiedNot
+		if e.SHA256 != "" {
+			loc.Verified = VerifiedOK
+		}
+		break
+	}
+	if loc.InstalledAt == nil {
+		if fi, err := os.Stat(filepath.Join(root, unpackedOkay)); err == nil {
+			t := fi.ModTime()
+			loc.InstalledAt = &t
+		}
+	}
+	return loc
+}
+
+// splitPlatform splits a platform such as linux/amd64 into its GOOS and
+// GOARCH.
+func splitPlatform(p string) (goos, goarch string) {
+	if i := strings.Index(p, "/"); i >= 0 {
+		return p[:i], p[i+1:]
+	}
+	return p, ""
+}
+
+// writeReleaseInfo writes info to w in the form dl info prints it.
+func writeReleaseInfo(w io.Writer, info ReleaseInfo) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Release:\t%s\n", info.Version)
+	if info.Note != "" {
+		fmt.Fprintf(tw, "Note:\t%s\n", info.Note)
+	}
+	if info.Listed {
+		stability := "stable"
+		if !info.Stable {
+			stability = "unstable (beta or release candidate)"
+		}
+		fmt.Fprintf(tw, "Stability:\t%s\n", stability)
+		if info.ReleaseDate != nil {
+			fmt.Fprintf(tw, "Released:\t%s\n", info.ReleaseDate.Format("2006-01-02"))
+		}
+	}
+	for i, r := range info.Requirements {
+		label := ""
+		if i == 0 {
+			label = "Requires:"
+		}
+		fmt.Fprintf(tw, "%s\t%s\n", label, r)
+	}
+
+	loc := info.Local
+	if !loc.Installed {
+		fmt.Fprintf(tw, "Installed:\tno\n")
+	} else {
+		fmt.Fprintf(tw, "Installed:\t%s\n", loc.GOROOT)
+		if loc.InstalledAt != nil {
+			fmt.Fprintf(tw, "Installed at:\t%s\n", loc.InstalledAt.Local().Format("2006-01-02 15:04:05"))
+		}
+		fmt.Fprintf(tw, "Size on disk:\t%s\n", formatByteSize(loc.Size))
+		fmt.Fprintf(tw, "Verified:\t%s\n", describeVerified(loc))
+	}
+	for i, name := range loc.Cached {
+		label := ""
+		if i == 0 {
+			label = "Cached:"
+		}
+		fmt.Fprintf(tw, "%s\t%s\n", label, name)
+	}
+	tw.Flush()
+
+	if !info.Listed {
+		return
+	}
+	fmt.Fprintln(w)
+	if len(info.Files) == 0 {
+		fmt.Fprintln(w, "The release listing has no files of this release.")
+		return
+	}
+	tw = tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "FILENAME\tPLATFORM\tKIND\tSIZE\tSHA256")
+	for _, f := range info.Files {
+		platform := f.Platform
+		if platform == "" {
+			platform = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", f.Filename, platform, f.Kind, formatByteSize(f.Size), f.SHA256)
+	}
+	tw.Flush()
+}
+
+func describeVerified(loc LocalInfo) string {
+	switch loc.Verified {
+	case VerifiedOK:
+		return fmt.Sprintf("yes, %s archive sha256 %s", loc.Platform, loc.SHA256)
+	case VerifiedMismatch:
+		return fmt.Sprintf("the %s archive installed had sha256 %s, which the release listing no longer has", loc.Platform, loc.SHA256)
+	case VerifiedNot:
+		return "no, the archive's checksum wasn't checked"
+	}
+	return "unknown, the install journal has no record of it"
+}
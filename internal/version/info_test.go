@@ -0,0 +1,143 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRequirements(t *testing.T) {
+	for name, want := range map[string][]string{
+		"go1.9":     nil,
+		"go1.17.13": {"Windows 7 or Windows Server 2008 R2 or later", "macOS 10.12 Sierra or later"},
+		"go1.21rc2": {"Windows 10 or Windows Server 2016 or later", "macOS 10.15 Catalina or later"},
+		"go1.24.1":  {"Windows 10 or Windows Server 2016 or later", "macOS 11 Big Sur or later", "Linux kernel 3.2 or later"},
+	} {
+		v, err := ParseVersion(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := requirements(v); !reflect.DeepEqual(got, want) {
+			t.Errorf("requirements(%s) = %q; want %q", name, got, want)
+		}
+	}
+}
+
+func TestReleaseInfo(t *testing.T) {
+	ctx := context.Background()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	sdk := t.TempDir()
+	makeTree(t, sdk, map[string]string{
+		"go1.22.7/" + unpackedOkay:  "",
+		"go1.22.7/bin/go":           "gobinary",
+		"go1.21.13/" + unpackedOkay: "",
+		"go1.20.14/" + unpackedOkay: "", // not listed
+	})
+	cache := t.TempDir()
+	makeTree(t, cache, map[string]string{
+		"go1.22.7.linux-amd64.tar.gz":        "archive",
+		"go1.22.7.linux-amd64.tar.gz.sha256": "dd",
+	})
+	l := &Locator{Root: sdk}
+	installed := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	journal := []JournalEntry{
+		{Time: installed, Toolchain: "go1.22.7", SHA256: "dd", Platform: "linux/amd64"},
+		{Time: installed.Add(time.Hour), Toolchain: "go1.22.7", Platform: "linux/amd64", Error: "interrupted"},
+		{Time: installed, Toolchain: "go1.21.13", SHA256: "ff", Platform: "linux/amd64"},
+	}
+	c := newCatalogServer(t).catalog(t.TempDir())
+
+	info, err := releaseInfo(ctx, c, l, "go1.22.7", journal, cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.Listed || !info.Stable || info.Note != "" || len(info.Files) != 3 {
+		t.Errorf("info = %+v; want the listed, stable release with 3 files", info)
+	}
+	if f := info.Files[2]; f != (InfoFile{"go1.22.7.windows-arm64.zip", "windows/arm64", "archive", 14, "ee"}) {
+		t.Errorf("third file = %+v", f)
+	}
+	if f := info.Files[0]; f.Platform != "" || f.Kind != "source" {
+		t.Errorf("source file = %+v; want no platform", f)
+	}
+	want := LocalInfo{
+		Installed:   true,
+		GOROOT:      filepath.Join(sdk, "go1.22.7"),
+		InstalledAt: &installed,
+		Size:        int64(len("gobinary")),
+		Platform:    "linux/amd64",
+		SHA256:      "dd",
+		Verified:    VerifiedOK,
+		Cached:      []string{"go1.22.7.linux-amd64.tar.gz"},
+	}
+	if !reflect.DeepEqual(info.Local, want) {
+		t.Errorf("local = %+v\nwant %+v", info.Local, want)
+	}
+
+	// The listing pins another checksum than the one installed.
+	info, err = releaseInfo(ctx, c, l, "go1.21.13", journal, "")
+	if err != nil || info.Local.Verified != VerifiedMismatch {
+		t.Errorf("go1.21.13: %+v, %v; want a mismatch", info.Local, err)
+	}
+
+	// Installed, but not listed nor in the journal.
+	info, err = releaseInfo(ctx, c, l, "go1.20.14", journal, "")
+	if err != nil || info.Listed || info.Note == "" || info.Local.Verified != VerifiedUnknown || info.Local.InstalledAt == nil {
+		t.Errorf("go1.20.14: %+v, %v; want only the local state, from the install marker", info, err)
+	}
+
+	// Archived releases are listed too, just without files.
+	info, err = releaseInfo(ctx, c, l, "go1.9", nil, "")
+	if err != nil || !info.Listed || info.Local.Installed || len(info.Files) != 0 {
+		t.Errorf("go1.9: %+v, %v; want listed and not installed", info, err)
+	}
+
+	var nf *NotFoundError
+	_, err = releaseInfo(ctx, c, l, "go1.22.70", nil, "")
+	if !errors.As(err, &nf) || nf.Suggestion != "go1.22.7" || !strings.Contains(err.Error(), "did you mean go1.22.7?") {
+		t.Errorf("go1.22.70: %v; want a not-found error suggesting go1.22.7", err)
+	}
+
+	// Offline, with nothing cached.
+	offline := &Catalog{CacheDir: t.TempDir(), Offline: true}
+	info, err = releaseInfo(ctx, offline, l, "go1.22.7", journal, "")
+	if err != nil || info.Listed || info.Note == "" || !info.Local.Installed {
+		t.Errorf("offline: %+v, %v; want only the local state, with a note", info, err)
+	}
+}
+
+func TestNearestRelease(t *testing.T) {
+	c := newCatalogServer(t).catalog(t.TempDir())
+	rs, err := c.All(context.Background(), Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{
+		"go1.22.70": "go1.22.7",
+		"go1.23.4":  "go1.23rc1",
+		"go1.99":    "go1.23rc1",
+		"go1.15.2":  "go1.9",
+		"go1.2":     "go1.9",
+	} {
+		v, err := ParseVersion(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := nearestRelease(rs, v); got != want {
+			t.Errorf("nearestRelease(%s) = %q; want %q", name, got, want)
+		}
+	}
+	if got := nearestRelease(nil, Version{Major: 1, Minor: 22}); got != "" {
+		t.Errorf("nearestRelease of no releases = %q; want none", got)
+	}
+}
@@ -0,0 +1,247 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testFiles is the content of the fake Go release served by newTestServer.
+var testFiles = map[string]string{
+	"go/VERSION":   "go1.99",
+	"go/bin/go":    "#!/bin/sh\necho fake go\n",
+	"go/src/a.go":  "package a\n",
+	"go/api/x.txt": "pkg a\n",
+}
+
+// makeTestArchive returns a tar.gz, or a zip when zipped is set, holding files.
+func makeTestArchive(t testing.TB, files map[string]string, zipped bool) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if zipped {
+		zw := zip.NewWriter(&buf)
+		for name, body := range files {
+			w, err := zw.Create(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := w.Write([]byte(body)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := zw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+	zw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+	for name, body := range files {
+		hdr := &tar.Header{Name: name, Mode: 0755, Size: int64(len(body)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// testServer serves a fake Go release archive and its checksum for any
+// requested archive name, and records the requests it sees.
+type testServer struct {
+	*httptest.Server
+	tar, zip []byte
+	etag     string // sent with the archives, if set
+
+	mu   sync.Mutex
+	reqs []string // "METHOD path"
+}
+
+func newTestServer(t testing.TB) *testServer {
+	ts := &testServer{
+		tar: makeTestArchive(t, testFiles, false),
+		zip: makeTestArchive(t, testFiles, true),
+	}
+	ts.Server = httptest.NewServer(http.HandlerFunc(ts.serve))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func (ts *testServer) serve(w http.ResponseWriter, r *http.Request) {
+	ts.mu.Lock()
+	ts.reqs = append(ts.reqs, r.Method+" "+r.URL.Path)
+	ts.mu.Unlock()
+
+	name := strings.TrimSuffix(r.URL.Path, ".sha256")
+	var body []byte
+	switch {
+	case strings.HasSuffix(name, ".tar.gz"):
+		body = ts.tar
+	case strings.HasSuffix(name, ".zip"):
+		body = ts.zip
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if name != r.URL.Path {
+		fmt.Fprintf(w, "%x\n", sha256.Sum256(body))
+		return
+	}
+	if ts.etag != "" {
+		w.Header().Set("ETag", ts.etag)
+	}
+	http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(body))
+}
+
+func (ts *testServer) requests() []string {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return append([]string(nil), ts.reqs...)
+}
+
+// redirectTransport sends every request to the test server, whatever host
+// it was addressed to, so code under test keeps using its real URLs.
+type redirectTransport struct {
+	target *url.URL
+	rt     http.RoundTripper
+}
+
+func (r redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	out.URL.Scheme = r.target.Scheme
+	out.URL.Host = r.target.Host
+	res, err := r.rt.RoundTrip(out)
+	if res != nil {
+		// As if the host asked had answered.
+		res.Request = req
+	}
+	return res, err
+}
+
+// client returns a client that routes all traffic to ts.
+func (ts *testServer) client() *http.Client {
+	u, _ := url.Parse(ts.URL)
+	return &http.Client{Transport: redirectTransport{u, http.DefaultTransport}}
+}
+
+// downloader returns a Downloader with opts whose requests all go to ts.
+func (ts *testServer) downloader(t testing.TB, opts DownloaderOptions) *Downloader {
+	t.Helper()
+	opts.Client = ts.client()
+	d, err := NewDownloader(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Keep the user's cached release listing out of tests.
+	d.catalog.CacheDir = t.TempDir()
+	return d
+}
+
+func TestInstallHTTPTest(t *testing.T) {
+	ts := newTestServer(t)
+	dir := t.TempDir()
+
+	d := ts.downloader(t, DownloaderOptions{})
+	if err := d.install(context.Background(), dir, "go1.99"); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+	got, err := ioutil.ReadFile(filepath.Join(dir, "bin", "go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != testFiles["go/bin/go"] {
+		t.Errorf("bin/go = %q; want %q", got, testFiles["go/bin/go"])
+	}
+	if _, err := os.Stat(filepath.Join(dir, unpackedOkay)); err != nil {
+		t.Errorf("missing %s marker: %v", unpackedOkay, err)
+	}
+	if len(ts.requests()) == 0 {
+		t.Errorf("no requests reached the injected client's server")
+	}
+
+	// A second install is a no-op that doesn't touch the network.
+	n := len(ts.requests())
+	if err := d.install(context.Background(), dir, "go1.99"); err != nil {
+		t.Fatalf("second install: %v", err)
+	}
+	if reqs := ts.requests(); len(reqs) != n {
+		t.Errorf("second install made requests %q; want none", reqs[n:])
+	}
+}
+
+func TestInstallNotFound(t *testing.T) {
+	ts := &testServer{Server: httptest.NewServer(http.NotFoundHandler())}
+	defer ts.Close()
+	err := ts.downloader(t, DownloaderOptions{}).install(context.Background(), t.TempDir(), "go1.99")
+	if err == nil || !strings.Contains(err.Error(), "no binary release") {
+		t.Errorf("install = %v; want no binary release error", err)
+	}
+}
+
+func TestInstallEvents(t *testing.T) {
+	ts := newTestServer(t)
+	dir := t.TempDir()
+	events := make(chan Event, 1000)
+	d := ts.downloader(t, DownloaderOptions{Events: events})
+	if err := d.install(context.Background(), dir, "go1.99"); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+	close(events)
+
+	var got []Event
+	for e := range events {
+		got = append(got, e)
+	}
+	if len(got) < 4 {
+		t.Fatalf("got events %#v; want at least resolution, verification, unpack and completion", got)
+	}
+	if e, ok := got[0].(ResolutionDone); !ok || e.Version != "go1.99" || e.Size != int64(len(ts.tar)) {
+		t.Errorf("first event = %#v; want ResolutionDone for go1.99 of size %d", got[0], len(ts.tar))
+	}
+	var verified, unpacked bool
+	for _, e := range got {
+		switch e := e.(type) {
+		case VerificationResult:
+			verified = e.Err == nil
+		case UnpackProgress:
+			unpacked = e.Files == len(testFiles)
+		case Failed:
+			t.Errorf("unexpected %#v", e)
+		}
+	}
+	if !verified {
+		t.Errorf("no successful VerificationResult in %#v", got)
+	}
+	if !unpacked {
+		t.Errorf("no UnpackProgress covering all %d files in %#v", len(testFiles), got)
+	}
+	if e, ok := got[len(got)-1].(Completed); !ok || e.GOROOT != dir {
+		t.Errorf("last event = %#v; want Completed in %s", got[len(got)-1], dir)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// nonInteractive is set by the -non-interactive flag of the commands.
+var nonInteractive bool
+
+var (
+	interactiveOnce sync.Once
+	interactiveOK   bool
+)
+
+// interactive reports whether the commands may ask the user questions.
+// It is decided once, the first time it is asked, so flags must have been
+// parsed by then. Every prompt goes through ask, which consults it; where
+// prompts are disabled, each question instead takes its documented
+// default or fails, naming the flag that answers it.
+func interactive() bool {
+	interactiveOnce.Do(func() {
+		interactiveOK = promptsAllowed(os.Getenv, isTerminal(os.Stdin), isTerminal(os.Stderr), nonInteractive)
+	})
+	return interactiveOK
+}
+
+// promptsAllowed reports whether prompts are allowed: only if both
+// standard input and standard error are terminals, the CI variable that
+// CI systems set isn't true, and -non-interactive wasn't given.
+func promptsAllowed(getenv func(string) string, stdinTTY, stderrTTY, disabled bool) bool {
+	if ci, err := strconv.ParseBool(getenv("CI")); err == nil && ci {
+		return false
+	}
+	return stdinTTY && stderrTTY && !disabled
+}
+
+// A nonInteractiveError reports a question that was not asked because
+// prompts are disabled.
+type nonInteractiveError struct {
+	Question string
+	Flag     string // the flag that answers the question
+}
+
+func (e *nonInteractiveError) Error() string {
+	return fmt.Sprintf("not running interactively, so can't ask %q; use %s to answer it", e.Question, e.Flag)
+}
+
+// ask asks question on standard error and returns the line answered on
+// standard input, trimmed, or "" at end of input. If prompts are
+// disabled it returns a *nonInteractiveError naming flag instead.
+func ask(question, flag string) (string, error) {
+	if !interactive() {
+		return "", &nonInteractiveError{Question: strings.TrimSpace(question), Flag: flag}
+	}
+	fmt.Fprint(os.Stderr, question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	return strings.TrimSpace(answer), nil
+}
@@ -0,0 +1,173 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPromptsAllowed(t *testing.T) {
+	tests := []struct {
+		ci                  string
+		stdinTTY, stderrTTY bool
+		disabled            bool
+		want                bool
+	}{
+		{"", true, true, false, true},
+		{"", false, true, false, false},
+		{"", true, false, false, false},
+		{"", true, true, true, false},
+		{"true", true, true, false, false},
+		{"1", true, true, false, false},
+		{"false", true, true, false, true},
+		{"woodpecker", true, true, false, true}, // not a boolean
+	}
+	for _, tt := range tests {
+		getenv := func(key string) string {
+			if key == "CI" {
+				return tt.ci
+			}
+			return ""
+		}
+		if got := promptsAllowed(getenv, tt.stdinTTY, tt.stderrTTY, tt.disabled); got != tt.want {
+			t.Errorf("promptsAllowed(CI=%q, %v, %v, %v) = %v; want %v", tt.ci, tt.stdinTTY, tt.stderrTTY, tt.disabled, got, tt.want)
+		}
+	}
+}
+
+func TestAskNonInteractive(t *testing.T) {
+	// Standard input is not a terminal under go test.
+	_, err := ask("Type yes to remove all of the above: ", "-y")
+	var ne *nonInteractiveError
+	if !errors.As(err, &ne) || ne.Flag != "-y" {
+		t.Errorf("ask = %v; want a *nonInteractiveError naming -y", err)
+	}
+}
+
+// TestNonInteractiveCommands runs commands that would prompt with standard
+// input at end of file, and checks that each fails at once, naming the
+// flag that would have answered the question.
+func TestNonInteractiveCommands(t *testing.T) {
+	switch os.Getenv("GODL_TEST_MAIN") {
+	case "dl":
+		os.Args = append([]string{"dl"}, strings.Fields(os.Getenv("GODL_TEST_ARGS"))...)
+		RunDL()
+		return
+	case "gotip":
+		os.Args = append([]string{"gotip"}, strings.Fields(os.Getenv("GODL_TEST_ARGS"))...)
+		RunTip()
+		return
+	}
+	if testing.Short() {
+		t.Skip("skipping subprocess test in short mode")
+	}
+
+	tests := []struct {
+		main, args string
+		want       string
+	}{
+		{"dl", "purge", "use -y to answer it"},
+		{"gotip", "download 12345", "use -y to answer it"},
+		{"dl", "", "usage: dl"},
+	}
+	for _, tt := range tests {
+		home := t.TempDir()
+		sdk := filepath.Join(home, "sdk")
+		makeTree(t, sdk, map[string]string{"go1.22.7/" + unpackedOkay: ""})
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		cmd := exec.CommandContext(ctx, os.Args[0], "-test.run=^TestNonInteractiveCommands$")
+		cmd.Env = append(os.Environ(),
+			"GODL_TEST_MAIN="+tt.main,
+			"GODL_TEST_ARGS="+tt.args,
+			"HOME="+home,
+			"XDG_CONFIG_HOME="+filepath.Join(home, "config"),
+			"GODL_CONFIG=",
+			"GODL_SDK_DIR="+sdk,
+			"GODL_CACHE_DIR="+filepath.Join(home, "cache"),
+		)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		err := cmd.Run()
+		blocked := ctx.Err() != nil
+		cancel()
+		if blocked {
+			t.Errorf("%s %s blocked", tt.main, tt.args)
+			continue
+		}
+		if err == nil || !strings.Contains(stderr.String(), tt.want) {
+			t.Errorf("%s %s = %v, with output:\n%s\nwant failure mentioning %q", tt.main, tt.args, err, stderr.String(), tt.want)
+		}
+		if _, err := os.Stat(filepath.Join(sdk, "go1.22.7")); err != nil {
+			t.Errorf("%s %s removed the installed release: %v", tt.main, tt.args, err)
+		}
+	}
+}
+
+func TestDLRunsRelease(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping subprocess test in short mode")
+	}
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+		t.Skipf("the test archive's go command is a shell script")
+	}
+	ts := newTestServer(t)
+	home := t.TempDir()
+	sdk := filepath.Join(home, "sdk")
+	dl := func(args string) (stdout, stderr string, err error) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		cmd := exec.CommandContext(ctx, os.Args[0], "-test.run=^TestNonInteractiveCommands$")
+		cmd.Env = append(os.Environ(),
+			"GODL_TEST_MAIN=dl",
+			"GODL_TEST_ARGS="+args,
+			"HOME="+home,
+			"XDG_CONFIG_HOME="+filepath.Join(home, "config"),
+			"GODL_CONFIG=",
+			"GODL_SDK_DIR="+sdk,
+			"GODL_BASE_URL="+ts.URL,
+		)
+		var out, errOut bytes.Buffer
+		cmd.Stdout = &out
+		cmd.Stderr = &errOut
+		err = cmd.Run()
+		return out.String(), errOut.String(), err
+	}
+
+	// Installed on demand, then run.
+	stdout, stderr, err := dl("go1.99.0 version")
+	if err != nil || !strings.HasPrefix(stdout, "fake go\n") || !strings.Contains(stderr, "downloading it first") {
+		t.Fatalf("dl go1.99.0 version = %v, with output:\n%s%s\nwant an install and then the go command", err, stdout, stderr)
+	}
+	if _, err := os.Stat(filepath.Join(sdk, "go1.99.0", unpackedOkay)); err != nil {
+		t.Errorf("dl go1.99.0 didn't install go1.99.0: %v", err)
+	}
+	// Already installed.
+	stdout, stderr, err = dl("go1.99.0 version")
+	if err != nil || !strings.HasPrefix(stdout, "fake go\n") || strings.Contains(stderr, "downloading") {
+		t.Errorf("second dl go1.99.0 version = %v, with output:\n%s%s\nwant just the go command", err, stdout, stderr)
+	}
+
+	// An alias of it.
+	makeTree(t, filepath.Join(home, "config", "godl"), map[string]string{"config": "alias.work = go1.99.0\n"})
+	stdout, stderr, err = dl("work version")
+	if err != nil || !strings.HasPrefix(stdout, "fake go\n") {
+		t.Errorf("dl work version = %v, with output:\n%s%s\nwant go1.99.0's go command", err, stdout, stderr)
+	}
+
+	_, stderr, err = dl("1.99.0 version")
+	var ee *exec.ExitError
+	if !errors.As(err, &ee) || ee.ExitCode() != ExitUsage || !strings.Contains(stderr, "did you mean dl go1.99.0?") {
+		t.Errorf("dl 1.99.0 = %v, with output:\n%s\nwant a usage error suggesting go1.99.0", err, stderr)
+	}
+}
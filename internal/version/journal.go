@@ -0,0 +1,245 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// A JournalEntry records one install by the go1.N.M or gotip commands, as
+// a line of the install journal. Its JSON form is stable: fields may be
+// added, but not renamed or removed.
+type JournalEntry struct {
+	Time      time.Time `json:"time"`
+	Toolchain string    `json:"toolchain"`        // such as "go1.22.7" or "gotip"
+	Target    string    `json:"target,omitempty"` // gotip's CL number or branch, if not master
+	URL       string    `json:"url,omitempty"`    // the archive, or the repository gotip was fetched from
+	SHA256    string    `json:"sha256,omitempty"` // of the archive, if it was verified
+	Commit    string    `json:"commit,omitempty"` // gotip's git commit
+	Platform  string    `json:"platform"`         // GOOS/GOARCH installed
+	Tool      string    `json:"tool"`             // version of this tool
+
+	// Error and ErrorClass are set if the install failed. ErrorClass is
+	// one of the errorClass values, for filtering.
+	Error      string `json:"error,omitempty"`
+	ErrorClass string `json:"error_class,omitempty"`
+}
+
+// maxJournalSize is the size past which the journal is rotated: moved to
+// the same name with a ".1" suffix, replacing any earlier one, so that at
+// most about twice this much history is kept.
+const maxJournalSize = 1 << 20
+
+// JournalFile returns the install journal's file: godl/journal.jsonl in
+// the user's configuration directory.
+func JournalFile() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "godl", "journal.jsonl"), nil
+}
+
+// appendJournal appends e to the journal file, rotating it first if it
+// has grown past maxJournalSize.
+func appendJournal(file string, e JournalEntry) error {
+	return appendJSONLine(file, e, maxJournalSize)
+}
+
+// appendJSONLine appends v, as a line of JSON, to file, first moving file
+// to the same name with a ".1" suffix if it has grown past max. Each line
+// is a single write to a file opened for appending, so lines from
+// concurrent processes don't interleave.
+func appendJSONLine(file string, v interface{}, max int64) error {
+	line, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	if fi, err := os.Stat(file); err == nil && fi.Size() >= max {
+		if err := os.Rename(file, file+".1"); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ReadJournal returns the entries of the journal file, including those
+// rotated out of it, oldest first. A missing journal has no entries.
+// Lines that can't be parsed, such as one cut short by a full disk, are
+// skipped.
+func ReadJournal(file string) ([]JournalEntry, error) {
+	var entries []JournalEntry
+	for _, name := range []string{file + ".1", file} {
+		f, err := os.Open(name)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		sc := bufio.NewScanner(f)
+		sc.Buffer(nil, maxJournalSize)
+		for sc.Scan() {
+			var e JournalEntry
+			if json.Unmarshal(sc.Bytes(), &e) == nil {
+				entries = append(entries, e)
+			}
+		}
+		err = sc.Err()
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %v", name, err)
+		}
+	}
+	return entries, nil
+}
+
+// errorClass classifies the error of an install that failed in phase:
+// "offline", "canceled", "checksum", "http", "network", or else the phase.
+func errorClass(err error, phase Phase) string {
+	var (
+		oerr *OfflineError
+		cerr *ChecksumError
+		serr *statusError
+		nerr net.Error
+	)
+	switch {
+	case errors.As(err, &oerr):
+		return "offline"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.As(err, &cerr):
+		return "checksum"
+	case errors.As(err, &serr):
+		return "http"
+	case errors.As(err, &nerr):
+		return "network"
+	}
+	return string(phase)
+}
+
+// A journalRecorder fills in a JournalEntry from the events of an install.
+type journalRecorder struct {
+	events chan Event
+	done   chan struct{}
+	entry  JournalEntry
+	record bool // whether the install did anything worth recording
+}
+
+// newJournalRecorder returns a recorder for an install of toolchain,
+// which must send its events to r.events.
+func newJournalRecorder(toolchain string) *journalRecorder {
+	r := &journalRecorder{
+		events: make(chan Event, 64),
+		done:   make(chan struct{}),
+		entry:  JournalEntry{Toolchain: toolchain, Tool: toolBuildInfo().Version},
+	}
+	go func() {
+		defer close(r.done)
+		for ev := range r.events {
+			switch ev := ev.(type) {
+			case ResolutionDone:
+				r.entry.URL = ev.URL
+				r.record = true
+			case VerificationResult:
+				if ev.Err == nil {
+					r.entry.SHA256 = ev.SHA256
+				}
+			case Failed:
+				r.entry.Error = ev.Err.Error()
+				r.entry.ErrorClass = errorClass(ev.Err, ev.Phase)
+				r.record = true
+			}
+		}
+	}()
+	return r
+}
+
+// wait waits for the install's events, and returns the entry built from
+// them, and whether the install did anything worth recording: a release
+// that was already installed did not.
+func (r *journalRecorder) wait() (JournalEntry, bool) {
+	close(r.events)
+	<-r.done
+	return r.entry, r.record
+}
+
+// recordInstall appends e to the journal, stamped with the current time.
+// Failing to write the journal doesn't fail the install, but is reported.
+func recordInstall(e JournalEntry) {
+	e.Time = time.Now().UTC()
+	file, err := JournalFile()
+	if err == nil {
+		err = appendJournal(file, e)
+	}
+	if err != nil {
+		log.Printf("Warning: recording the install in the journal: %v", err)
+	}
+}
+
+// filterJournal returns the entries of toolchain, if non-empty, made at
+// or after since, and only the failed ones if failed is set.
+func filterJournal(entries []JournalEntry, toolchain string, since time.Time, failed bool) []JournalEntry {
+	var out []JournalEntry
+	for _, e := range entries {
+		if toolchain != "" && e.Toolchain != toolchain || e.Time.Before(since) || failed && e.Error == "" {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// writeJournal prints entries in the format of dl history.
+func writeJournal(w io.Writer, entries []JournalEntry) {
+	for _, e := range entries {
+		result := "ok"
+		if e.Error != "" {
+			result = "FAILED (" + e.ErrorClass + ")"
+		}
+		id := e.SHA256
+		if e.Commit != "" {
+			id = "commit " + e.Commit
+		} else if id != "" {
+			id = "sha256 " + id
+		}
+		name := e.Toolchain
+		if e.Target != "" {
+			name += " " + e.Target
+		}
+		fmt.Fprintf(w, "%s  %-12s  %s  %s", e.Time.Local().Format("2006-01-02 15:04:05"), name, e.Platform, result)
+		if id != "" {
+			fmt.Fprintf(w, "  %s", id)
+		}
+		if e.URL != "" {
+			fmt.Fprintf(w, "  %s", e.URL)
+		}
+		fmt.Fprintln(w)
+		if e.Error != "" {
+			fmt.Fprintf(w, "    %s\n", e.Error)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJournalRotation(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "godl", "journal.jsonl")
+	big := JournalEntry{Toolchain: "go1.22.7", Error: strings.Repeat("x", maxJournalSize/2)}
+	for i := 0; i < 3; i++ {
+		if err := appendJournal(file, big); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := appendJournal(file, JournalEntry{Toolchain: "gotip"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(file + ".1"); err != nil {
+		t.Errorf("journal was not rotated: %v", err)
+	}
+	entries, err := ReadJournal(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) < 2 || len(entries) > 4 || entries[len(entries)-1].Toolchain != "gotip" {
+		t.Errorf("after rotation, ReadJournal returned %d entries, last %+v; want gotip last", len(entries), entries[len(entries)-1])
+	}
+
+	if entries, err := ReadJournal(filepath.Join(t.TempDir(), "missing")); err != nil || len(entries) != 0 {
+		t.Errorf("ReadJournal of a missing journal = %v, %v; want none", entries, err)
+	}
+}
+
+func TestErrorClass(t *testing.T) {
+	tests := []struct {
+		err   error
+		phase Phase
+		want  string
+	}{
+		{&OfflineError{URL: "https://dl.google.com/go/"}, PhaseResolve, "offline"},
+		{fmt.Errorf("fetching: %w", context.Canceled), PhaseDownload, "canceled"},
+		{&ChecksumError{Want: "a", Got: "b"}, PhaseVerify, "checksum"},
+		{&statusError{Code: 404, Status: "404 Not Found"}, PhaseResolve, "http"},
+		{errors.New("disk full"), PhaseUnpack, "unpack"},
+	}
+	for _, tt := range tests {
+		if got := errorClass(tt.err, tt.phase); got != tt.want {
+			t.Errorf("errorClass(%v, %s) = %q; want %q", tt.err, tt.phase, got, tt.want)
+		}
+	}
+}
+
+func TestJournalRecorder(t *testing.T) {
+	ts := newTestServer(t)
+	rec := newJournalRecorder("go1.99")
+	d := ts.downloader(t, DownloaderOptions{Events: rec.events})
+	if err := d.install(context.Background(), t.TempDir(), "go1.99"); err != nil {
+		t.Fatal(err)
+	}
+	e, ok := rec.wait()
+	if !ok {
+		t.Fatal("install was not recorded")
+	}
+	if want := fmt.Sprintf("%x", sha256.Sum256(ts.tar)); !strings.HasSuffix(e.URL, ".tar.gz") || e.SHA256 != want || e.Error != "" {
+		t.Errorf("entry = %+v; want a .tar.gz URL and SHA-256 %s", e, want)
+	}
+
+	rec = newJournalRecorder("go1.99")
+	d = ts.downloader(t, DownloaderOptions{Events: rec.events, Offline: true})
+	if err := d.install(context.Background(), t.TempDir(), "go1.99"); err == nil {
+		t.Fatal("offline install succeeded")
+	}
+	if e, ok := rec.wait(); !ok || e.ErrorClass != "offline" {
+		t.Errorf("offline install recorded as %+v, %v; want error class offline", e, ok)
+	}
+}
+
+func TestFilterJournal(t *testing.T) {
+	now := time.Now()
+	entries := []JournalEntry{
+		{Time: now.Add(-48 * time.Hour), Toolchain: "go1.22.7"},
+		{Time: now.Add(-time.Hour), Toolchain: "gotip", Error: "exit status 1", ErrorClass: "build"},
+		{Time: now, Toolchain: "go1.22.7"},
+	}
+	tests := []struct {
+		toolchain string
+		since     time.Time
+		failed    bool
+		want      int
+	}{
+		{"", time.Time{}, false, 3},
+		{"go1.22.7", time.Time{}, false, 2},
+		{"", now.Add(-2 * time.Hour), false, 2},
+		{"", time.Time{}, true, 1},
+		{"go1.22.7", time.Time{}, true, 0},
+	}
+	for _, tt := range tests {
+		if got := filterJournal(entries, tt.toolchain, tt.since, tt.failed); len(got) != tt.want {
+			t.Errorf("filterJournal(%q, %v, %v) returned %d entries; want %d", tt.toolchain, tt.since, tt.failed, len(got), tt.want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/tabwriter"
+)
+
+// A ListEntry is a toolchain directory under the SDK root, as dl list
+// shows it.
+type ListEntry struct {
+	Name string `json:"name"` // the release, such as go1.22.7, or gotip
+	Path string `json:"path"`
+	Size int64  `json:"size"` // on disk
+
+	// Marker reports whether the directory has the marker an install
+	// leaves once the archive is completely unpacked. The gotip tree,
+	// which is built rather than unpacked, never has one.
+	Marker bool `json:"marker"`
+
+	// Aside is set for an old copy of Name moved aside by a reinstall,
+	// not yet removed.
+	Aside bool `json:"aside,omitempty"`
+}
+
+// List returns every toolchain directory under l's SDK root, installed
+// completely or not, including gotip and old copies moved aside, in
+// release order.
+func (l *Locator) List() ([]ListEntry, error) {
+	root, err := l.SDKRoot()
+	if err != nil {
+		return nil, err
+	}
+	fis, err := ioutil.ReadDir(root)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	sortEntries(fis)
+
+	var entries []ListEntry
+	for _, fi := range fis {
+		name := fi.Name()
+		if !fi.IsDir() || toolchainEntry(name) == "" {
+			continue
+		}
+		e := ListEntry{Name: name, Path: filepath.Join(root, name)}
+		if i := strings.Index(name, asideSuffix); i > 0 {
+			e.Name, e.Aside = name[:i], true
+		}
+		e.Marker = isFile(filepath.Join(e.Path, unpackedOkay))
+		entries = append(entries, e)
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxParallelWalks)
+	for i := range entries {
+		wg.Add(1)
+		go func(e *ListEntry) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			e.Size = diskUsage(e.Path)
+		}(&entries[i])
+	}
+	wg.Wait()
+	return entries, nil
+}
+
+// writeList writes entries to w as the table dl list prints.
+func writeList(w io.Writer, entries []ListEntry) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERSION\tSIZE\tMARKER\tPATH")
+	for _, e := range entries {
+		marker := "no"
+		switch {
+		case e.Name == "gotip":
+			marker = "-"
+		case e.Marker:
+			marker = "yes"
+		}
+		name := e.Name
+		if e.Aside {
+			name += " (old copy)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", name, formatByteSize(e.Size), marker, e.Path)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,53 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestList(t *testing.T) {
+	root := t.TempDir()
+	makeTree(t, root, map[string]string{
+		"go1.22.7/" + unpackedOkay: "",
+		"go1.22.7/bin/go":          "gobinary",
+		"go1.10/" + unpackedOkay:   "",
+		"go1.9/bin/go":             "half", // not completely unpacked
+		"go1.21.0.old-abc/VERSION": "go1.21.0",
+		"gotip/bin/go":             "tip",
+		"Documents/notes.txt":      "not ours",
+		"go1.22.7.linux-amd64.txt": "a file",
+	})
+	entries, err := (&Locator{Root: root}).List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ListEntry{
+		{Name: "go1.9", Path: filepath.Join(root, "go1.9"), Size: 4},
+		{Name: "go1.10", Path: filepath.Join(root, "go1.10"), Marker: true},
+		{Name: "go1.21.0", Path: filepath.Join(root, "go1.21.0.old-abc"), Size: 8, Aside: true},
+		{Name: "go1.22.7", Path: filepath.Join(root, "go1.22.7"), Size: 8, Marker: true},
+		{Name: "gotip", Path: filepath.Join(root, "gotip"), Size: 3},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("List() =\n%+v\nwant\n%+v", entries, want)
+	}
+
+	var b strings.Builder
+	writeList(&b, entries)
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 6 || !strings.HasPrefix(lines[0], "VERSION") ||
+		!strings.Contains(lines[3], "go1.21.0 (old copy)") || !strings.Contains(lines[5], " - ") {
+		t.Errorf("writeList printed\n%s", b.String())
+	}
+
+	entries, err = (&Locator{Root: filepath.Join(root, "missing")}).List()
+	if err != nil || len(entries) != 0 {
+		t.Errorf("List() of a missing SDK root = %v, %v; want nothing", entries, err)
+	}
+}
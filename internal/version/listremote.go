@@ -0,0 +1,82 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// A RemoteRelease is a published release, as dl list-remote shows it.
+type RemoteRelease struct {
+	Version string     `json:"version"`
+	Kind    string     `json:"kind"` // "stable", "rc" or "beta"
+	Files   []InfoFile `json:"files"`
+}
+
+// releaseKind returns whether v is a stable release, a release candidate
+// or a beta.
+func releaseKind(v Version) string {
+	if v.Pre == "" {
+		return "stable"
+	}
+	return v.Pre
+}
+
+// remoteReleases converts rs for dl list-remote.
+func remoteReleases(rs []Release) []RemoteRelease {
+	out := make([]RemoteRelease, 0, len(rs))
+	for _, r := range rs {
+		rr := RemoteRelease{Version: r.Version.String(), Kind: releaseKind(r.Version), Files: []InfoFile{}}
+		for _, f := range r.Files {
+			platform := ""
+			if f.OS != "" {
+				platform = f.OS + "/" + f.Arch
+			}
+			rr.Files = append(rr.Files, InfoFile{f.Filename, platform, f.Kind, f.Size, f.SHA256})
+		}
+		out = append(out, rr)
+	}
+	return out
+}
+
+// writeRemoteReleases writes rs to w as the table dl list-remote prints:
+// a line per release, with the number of platforms it has archives for
+// and the size of the archive for goos/goarch, or with files set, a line
+// per file.
+func writeRemoteReleases(w io.Writer, rs []RemoteRelease, goos, goarch string, files bool) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if files {
+		fmt.Fprintln(tw, "VERSION\tKIND\tFILENAME\tPLATFORM\tSIZE")
+		for _, r := range rs {
+			for _, f := range r.Files {
+				platform := f.Platform
+				if platform == "" {
+					platform = "-"
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", r.Version, f.Kind, f.Filename, platform, formatByteSize(f.Size))
+			}
+		}
+		tw.Flush()
+		return
+	}
+	fmt.Fprintf(tw, "VERSION\tKIND\tPLATFORMS\t%s/%s\n", goos, goarch)
+	for _, r := range rs {
+		platforms := make(map[string]bool)
+		size := "-"
+		for _, f := range r.Files {
+			if f.Kind != "archive" {
+				continue
+			}
+			platforms[f.Platform] = true
+			if f.Platform == goos+"/"+goarch {
+				size = formatByteSize(f.Size)
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\n", r.Version, r.Kind, len(platforms), size)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestListRemote(t *testing.T) {
+	c := newCatalogServer(t).catalog(t.TempDir())
+	go122, _ := ParseVersion("go1.22")
+	tests := []struct {
+		f     Filter
+		files bool
+		want  string
+	}{
+		{Filter{}, false, `
+VERSION    KIND    PLATFORMS  linux/amd64
+go1.23rc1  rc      1          11 B
+go1.22.7   stable  2          13 B
+go1.22.0   stable  0          -
+go1.21.13  stable  1          10 B
+go1.9      stable  0          -
+`},
+		{Filter{StableOnly: true, Since: go122}, false, `
+VERSION   KIND    PLATFORMS  linux/amd64
+go1.22.7  stable  2          13 B
+go1.22.0  stable  0          -
+`},
+		{Filter{OS: "windows"}, true, `
+VERSION   KIND     FILENAME                     PLATFORM       SIZE
+go1.22.7  source   go1.22.7.src.tar.gz          -              12 B
+go1.22.7  archive  go1.22.7.linux-amd64.tar.gz  linux/amd64    13 B
+go1.22.7  archive  go1.22.7.windows-arm64.zip   windows/arm64  14 B
+`},
+	}
+	for _, tt := range tests {
+		rs, err := c.All(context.Background(), tt.f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var b strings.Builder
+		writeRemoteReleases(&b, remoteReleases(rs), "linux", "amd64", tt.files)
+		if got, want := b.String(), strings.TrimPrefix(tt.want, "\n"); got != want {
+			t.Errorf("with %+v, printed\n%s\nwant\n%s", tt.f, got, want)
+		}
+	}
+}
+
+func TestRemoteReleasesJSON(t *testing.T) {
+	rs, err := newCatalogServer(t).catalog(t.TempDir()).All(context.Background(), Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := remoteReleases(rs)
+	if len(out) != 5 || out[0].Kind != "rc" || out[1].Kind != "stable" {
+		t.Fatalf("remoteReleases = %+v", out)
+	}
+	if f := out[1].Files[1]; f != (InfoFile{"go1.22.7.linux-amd64.tar.gz", "linux/amd64", "archive", 13, "dd"}) {
+		t.Errorf("go1.22.7's second file = %+v", f)
+	}
+	if out[2].Files == nil {
+		t.Errorf("a release without files has nil Files; want an empty list in JSON")
+	}
+}
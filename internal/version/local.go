@@ -0,0 +1,76 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// installFile installs version into targetDir from the archive file
+// instead of downloading it, for machines without access to the download
+// site. The archive is verified as planFile says.
+func (d *Downloader) installFile(ctx context.Context, targetDir, version, file, sum string) error {
+	p, err := d.planFile(targetDir, version, file, sum)
+	if err != nil {
+		d.emitter().emit(Failed{Err: err, Phase: PhaseResolve})
+		return err
+	}
+	return d.Execute(ctx, p)
+}
+
+// planFile returns the steps installing version into targetDir from the
+// archive file would take. The file must be named as the release's
+// archive for this platform is. It is verified against sum, if set, or
+// else against the checksum in file.sha256 beside it, if there is one,
+// or else as the checksum policy says, against the one published for the
+// archive.
+func (d *Downloader) planFile(targetDir, version, file, sum string) (*Plan, error) {
+	p := &Plan{Version: version, GOROOT: targetDir}
+	marker := filepath.Join(targetDir, unpackedOkay)
+	if _, err := os.Stat(marker); err == nil {
+		p.Installed = true
+		return p, nil
+	}
+
+	arch, _ := d.arch()
+	name := archiveName(version, d.goos(), arch)
+	if filepath.Base(file) != name {
+		return nil, fmt.Errorf("%s is not the archive of %s for %s/%s, which is named %s", file, version, d.goos(), arch, name)
+	}
+	file, err := filepath.Abs(file)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := os.Stat(file)
+	if err != nil {
+		return nil, err
+	}
+	p.URL, p.Size = file, fi.Size()
+
+	if sum == "" {
+		if data, err := ioutil.ReadFile(file + ".sha256"); err == nil {
+			// As published, or as written by sha256sum.
+			if f := strings.Fields(string(data)); len(f) > 0 {
+				sum = f[0]
+			}
+		}
+	}
+	if sum != "" {
+		if _, err := parseSHA256(sum); err != nil {
+			return nil, err
+		}
+	}
+	p.Steps = append(p.Steps,
+		Step{Kind: StepVerify, URL: d.baseURL + name + ".sha256", File: file, Checksum: d.opts.Checksum, SHA256: sum},
+		Step{Kind: StepUnpack, File: file, Target: targetDir, Slim: d.opts.Slim},
+	)
+	p.Steps = append(p.Steps, d.finishSteps(targetDir, marker)...)
+	return p, nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInstallFile(t *testing.T) {
+	ts := newTestServer(t)
+	ctx := context.Background()
+	d := ts.downloader(t, DownloaderOptions{Offline: true})
+	arch, _ := d.arch()
+	name := archiveName("go1.99", getOS(), arch)
+	archive := ts.tar
+	if strings.HasSuffix(name, ".zip") {
+		archive = ts.zip
+	}
+	h := sha256.Sum256(archive)
+	sum := hex.EncodeToString(h[:])
+	dir := t.TempDir()
+	file := filepath.Join(dir, name)
+	writeTestFile(t, file, archive)
+
+	// With the SHA-256 given.
+	root := t.TempDir()
+	if err := d.installFile(ctx, root, "go1.99", file, sum); err != nil {
+		t.Fatalf("installFile with -sha256: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, unpackedOkay)); err != nil {
+		t.Errorf("installFile didn't mark the install complete: %v", err)
+	}
+	if _, err := os.Stat(file); err != nil {
+		t.Errorf("installFile removed the archive it installed from: %v", err)
+	}
+
+	// With a wrong one.
+	var ce *ChecksumError
+	wrong := strings.Repeat("0", 64)
+	if err := d.installFile(ctx, t.TempDir(), "go1.99", file, wrong); !errors.As(err, &ce) {
+		t.Errorf("installFile with a wrong SHA-256 = %v; want a checksum error", err)
+	}
+
+	// With none, offline, the archive can't be verified.
+	var oe *OfflineError
+	if err := d.installFile(ctx, t.TempDir(), "go1.99", file, ""); !errors.As(err, &oe) {
+		t.Errorf("installFile with no checksum = %v; want an offline mode error", err)
+	}
+
+	// With the checksum in a file beside the archive, as sha256sum writes it.
+	writeTestFile(t, file+".sha256", []byte(sum+"  "+name+"\n"))
+	if err := d.installFile(ctx, t.TempDir(), "go1.99", file, ""); err != nil {
+		t.Errorf("installFile with %s.sha256: %v", name, err)
+	}
+
+	// The archive must be the release's for this platform.
+	other := filepath.Join(dir, "go1.98"+strings.TrimPrefix(name, "go1.99"))
+	writeTestFile(t, other, archive)
+	if err := d.installFile(ctx, t.TempDir(), "go1.99", other, sum); err == nil || !strings.Contains(err.Error(), name) {
+		t.Errorf("installFile of another release's archive = %v; want an error naming %s", err, name)
+	}
+	if _, err := d.planFile(t.TempDir(), "go1.99", file, "xyz"); err == nil {
+		t.Errorf("planFile with an invalid SHA-256 succeeded")
+	}
+
+	if reqs := ts.requests(); len(reqs) > 0 {
+		t.Errorf("installing from a file made requests: %v", reqs)
+	}
+}
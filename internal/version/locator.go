@@ -0,0 +1,89 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// A Locator knows where toolchains are installed on this machine.
+// A nil or zero Locator uses the default layout under the user's home
+// directory, which is where the go1.N.M and gotip commands look.
+type Locator struct {
+	// Root, if non-empty, is the directory holding installed toolchains,
+	// one subdirectory per toolchain name.
+	Root string
+}
+
+// defaultLocator is the Locator used by the wrapper commands.
+var defaultLocator = &Locator{}
+
+// SDKRoot returns the directory holding installed toolchains.
+func (l *Locator) SDKRoot() (string, error) {
+	if l != nil && l.Root != "" {
+		return l.Root, nil
+	}
+	home, err := homedir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %v", err)
+	}
+	return filepath.Join(home, "Cache/go_sdk"), nil
+}
+
+// Goroot returns the GOROOT of the toolchain with the given name, such as
+// "go1.22.7" or "gotip". The directory need not exist.
+func (l *Locator) Goroot(name string) (string, error) {
+	root, err := l.SDKRoot()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, name), nil
+}
+
+// Parse is like ParseVersion, but the returned Version resolves its
+// installation directory using l.
+func (l *Locator) Parse(s string) (Version, error) {
+	v, err := ParseVersion(s)
+	v.loc = l
+	return v, err
+}
+
+// Installed returns the releases completely installed under l, oldest
+// first. Entries of the SDK root that are not named for a release, such as
+// gotip or stray files, are skipped. A missing SDK root holds no releases.
+func (l *Locator) Installed() ([]Version, error) {
+	root, err := l.SDKRoot()
+	if err != nil {
+		return nil, err
+	}
+	fis, err := ioutil.ReadDir(root)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var vs []Version
+	for _, fi := range fis {
+		if !fi.IsDir() {
+			continue
+		}
+		v, err := l.Parse(fi.Name())
+		if err != nil || !v.Installed() {
+			continue
+		}
+		vs = append(vs, v)
+	}
+	SortVersions(vs)
+	return vs, nil
+}
+
+// Tip returns the gotip toolchain, installed under l.
+func (l *Locator) Tip() Toolchain {
+	return tipToolchain{l}
+}
@@ -0,0 +1,227 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LockfileName is the name of the lockfile that dl lock writes and
+// dl install -locked reads, in the current directory.
+const LockfileName = "godl.lock"
+
+// LockfileFormat is the version of the lockfile format that this package
+// writes, and the newest it reads.
+const LockfileFormat = 1
+
+// A Lockfile pins a project's toolchain: an exact release, and the SHA-256
+// of its archive for each platform. Its text form is
+//
+//	# comments
+//	format 1
+//	go go1.22.7
+//	archive linux/amd64 go1.22.7.linux-amd64.tar.gz 4a7b...
+//	archive windows/amd64 go1.22.7.windows-amd64.zip 6c2c...
+//
+// with one archive line per platform, named as in the release listing.
+type Lockfile struct {
+	Format   int
+	Go       Version
+	Archives []LockedArchive
+}
+
+// A LockedArchive is the archive a Lockfile pins for a platform.
+type LockedArchive struct {
+	OS, Arch string // as in the release listing, such as linux and armv6l
+	Filename string
+	SHA256   string // lower-case hex
+}
+
+// NewLockfile returns a Lockfile pinning r's binary archives.
+func NewLockfile(r Release) *Lockfile {
+	l := &Lockfile{Format: LockfileFormat, Go: r.Version}
+	for _, f := range r.Files {
+		if f.Kind == "archive" && f.SHA256 != "" {
+			l.Archives = append(l.Archives, LockedArchive{OS: f.OS, Arch: f.Arch, Filename: f.Filename, SHA256: strings.ToLower(f.SHA256)})
+		}
+	}
+	sort.Slice(l.Archives, func(i, j int) bool {
+		a, b := l.Archives[i], l.Archives[j]
+		if a.OS != b.OS {
+			return a.OS < b.OS
+		}
+		return a.Arch < b.Arch
+	})
+	return l
+}
+
+// ParseLockfile parses the text form of a Lockfile. It rejects formats
+// newer than LockfileFormat, and releases named by an alias, such as
+// "latest", rather than exactly.
+func ParseLockfile(data []byte) (*Lockfile, error) {
+	ls, err := parseLocks(LockfileName, data, false)
+	if err != nil {
+		return nil, err
+	}
+	return ls[0], nil
+}
+
+// parseLocks parses the text form of a Lockfile or, if many is set, of a
+// VersionsLock, naming the file name in errors. Unless many is set, it
+// returns exactly one Lockfile.
+func parseLocks(name string, data []byte, many bool) ([]*Lockfile, error) {
+	var (
+		format int
+		ls     []*Lockfile
+		l      *Lockfile
+	)
+	if !many {
+		l = &Lockfile{}
+		ls = append(ls, l)
+	}
+	seen := make(map[Version]bool)
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		f := strings.Fields(line)
+		errorf := func(format string, args ...interface{}) error {
+			return fmt.Errorf("%s:%d: %s", name, n, fmt.Sprintf(format, args...))
+		}
+		if format == 0 && f[0] != "format" {
+			return nil, errorf("missing format line")
+		}
+		switch f[0] {
+		case "format":
+			if len(f) != 2 || format != 0 {
+				return nil, errorf("malformed format line")
+			}
+			v, err := strconv.Atoi(f[1])
+			if err != nil || v < 1 {
+				return nil, errorf("malformed format line")
+			}
+			if v > LockfileFormat {
+				return nil, errorf("format %d is newer than this tool supports (%d); update it", v, LockfileFormat)
+			}
+			format = v
+		case "go":
+			if len(f) != 2 || !many && l.Go != (Version{}) {
+				return nil, errorf("malformed go line")
+			}
+			v, err := ParseVersion(f[1])
+			if err != nil {
+				return nil, errorf("go %s: not an exact release name", f[1])
+			}
+			if many {
+				if seen[v] {
+					return nil, errorf("%s is listed twice", v)
+				}
+				seen[v] = true
+				l = &Lockfile{}
+				ls = append(ls, l)
+			}
+			l.Go = v
+		case "archive":
+			if len(f) != 4 {
+				return nil, errorf("malformed archive line")
+			}
+			if l == nil {
+				return nil, errorf("archive line before any go line")
+			}
+			i := strings.Index(f[1], "/")
+			if i < 0 {
+				return nil, errorf("malformed platform %q", f[1])
+			}
+			if _, err := parseSHA256(f[3]); err != nil {
+				return nil, errorf("%s: %v", f[2], err)
+			}
+			l.Archives = append(l.Archives, LockedArchive{OS: f[1][:i], Arch: f[1][i+1:], Filename: f[2], SHA256: strings.ToLower(f[3])})
+		default:
+			return nil, errorf("unknown directive %q", f[0])
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if format == 0 {
+		return nil, fmt.Errorf("%s: missing format line", name)
+	}
+	if len(ls) == 0 || ls[0].Go == (Version{}) {
+		return nil, fmt.Errorf("%s: missing go line", name)
+	}
+	for _, l := range ls {
+		l.Format = format
+	}
+	return ls, nil
+}
+
+// Bytes returns the text form of l.
+func (l *Lockfile) Bytes() []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# Generated by 'dl lock'. 'dl install -locked' installs exactly this.\n")
+	fmt.Fprintf(&b, "format %d\n", l.Format)
+	fmt.Fprintf(&b, "go %s\n", l.Go)
+	for _, a := range l.Archives {
+		fmt.Fprintf(&b, "archive %s/%s %s %s\n", a.OS, a.Arch, a.Filename, a.SHA256)
+	}
+	return b.Bytes()
+}
+
+// Archive returns the archive l pins for goos/goarch, under any of the
+// names the platform's archives are published as.
+func (l *Lockfile) Archive(goos, goarch string) (LockedArchive, bool) {
+	for _, g := range releaseOSes(goos) {
+		arch := releaseArch(g, goarch)
+		for _, a := range l.Archives {
+			if a.OS == g && a.Arch == arch {
+				return a, true
+			}
+		}
+	}
+	return LockedArchive{}, false
+}
+
+// installLocked installs the release l pins into targetDir. The archive,
+// whether downloaded or found in the cache, must be the one l pins for
+// this platform, with the pinned SHA-256.
+func (d *Downloader) installLocked(ctx context.Context, targetDir string, l *Lockfile) error {
+	defer d.holdCache()()
+	p, err := d.planLocked(ctx, targetDir, l)
+	if err != nil {
+		d.emitter().emit(Failed{Err: err, Phase: PhaseResolve})
+		return err
+	}
+	return d.Execute(ctx, p)
+}
+
+func (d *Downloader) planLocked(ctx context.Context, targetDir string, l *Lockfile) (*Plan, error) {
+	arch, _ := d.arch()
+	a, ok := l.Archive(d.goos(), arch)
+	if !ok {
+		return nil, fmt.Errorf("%s pins no archive of %s for %s/%s; run 'dl lock %s' to pin every platform the release has", LockfileName, l.Go, d.goos(), arch, l.Go)
+	}
+	p, err := d.plan(ctx, targetDir, l.Go.String())
+	if err != nil || p.Installed {
+		return p, err
+	}
+	if name := path.Base(p.URL); name != a.Filename {
+		return nil, fmt.Errorf("%s pins %s for %s/%s, but the install would use %s", LockfileName, a.Filename, d.goos(), arch, name)
+	}
+	for i := range p.Steps {
+		if p.Steps[i].Kind == StepVerify {
+			p.Steps[i].SHA256 = a.SHA256
+		}
+	}
+	return p, nil
+}
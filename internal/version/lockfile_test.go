@@ -0,0 +1,107 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLockfileRoundTrip(t *testing.T) {
+	v, err := ParseVersion("go1.22.7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := Release{Version: v, Files: []File{
+		{Filename: "go1.22.7.src.tar.gz", Kind: "source", SHA256: strings.Repeat("0", 64)},
+		{Filename: "go1.22.7.windows-amd64.zip", OS: "windows", Arch: "amd64", Kind: "archive", SHA256: strings.Repeat("A", 64)},
+		{Filename: "go1.22.7.windows-amd64.msi", OS: "windows", Arch: "amd64", Kind: "installer", SHA256: strings.Repeat("b", 64)},
+		{Filename: "go1.22.7.linux-armv6l.tar.gz", OS: "linux", Arch: "armv6l", Kind: "archive", SHA256: strings.Repeat("c", 64)},
+	}}
+	l := NewLockfile(r)
+	want := []LockedArchive{
+		{"linux", "armv6l", "go1.22.7.linux-armv6l.tar.gz", strings.Repeat("c", 64)},
+		{"windows", "amd64", "go1.22.7.windows-amd64.zip", strings.Repeat("a", 64)},
+	}
+	if !reflect.DeepEqual(l.Archives, want) {
+		t.Errorf("NewLockfile pinned %+v; want %+v", l.Archives, want)
+	}
+
+	parsed, err := ParseLockfile(l.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Format != LockfileFormat || parsed.Go.String() != "go1.22.7" || !reflect.DeepEqual(parsed.Archives, want) {
+		t.Errorf("ParseLockfile(%q) = %+v; want %+v", l.Bytes(), parsed, l)
+	}
+	if a, ok := parsed.Archive("linux", "arm"); !ok || a.Arch != "armv6l" {
+		t.Errorf("Archive(linux, arm) = %+v, %v; want the armv6l archive", a, ok)
+	}
+	if _, ok := parsed.Archive("darwin", "arm64"); ok {
+		t.Error("Archive(darwin, arm64) found an archive that isn't pinned")
+	}
+}
+
+func TestParseLockfileErrors(t *testing.T) {
+	sum := strings.Repeat("a", 64)
+	tests := []struct {
+		text, err string
+	}{
+		{"go go1.22.7\n", "missing format line"},
+		{"format 2\ngo go1.22.7\n", "newer than this tool supports"},
+		{"format 1\n", "missing go line"},
+		{"format 1\ngo latest\n", "not an exact release name"},
+		{"format 1\ngo go1.22.7\narchive linux-amd64 go1.22.7.linux-amd64.tar.gz " + sum + "\n", "malformed platform"},
+		{"format 1\ngo go1.22.7\narchive linux/amd64 go1.22.7.linux-amd64.tar.gz abc\n", ":3: go1.22.7.linux-amd64.tar.gz"},
+		{"format 1\ngo go1.22.7\ntoolchain go1.23.0\n", "unknown directive"},
+	}
+	for _, tt := range tests {
+		if _, err := ParseLockfile([]byte(tt.text)); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("ParseLockfile(%q) = %v; want error containing %q", tt.text, err, tt.err)
+		}
+	}
+}
+
+func TestInstallLocked(t *testing.T) {
+	ts := newTestServer(t)
+	cache := t.TempDir()
+	d := ts.downloader(t, DownloaderOptions{CacheDir: cache})
+	arch, _ := d.arch()
+	name := archiveName("go1.99", getOS(), arch)
+	lock := func(sum string) *Lockfile {
+		v, err := ParseVersion("go1.99")
+		if err != nil {
+			t.Fatal(err)
+		}
+		goos := getOS()
+		return &Lockfile{Format: LockfileFormat, Go: v, Archives: []LockedArchive{{goos, releaseArch(goos, arch), name, sum}}}
+	}
+	good := fmt.Sprintf("%x", sha256.Sum256(ts.tar))
+	if getOS() == "windows" {
+		good = fmt.Sprintf("%x", sha256.Sum256(ts.zip))
+	}
+
+	if err := d.installLocked(context.Background(), t.TempDir(), lock(good)); err != nil {
+		t.Fatalf("installLocked with the right checksum: %v", err)
+	}
+
+	// The archive is now cached, but must still match the pin.
+	var ce *ChecksumError
+	err := d.installLocked(context.Background(), t.TempDir(), lock(strings.Repeat("0", 64)))
+	if !errors.As(err, &ce) {
+		t.Errorf("installLocked with a drifted checksum = %v; want a *ChecksumError", err)
+	}
+
+	other := lock(good)
+	other.Archives[0].Arch = "sparc"
+	if err := d.installLocked(context.Background(), t.TempDir(), other); err == nil || !strings.Contains(err.Error(), "pins no archive") {
+		t.Errorf("installLocked for an unpinned platform = %v; want an error", err)
+	}
+}
@@ -0,0 +1,296 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ManifestFormat is the version of the manifest format that this package
+// writes, and the newest it reads.
+const ManifestFormat = 1
+
+// A Manifest lists a machine's toolchains, so that they can be installed
+// again on another one: each release, with the SHA-256 of its archive for
+// every platform, and the commit the gotip tree was built at. It records
+// no file contents. Its text form is indented JSON, such as
+//
+//	{
+//	  "format": 1,
+//	  "toolchains": [
+//	    {
+//	      "version": "go1.22.7",
+//	      "archives": [
+//	        {"platform": "linux/amd64", "filename": "go1.22.7.linux-amd64.tar.gz", "sha256": "4a7b..."}
+//	      ]
+//	    }
+//	  ],
+//	  "gotip": {"commit": "2621ba2c60d05ec0b9ef37cd71e45047b004cead"}
+//	}
+//
+// which may be edited by hand: an entry needs only its version.
+type Manifest struct {
+	Format     int             `json:"format"`
+	Toolchains []ManifestEntry `json:"toolchains"`
+	Gotip      *ManifestTip    `json:"gotip,omitempty"`
+}
+
+// A ManifestEntry is a release in a Manifest.
+type ManifestEntry struct {
+	Version  string            `json:"version"`
+	Archives []ManifestArchive `json:"archives,omitempty"`
+}
+
+// A ManifestArchive pins the archive of a release for a platform.
+type ManifestArchive struct {
+	Platform string `json:"platform"` // as in the release listing, such as linux/armv6l
+	Filename string `json:"filename"`
+	SHA256   string `json:"sha256"` // lower-case hex
+}
+
+// A ManifestTip is the gotip tree in a Manifest.
+type ManifestTip struct {
+	Commit string `json:"commit"`
+}
+
+// NewManifest returns a Manifest of the releases vs, pinning the archives
+// c lists for each, and of the gotip tree at tipCommit, if that isn't
+// empty. It also returns the releases c doesn't list, which are recorded
+// without pins.
+func NewManifest(ctx context.Context, c *Catalog, vs []Version, tipCommit string) (m *Manifest, unpinned []Version, err error) {
+	m = &Manifest{Format: ManifestFormat, Toolchains: []ManifestEntry{}}
+	for _, v := range vs {
+		e := ManifestEntry{Version: v.String()}
+		r, err := c.Resolve(ctx, v.String())
+		var nf *NotFoundError
+		switch {
+		case errors.As(err, &nf):
+			unpinned = append(unpinned, v)
+		case err != nil:
+			return nil, nil, err
+		default:
+			for _, a := range NewLockfile(r).Archives {
+				e.Archives = append(e.Archives, ManifestArchive{Platform: a.OS + "/" + a.Arch, Filename: a.Filename, SHA256: a.SHA256})
+			}
+		}
+		m.Toolchains = append(m.Toolchains, e)
+	}
+	if tipCommit != "" {
+		m.Gotip = &ManifestTip{Commit: tipCommit}
+	}
+	return m, unpinned, nil
+}
+
+// installedReleases returns the releases installed under l and, if l has
+// its own SDK directory, under the default one, oldest first.
+func installedReleases(l *Locator) ([]Version, error) {
+	vs, err := l.Installed()
+	if err != nil || l.Root == "" {
+		return vs, err
+	}
+	seen := make(map[string]bool)
+	for _, v := range vs {
+		seen[v.String()] = true
+	}
+	more, err := (&Locator{}).Installed()
+	if err != nil {
+		return vs, nil
+	}
+	for _, v := range more {
+		if !seen[v.String()] {
+			vs = append(vs, v)
+		}
+	}
+	SortVersions(vs)
+	return vs, nil
+}
+
+// ParseManifest parses the text form of a Manifest. It rejects formats
+// newer than ManifestFormat, unknown fields, which are most likely
+// misspellings, and releases named by an alias rather than exactly.
+func ParseManifest(data []byte) (*Manifest, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	m := &Manifest{}
+	if err := dec.Decode(m); err != nil {
+		return nil, fmt.Errorf("manifest: %v", err)
+	}
+	switch {
+	case m.Format == 0:
+		return nil, errors.New("manifest: missing format")
+	case m.Format > ManifestFormat:
+		return nil, fmt.Errorf("manifest: format %d is newer than this tool supports (%d); update it", m.Format, ManifestFormat)
+	}
+	seen := make(map[string]bool)
+	for _, e := range m.Toolchains {
+		if _, err := ParseVersion(e.Version); err != nil {
+			return nil, fmt.Errorf("manifest: %s: not an exact release name", e.Version)
+		}
+		if seen[e.Version] {
+			return nil, fmt.Errorf("manifest: %s is listed twice", e.Version)
+		}
+		seen[e.Version] = true
+		for _, a := range e.Archives {
+			if i := strings.Index(a.Platform, "/"); i <= 0 || i == len(a.Platform)-1 {
+				return nil, fmt.Errorf("manifest: %s: malformed platform %q", e.Version, a.Platform)
+			}
+			if _, err := parseSHA256(a.SHA256); err != nil {
+				return nil, fmt.Errorf("manifest: %s: %v", a.Filename, err)
+			}
+		}
+	}
+	if m.Gotip != nil && !isCommitHash(m.Gotip.Commit) {
+		return nil, fmt.Errorf("manifest: gotip commit %q is not a full commit hash", m.Gotip.Commit)
+	}
+	return m, nil
+}
+
+// Bytes returns the text form of m.
+func (m *Manifest) Bytes() []byte {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		panic(err) // a Manifest always marshals
+	}
+	return append(b, '\n')
+}
+
+// lockfile returns a Lockfile pinning what e pins.
+func (e ManifestEntry) lockfile() (*Lockfile, error) {
+	v, err := ParseVersion(e.Version)
+	if err != nil {
+		return nil, err
+	}
+	l := &Lockfile{Format: LockfileFormat, Go: v}
+	for _, a := range e.Archives {
+		i := strings.Index(a.Platform, "/")
+		l.Archives = append(l.Archives, LockedArchive{OS: a.Platform[:i], Arch: a.Platform[i+1:], Filename: a.Filename, SHA256: strings.ToLower(a.SHA256)})
+	}
+	return l, nil
+}
+
+// Import result statuses.
+const (
+	ImportInstalled = "installed"
+	ImportPresent   = "present" // already installed, so skipped
+	ImportFailed    = "failed"
+)
+
+// An ImportResult reports what importing one toolchain of a Manifest did.
+type ImportResult struct {
+	Toolchain string `json:"toolchain"`
+	Status    string `json:"status"`
+	Note      string `json:"note,omitempty"`
+	Error     string `json:"error,omitempty"`
+
+	err error
+}
+
+// maxParallelImports bounds how many releases an import installs at once.
+const maxParallelImports = 3
+
+// An importer installs the toolchains of a Manifest on this platform,
+// goos/goarch, translating the manifest's pins to its archives.
+type importer struct {
+	goos, goarch string
+
+	// strict makes a release that has no pinned archive for this
+	// platform a failure, rather than an install verified against the
+	// checksum the release listing publishes.
+	strict bool
+
+	present    func(name string) bool
+	install    func(ctx context.Context, v Version, l *Lockfile) error // l is nil if unpinned
+	installTip func(ctx context.Context, commit string) error
+}
+
+// check returns an error listing the releases of m that have no pinned
+// archive for this platform, if the import is strict, so that it can fail
+// before installing anything.
+func (im *importer) check(m *Manifest) error {
+	if !im.strict {
+		return nil
+	}
+	var missing []string
+	for _, e := range m.Toolchains {
+		l, err := e.lockfile()
+		if err != nil {
+			return err
+		}
+		if _, ok := l.Archive(im.goos, im.goarch); !ok && !im.present(e.Version) {
+			missing = append(missing, e.Version)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("the manifest pins no %s/%s archive of %s; -strict installs only pinned archives", im.goos, im.goarch, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// run installs the toolchains of m, releases concurrently and then the
+// gotip tree, skipping those already present, and returns a result for
+// each, in the manifest's order.
+func (im *importer) run(ctx context.Context, m *Manifest) []ImportResult {
+	results := make([]ImportResult, len(m.Toolchains))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxParallelImports)
+	for i, e := range m.Toolchains {
+		results[i] = ImportResult{Toolchain: e.Version}
+		if im.present(e.Version) {
+			results[i].Status = ImportPresent
+			continue
+		}
+		wg.Add(1)
+		go func(r *ImportResult, e ManifestEntry) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			im.importRelease(ctx, r, e)
+		}(&results[i], e)
+	}
+	wg.Wait()
+
+	if m.Gotip != nil {
+		r := ImportResult{Toolchain: "gotip", Status: ImportPresent}
+		if !im.present("gotip") {
+			r.Status = ImportInstalled
+			r.Note = "built at " + m.Gotip.Commit
+			r.err = im.installTip(ctx, m.Gotip.Commit)
+		} else {
+			r.Note = "left at the commit it is at; run 'gotip download " + m.Gotip.Commit + "' for the manifest's"
+		}
+		results = append(results, r)
+	}
+	for i := range results {
+		if err := results[i].err; err != nil {
+			results[i].Status, results[i].Note, results[i].Error = ImportFailed, "", err.Error()
+		}
+	}
+	return results
+}
+
+func (im *importer) importRelease(ctx context.Context, r *ImportResult, e ManifestEntry) {
+	l, err := e.lockfile()
+	if err != nil {
+		r.err = err
+		return
+	}
+	v := l.Go
+	if _, ok := l.Archive(im.goos, im.goarch); !ok {
+		if im.strict {
+			r.err = fmt.Errorf("the manifest pins no %s/%s archive", im.goos, im.goarch)
+			return
+		}
+		r.Note = fmt.Sprintf("no pinned %s/%s archive; verified against the release listing", im.goos, im.goarch)
+		l = nil
+	}
+	r.Status = ImportInstalled
+	r.err = im.install(ctx, v, l)
+}
@@ -0,0 +1,234 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const testCommit = "2621ba2c60d05ec0b9ef37cd71e45047b004cead"
+
+func TestManifestRoundTrip(t *testing.T) {
+	c := newCatalogServer(t).catalog(t.TempDir())
+	var vs []Version
+	for _, name := range []string{"go1.9", "go1.20.1", "go1.22.7"} {
+		v, err := ParseVersion(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		vs = append(vs, v)
+	}
+	m, unpinned, err := NewManifest(context.Background(), c, vs, testCommit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unpinned) != 1 || unpinned[0].String() != "go1.20.1" {
+		t.Errorf("unpinned = %v; want go1.20.1, which isn't listed", unpinned)
+	}
+	want := &Manifest{
+		Format: ManifestFormat,
+		Toolchains: []ManifestEntry{
+			{Version: "go1.9"},
+			{Version: "go1.20.1"},
+			{Version: "go1.22.7", Archives: []ManifestArchive{
+				{"linux/amd64", "go1.22.7.linux-amd64.tar.gz", "dd"},
+				{"windows/arm64", "go1.22.7.windows-arm64.zip", "ee"},
+			}},
+		},
+		Gotip: &ManifestTip{Commit: testCommit},
+	}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("NewManifest = %+v; want %+v", m, want)
+	}
+
+	// The listing's checksums are too short to parse back; pin real ones.
+	m.Toolchains[2].Archives[0].SHA256 = strings.Repeat("d", 64)
+	m.Toolchains[2].Archives[1].SHA256 = strings.Repeat("e", 64)
+	got, err := ParseManifest(m.Bytes())
+	if err != nil {
+		t.Fatalf("ParseManifest(%s): %v", m.Bytes(), err)
+	}
+	if !reflect.DeepEqual(got, m) {
+		t.Errorf("ParseManifest(Bytes()) = %+v; want %+v", got, m)
+	}
+}
+
+func TestParseManifestErrors(t *testing.T) {
+	sum := strings.Repeat("a", 64)
+	tests := []struct {
+		data, want string
+	}{
+		{`{"toolchains": []}`, "missing format"},
+		{`{"format": 2, "toolchains": []}`, "format 2 is newer"},
+		{`{"format": 1, "toolchain": []}`, "unknown field"},
+		{`{"format": 1, "toolchains": [{"version": "latest"}]}`, "not an exact release name"},
+		{`{"format": 1, "toolchains": [{"version": "go1.22.7"}, {"version": "go1.22.7"}]}`, "listed twice"},
+		{`{"format": 1, "toolchains": [{"version": "go1.22.7", "archives": [{"platform": "linux", "filename": "x", "sha256": "` + sum + `"}]}]}`, "malformed platform"},
+		{`{"format": 1, "toolchains": [{"version": "go1.22.7", "archives": [{"platform": "linux/amd64", "filename": "x", "sha256": "abc"}]}]}`, "x: "},
+		{`{"format": 1, "toolchains": [], "gotip": {"commit": "master"}}`, "not a full commit hash"},
+		{`[`, "manifest: "},
+	}
+	for _, tt := range tests {
+		_, err := ParseManifest([]byte(tt.data))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ParseManifest(%s) = %v; want an error containing %q", tt.data, err, tt.want)
+		}
+	}
+
+	// An entry needs only its version.
+	m, err := ParseManifest([]byte(`{"format": 1, "toolchains": [{"version": "go1.22.7"}]}`))
+	if err != nil || len(m.Toolchains) != 1 || m.Gotip != nil {
+		t.Errorf("ParseManifest of a minimal manifest = %+v, %v", m, err)
+	}
+}
+
+// fakeImporter records what an importer installs, instead of installing.
+type fakeImporter struct {
+	mu        sync.Mutex
+	installed map[string]*Lockfile
+	tip       string
+}
+
+func (f *fakeImporter) importer(present ...string) *importer {
+	f.installed = make(map[string]*Lockfile)
+	return &importer{
+		goos:   "windows",
+		goarch: "arm64",
+		present: func(name string) bool {
+			for _, p := range present {
+				if p == name {
+					return true
+				}
+			}
+			return false
+		},
+		install: func(ctx context.Context, v Version, l *Lockfile) error {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			f.installed[v.String()] = l
+			if v.String() == "go1.19" {
+				return &ChecksumError{Want: "a", Got: "b"}
+			}
+			return nil
+		},
+		installTip: func(ctx context.Context, commit string) error {
+			f.tip = commit
+			return nil
+		},
+	}
+}
+
+func testImportManifest(t *testing.T) *Manifest {
+	sum := strings.Repeat("a", 64)
+	m, err := ParseManifest([]byte(`{
+  "format": 1,
+  "toolchains": [
+    {"version": "go1.22.7", "archives": [
+      {"platform": "linux/amd64", "filename": "go1.22.7.linux-amd64.tar.gz", "sha256": "` + sum + `"},
+      {"platform": "windows/arm64", "filename": "go1.22.7.windows-arm64.zip", "sha256": "` + strings.ToUpper(sum) + `"}]},
+    {"version": "go1.21.13", "archives": [
+      {"platform": "linux/amd64", "filename": "go1.21.13.linux-amd64.tar.gz", "sha256": "` + sum + `"}]},
+    {"version": "go1.20.14"},
+    {"version": "go1.19", "archives": [
+      {"platform": "windows/arm64", "filename": "go1.19.windows-arm64.zip", "sha256": "` + sum + `"}]}
+  ],
+  "gotip": {"commit": "` + testCommit + `"}
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestImporter(t *testing.T) {
+	var f fakeImporter
+	im := f.importer("go1.20.14")
+	results := im.run(context.Background(), testImportManifest(t))
+
+	var got []string
+	for _, r := range results {
+		got = append(got, r.Toolchain+" "+r.Status)
+	}
+	want := []string{"go1.22.7 installed", "go1.21.13 installed", "go1.20.14 present", "go1.19 failed", "gotip installed"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("results = %q; want %q", got, want)
+	}
+	if !strings.Contains(results[1].Note, "no pinned windows/arm64 archive") {
+		t.Errorf("go1.21.13 note = %q; want it to say it wasn't pinned", results[1].Note)
+	}
+	if results[3].Error == "" || ExitCode(results[3].err) != ExitVerify {
+		t.Errorf("go1.19 result = %+v; want the checksum error", results[3])
+	}
+
+	// The pins are translated to this platform's archive.
+	l := f.installed["go1.22.7"]
+	if l == nil {
+		t.Fatal("go1.22.7 installed without its pins")
+	}
+	if a, ok := l.Archive("windows", "arm64"); !ok || a.Filename != "go1.22.7.windows-arm64.zip" || a.SHA256 != strings.Repeat("a", 64) {
+		t.Errorf("go1.22.7 pinned %+v, %v; want the windows/arm64 zip", a, ok)
+	}
+	if l, ok := f.installed["go1.21.13"]; !ok || l != nil {
+		t.Errorf("go1.21.13 installed with %v, %v; want it installed unpinned", l, ok)
+	}
+	if _, ok := f.installed["go1.20.14"]; ok {
+		t.Errorf("go1.20.14 was installed again")
+	}
+	if f.tip != testCommit {
+		t.Errorf("gotip built at %q; want %s", f.tip, testCommit)
+	}
+
+	// A gotip tree already there is left alone.
+	f = fakeImporter{}
+	results = f.importer("gotip").run(context.Background(), &Manifest{Format: 1, Gotip: &ManifestTip{testCommit}})
+	if len(results) != 1 || results[0].Status != ImportPresent || f.tip != "" {
+		t.Errorf("importing over gotip = %+v, built %q; want it left alone", results, f.tip)
+	}
+}
+
+func TestImporterStrict(t *testing.T) {
+	var f fakeImporter
+	im := f.importer("go1.20.14")
+	im.strict = true
+	m := testImportManifest(t)
+	err := im.check(m)
+	if err == nil || !strings.Contains(err.Error(), "go1.21.13") || strings.Contains(err.Error(), "go1.20.14") {
+		t.Errorf("strict check = %v; want it to name go1.21.13 only", err)
+	}
+
+	// Even if run without the check, nothing unpinned is installed.
+	results := im.run(context.Background(), m)
+	if results[1].Status != ImportFailed {
+		t.Errorf("strict import of go1.21.13 = %+v; want it failed", results[1])
+	}
+	if _, ok := f.installed["go1.21.13"]; ok {
+		t.Errorf("strict import installed go1.21.13 unpinned")
+	}
+
+	m.Toolchains = m.Toolchains[:1]
+	if err := im.check(m); err != nil {
+		t.Errorf("strict check of a fully pinned manifest = %v", err)
+	}
+}
+
+func TestImporterCanceled(t *testing.T) {
+	var f fakeImporter
+	im := f.importer()
+	im.install = func(ctx context.Context, v Version, l *Lockfile) error { return ctx.Err() }
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	m := testImportManifest(t)
+	m.Gotip = nil
+	for _, r := range im.run(ctx, m) {
+		if !errors.Is(r.err, context.Canceled) || ExitCode(r.err) != ExitInterrupted {
+			t.Errorf("%s: %v; want it canceled", r.Toolchain, r.err)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// How often a meter redraws its line on a terminal, and prints a new line
+// elsewhere, such as in CI logs.
+var (
+	meterRedraw   = 250 * time.Millisecond
+	meterInterval = 5 * time.Second
+)
+
+// A meter shows the progress of a download or of unpacking an archive: on
+// a terminal as one line redrawn in place, with the speed and the time
+// left, and elsewhere as a line every meterInterval. A nil *meter shows
+// nothing.
+type meter struct {
+	w     io.Writer
+	tty   bool
+	verb  string // such as "Downloaded"
+	speed bool   // whether to show the speed, as well as the time left
+	total int64  // -1 if unknown
+	base  int64  // done before this meter started, as when resuming
+
+	start time.Time
+	last  time.Time // of the last line shown
+	lastN int64     // done as of last
+	rate  float64   // in bytes per second, smoothed
+	drawn bool      // whether a line is drawn without a newline
+}
+
+// newMeter returns a meter on standard error of total bytes, of which
+// base are already done, or nil if d is quiet.
+func (d *Downloader) newMeter(verb string, total, base int64, speed bool) *meter {
+	if d.quiet() {
+		return nil
+	}
+	return newMeterTo(os.Stderr, isTerminal(os.Stderr), verb, total, base, speed)
+}
+
+func newMeterTo(w io.Writer, tty bool, verb string, total, base int64, speed bool) *meter {
+	now := time.Now()
+	return &meter{w: w, tty: tty, verb: verb, speed: speed, total: total, base: base, start: now, last: now, lastN: base}
+}
+
+// update notes that n bytes are done, showing detail, or the bytes done
+// and in total if it is empty, if it's time for a line.
+func (m *meter) update(n int64, detail string) {
+	if m == nil {
+		return
+	}
+	now := time.Now()
+	dt := now.Sub(m.last)
+	if m.tty && dt < meterRedraw || !m.tty && dt < meterInterval {
+		return
+	}
+	if inst := float64(n-m.lastN) / dt.Seconds(); m.rate == 0 {
+		m.rate = inst
+	} else {
+		m.rate = 0.3*inst + 0.7*m.rate
+	}
+	m.last, m.lastN = now, n
+	m.show(m.line(n, detail, m.rate, m.eta(n)), false)
+}
+
+// finish shows the final line, with the average speed and the time taken.
+func (m *meter) finish(n int64, detail string) {
+	if m == nil {
+		return
+	}
+	elapsed := time.Since(m.start)
+	line := m.line(n, detail, 0, -1) + " in " + roundDuration(elapsed).String()
+	if m.speed && elapsed > 0 {
+		line += fmt.Sprintf(", %s/s", formatByteSize(int64(float64(n-m.base)/elapsed.Seconds())))
+	}
+	m.show(line, true)
+}
+
+// stop ends a line left drawn, as by a download that failed, so that
+// what is printed next starts on a line of its own.
+func (m *meter) stop() {
+	if m != nil && m.drawn {
+		fmt.Fprintln(m.w)
+		m.drawn = false
+	}
+}
+
+func (m *meter) show(line string, final bool) {
+	switch {
+	case m.tty && final:
+		fmt.Fprintf(m.w, "\r%s\x1b[K\n", line)
+		m.drawn = false
+	case m.tty:
+		fmt.Fprintf(m.w, "\r%s\x1b[K", line)
+		m.drawn = true
+	default:
+		fmt.Fprintln(m.w, line)
+	}
+}
+
+// eta returns the time left with n bytes done, or -1 if it can't tell.
+func (m *meter) eta(n int64) time.Duration {
+	if m.total <= 0 || m.rate <= 0 {
+		return -1
+	}
+	return time.Duration(float64(m.total-n) / m.rate * float64(time.Second))
+}
+
+// line formats the progress at n bytes done, at rate bytes per second,
+// with eta left, if it is known.
+func (m *meter) line(n int64, detail string, rate float64, eta time.Duration) string {
+	if detail == "" {
+		detail = formatByteSize(n)
+		if m.total > 0 {
+			detail += " / " + formatByteSize(m.total)
+		}
+	}
+	var b strings.Builder
+	b.WriteString(m.verb)
+	if m.total > 0 {
+		fmt.Fprintf(&b, " %5.1f%%", 100*float64(n)/float64(m.total))
+	}
+	fmt.Fprintf(&b, " (%s)", detail)
+	if m.speed && rate > 0 {
+		fmt.Fprintf(&b, ", %s/s", formatByteSize(int64(rate)))
+	}
+	if eta >= 0 {
+		fmt.Fprintf(&b, ", %v left", roundDuration(eta))
+	}
+	return b.String()
+}
+
+// roundDuration rounds d for showing to people: to the second, or to the
+// tenth of a second under ten seconds.
+func roundDuration(d time.Duration) time.Duration {
+	if d < 10*time.Second {
+		return d.Round(100 * time.Millisecond)
+	}
+	return d.Round(time.Second)
+}
+
+// unpackDetail describes the progress of unpacking an archive.
+func unpackDetail(p UnpackProgress) string {
+	return fmt.Sprintf("%d files, %s", p.Files, formatByteSize(p.Bytes))
+}
+
+// A countingReader counts the bytes read from r.
+type countingReader struct {
+	r io.Reader
+	n int64 // atomic, as the unpacker may read from another goroutine
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	atomic.AddInt64(&c.n, int64(n))
+	return n, err
+}
+
+// count returns the number of bytes read so far.
+func (c *countingReader) count() int64 {
+	return atomic.LoadInt64(&c.n)
+}
@@ -0,0 +1,73 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMeterLine(t *testing.T) {
+	m := newMeterTo(nil, false, "Downloaded", 4<<20, 0, true)
+	for _, tt := range []struct {
+		n      int64
+		detail string
+		rate   float64
+		eta    time.Duration
+		want   string
+	}{
+		{1 << 20, "", 0, -1, "Downloaded  25.0% (1.0 MiB / 4.0 MiB)"},
+		{1 << 20, "", 512 << 10, 6 * time.Second, "Downloaded  25.0% (1.0 MiB / 4.0 MiB), 512.0 KiB/s, 6s left"},
+		{2 << 20, "12 files, 9.0 MiB", 0, 90 * time.Second, "Downloaded  50.0% (12 files, 9.0 MiB), 1m30s left"},
+	} {
+		if got := m.line(tt.n, tt.detail, tt.rate, tt.eta); got != tt.want {
+			t.Errorf("line(%d, %q, %v, %v) = %q; want %q", tt.n, tt.detail, tt.rate, tt.eta, got, tt.want)
+		}
+	}
+
+	// Without a total, only the bytes done are known.
+	m = newMeterTo(nil, false, "Downloaded", -1, 0, true)
+	if got, want := m.line(3<<10, "", 0, -1), "Downloaded (3.0 KiB)"; got != want {
+		t.Errorf("line without a total = %q; want %q", got, want)
+	}
+}
+
+func TestMeterOutput(t *testing.T) {
+	defer func(redraw, interval time.Duration) {
+		meterRedraw, meterInterval = redraw, interval
+	}(meterRedraw, meterInterval)
+	meterRedraw, meterInterval = 0, time.Hour
+
+	// Elsewhere than on a terminal, lines are printed only every
+	// meterInterval, and the final one always.
+	var buf bytes.Buffer
+	m := newMeterTo(&buf, false, "Unpacked", 100, 0, false)
+	m.update(50, "")
+	m.finish(100, "")
+	if lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n"); len(lines) != 1 || !strings.HasPrefix(lines[0], "Unpacked 100.0% (100 B / 100 B) in ") {
+		t.Errorf("meter printed %q; want only the final line", buf.String())
+	}
+
+	// On a terminal, the line is redrawn in place.
+	buf.Reset()
+	m = newMeterTo(&buf, true, "Downloaded", 100, 0, true)
+	time.Sleep(time.Millisecond)
+	m.update(50, "")
+	if !m.drawn || !strings.HasPrefix(buf.String(), "\rDownloaded  50.0%") || strings.Contains(buf.String(), "\n") {
+		t.Errorf("meter on a terminal drew %q; want the line redrawn in place", buf.String())
+	}
+	m.stop()
+	if m.drawn || !strings.HasSuffix(buf.String(), "\n") {
+		t.Errorf("stop left the line open: %q", buf.String())
+	}
+
+	// A nil meter shows nothing.
+	var nilMeter *meter
+	nilMeter.update(1, "")
+	nilMeter.finish(1, "")
+	nilMeter.stop()
+}
@@ -0,0 +1,102 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import "time"
+
+// Metrics is a set of hooks called at well-defined points of an install,
+// for feeding telemetry systems. Any hook may be nil. Hooks may be called
+// concurrently when a Downloader is used concurrently, and should return
+// quickly.
+type Metrics struct {
+	// Download is called when an archive download ends, successfully or
+	// not. host is the server it was fetched from, which is a mirror's
+	// when one answered in place of the base URL, bytes the number of
+	// bytes transferred by this attempt (excluding any resumed prefix),
+	// and d the time the transfer took.
+	Download func(host string, bytes int64, d time.Duration, err error)
+
+	// Verify is called once per install with the outcome of checking the
+	// archive's checksum.
+	Verify func(outcome VerifyOutcome)
+
+	// Unpack is called when extracting an archive ends, with the number of
+	// files and bytes written.
+	Unpack func(files int, bytes int64, d time.Duration, err error)
+
+	// Build is called when building the gotip toolchain ends.
+	Build func(d time.Duration, err error)
+
+	// BuildPhase is called when building the gotip toolchain starts a
+	// phase that the build announces, elapsed into the build.
+	BuildPhase func(phase string, elapsed time.Duration)
+
+	// Retry is called before a failed request or download is retried,
+	// with its URL, the number of the retry, counting from 1, the wait
+	// before it and the error it failed with. A response saying the
+	// server is overloaded or down is reported as an error naming its
+	// status.
+	Retry func(url string, attempt int, delay time.Duration, err error)
+
+	// Cache is called when looking for an archive in DownloaderOptions.CacheDir,
+	// with whether a complete archive was found there. It is not called
+	// when no CacheDir is configured.
+	Cache func(hit bool)
+}
+
+// A VerifyOutcome is the result of checking an archive's checksum.
+type VerifyOutcome string
+
+const (
+	VerifyOK          VerifyOutcome = "ok"          // the checksum matched
+	VerifyMismatch    VerifyOutcome = "mismatch"    // the checksum didn't match
+	VerifyUnpublished VerifyOutcome = "unpublished" // no checksum was published, and policy allowed that
+	VerifySkipped     VerifyOutcome = "skipped"     // policy is ChecksumSkip
+	VerifyError       VerifyOutcome = "error"       // the checksum couldn't be fetched or computed
+)
+
+// The methods below call the corresponding hook, if any.
+
+func (m *Metrics) download(host string, bytes int64, d time.Duration, err error) {
+	if m != nil && m.Download != nil {
+		m.Download(host, bytes, d, err)
+	}
+}
+
+func (m *Metrics) verify(o VerifyOutcome) {
+	if m != nil && m.Verify != nil {
+		m.Verify(o)
+	}
+}
+
+func (m *Metrics) unpack(p UnpackProgress, d time.Duration, err error) {
+	if m != nil && m.Unpack != nil {
+		m.Unpack(p.Files, p.Bytes, d, err)
+	}
+}
+
+func (m *Metrics) build(d time.Duration, err error) {
+	if m != nil && m.Build != nil {
+		m.Build(d, err)
+	}
+}
+
+func (m *Metrics) buildPhase(phase string, elapsed time.Duration) {
+	if m != nil && m.BuildPhase != nil {
+		m.BuildPhase(phase, elapsed)
+	}
+}
+
+func (m *Metrics) retry(url string, attempt int, delay time.Duration, err error) {
+	if m != nil && m.Retry != nil {
+		m.Retry(url, attempt, delay, err)
+	}
+}
+
+func (m *Metrics) cache(hit bool) {
+	if m != nil && m.Cache != nil {
+		m.Cache(hit)
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// metricsRecorder records the hooks called, in order.
+type metricsRecorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *metricsRecorder) record(format string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, fmt.Sprintf(format, args...))
+}
+
+func (r *metricsRecorder) metrics() *Metrics {
+	return &Metrics{
+		Download: func(host string, bytes int64, d time.Duration, err error) {
+			r.record("download %s %d %v", host, bytes, err)
+		},
+		Verify: func(o VerifyOutcome) { r.record("verify %s", o) },
+		Unpack: func(files int, bytes int64, d time.Duration, err error) {
+			r.record("unpack %d %d %v", files, bytes, err)
+		},
+		Retry: func(url string, attempt int, delay time.Duration, err error) {
+			r.record("retry %s %d %v", path.Base(url), attempt, err)
+		},
+		Cache: func(hit bool) { r.record("cache hit=%v", hit) },
+	}
+}
+
+func (r *metricsRecorder) take() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	calls := r.calls
+	r.calls = nil
+	return calls
+}
+
+func TestMetrics(t *testing.T) {
+	ts := newTestServer(t)
+	u, _ := url.Parse(DefaultBaseURL)
+	archive := ts.tar
+	if strings.HasSuffix(versionArchiveURL(DefaultBaseURL, "go1.99"), ".zip") {
+		archive = ts.zip
+	}
+	var size int64
+	for _, body := range testFiles {
+		size += int64(len(body))
+	}
+
+	var r metricsRecorder
+	d := ts.downloader(t, DownloaderOptions{CacheDir: t.TempDir(), Metrics: r.metrics()})
+	if err := d.install(context.Background(), t.TempDir(), "go1.99"); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+	want := []string{
+		"cache hit=false",
+		fmt.Sprintf("download %s %d <nil>", u.Host, len(archive)),
+		"verify ok",
+		fmt.Sprintf("unpack %d %d <nil>", len(testFiles), size),
+	}
+	if got := r.take(); !reflect.DeepEqual(got, want) {
+		t.Errorf("first install metrics:\n%q\nwant:\n%q", got, want)
+	}
+
+	// Installing elsewhere reuses the cached archive.
+	if err := d.install(context.Background(), t.TempDir(), "go1.99"); err != nil {
+		t.Fatalf("second install: %v", err)
+	}
+	want = append([]string{"cache hit=true"}, want[2:]...)
+	if got := r.take(); !reflect.DeepEqual(got, want) {
+		t.Errorf("cached install metrics:\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestMetricsVerifyMismatch(t *testing.T) {
+	ts := &testServer{
+		tar: makeTestArchive(t, testFiles, false),
+		zip: makeTestArchive(t, testFiles, true),
+	}
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, ".sha256") {
+			fmt.Fprintln(w, strings.Repeat("0", 64))
+			return
+		}
+		ts.serve(w, r)
+	}))
+	defer ts.Close()
+
+	var r metricsRecorder
+	d := ts.downloader(t, DownloaderOptions{Metrics: r.metrics()})
+	if err := d.install(context.Background(), t.TempDir(), "go1.99"); err == nil {
+		t.Fatal("install succeeded despite a checksum mismatch")
+	}
+	calls := r.take()
+	if last := calls[len(calls)-1]; last != "verify mismatch" {
+		t.Errorf("last metric = %q; want verify mismatch (all: %q)", last, calls)
+	}
+}
+
+func TestMetricsRetry(t *testing.T) {
+	ts := &testServer{
+		tar: makeTestArchive(t, testFiles, false),
+		zip: makeTestArchive(t, testFiles, true),
+	}
+	var failed int32
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && isArchiveName(path.Base(r.URL.Path)) && !strings.HasSuffix(r.URL.Path, ".sha256") && atomic.AddInt32(&failed, 1) == 1 {
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+			return
+		}
+		ts.serve(w, r)
+	}))
+	defer ts.Close()
+
+	var r metricsRecorder
+	d := ts.downloader(t, DownloaderOptions{Retries: 1, RetryBackoff: time.Millisecond, Metrics: r.metrics()})
+	if err := d.install(context.Background(), t.TempDir(), "go1.99"); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+	name := path.Base(versionArchiveURL(DefaultBaseURL, "go1.99"))
+	calls := r.take()
+	if len(calls) == 0 || !strings.HasPrefix(calls[0], "retry "+name+" 1 ") || !strings.Contains(calls[0], "503") {
+		t.Errorf("metrics = %q; want a first retry of %s after a 503", calls, name)
+	}
+}
+
+// failingHostTransport fails every request to host, sending the rest on
+// to rt.
+type failingHostTransport struct {
+	host string
+	rt   http.RoundTripper
+}
+
+func (f failingHostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host == f.host {
+		return nil, errors.New("connection refused")
+	}
+	return f.rt.RoundTrip(req)
+}
+
+func TestMetricsMirrorHost(t *testing.T) {
+	ts := newTestServer(t)
+	u, _ := url.Parse(ts.URL)
+	client := &http.Client{Transport: failingHostTransport{"primary.example.com", redirectTransport{u, http.DefaultTransport}}}
+	tests := []struct {
+		name string
+		opts DownloaderOptions
+	}{
+		{"whole", DownloaderOptions{}},
+		{"segments", DownloaderOptions{Segments: 2}},
+		{"stream", DownloaderOptions{Stream: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r metricsRecorder
+			opts := tt.opts
+			opts.Client = client
+			opts.BaseURL = "https://primary.example.com/go/"
+			opts.Mirrors = []string{"https://secondary.example.com/go/"}
+			opts.Metrics = r.metrics()
+			d, err := NewDownloader(opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := d.install(context.Background(), t.TempDir(), "go1.99"); err != nil {
+				t.Fatalf("install: %v", err)
+			}
+			// The secondary mirror answered for the primary.
+			var downloads []string
+			for _, c := range r.take() {
+				if strings.HasPrefix(c, "download ") {
+					downloads = append(downloads, strings.Fields(c)[1])
+				}
+			}
+			if want := []string{"secondary.example.com"}; !reflect.DeepEqual(downloads, want) {
+				t.Errorf("Download hosts = %q; want %q", downloads, want)
+			}
+		})
+	}
+}
+
+func TestMetricsNil(t *testing.T) {
+	// A nil *Metrics, or one with nil hooks, is safe to call.
+	var m *Metrics
+	m.download("example.com", 1, time.Second, nil)
+	m.verify(VerifyOK)
+	m.cache(true)
+	m.retry("https://example.com/x", 1, time.Second, nil)
+	m = new(Metrics)
+	m.unpack(UnpackProgress{}, time.Second, nil)
+	m.build(time.Second, nil)
+}
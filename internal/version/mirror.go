@@ -0,0 +1,125 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// A mirrorDoer makes each request for a file under the first of bases
+// with each of bases in turn, until one serves it: a base that can't be
+// reached, or answers that it is overloaded or down, or doesn't have the
+// file, is passed over for the next. Bases that failed are remembered,
+// and tried only after those that haven't, for the rest of the session,
+// so that a flaky mirror costs one wait rather than one per file.
+type mirrorDoer struct {
+	d     Doer
+	bases []string // base URLs with a trailing slash, the primary first
+
+	mu       sync.Mutex
+	failures []int // of each base, since it last served a file
+}
+
+func newMirrorDoer(d Doer, bases []string) *mirrorDoer {
+	return &mirrorDoer{d: d, bases: bases, failures: make([]int, len(bases))}
+}
+
+func (m *mirrorDoer) Do(req *http.Request) (*http.Response, error) {
+	s := req.URL.String()
+	if !strings.HasPrefix(s, m.bases[0]) {
+		return m.d.Do(req)
+	}
+	path := strings.TrimPrefix(s, m.bases[0])
+	order := m.order()
+	for i, b := range order {
+		r := req
+		if b != 0 {
+			var err error
+			if r, err = withURL(req, m.bases[b]+path); err != nil {
+				return nil, err
+			}
+		}
+		res, err := m.d.Do(r)
+		if err != nil && req.Context().Err() != nil {
+			return nil, err
+		}
+		var why string
+		switch {
+		case err != nil:
+			m.failed(b)
+			why = err.Error()
+		case retryableStatus(res.StatusCode):
+			m.failed(b)
+			why = res.Status
+		case res.StatusCode == http.StatusNotFound:
+			why = res.Status // a partial mirror, but not a failing one
+		default:
+			m.served(b)
+			return res, nil
+		}
+		if i == len(order)-1 {
+			return res, err
+		}
+		if res != nil {
+			_, _ = io.Copy(ioutil.Discard, io.LimitReader(res.Body, 4<<10))
+			_ = res.Body.Close()
+		}
+		log.Printf("Note: %s: %s; trying %s", hostOf(m.bases[b]), why, hostOf(m.bases[order[i+1]]))
+	}
+	panic("unreachable")
+}
+
+// order returns the indexes of the bases to try, in order: those that
+// haven't failed, then those that have, each in the order configured.
+func (m *mirrorDoer) order() []int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var healthy, failing []int
+	for i, n := range m.failures {
+		if n == 0 {
+			healthy = append(healthy, i)
+		} else {
+			failing = append(failing, i)
+		}
+	}
+	return append(healthy, failing...)
+}
+
+func (m *mirrorDoer) failed(b int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.failures[b]++
+}
+
+func (m *mirrorDoer) served(b int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.failures[b] = 0
+}
+
+// checkMirrors validates mirror base URLs, as checkBaseURL does, and
+// returns them each with a trailing slash.
+func checkMirrors(mirrors []string) ([]string, error) {
+	var out []string
+	for _, s := range mirrors {
+		u, err := checkBaseURL(s)
+		if err != nil || s == "" {
+			return nil, fmt.Errorf("invalid mirror %q: must be an absolute http, https, s3 or gs URL", s)
+		}
+		out = append(out, u)
+	}
+	return out, nil
+}
+
+// parseMirrors splits a list of mirrors separated by commas or spaces.
+func parseMirrors(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+}
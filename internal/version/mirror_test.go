@@ -0,0 +1,89 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestMirrorDoer(t *testing.T) {
+	const (
+		primary   = "https://primary.example.com/go/"
+		secondary = "https://secondary.example.com/go/"
+	)
+	bases := []string{primary, secondary, DefaultBaseURL}
+	b := &blockedDoer{
+		blocked:  map[string]bool{"primary.example.com": true},
+		status:   http.StatusOK,
+		statuses: map[string]int{"secondary.example.com": http.StatusNotFound},
+	}
+	m := newMirrorDoer(b, bases)
+	get := func(url string) int {
+		t.Helper()
+		b.urls = nil
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := m.Do(req)
+		if err != nil {
+			t.Fatalf("GET %s: %v", url, err)
+		}
+		res.Body.Close()
+		return res.StatusCode
+	}
+
+	// The primary can't be reached and the secondary lacks the file, so
+	// the public site serves it.
+	if code := get(primary + "go1.22.7.linux-amd64.tar.gz"); code != http.StatusOK {
+		t.Errorf("GET = %d; want %d", code, http.StatusOK)
+	}
+	want := []string{
+		primary + "go1.22.7.linux-amd64.tar.gz",
+		secondary + "go1.22.7.linux-amd64.tar.gz",
+		DefaultBaseURL + "go1.22.7.linux-amd64.tar.gz",
+	}
+	if !reflect.DeepEqual(b.urls, want) {
+		t.Errorf("first GET requested\n%q\nwant\n%q", b.urls, want)
+	}
+
+	// The primary failed, so it is tried last from then on, but the
+	// secondary, which only lacked a file, is still tried first.
+	get(primary + "go1.22.7.linux-amd64.tar.gz.sha256")
+	want = []string{
+		secondary + "go1.22.7.linux-amd64.tar.gz.sha256",
+		DefaultBaseURL + "go1.22.7.linux-amd64.tar.gz.sha256",
+	}
+	if !reflect.DeepEqual(b.urls, want) {
+		t.Errorf("second GET requested\n%q\nwant\n%q", b.urls, want)
+	}
+
+	// Once it recovers and serves a file, it is first again.
+	b.blocked = nil
+	b.statuses = map[string]int{"secondary.example.com": http.StatusServiceUnavailable, "dl.google.com": http.StatusServiceUnavailable}
+	get(primary + "go1.21.13.linux-amd64.tar.gz")
+	get(primary + "go1.21.13.linux-amd64.tar.gz.sha256")
+	if want := []string{primary + "go1.21.13.linux-amd64.tar.gz.sha256"}; !reflect.DeepEqual(b.urls, want) {
+		t.Errorf("GET after recovering requested %q; want %q", b.urls, want)
+	}
+
+	// When every base fails, the last answer is returned.
+	b.statuses["primary.example.com"] = http.StatusBadGateway
+	if code := get(primary + "go1.20.14.linux-amd64.tar.gz"); code != http.StatusServiceUnavailable || len(b.urls) != len(bases) {
+		t.Errorf("GET from failing mirrors = %d after %q; want %d after trying each", code, b.urls, http.StatusServiceUnavailable)
+	}
+
+	// Other URLs are left alone.
+	get(DefaultCatalogURL)
+	if want := []string{DefaultCatalogURL}; !reflect.DeepEqual(b.urls, want) {
+		t.Errorf("GET of another URL requested %q; want %q", b.urls, want)
+	}
+
+	if _, err := NewDownloader(DownloaderOptions{Mirrors: []string{"ftp://mirror.example.com/"}}); err == nil {
+		t.Errorf("NewDownloader accepted an ftp mirror")
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+)
+
+// toolchainModule is the module whose versions hold the Go releases, for
+// the go command to switch toolchains with.
+const toolchainModule = "golang.org/toolchain"
+
+// toolchainModuleVersion returns the version of toolchainModule that holds
+// release version for goos/goarch, such as v0.0.1-go1.22.7.linux-amd64.
+func toolchainModuleVersion(version, goos, goarch string) string {
+	if goos == "android" {
+		goos = "linux"
+	}
+	return "v0.0.1-" + version + "." + goos + "-" + goarch
+}
+
+// moduleArchiveName returns the name a module zip of release version for
+// goos/goarch is downloaded as, such as go1.22.7.linux-amd64.module.zip,
+// apart from the release archives.
+func moduleArchiveName(version, goos, goarch string) string {
+	return strings.TrimPrefix(toolchainModuleVersion(version, goos, goarch), "v0.0.1-") + ".module.zip"
+}
+
+// moduleZipURL returns the URL of the module zip of release version for
+// goos/goarch on the module proxy.
+func (d *Downloader) moduleZipURL(version, goos, goarch string) string {
+	proxy, _ := moduleProxy(d.opts.ModuleProxy)
+	return proxy + "/" + toolchainModule + "/@v/" + toolchainModuleVersion(version, goos, goarch) + ".zip"
+}
+
+// moduleLookupURL returns the URL that the checksum database records the
+// go.sum hash of the module zip of release version for goos/goarch at,
+// or "" if there is no database to verify it with.
+func (d *Downloader) moduleLookupURL(version, goos, goarch string) string {
+	db, _ := parseSumDB(d.opts.SumDB)
+	if db == nil {
+		return ""
+	}
+	return db.url + "/lookup/" + toolchainModule + "@" + toolchainModuleVersion(version, goos, goarch)
+}
+
+// moduleZipPrefix returns the directory, such as
+// golang.org/toolchain@v0.0.1-go1.22.7.linux-amd64/, that the entry name
+// of a module zip is in, or "" if name isn't in a toolchain module zip.
+func moduleZipPrefix(name string) string {
+	if !strings.HasPrefix(name, toolchainModule+"@") {
+		return ""
+	}
+	i := strings.Index(name[len(toolchainModule):], "/")
+	if i < 0 {
+		return ""
+	}
+	return name[:len(toolchainModule)+i+1]
+}
+
+// moduleFileMode returns the mode to unpack the file at rel, relative to
+// the GOROOT, from a module zip with, which keeps no modes: the commands
+// in bin and pkg/tool are made executable, as the go command makes them.
+func moduleFileMode(rel string) os.FileMode {
+	if strings.HasPrefix(rel, "bin/") || strings.HasPrefix(rel, "pkg/tool/") {
+		return 0755
+	}
+	return 0644
+}
+
+// verifyModule checks the module zip File of the verify-module step s
+// against the go.sum hash that the checksum database records for it at
+// s.URL, according to s.Checksum.
+func (d *Downloader) verifyModule(ctx context.Context, s Step) error {
+	if s.Checksum == ChecksumSkip {
+		log.Printf("Skipping checksum verification of %v", s.File)
+		d.opts.Metrics.verify(VerifySkipped)
+		return nil
+	}
+	want, err := d.moduleHash(ctx, s)
+	if err != nil {
+		if s.Checksum == ChecksumIfPublished && errors.Is(err, errNoRecord) {
+			log.Printf("Note: %v; installing without verification", err)
+			d.opts.Metrics.verify(VerifyUnpublished)
+			return nil
+		}
+		d.opts.Metrics.verify(VerifyError)
+		return fmt.Errorf("error verifying %v: %w", s.File, err)
+	}
+	got, err := hashZip(s.File)
+	if err == nil && got != want {
+		err = &ChecksumError{File: s.File, Want: want, Got: got, Hash: "go.sum hash"}
+	}
+	d.emitter().emit(VerificationResult{File: s.File, SHA256: want, Err: err})
+	var ce *ChecksumError
+	switch {
+	case err == nil:
+		verbosef("Verified go.sum hash %s of %v, from %s", want, s.File, s.URL)
+		d.opts.Metrics.verify(VerifyOK)
+		return nil
+	case errors.As(err, &ce):
+		d.opts.Metrics.verify(VerifyMismatch)
+	default:
+		d.opts.Metrics.verify(VerifyError)
+	}
+	return fmt.Errorf("error verifying %v: %w", s.File, err)
+}
+
+// moduleHash returns the go.sum hash that the checksum database records
+// for the module zip of the verify-module step s. It is saved beside the
+// zip in the cache, as a release archive's SHA-256 is, for verifying it
+// in offline mode.
+func (d *Downloader) moduleHash(ctx context.Context, s Step) (string, error) {
+	saved := s.File + ".sha256"
+	if d.opts.Offline {
+		sum, err := ioutil.ReadFile(saved)
+		if err != nil {
+			return "", netGate(true, s.URL)
+		}
+		return strings.TrimSpace(string(sum)), nil
+	}
+	db, err := parseSumDB(d.opts.SumDB)
+	if err != nil {
+		return "", err
+	}
+	if db == nil || s.URL == "" {
+		return "", fmt.Errorf("%w, as %s is off", errNoRecord, envSumDB)
+	}
+	var modVersion []string
+	if i := strings.LastIndex(s.URL, "/lookup/"); i >= 0 {
+		modVersion = strings.SplitN(s.URL[i+len("/lookup/"):], "@", 2)
+	}
+	if len(modVersion) != 2 {
+		return "", fmt.Errorf("invalid checksum database lookup %s", s.URL)
+	}
+	proxy, _ := moduleProxy(d.opts.ModuleProxy)
+	sum, err := d.lookup(ctx, db, proxy, modVersion[0], modVersion[1])
+	if err == nil && d.opts.CacheDir != "" {
+		_ = writeFileAtomic(saved, []byte(sum+"\n"))
+	}
+	return sum, err
+}
@@ -0,0 +1,214 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHashZip(t *testing.T) {
+	const prefix = "golang.org/toolchain@v0.0.1-go1.99.linux-amd64/"
+	file := filepath.Join(t.TempDir(), "go1.99.linux-amd64.module.zip")
+	writeTestFile(t, file, makeTestArchive(t, map[string]string{
+		prefix + "VERSION": "go1.99",
+		prefix + "bin/go":  "#!/bin/sh\necho fake go\n",
+	}, true))
+	got, err := hashZip(file)
+	if want := "h1:PV9TVETyRr7gU62/4YJUasDtIdg90xdFQBQ4fXTFxfg="; got != want || err != nil {
+		t.Errorf("hashZip = %q, %v; want %q", got, err, want)
+	}
+}
+
+func TestParseSumDB(t *testing.T) {
+	for _, tt := range []struct {
+		in       string
+		name     string
+		url      string
+		off, bad bool
+	}{
+		{in: "", name: "sum.golang.org", url: "https://sum.golang.org"},
+		{in: "sum.golang.org", name: "sum.golang.org", url: "https://sum.golang.org"},
+		{in: "sum.golang.google.cn", name: "sum.golang.org", url: "https://sum.golang.google.cn"},
+		{in: "sum.golang.org https://sumdb.example.com/", name: "sum.golang.org", url: "https://sumdb.example.com"},
+		{in: knownSumDBs["sum.golang.org"], name: "sum.golang.org", url: "https://sum.golang.org"},
+		{in: "off", off: true},
+		{in: "sum.example.com", bad: true},
+		{in: "sum.golang.org+00000000+Ac4zctda0e5eza+HJyk9SxEdh+s3Ux18htTTAD8OuAn8", bad: true},
+		{in: "sum.golang.org ftp://sumdb.example.com", bad: true},
+	} {
+		db, err := parseSumDB(tt.in)
+		switch {
+		case tt.bad:
+			if err == nil {
+				t.Errorf("parseSumDB(%q) succeeded; want an error", tt.in)
+			}
+		case err != nil:
+			t.Errorf("parseSumDB(%q): %v", tt.in, err)
+		case tt.off:
+			if db != nil {
+				t.Errorf("parseSumDB(%q) = %+v; want none", tt.in, db)
+			}
+		case db.name != tt.name || db.url != tt.url:
+			t.Errorf("parseSumDB(%q) = %s at %s; want %s at %s", tt.in, db.name, db.url, tt.name, tt.url)
+		}
+	}
+}
+
+// moduleServer is a module proxy serving fake golang.org/toolchain zips,
+// that mirrors a checksum database signed with key.
+type moduleServer struct {
+	*httptest.Server
+	name  string // of the checksum database
+	key   ed25519.PrivateKey
+	vkey  string // its verifier key
+	hash  uint32 // of the verifier key
+	files map[string]string
+
+	// record, if set, replaces the go.sum hash the database records.
+	record string
+}
+
+func newModuleServer(t *testing.T) *moduleServer {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := append([]byte{1}, pub...)
+	ms := &moduleServer{
+		name: "sum.example.com",
+		key:  priv,
+		files: map[string]string{
+			"VERSION":            "go1.99",
+			"bin/go":             "#!/bin/sh\necho fake go\n",
+			"pkg/tool/x/compile": "#!/bin/sh\n",
+			"src/a.go":           "package a\n",
+		},
+	}
+	ms.hash = keyHash(ms.name, key)
+	ms.vkey = fmt.Sprintf("%s+%08x+%s", ms.name, ms.hash, base64.StdEncoding.EncodeToString(key))
+	ms.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ms.serve(t, w, r)
+	}))
+	t.Cleanup(ms.Close)
+	return ms
+}
+
+// zip returns the module zip of version of the toolchain module.
+func (ms *moduleServer) zip(t *testing.T, version string) []byte {
+	files := map[string]string{}
+	for name, body := range ms.files {
+		files[toolchainModule+"@"+version+"/"+name] = body
+	}
+	return makeTestArchive(t, files, true)
+}
+
+func (ms *moduleServer) serve(t *testing.T, w http.ResponseWriter, r *http.Request) {
+	zipPrefix := "/" + toolchainModule + "/@v/"
+	lookupPrefix := "/sumdb/" + ms.name + "/lookup/" + toolchainModule + "@"
+	switch {
+	case strings.HasPrefix(r.URL.Path, zipPrefix) && strings.HasSuffix(r.URL.Path, ".zip"):
+		version := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, zipPrefix), ".zip")
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(ms.zip(t, version)))
+	case strings.HasPrefix(r.URL.Path, lookupPrefix):
+		version := strings.TrimPrefix(r.URL.Path, lookupPrefix)
+		sum := ms.record
+		if sum == "" {
+			file := filepath.Join(t.TempDir(), "m.zip")
+			writeTestFile(t, file, ms.zip(t, version))
+			var err error
+			if sum, err = hashZip(file); err != nil {
+				t.Error(err)
+			}
+		}
+		text := "go.sum database tree\n42\n" + base64.StdEncoding.EncodeToString(make([]byte, 32)) + "\n"
+		sig := make([]byte, 4, 4+ed25519.SignatureSize)
+		binary.BigEndian.PutUint32(sig, ms.hash)
+		sig = append(sig, ed25519.Sign(ms.key, []byte(text))...)
+		fmt.Fprintf(w, "41\n%s %s %s\n%s %s/go.mod h1:x\n\n%s\n— %s %s\n",
+			toolchainModule, version, sum, toolchainModule, version, text, ms.name, base64.StdEncoding.EncodeToString(sig))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestModuleProxyInstall(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake go command is a shell script")
+	}
+	ms := newModuleServer(t)
+	ctx := context.Background()
+	newDownloader := func(opts DownloaderOptions) *Downloader {
+		opts.BaseURL = ms.URL + "/dl/"
+		opts.ModuleProxy = "off," + ms.URL + "|direct"
+		opts.SumDB = ms.vkey + " " + ms.URL + "/nosumdb"
+		d, err := NewDownloader(opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	cache := t.TempDir()
+	d := newDownloader(DownloaderOptions{CacheDir: cache})
+	root := filepath.Join(t.TempDir(), "go1.99")
+	p, err := d.plan(ctx, root, "go1.99")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hasStep(p, StepVerifyModule) || hasStep(p, StepVerify) {
+		t.Errorf("plan = %v; want the module zip verified with the checksum database", p)
+	}
+	if err := d.Execute(ctx, p); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	for _, name := range []string{"bin/go", "pkg/tool/x/compile"} {
+		fi, err := os.Stat(filepath.Join(root, filepath.FromSlash(name)))
+		if err != nil || fi.Mode()&0100 == 0 {
+			t.Errorf("%s isn't executable: %v, %v", name, fi, err)
+		}
+	}
+	if b, err := ioutil.ReadFile(filepath.Join(root, "VERSION")); err != nil || string(b) != "go1.99" {
+		t.Errorf("VERSION = %q, %v; want the module directory stripped", b, err)
+	}
+	entries, err := cacheEntries(cache)
+	if err != nil || len(entries) != 1 || entries[0].Version != "go1.99" {
+		t.Errorf("cache entries = %+v, %v; want the module zip of go1.99", entries, err)
+	}
+
+	// A zip that isn't the one the database records isn't unpacked.
+	ms.record = "h1:" + base64.StdEncoding.EncodeToString(make([]byte, 32))
+	bad := filepath.Join(t.TempDir(), "go1.99")
+	var ce *ChecksumError
+	if err := newDownloader(DownloaderOptions{}).install(ctx, bad, "go1.99"); !errors.As(err, &ce) {
+		t.Errorf("install with the wrong go.sum hash = %v; want a checksum error", err)
+	}
+	if _, err := os.Stat(filepath.Join(bad, "bin", "go")); err == nil {
+		t.Errorf("install with the wrong go.sum hash unpacked the zip")
+	}
+
+	// Nor is one whose record isn't signed by the database.
+	ms.record = ""
+	_, other, _ := ed25519.GenerateKey(rand.Reader)
+	ms.key = other
+	if err := newDownloader(DownloaderOptions{}).install(ctx, filepath.Join(t.TempDir(), "go1.99"), "go1.99"); err == nil || !strings.Contains(err.Error(), "signature") {
+		t.Errorf("install with a forged tree head = %v; want a signature error", err)
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// stNoexec is ST_NOEXEC, the statfs flag of file systems mounted noexec.
+const stNoexec = 0x8
+
+// checkExecAllowed reports an error if programs in dir can't be run, as
+// on Android's shared storage or a noexec /tmp, so that installs there
+// fail before downloading anything.
+func checkExecAllowed(dir string) error {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(dir, &st); err != nil {
+		return nil // can't tell; the smoke test will
+	}
+	if st.Flags&stNoexec != 0 {
+		hint := ""
+		if termuxPrefix() != "" {
+			hint = "; in Termux, install under $HOME"
+		}
+		return fmt.Errorf("%s is on a file system that doesn't allow running programs%s", dir, hint)
+	}
+	return nil
+}
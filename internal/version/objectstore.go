@@ -0,0 +1,111 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// An objectStoreDoer makes the requests for s3:// and gs:// URLs, which
+// name objects in Amazon S3 and Google Cloud Storage buckets, as requests
+// to the services' https APIs, with the credentials that the clouds' own
+// tools would find. Other requests are passed on to d.
+type objectStoreDoer struct {
+	d   Doer
+	s3  *s3Client
+	gcs *gcsClient
+}
+
+// newObjectStoreDoer returns d, able to fetch from object storage too.
+// Credentials are looked up on the first request that needs them.
+func newObjectStoreDoer(d Doer) *objectStoreDoer {
+	meta := newMetadataClient()
+	return &objectStoreDoer{
+		d:   d,
+		s3:  &s3Client{d: d, meta: meta, regions: map[string]string{}},
+		gcs: &gcsClient{d: d, meta: meta},
+	}
+}
+
+func (o *objectStoreDoer) Do(req *http.Request) (*http.Response, error) {
+	switch req.URL.Scheme {
+	case "s3":
+		return o.s3.do(req)
+	case "gs":
+		return o.gcs.do(req)
+	}
+	return o.d.Do(req)
+}
+
+// isObjectStoreURL reports whether s is an s3:// or gs:// URL.
+func isObjectStoreURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "s3" || u.Scheme == "gs")
+}
+
+// anyObjectStoreURL reports whether any of urls is an s3:// or gs:// URL.
+func anyObjectStoreURL(urls []string) bool {
+	for _, u := range urls {
+		if isObjectStoreURL(u) {
+			return true
+		}
+	}
+	return false
+}
+
+// newMetadataClient returns the client that asks the instance metadata
+// services of the clouds for credentials. They are on link-local
+// addresses, so it never uses a proxy, and gives up quickly off the cloud.
+func newMetadataClient() *http.Client {
+	return &http.Client{
+		Timeout: 5 * time.Second,
+		Transport: &http.Transport{
+			DialContext: (&net.Dialer{Timeout: time.Second}).DialContext,
+		},
+	}
+}
+
+// objectRequest returns req for the https URL u instead, without the body
+// it may have had: object requests have none.
+func objectRequest(req *http.Request, u string) (*http.Request, error) {
+	r, err := http.NewRequestWithContext(req.Context(), req.Method, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range req.Header {
+		r.Header[k] = v
+	}
+	return r, nil
+}
+
+// fetchJSON makes req with d and decodes the JSON it answers with into
+// v. A response other than 200 OK is an error.
+func fetchJSON(d Doer, req *http.Request, v interface{}) error {
+	res, err := d.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	body, err := ioutil.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		return &statusError{URL: req.URL.String(), Code: res.StatusCode, Status: res.Status}
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("unexpected reply from %s: %v", req.URL.Redacted(), err)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import "net/http"
+
+// An OfflineError reports that an operation needed the network, which
+// offline mode forbids.
+type OfflineError struct {
+	URL string
+}
+
+func (e *OfflineError) Error() string {
+	return "offline mode: would need to fetch " + e.URL
+}
+
+// netGate is the one place network access is allowed or refused. In
+// offline mode every HTTP request, made through offlineDoer, and every
+// other use of the network, such as gotip's git commands, is refused with
+// an *OfflineError.
+func netGate(offline bool, url string) error {
+	if offline {
+		return &OfflineError{URL: url}
+	}
+	return nil
+}
+
+// offlineDoer is the Doer of a Downloader in offline mode. It refuses
+// every request, before it reaches any transport.
+type offlineDoer struct{}
+
+func (offlineDoer) Do(req *http.Request) (*http.Response, error) {
+	return nil, netGate(true, req.URL.String())
+}
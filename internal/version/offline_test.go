@@ -0,0 +1,78 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestOffline(t *testing.T) {
+	ts := newTestServer(t)
+	ctx := context.Background()
+	cache := t.TempDir()
+
+	// Fill the cache while online.
+	online := ts.downloader(t, DownloaderOptions{CacheDir: cache})
+	if err := online.install(ctx, t.TempDir(), "go1.99"); err != nil {
+		t.Fatalf("online install: %v", err)
+	}
+	ts.mu.Lock()
+	ts.reqs = nil
+	ts.mu.Unlock()
+
+	d := ts.downloader(t, DownloaderOptions{CacheDir: cache, Offline: true})
+	installed := t.TempDir()
+	if err := d.install(ctx, installed, "go1.99"); err != nil {
+		t.Errorf("offline install from the cache: %v", err)
+	}
+	if p, err := d.plan(ctx, installed, "go1.99"); err != nil || !p.Installed {
+		t.Errorf("offline plan of an installed release = %+v, %v; want installed", p, err)
+	}
+
+	// Everything else needs the network, and must say so.
+	empty := ts.downloader(t, DownloaderOptions{CacheDir: t.TempDir(), Offline: true})
+	noCache := ts.downloader(t, DownloaderOptions{Offline: true})
+	offline := &DownloaderOptions{Offline: true}
+	ops := []struct {
+		name string
+		run  func() error
+	}{
+		{"install uncached release", func() error { return d.install(ctx, t.TempDir(), "go1.98") }},
+		{"install with empty cache", func() error { return empty.install(ctx, t.TempDir(), "go1.99") }},
+		{"install without cache", func() error { return noCache.install(ctx, t.TempDir(), "go1.99") }},
+		{"release listing", func() error { _, err := d.catalog.All(ctx, Filter{}); return err }},
+		{"self-update check", func() error { _, err := d.latestToolVersion(ctx, defaultProxy); return err }},
+		{"gotip download", func() error { return installTip(ctx, filepath.Join(t.TempDir(), "gotip"), "", nil, nil, offline) }},
+		{"gotip CL download", func() error { return installTip(ctx, filepath.Join(t.TempDir(), "gotip"), "12345", nil, nil, offline) }},
+	}
+	for _, op := range ops {
+		err := op.run()
+		var oe *OfflineError
+		if !errors.As(err, &oe) {
+			t.Errorf("%s: got %v; want an offline mode error", op.name, err)
+		}
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if len(ts.reqs) > 0 {
+		t.Errorf("offline mode made requests: %q", ts.reqs)
+	}
+}
+
+func TestOfflineFromEnvironment(t *testing.T) {
+	t.Setenv(envOffline, "1")
+	opts, err := FromEnvironment()
+	if err != nil || !opts.Offline {
+		t.Fatalf("FromEnvironment with %s=1 = %+v, %v; want Offline", envOffline, opts, err)
+	}
+	t.Setenv(envOffline, "maybe")
+	if _, err := FromEnvironment(); err == nil {
+		t.Errorf("FromEnvironment accepted %s=maybe", envOffline)
+	}
+}
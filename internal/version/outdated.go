@@ -0,0 +1,61 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"fmt"
+	"io"
+)
+
+// An OutdatedEntry is a minor version with releases installed, as
+// dl outdated reports it: the newest of them, and the newest published.
+type OutdatedEntry struct {
+	Minor     string `json:"minor"`     // such as go1.22
+	Installed string `json:"installed"` // the newest release of Minor installed
+
+	// Latest is the newest release of Minor published: the newest stable
+	// one, unless only betas or release candidates of Minor are installed,
+	// when it may be a newer one of those. It is empty if the release
+	// listing has none.
+	Latest   string `json:"latest,omitempty"`
+	Outdated bool   `json:"outdated"` // Latest is newer than Installed
+}
+
+// outdated compares the newest installed release of each minor version in
+// installed, which are oldest first, with the releases rs, newest first,
+// and returns an entry for each minor version, oldest first.
+func outdated(installed []Version, rs []Release) []OutdatedEntry {
+	var entries []OutdatedEntry
+	for i, v := range installed {
+		if i+1 < len(installed) && installed[i+1].Major == v.Major && installed[i+1].Minor == v.Minor {
+			continue // not the newest of its minor version
+		}
+		e := OutdatedEntry{Minor: fmt.Sprintf("go%d.%d", v.Major, v.Minor), Installed: v.String()}
+		for _, r := range rs {
+			if r.Version.Major == v.Major && r.Version.Minor == v.Minor && (r.Stable || v.Pre != "") {
+				e.Latest = r.Version.String()
+				e.Outdated = v.Less(r.Version)
+				break
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// writeOutdated writes the outdated entries to w, as dl outdated prints
+// them, or says that there are none.
+func writeOutdated(w io.Writer, entries []OutdatedEntry) {
+	n := 0
+	for _, e := range entries {
+		if e.Outdated {
+			fmt.Fprintf(w, "%s → %s available\n", e.Installed, e.Latest)
+			n++
+		}
+	}
+	if n == 0 {
+		fmt.Fprintln(w, "Every installed minor version has its newest release installed.")
+	}
+}
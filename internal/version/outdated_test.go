@@ -0,0 +1,49 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestOutdated(t *testing.T) {
+	rs, err := newCatalogServer(t).catalog(t.TempDir()).All(context.Background(), Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var installed []Version
+	for _, name := range []string{"go1.9", "go1.21.13", "go1.22.0", "go1.22.5", "go1.23beta1", "go1.24.1"} {
+		v, err := ParseVersion(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		installed = append(installed, v)
+	}
+	got := outdated(installed, rs)
+	want := []OutdatedEntry{
+		{Minor: "go1.9", Installed: "go1.9", Latest: "go1.9"},
+		{Minor: "go1.21", Installed: "go1.21.13", Latest: "go1.21.13"},
+		{Minor: "go1.22", Installed: "go1.22.5", Latest: "go1.22.7", Outdated: true},
+		{Minor: "go1.23", Installed: "go1.23beta1", Latest: "go1.23rc1", Outdated: true},
+		{Minor: "go1.24", Installed: "go1.24.1"}, // not listed
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("outdated() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	var buf bytes.Buffer
+	writeOutdated(&buf, got)
+	if want := "go1.22.5 → go1.22.7 available\ngo1.23beta1 → go1.23rc1 available\n"; buf.String() != want {
+		t.Errorf("writeOutdated printed\n%s\nwant\n%s", buf.String(), want)
+	}
+	buf.Reset()
+	writeOutdated(&buf, want[:2])
+	if buf.String() != "Every installed minor version has its newest release installed.\n" {
+		t.Errorf("writeOutdated of none outdated printed %q", buf.String())
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"strconv"
+)
+
+// The output levels of the commands, set by their -q and -v flags.
+const (
+	outputQuiet   = -1 // errors only
+	outputNormal  = 0
+	outputVerbose = 1 // also URLs, paths, timings and checksums
+)
+
+// outputLevel is set by the -q and -v flags of the commands.
+var outputLevel = outputNormal
+
+// errorLog prints the errors the commands exit with, which -q leaves on.
+var errorLog = log.New(os.Stderr, "", 0)
+
+// A levelFlag is a boolean flag that sets outputLevel to its level.
+type levelFlag int
+
+func (f levelFlag) String() string   { return "false" }
+func (f levelFlag) IsBoolFlag() bool { return true }
+
+func (f levelFlag) Set(s string) error {
+	on, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
+	}
+	if on {
+		setOutputLevel(int(f))
+	}
+	return nil
+}
+
+// addOutputFlags adds the -q and -v flags to flags.
+func addOutputFlags(flags *flag.FlagSet) {
+	flags.Var(levelFlag(outputQuiet), "q", "print nothing but errors")
+	flags.Var(levelFlag(outputVerbose), "v", "also print URLs, resolved paths, timings and checksums")
+}
+
+// setOutputLevel sets outputLevel. At outputQuiet, whatever the commands
+// log, short of the errors they exit with, is discarded.
+func setOutputLevel(level int) {
+	outputLevel = level
+	if level == outputQuiet {
+		log.SetOutput(ioutil.Discard)
+	} else {
+		log.SetOutput(os.Stderr)
+	}
+}
+
+// verbosef logs a message at outputVerbose only.
+func verbosef(format string, args ...interface{}) {
+	if outputLevel >= outputVerbose {
+		log.Printf(format, args...)
+	}
+}
+
+// quietWriters returns where a subprocess that -q silences should write
+// its output and errors, and a function that prints what it wrote if
+// failed is true, so that a failure can still be understood. At other
+// levels they are stdout and stderr themselves.
+func quietWriters(stdout, stderr io.Writer) (io.Writer, io.Writer, func(failed bool)) {
+	if outputLevel != outputQuiet {
+		return stdout, stderr, func(bool) {}
+	}
+	var buf bytes.Buffer
+	return &buf, &buf, func(failed bool) {
+		if failed {
+			_, _ = stderr.Write(buf.Bytes())
+		}
+	}
+}
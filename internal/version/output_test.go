@@ -0,0 +1,90 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"testing"
+)
+
+func TestOutputFlags(t *testing.T) {
+	defer setOutputLevel(outputNormal)
+	for _, tt := range []struct {
+		args []string
+		want int
+	}{
+		{nil, outputNormal},
+		{[]string{"-q"}, outputQuiet},
+		{[]string{"-v"}, outputVerbose},
+		{[]string{"-v=false"}, outputNormal},
+		{[]string{"-q", "-v"}, outputVerbose},
+	} {
+		setOutputLevel(outputNormal)
+		flags := flag.NewFlagSet("test", flag.ContinueOnError)
+		addOutputFlags(flags)
+		if err := flags.Parse(tt.args); err != nil {
+			t.Fatalf("Parse(%q): %v", tt.args, err)
+		}
+		if outputLevel != tt.want {
+			t.Errorf("Parse(%q): outputLevel = %d; want %d", tt.args, outputLevel, tt.want)
+		}
+	}
+}
+
+func TestOutputLevels(t *testing.T) {
+	var logged bytes.Buffer
+	defer setOutputLevel(outputNormal)
+	log.SetFlags(0)
+	defer log.SetFlags(log.LstdFlags)
+	for _, tt := range []struct {
+		level int
+		want  string
+	}{
+		{outputQuiet, ""},
+		{outputNormal, "note\n"},
+		{outputVerbose, "note\ndetail\n"},
+	} {
+		setOutputLevel(tt.level)
+		if tt.level != outputQuiet {
+			log.SetOutput(&logged)
+		}
+		logged.Reset()
+		log.Printf("note")
+		verbosef("detail")
+		if logged.String() != tt.want {
+			t.Errorf("at level %d, logged %q; want %q", tt.level, logged.String(), tt.want)
+		}
+	}
+}
+
+func TestQuietWriters(t *testing.T) {
+	defer setOutputLevel(outputNormal)
+	setOutputLevel(outputNormal)
+	stdout, stderr, _ := quietWriters(os.Stdout, os.Stderr)
+	if stdout != io.Writer(os.Stdout) || stderr != io.Writer(os.Stderr) {
+		t.Errorf("quietWriters at the normal level replaced stdout and stderr")
+	}
+
+	setOutputLevel(outputQuiet)
+	for _, failed := range []bool{false, true} {
+		var shown bytes.Buffer
+		stdout, stderr, flush := quietWriters(ioutil.Discard, &shown)
+		io.WriteString(stdout, "building\n")
+		io.WriteString(stderr, "error\n")
+		flush(failed)
+		want := ""
+		if failed {
+			want = "building\nerror\n"
+		}
+		if shown.String() != want {
+			t.Errorf("with -q and failed=%v, showed %q; want %q", failed, shown.String(), want)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// A Version is a published Go release, such as go1.22.7, go1.21rc2 or
+// go1.9beta1. Versions are ordered by Compare: betas come before release
+// candidates, which come before the release itself. Compare is the only
+// ordering of releases; their names must never be compared as strings,
+// which puts go1.10 before go1.9 and go1.22.0 before go1.22rc1.
+type Version struct {
+	Major, Minor, Patch int
+
+	// Pre is the pre-release kind, "beta" or "rc", or empty for a
+	// stable release.
+	Pre string
+	// PreNum is the pre-release number, as the 2 in go1.21rc2.
+	PreNum int
+
+	name string   // as parsed, if it was
+	loc  *Locator // nil means defaultLocator
+}
+
+// A VersionError reports a string that is not a valid Go release name.
+type VersionError struct {
+	Input  string
+	Reason string // why Input is invalid, if known
+
+	// Suggestion is a valid release name that Input was probably meant
+	// to be, or empty if there is no obvious one.
+	Suggestion string
+}
+
+func (e *VersionError) Error() string {
+	s := fmt.Sprintf("invalid Go version %q", e.Input)
+	if e.Reason != "" {
+		s += ": " + e.Reason
+	}
+	if e.Suggestion != "" {
+		s += fmt.Sprintf(" (did you mean %s?)", e.Suggestion)
+	}
+	return s
+}
+
+// ParseVersion parses a Go release name such as "go1.22.7", "go1.21rc2"
+// or "go1.9beta1". Any other input, including "gotip", which names the
+// development tree rather than a release, is reported as a *VersionError,
+// with a suggested correction for near misses such as "1.22.7" or
+// "go1.22-rc1".
+func ParseVersion(s string) (Version, error) {
+	v, reason := parseVersion(s)
+	if reason == "" {
+		return v, nil
+	}
+	err := &VersionError{Input: s, Reason: reason}
+	if s == "gotip" {
+		return Version{}, err
+	}
+	if fixed := normalizeVersion(s); fixed != s {
+		if _, r := parseVersion(fixed); r == "" {
+			err.Suggestion = fixed
+		}
+	}
+	return Version{}, err
+}
+
+// parseVersion parses a release name strictly, returning the reason it is
+// invalid if it is.
+func parseVersion(s string) (Version, string) {
+	v := Version{name: s}
+	if s == "gotip" {
+		return Version{}, "gotip is the development tree, not a release"
+	}
+	rest := strings.TrimPrefix(s, "go")
+	if rest == s {
+		return Version{}, "missing go prefix"
+	}
+	for _, pre := range []string{"beta", "rc"} {
+		if i := strings.Index(rest, pre); i >= 0 {
+			n, ok := parseNum(rest[i+len(pre):])
+			if !ok || n == 0 {
+				return Version{}, fmt.Sprintf("bad %s number", pre)
+			}
+			v.Pre, v.PreNum = pre, n
+			rest = rest[:i]
+			break
+		}
+	}
+	fields := strings.Split(rest, ".")
+	if len(fields) > 3 {
+		return Version{}, "too many dots"
+	}
+	nums := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, f := range fields {
+		n, ok := parseNum(f)
+		if !ok {
+			if f == "" {
+				return Version{}, "missing number"
+			}
+			return Version{}, fmt.Sprintf("bad number %q", f)
+		}
+		*nums[i] = n
+	}
+	if v.Major < 1 {
+		return Version{}, "major version must be at least 1"
+	}
+	return v, ""
+}
+
+// normalizeVersion rewrites common misspellings of release names, such as
+// "1.22.7", "Go 1.22", "v1.22.7", "go1.22.07" and "go1.22-rc1", into the
+// canonical form. The result need not be valid.
+func normalizeVersion(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.Join(strings.Fields(s), "")
+	switch {
+	case strings.HasPrefix(s, "go"):
+	case strings.HasPrefix(s, "version"):
+		s = "go" + strings.TrimPrefix(s, "version")
+	case strings.HasPrefix(s, "v"):
+		s = "go" + s[1:]
+	default:
+		s = "go" + s
+	}
+	for _, pre := range []string{"beta", "rc"} {
+		for _, sep := range []string{"-", ".", "_"} {
+			s = strings.Replace(s, sep+pre, pre, 1)
+		}
+	}
+	// Drop leading zeros from each number.
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '0' && i+1 < len(s) && isDigit(s[i+1]) && (i == 0 || !isDigit(s[i-1])) {
+			for i+1 < len(s) && s[i] == '0' && isDigit(s[i+1]) {
+				i++
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// parseNum parses a non-empty decimal number without leading zeros.
+func parseNum(s string) (int, bool) {
+	if s == "" || len(s) > 1 && s[0] == '0' {
+		return 0, false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			return 0, false
+		}
+	}
+	n, err := strconv.Atoi(s)
+	return n, err == nil
+}
+
+// String returns the release name, such as "go1.22.7".
+func (v Version) String() string {
+	if v.name != "" {
+		return v.name
+	}
+	s := fmt.Sprintf("go%d.%d", v.Major, v.Minor)
+	// Starting with Go 1.21, the first release of a minor version
+	// carries an explicit .0 patch number.
+	if v.Patch > 0 || v.Pre == "" && (v.Major > 1 || v.Minor >= 21) {
+		s += fmt.Sprintf(".%d", v.Patch)
+	}
+	if v.Pre != "" {
+		s += fmt.Sprintf("%s%d", v.Pre, v.PreNum)
+	}
+	return s
+}
+
+// Compare returns -1, 0 or +1 depending on whether v orders before,
+// the same as, or after w.
+func (v Version) Compare(w Version) int {
+	for _, c := range [][2]int{
+		{v.Major, w.Major},
+		{v.Minor, w.Minor},
+		{v.Patch, w.Patch},
+		{preRank(v.Pre), preRank(w.Pre)},
+		{v.PreNum, w.PreNum},
+	} {
+		if c[0] < c[1] {
+			return -1
+		}
+		if c[0] > c[1] {
+			return +1
+		}
+	}
+	return 0
+}
+
+// Less reports whether v orders before w.
+func (v Version) Less(w Version) bool {
+	return v.Compare(w) < 0
+}
+
+func preRank(pre string) int {
+	switch pre {
+	case "beta":
+		return 0
+	case "rc":
+		return 1
+	default:
+		return 2
+	}
+}
+
+// SortVersions sorts vs in increasing order.
+func SortVersions(vs []Version) {
+	sort.SliceStable(vs, func(i, j int) bool { return vs[i].Less(vs[j]) })
+}
+
+// compareEntries orders the names of entries in an SDK directory:
+// releases by Compare, then gotip, the development tree, which is newer
+// than every release, then any other names as strings. A copy moved aside
+// by removeInstall follows the toolchain it was moved from.
+func compareEntries(a, b string) int {
+	abase, bbase := a, b
+	if i := strings.Index(a, asideSuffix); i > 0 {
+		abase = a[:i]
+	}
+	if i := strings.Index(b, asideSuffix); i > 0 {
+		bbase = b[:i]
+	}
+	if c := compareEntryBases(abase, bbase); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
+
+func compareEntryBases(a, b string) int {
+	rank := func(name string) (Version, int) {
+		if v, err := ParseVersion(name); err == nil {
+			return v, 0
+		}
+		if name == "gotip" {
+			return Version{}, 1
+		}
+		return Version{}, 2
+	}
+	va, ra := rank(a)
+	vb, rb := rank(b)
+	switch {
+	case ra != rb:
+		if ra < rb {
+			return -1
+		}
+		return +1
+	case ra == 0:
+		return va.Compare(vb)
+	}
+	return 0
+}
+
+// sortEntries sorts the entries of an SDK directory by compareEntries.
+func sortEntries(fis []os.FileInfo) {
+	sort.SliceStable(fis, func(i, j int) bool { return compareEntries(fis[i].Name(), fis[j].Name()) < 0 })
+}
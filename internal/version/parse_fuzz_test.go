@@ -0,0 +1,43 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.18
+// +build go1.18
+
+package version
+
+import "testing"
+
+func FuzzParseVersion(f *testing.F) {
+	for _, s := range []string{"go1", "go1.22.7", "go1.21rc2", "go1.9beta1", "1.22", "go1.22-rc1", "gotip", "go1.022"} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		v, err := ParseVersion(s)
+		if err != nil {
+			ve, ok := err.(*VersionError)
+			if !ok {
+				t.Fatalf("ParseVersion(%q) error %v is a %T; want *VersionError", s, err, err)
+			}
+			if ve.Suggestion != "" {
+				if _, err := ParseVersion(ve.Suggestion); err != nil {
+					t.Fatalf("ParseVersion(%q) suggests invalid %q: %v", s, ve.Suggestion, err)
+				}
+			}
+			return
+		}
+		if v.String() != s {
+			t.Fatalf("ParseVersion(%q).String() = %q", s, v.String())
+		}
+		// The canonical form of a version parses to the same version.
+		v.name = ""
+		w, err := ParseVersion(v.String())
+		if err != nil {
+			t.Fatalf("ParseVersion(%q) of %q's canonical form: %v", v.String(), s, err)
+		}
+		if v.Compare(w) != 0 {
+			t.Fatalf("%q reparsed as %+v; want %+v", s, w, v)
+		}
+	})
+}
@@ -0,0 +1,324 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Version
+		ok   bool
+	}{
+		{"go1", Version{Major: 1}, true},
+		{"go1.9", Version{Major: 1, Minor: 9}, true},
+		{"go1.22.7", Version{Major: 1, Minor: 22, Patch: 7}, true},
+		{"go1.21rc2", Version{Major: 1, Minor: 21, Pre: "rc", PreNum: 2}, true},
+		{"go1.9beta1", Version{Major: 1, Minor: 9, Pre: "beta", PreNum: 1}, true},
+		{"go1.9.2rc2", Version{Major: 1, Minor: 9, Patch: 2, Pre: "rc", PreNum: 2}, true},
+		{"", Version{}, false},
+		{"1.22.7", Version{}, false},
+		{"go", Version{}, false},
+		{"go0.1", Version{}, false},
+		{"go1.22.7.1", Version{}, false},
+		{"go1.022", Version{}, false},
+		{"go1.22rc", Version{}, false},
+		{"go1.22rc0", Version{}, false},
+		{"go1..2", Version{}, false},
+		{"gotip", Version{}, false},
+		{"go1.0", Version{Major: 1}, true},
+		{"go1.21.0", Version{Major: 1, Minor: 21}, true},
+		{"go1.22.10", Version{Major: 1, Minor: 22, Patch: 10}, true},
+		{"go1.10beta2", Version{Major: 1, Minor: 10, Pre: "beta", PreNum: 2}, true},
+		{"go2", Version{Major: 2}, true},
+		{"go1.22.7 ", Version{}, false},
+		{" go1.22.7", Version{}, false},
+		{"Go1.22.7", Version{}, false},
+		{"v1.22.7", Version{}, false},
+		{"go1.22.", Version{}, false},
+		{"go1.22.-1", Version{}, false},
+		{"go1.22.x", Version{}, false},
+		{"go1.22.07", Version{}, false},
+		{"go01.22", Version{}, false},
+		{"go1.22-rc1", Version{}, false},
+		{"go1.22.rc1", Version{}, false},
+		{"go1.22rc01", Version{}, false},
+		{"go1.22rc1rc2", Version{}, false},
+		{"go1.22beta", Version{}, false},
+		{"go1.22alpha1", Version{}, false},
+		{"go1.22+auto", Version{}, false},
+		{"go1.22.7-custom", Version{}, false},
+		{"go99999999999999999999", Version{}, false},
+		{"go1.2.3.4.5", Version{}, false},
+		{"go.1", Version{}, false},
+		{"go1.22\x00", Version{}, false},
+		{"gotip1", Version{}, false},
+		{"devel", Version{}, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseVersion(tt.in)
+		if (err == nil) != tt.ok {
+			t.Errorf("ParseVersion(%q) error = %v; want ok=%v", tt.in, err, tt.ok)
+			continue
+		}
+		if !tt.ok {
+			continue
+		}
+		got.name = ""
+		if got != tt.want {
+			t.Errorf("ParseVersion(%q) = %+v; want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	ordered := []string{
+		"go1", "go1.2", "go1.9beta1", "go1.9beta2", "go1.9rc1", "go1.9",
+		"go1.9.2rc2", "go1.9.2", "go1.10", "go1.21rc1", "go1.21.0", "go1.21.1",
+	}
+	for i, a := range ordered {
+		va, err := ParseVersion(a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for j, b := range ordered {
+			vb, err := ParseVersion(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := 0
+			if i < j {
+				want = -1
+			} else if i > j {
+				want = +1
+			}
+			if got := va.Compare(vb); got != want {
+				t.Errorf("Compare(%s, %s) = %d; want %d", a, b, got, want)
+			}
+		}
+	}
+}
+
+func TestVersionCompareAdversarial(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"go1.9", "go1.10", -1},
+		{"go1.2", "go1.10", -1},
+		{"go1.2", "go1.20", -1},
+		{"go1.99", "go1.100", -1},
+		{"go1.9.9", "go1.9.10", -1},
+		{"go1.1.9", "go1.10", -1},
+		{"go1.99.99", "go2", -1},
+		{"go1.22rc1", "go1.22.0", -1},
+		{"go1.22rc2", "go1.22.0", -1},
+		{"go1.22rc9", "go1.22rc10", -1},
+		{"go1.22beta1", "go1.22rc1", -1},
+		{"go1.22beta10", "go1.22rc1", -1},
+		{"go1.22beta2", "go1.22beta10", -1},
+		{"go1.21rc4", "go1.21.0", -1},
+		{"go1.21.0", "go1.21.1", -1},
+		{"go1.9.2rc2", "go1.9.2", -1},
+		{"go1.9.1", "go1.9.2rc2", -1},
+		{"go1.21.13", "go1.22rc1", -1},
+		{"go1", "go1.0.1", -1},
+		{"go1.21", "go1.21.0", 0},
+		{"go1", "go1.0", 0},
+		{"go1.9rc1", "go1.9rc1", 0},
+	}
+	for _, tt := range tests {
+		a, err := ParseVersion(tt.a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := ParseVersion(tt.b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := a.Compare(b); got != tt.want {
+			t.Errorf("Compare(%s, %s) = %d; want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := b.Compare(a); got != -tt.want {
+			t.Errorf("Compare(%s, %s) = %d; want %d", tt.b, tt.a, got, -tt.want)
+		}
+		if got := a.Less(b); got != (tt.want < 0) {
+			t.Errorf("%s.Less(%s) = %v; want %v", tt.a, tt.b, got, tt.want < 0)
+		}
+	}
+}
+
+func TestCompareEntries(t *testing.T) {
+	want := []string{
+		"go1.2", "go1.9beta1", "go1.9rc1", "go1.9", "go1.9.2", "go1.10",
+		"go1.10.old-1", "go1.22rc1", "go1.22.0", "go1.22.0.old-1", "go1.22.0.old-2",
+		"gotip", "gotip.old-1", "a-file", "z-file",
+	}
+	// Every rotation of the reversed names, so that no input order
+	// happens to be the answer.
+	for i := range want {
+		var fis []os.FileInfo
+		for j := range want {
+			fis = append(fis, fakeFileInfo(want[len(want)-1-(i+j)%len(want)]))
+		}
+		sortEntries(fis)
+		var got []string
+		for _, fi := range fis {
+			got = append(got, fi.Name())
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("sortEntries = %q; want %q", got, want)
+		}
+	}
+}
+
+// fakeFileInfo is an os.FileInfo with only a name.
+type fakeFileInfo string
+
+func (f fakeFileInfo) Name() string       { return string(f) }
+func (f fakeFileInfo) Size() int64        { return 0 }
+func (f fakeFileInfo) Mode() os.FileMode  { return os.ModeDir }
+func (f fakeFileInfo) ModTime() time.Time { return time.Time{} }
+func (f fakeFileInfo) IsDir() bool        { return true }
+func (f fakeFileInfo) Sys() interface{}   { return nil }
+
+// TestNoLexicalOrdering guards against ordering release names as strings,
+// which puts go1.10 before go1.9: only compareEntries, in parse.go, may
+// compare names that way, after it has compared the releases.
+func TestNoLexicalOrdering(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if file == "parse.go" || strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			sel, ok := n.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			if pkg, ok := sel.X.(*ast.Ident); ok {
+				switch pkg.Name + "." + sel.Sel.Name {
+				case "sort.Strings", "sort.StringSlice", "sort.SearchStrings", "strings.Compare":
+					t.Errorf("%s: %s.%s orders strings lexically; order releases with Version.Compare or compareEntries", fset.Position(sel.Pos()), pkg.Name, sel.Sel.Name)
+				}
+			}
+			return true
+		})
+	}
+}
+
+func TestVersionString(t *testing.T) {
+	tests := []struct {
+		v    Version
+		want string
+	}{
+		{Version{Major: 1, Minor: 20}, "go1.20"},
+		{Version{Major: 1, Minor: 21}, "go1.21.0"},
+		{Version{Major: 1, Minor: 21, Pre: "rc", PreNum: 1}, "go1.21rc1"},
+		{Version{Major: 1, Minor: 9, Patch: 2, Pre: "rc", PreNum: 2}, "go1.9.2rc2"},
+	}
+	for _, tt := range tests {
+		if got := tt.v.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q; want %q", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestParseVersionSuggestion(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"1.22.7", "go1.22.7"},
+		{"1.21rc2", "go1.21rc2"},
+		{"v1.22.7", "go1.22.7"},
+		{"Go1.22", "go1.22"},
+		{"Go 1.22", "go1.22"},
+		{"go 1.22.3", "go1.22.3"},
+		{" go1.22.3\n", "go1.22.3"},
+		{"version1.9", "go1.9"},
+		{"go1.22.03", "go1.22.3"},
+		{"go01.022", "go1.22"},
+		{"go1.22-rc1", "go1.22rc1"},
+		{"go1.22.rc1", "go1.22rc1"},
+		{"go1.9_beta1", "go1.9beta1"},
+		{"go1.22RC1", "go1.22rc1"},
+		{"go1.22rc01", "go1.22rc1"},
+		{"go1.22.0.0", ""},
+		{"go1.22rc0", ""},
+		{"gotip", ""},
+		{"latest", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		_, err := ParseVersion(tt.in)
+		ve, ok := err.(*VersionError)
+		if !ok {
+			t.Errorf("ParseVersion(%q) error = %v; want *VersionError", tt.in, err)
+			continue
+		}
+		if ve.Suggestion != tt.want {
+			t.Errorf("ParseVersion(%q) suggestion = %q; want %q", tt.in, ve.Suggestion, tt.want)
+		}
+		if tt.want != "" && !strings.Contains(err.Error(), "did you mean "+tt.want+"?") {
+			t.Errorf("ParseVersion(%q) error %q doesn't offer %s", tt.in, err, tt.want)
+		}
+	}
+}
+
+func TestLocatorInstalled(t *testing.T) {
+	l := &Locator{Root: t.TempDir()}
+	for _, name := range []string{"go1.22.7", "go1.9", "go1.21rc2", "gotip", "go1.23", "not-a-version", "go1.22.07"} {
+		dir := filepath.Join(l.Root, name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if name == "go1.23" {
+			continue // partially installed: no marker
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, unpackedOkay), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ioutil.WriteFile(filepath.Join(l.Root, "go1.20"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	vs, err := l.Installed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, v := range vs {
+		got = append(got, v.String())
+	}
+	want := []string{"go1.9", "go1.21rc2", "go1.22.7"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Installed() = %q; want %q", got, want)
+	}
+
+	l.Root = filepath.Join(l.Root, "missing")
+	if vs, err := l.Installed(); err != nil || len(vs) != 0 {
+		t.Errorf("Installed() with missing root = %v, %v; want none", vs, err)
+	}
+}
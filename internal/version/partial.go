@@ -0,0 +1,92 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// An archive is downloaded to its name with partialSuffix appended, and
+// renamed into place once complete, so that a partial download is never
+// taken for the archive. Beside it, the file with partialInfoSuffix
+// records what it is a part of.
+const (
+	partialSuffix     = ".partial"
+	partialInfoSuffix = ".partial.json"
+)
+
+// A partialInfo records what a partial download is a part of, so that it
+// is only resumed from the same file.
+type partialInfo struct {
+	URL  string `json:"url"`
+	Size int64  `json:"size"`
+
+	// ETag and LastModified are the validators the server sent with the
+	// file, which resuming sends back in If-Range so that the server
+	// starts over if the file has changed since.
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// newPartialInfo returns the record of a download of url, as the server
+// described it in res.
+func newPartialInfo(url string, res *http.Response) partialInfo {
+	return partialInfo{
+		URL:          url,
+		Size:         res.ContentLength,
+		ETag:         res.Header.Get("ETag"),
+		LastModified: res.Header.Get("Last-Modified"),
+	}
+}
+
+// ifRange returns the validator to send in If-Range when resuming, or ""
+// if there is none. Weak ETags can't be used in If-Range.
+func (info partialInfo) ifRange() string {
+	if info.ETag != "" && !strings.HasPrefix(info.ETag, "W/") {
+		return info.ETag
+	}
+	return info.LastModified
+}
+
+// readPartialInfo returns the record of the partial download of file.
+func readPartialInfo(file string) (partialInfo, bool) {
+	var info partialInfo
+	data, err := ioutil.ReadFile(file + partialInfoSuffix)
+	if err != nil || json.Unmarshal(data, &info) != nil {
+		return info, false
+	}
+	return info, true
+}
+
+// writePartialInfo records what the partial download of file is a part of.
+func writePartialInfo(file string, info partialInfo) error {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(file+partialInfoSuffix, data)
+}
+
+// removePartial removes the partial download of file, and its record.
+func removePartial(file string) {
+	_ = os.Remove(file + partialSuffix)
+	_ = os.Remove(file + partialInfoSuffix)
+}
+
+// resumeOffset returns how many bytes of url, size bytes long, the partial
+// download of file holds, or 0 if there is none to resume: none was kept,
+// or it is of another file, or of an older copy of this one.
+func resumeOffset(file, url string, size int64) int64 {
+	info, ok := readPartialInfo(file)
+	fi, err := os.Stat(file + partialSuffix)
+	if !ok || err != nil || info.URL != url || info.Size != size || fi.Size() >= size {
+		return 0
+	}
+	return fi.Size()
+}
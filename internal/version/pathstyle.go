@@ -0,0 +1,108 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// A PathStyle says how paths printed for a shell to consume are spelled.
+// Files are always opened by their native paths; only output changes.
+//
+// A PathStyle is a flag.Value, for commands' -path-style flags.
+type PathStyle string
+
+const (
+	// PathStyleAuto prints Unix-style paths when running on Windows under
+	// an MSYS2, Git Bash or Cygwin shell, and native paths otherwise. It
+	// is the default.
+	PathStyleAuto PathStyle = "auto"
+
+	// PathStyleWindows prints native paths, such as C:\Users\me\sdk.
+	PathStyleWindows PathStyle = "windows"
+
+	// PathStyleUnix prints Windows paths as MSYS and Cygwin shells spell
+	// them, such as /c/Users/me/sdk.
+	PathStyleUnix PathStyle = "unix"
+)
+
+func (s *PathStyle) String() string {
+	if *s == "" {
+		return string(PathStyleAuto)
+	}
+	return string(*s)
+}
+
+func (s *PathStyle) Set(v string) error {
+	switch PathStyle(v) {
+	case PathStyleAuto, PathStyleWindows, PathStyleUnix:
+		*s = PathStyle(v)
+		return nil
+	}
+	return fmt.Errorf("unknown path style %q: must be auto, windows or unix", v)
+}
+
+// addPathStyleFlag adds the -path-style flag to flags, for the commands
+// whose output a shell reads, and returns the style it sets.
+func addPathStyleFlag(flags *flag.FlagSet) *PathStyle {
+	s := PathStyleAuto
+	flags.Var(&s, "path-style", "print paths in `style` windows, unix, as MSYS and Cygwin shells spell them, or auto, unix only under such a shell")
+	return &s
+}
+
+// Format returns path p spelled in style s.
+func (s PathStyle) Format(p string) string {
+	if runtime.GOOS != "windows" {
+		return p
+	}
+	prefix, unix := unixShell(os.Getenv)
+	if s == PathStyleWindows || s != PathStyleUnix && !unix {
+		return p
+	}
+	return unixPath(p, prefix)
+}
+
+// unixShell reports whether the environment is that of an MSYS or Cygwin
+// shell, and the prefix it mounts drives under.
+func unixShell(getenv func(string) string) (drivePrefix string, ok bool) {
+	if getenv("MSYSTEM") != "" {
+		return "/", true
+	}
+	switch strings.ToLower(getenv("OSTYPE")) {
+	case "msys":
+		return "/", true
+	case "cygwin":
+		return "/cygdrive/", true
+	}
+	return "/", false
+}
+
+// unixPath converts the Windows path p to the Unix style of MSYS and
+// Cygwin, mounting drive letters under prefix: with prefix "/",
+// C:\Users\me becomes /c/Users/me, and \\server\share becomes
+// //server/share.
+func unixPath(p, prefix string) string {
+	p = strings.TrimPrefix(p, `\\?\`)
+	if strings.HasPrefix(p, `UNC\`) {
+		p = `\\` + p[len(`UNC\`):]
+	}
+	p = strings.ReplaceAll(p, `\`, "/")
+	if len(p) >= 2 && p[1] == ':' && isDriveLetter(p[0]) {
+		rest := strings.TrimPrefix(p[2:], "/")
+		p = prefix + strings.ToLower(p[:1])
+		if rest != "" {
+			p += "/" + rest
+		}
+	}
+	return p
+}
+
+func isDriveLetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
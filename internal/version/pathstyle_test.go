@@ -0,0 +1,67 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import "testing"
+
+func TestUnixPath(t *testing.T) {
+	tests := []struct {
+		path, prefix, want string
+	}{
+		{`C:\Users\me\sdk\go1.22.7`, "/", "/c/Users/me/sdk/go1.22.7"},
+		{`d:\go`, "/", "/d/go"},
+		{`C:\`, "/", "/c"},
+		{`C:`, "/", "/c"},
+		{`C:/Users/me`, "/", "/c/Users/me"},
+		{`C:\Program Files\Go\bin`, "/", "/c/Program Files/Go/bin"},
+		{`C:\Users\John Smith\sdk`, "/cygdrive/", "/cygdrive/c/Users/John Smith/sdk"},
+		{`\\server\share\sdk\go1.22.7`, "/", "//server/share/sdk/go1.22.7"},
+		{`\\my server\my share`, "/", "//my server/my share"},
+		{`\\?\C:\Users\me\sdk`, "/", "/c/Users/me/sdk"},
+		{`\\?\UNC\server\share\sdk`, "/", "//server/share/sdk"},
+		{`sdk\go1.22.7\bin`, "/", "sdk/go1.22.7/bin"},
+		{`/c/already/unix`, "/", "/c/already/unix"},
+	}
+	for _, tt := range tests {
+		if got := unixPath(tt.path, tt.prefix); got != tt.want {
+			t.Errorf("unixPath(%q, %q) = %q; want %q", tt.path, tt.prefix, got, tt.want)
+		}
+	}
+}
+
+func TestUnixShell(t *testing.T) {
+	tests := []struct {
+		env    map[string]string
+		prefix string
+		ok     bool
+	}{
+		{nil, "/", false},
+		{map[string]string{"MSYSTEM": "MINGW64"}, "/", true},
+		{map[string]string{"OSTYPE": "msys"}, "/", true},
+		{map[string]string{"OSTYPE": "cygwin"}, "/cygdrive/", true},
+		{map[string]string{"OSTYPE": "linux-gnu"}, "/", false},
+	}
+	for _, tt := range tests {
+		prefix, ok := unixShell(func(k string) string { return tt.env[k] })
+		if prefix != tt.prefix || ok != tt.ok {
+			t.Errorf("unixShell(%v) = %q, %v; want %q, %v", tt.env, prefix, ok, tt.prefix, tt.ok)
+		}
+	}
+}
+
+func TestPathStyleSet(t *testing.T) {
+	var s PathStyle
+	if got := s.String(); got != "auto" {
+		t.Errorf("zero PathStyle = %q; want auto", got)
+	}
+	for _, v := range []string{"auto", "windows", "unix"} {
+		if err := s.Set(v); err != nil || string(s) != v {
+			t.Errorf("Set(%q) = %v, leaving %q", v, err, s)
+		}
+	}
+	if err := s.Set("posix"); err == nil {
+		t.Error("Set(posix) succeeded")
+	}
+}
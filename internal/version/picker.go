@@ -0,0 +1,348 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// A pickerItem is a release shown by the interactive picker.
+type pickerItem struct {
+	Version   Version
+	Installed bool
+}
+
+// pickerItems returns the releases for the picker, newest first: those in
+// the release listing, and any installed ones it lacks. Without the
+// listing, such as offline, it has only the installed releases.
+func pickerItems(releases []Release, installed []Version) []pickerItem {
+	have := make(map[string]bool)
+	for _, v := range installed {
+		have[v.String()] = true
+	}
+	var items []pickerItem
+	listed := make(map[string]bool)
+	for _, r := range releases {
+		listed[r.Version.String()] = true
+		items = append(items, pickerItem{r.Version, have[r.Version.String()]})
+	}
+	for _, v := range installed {
+		if !listed[v.String()] {
+			items = append(items, pickerItem{v, true})
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[j].Version.Less(items[i].Version) })
+	return items
+}
+
+// A pickerKey is a key pressed in the picker: one of the key constants,
+// or keyRune with the rune typed.
+type pickerKey struct {
+	code int
+	r    rune
+}
+
+const (
+	keyRune = iota
+	keyUp
+	keyDown
+	keyEnter
+	keyTab
+	keyBackspace
+	keyQuit
+)
+
+// parseKeys decodes the bytes read from a terminal in raw mode. A lone
+// escape quits; the escape sequences of keys other than the arrows are
+// skipped.
+func parseKeys(b []byte) []pickerKey {
+	var keys []pickerKey
+	for len(b) > 0 {
+		switch c := b[0]; {
+		case c == 0x1b && len(b) >= 3 && (b[1] == '[' || b[1] == 'O'):
+			// A control sequence: parameters, then a final byte.
+			n := 2
+			for n < len(b) && (isDigit(b[n]) || b[n] == ';') {
+				n++
+			}
+			if n < len(b) {
+				switch b[n] {
+				case 'A':
+					keys = append(keys, pickerKey{code: keyUp})
+				case 'B':
+					keys = append(keys, pickerKey{code: keyDown})
+				}
+				n++
+			}
+			b = b[n:]
+			continue
+		case c == 0x1b, c == 0x03, c == 0x04: // escape, ^C, ^D
+			keys = append(keys, pickerKey{code: keyQuit})
+		case c == '\r' || c == '\n':
+			keys = append(keys, pickerKey{code: keyEnter})
+		case c == '\t':
+			keys = append(keys, pickerKey{code: keyTab})
+		case c == 0x7f || c == 0x08:
+			keys = append(keys, pickerKey{code: keyBackspace})
+		case c == 0x10: // ^P
+			keys = append(keys, pickerKey{code: keyUp})
+		case c == 0x0e: // ^N
+			keys = append(keys, pickerKey{code: keyDown})
+		case c >= 0x20:
+			r, n := utf8.DecodeRune(b)
+			keys = append(keys, pickerKey{code: keyRune, r: r})
+			b = b[n:]
+			continue
+		}
+		b = b[1:]
+	}
+	return keys
+}
+
+// Picker actions, as the commands that do them.
+const (
+	actionInstall = "install"
+	actionWhich   = "which"
+	actionDefault = "default"
+	actionRemove  = "uninstall"
+)
+
+// actions returns what the picker offers to do with it, the action Enter
+// takes first: install or set as the default a release that isn't
+// installed, and print the GOROOT of, set as the default or remove one
+// that is.
+func (it pickerItem) actions() []string {
+	if it.Installed {
+		return []string{actionWhich, actionDefault, actionRemove}
+	}
+	return []string{actionInstall, actionDefault}
+}
+
+// actionText describes the picker actions in its menu.
+var actionText = map[string]string{
+	actionInstall: "install it",
+	actionWhich:   "print its GOROOT",
+	actionDefault: "set it as the default release, which go runs",
+	actionRemove:  "remove it",
+}
+
+// A picker is the state of the interactive picker: the releases, the
+// filter typed so far, and the selected line among those matching it.
+// While the menu of actions for a release is open, menu is that release
+// and action the selected line of the menu.
+type picker struct {
+	items  []pickerItem
+	filter string
+	cursor int
+	menu   *pickerItem
+	action int
+}
+
+// visible returns the items whose names match the filter: those that
+// have its characters in order, if not together, so that 1227 finds
+// go1.22.7.
+func (p *picker) visible() []pickerItem {
+	var vis []pickerItem
+	for _, it := range p.items {
+		if fuzzyMatch(it.Version.String(), p.filter) {
+			vis = append(vis, it)
+		}
+	}
+	return vis
+}
+
+// fuzzyMatch reports whether the characters of pattern appear in s, in
+// order.
+func fuzzyMatch(s, pattern string) bool {
+	for _, r := range pattern {
+		i := strings.IndexRune(s, r)
+		if i < 0 {
+			return false
+		}
+		s = s[i+utf8.RuneLen(r):]
+	}
+	return true
+}
+
+// handle applies k. It reports whether the picker is done, and the item
+// chosen and the action to take on it, if any.
+func (p *picker) handle(k pickerKey) (done bool, chosen *pickerItem, action string) {
+	if p.menu != nil {
+		return p.handleMenu(k)
+	}
+	switch k.code {
+	case keyUp:
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case keyDown:
+		if p.cursor < len(p.visible())-1 {
+			p.cursor++
+		}
+	case keyRune:
+		p.filter += string(k.r)
+		p.cursor = 0
+	case keyBackspace:
+		if p.filter != "" {
+			_, n := utf8.DecodeLastRuneInString(p.filter)
+			p.filter = p.filter[:len(p.filter)-n]
+			p.cursor = 0
+		}
+	case keyEnter, keyTab:
+		vis := p.visible()
+		if len(vis) == 0 {
+			return false, nil, ""
+		}
+		it := &vis[p.cursor]
+		if k.code == keyEnter {
+			return true, it, it.actions()[0]
+		}
+		p.menu, p.action = it, 0
+	case keyQuit:
+		return true, nil, ""
+	}
+	return false, nil, ""
+}
+
+// handleMenu applies k to the open menu of actions. Escape closes it.
+func (p *picker) handleMenu(k pickerKey) (done bool, chosen *pickerItem, action string) {
+	actions := p.menu.actions()
+	switch k.code {
+	case keyUp:
+		if p.action > 0 {
+			p.action--
+		}
+	case keyDown:
+		if p.action < len(actions)-1 {
+			p.action++
+		}
+	case keyEnter:
+		return true, p.menu, actions[p.action]
+	case keyQuit, keyBackspace:
+		p.menu = nil
+	}
+	return false, nil, ""
+}
+
+// pickerRows is how many releases the picker shows at a time.
+const pickerRows = 15
+
+// render draws the picker on a terminal: the filter, a window of the
+// matching releases around the selected one, under a heading for each
+// minor version, and a line of help; or the menu of actions, if open.
+func (p *picker) render(w io.Writer) {
+	vis := p.visible()
+	var b strings.Builder
+	b.WriteString("\x1b[H\x1b[J") // home, clear screen
+	if p.menu != nil {
+		fmt.Fprintf(&b, "%s\n\n", p.menu.Version)
+		for i, a := range p.menu.actions() {
+			mark := " "
+			if i == p.action {
+				mark = ">"
+			}
+			fmt.Fprintf(&b, " %s %s\n", mark, actionText[a])
+		}
+		b.WriteString("\nup/down: move  enter: do it  esc: back\n")
+		io.WriteString(w, b.String())
+		return
+	}
+	fmt.Fprintf(&b, "Filter: %s\n\n", p.filter)
+	start := 0
+	if p.cursor >= pickerRows {
+		start = p.cursor - pickerRows + 1
+	}
+	group := ""
+	for i := start; i < len(vis) && i < start+pickerRows; i++ {
+		v := vis[i].Version
+		if g := fmt.Sprintf("go%d.%d", v.Major, v.Minor); g != group {
+			group = g
+			fmt.Fprintf(&b, "%s\n", g)
+		}
+		mark, state := " ", ""
+		if i == p.cursor {
+			mark = ">"
+		}
+		if vis[i].Installed {
+			state = "installed"
+		}
+		fmt.Fprintf(&b, " %s %-14s %s\n", mark, v, state)
+	}
+	if len(vis) == 0 {
+		b.WriteString("  no release matches\n")
+	}
+	b.WriteString("\nup/down: move  type: filter  enter: install, or print the GOROOT if installed  tab: more actions  esc: quit\n")
+	io.WriteString(w, b.String())
+}
+
+// runPicker runs the interactive picker on the terminal, and then the
+// command that is the flag equivalent of the choice: by default dl install
+// for a release that isn't installed, and dl which for one that is, or
+// dl default or dl uninstall from the menu of actions. It reports false if
+// the terminal can't be put in raw mode.
+func runPicker(cfg *Config) bool {
+	opts, err := cfg.Options()
+	if err != nil {
+		log.Fatalf("dl: %v", err)
+	}
+	installed, _ := cfg.Locator().Installed()
+	var releases []Release
+	if d, err := NewDownloader(opts); err == nil {
+		releases, err = d.Catalog().All(context.Background(), Filter{})
+		if err != nil {
+			releases = nil
+		}
+	}
+	p := &picker{items: pickerItems(releases, installed)}
+	restore, err := makeRaw(os.Stdin)
+	if err != nil {
+		return false
+	}
+	chosen, action := pick(p, os.Stdin, os.Stdout)
+	io.WriteString(os.Stdout, "\x1b[H\x1b[J")
+	restore()
+	if chosen == nil {
+		return true
+	}
+	name := chosen.Version.String()
+	fmt.Fprintf(os.Stderr, "dl %s %s\n", action, name)
+	switch action {
+	case actionInstall:
+		runInstall(cfg, []string{name})
+	case actionWhich:
+		runWhich(cfg, []string{name})
+	case actionDefault:
+		runDefault(cfg, []string{name})
+	case actionRemove:
+		runUninstall(cfg, []string{name})
+	}
+	return true
+}
+
+// pick runs p, reading keys from in and drawing on out, until a release
+// and an action are chosen or the user quits.
+func pick(p *picker, in io.Reader, out io.Writer) (*pickerItem, string) {
+	buf := make([]byte, 64)
+	p.render(out)
+	for {
+		n, err := in.Read(buf)
+		if err != nil {
+			return nil, ""
+		}
+		for _, k := range parseKeys(buf[:n]) {
+			if done, chosen, action := p.handle(k); done {
+				return chosen, action
+			}
+		}
+		p.render(out)
+	}
+}
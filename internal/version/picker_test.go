@@ -0,0 +1,90 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseKeys(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []pickerKey
+	}{
+		{"\x1b[A\x1b[B", []pickerKey{{code: keyUp}, {code: keyDown}}},
+		{"\x1bOA", []pickerKey{{code: keyUp}}},
+		{"\x1b[3~1.2", []pickerKey{{code: keyRune, r: '1'}, {code: keyRune, r: '.'}, {code: keyRune, r: '2'}}},
+		{"\x7f\r", []pickerKey{{code: keyBackspace}, {code: keyEnter}}},
+		{"\x1b", []pickerKey{{code: keyQuit}}},
+		{"\x03", []pickerKey{{code: keyQuit}}},
+		{"\x10\x0e", []pickerKey{{code: keyUp}, {code: keyDown}}},
+		{"\t", []pickerKey{{code: keyTab}}},
+	}
+	for _, tt := range tests {
+		if got := parseKeys([]byte(tt.in)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseKeys(%q) = %v; want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPick(t *testing.T) {
+	var releases []Release
+	for _, s := range []string{"go1.22.7", "go1.22.6", "go1.21.13"} {
+		v, err := ParseVersion(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		releases = append(releases, Release{Version: v})
+	}
+	old, err := (&Locator{Root: t.TempDir()}).Parse("go1.9.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := pickerItems(releases, []Version{releases[1].Version, old})
+	var names []string
+	for _, it := range items {
+		names = append(names, it.Version.String())
+	}
+	if want := []string{"go1.22.7", "go1.22.6", "go1.21.13", "go1.9.2"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("pickerItems = %v; want %v", names, want)
+	}
+	if !items[1].Installed || items[0].Installed || !items[3].Installed {
+		t.Errorf("pickerItems marked installed wrongly: %+v", items)
+	}
+
+	tests := []struct {
+		keys   string
+		want   string // chosen release, or "" to quit
+		action string
+	}{
+		{"\r", "go1.22.7", actionInstall},
+		{"\x1b[B\x1b[B\r", "go1.21.13", actionInstall},
+		{"\x1b[A\r", "go1.22.7", actionInstall},
+		{"1.22\x1b[B\x1b[B\x1b[B\r", "go1.22.6", actionWhich},
+		{"1.21x\x7f\r", "go1.21.13", actionInstall},
+		{"1226\r", "go1.22.6", actionWhich}, // fuzzy
+		{"zzz\r\x1b", "", ""},
+		{"", "", ""}, // end of input
+
+		// Tab opens the menu of actions, where escape goes back.
+		{"\t\x1b[B\r", "go1.22.7", actionDefault},
+		{"1226\t\x1b[B\x1b[B\x1b[B\r", "go1.22.6", actionRemove},
+		{"\t\x1b\x1b[B\r", "go1.22.6", actionWhich},
+	}
+	for _, tt := range tests {
+		p := &picker{items: items}
+		chosen, action := pick(p, strings.NewReader(tt.keys), ioutil.Discard)
+		got := ""
+		if chosen != nil {
+			got = chosen.Version.String()
+		}
+		if got != tt.want || action != tt.action {
+			t.Errorf("picking with %q chose %q, %q; want %q, %q", tt.keys, got, action, tt.want, tt.action)
+		}
+	}
+}
@@ -0,0 +1,553 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// A Plan lists the steps installing a release would take on this machine.
+// Planning has no side effects; Downloader.Execute carries out a Plan.
+type Plan struct {
+	Version string `json:"version"`
+	GOROOT  string `json:"goroot"`
+
+	// Installed reports that the release is already installed, in which
+	// case there are no steps.
+	Installed bool `json:"installed"`
+
+	// URL and Size describe the release archive.
+	URL  string `json:"url,omitempty"`
+	Size int64  `json:"size,omitempty"`
+
+	Steps []Step `json:"steps,omitempty"`
+}
+
+// A StepKind identifies what a Step does.
+type StepKind string
+
+const (
+	// StepDownload fetches URL to File, starting at byte Offset.
+	StepDownload StepKind = "download"
+
+	// StepVerify checks File against the SHA-256 published at URL,
+	// according to the Checksum policy.
+	StepVerify StepKind = "verify"
+
+	// StepVerifyModule checks the module zip File against the go.sum
+	// hash that the checksum database records for it at URL, according
+	// to the Checksum policy. It takes the place of StepVerify when
+	// DownloaderOptions.ModuleProxy is set.
+	StepVerifyModule StepKind = "verify-module"
+
+	// StepUnpack extracts the archive File into Target.
+	StepUnpack StepKind = "unpack"
+
+	// StepStream downloads the tar.gz archive at URL, Size bytes long,
+	// and unpacks it into Target as it arrives, then checks it as
+	// StepVerify would have, removing what it unpacked if it doesn't
+	// match. It takes the place of StepDownload, StepVerify and
+	// StepUnpack when DownloaderOptions.Stream is set.
+	StepStream StepKind = "stream"
+
+	// StepDedupe makes the files of Target that are identical to those of
+	// the installed release nearest to it share their storage, as Mode
+	// says, when DownloaderOptions.Dedupe is set.
+	StepDedupe StepKind = "dedupe"
+
+	// StepMarkInstalled checks that the go command in Target runs, then
+	// records that Target is completely installed by creating the marker
+	// File.
+	StepMarkInstalled StepKind = "mark-installed"
+)
+
+// A Step is one action of a Plan. Only the fields relevant to its Kind
+// are set.
+type Step struct {
+	Kind     StepKind       `json:"kind"`
+	URL      string         `json:"url,omitempty"`
+	File     string         `json:"file,omitempty"`
+	Size     int64          `json:"size,omitempty"`
+	Offset   int64          `json:"offset,omitempty"`
+	Checksum ChecksumPolicy `json:"checksum,omitempty"`
+	Target   string         `json:"target,omitempty"`
+
+	// SHA256, if set on a StepVerify, is the digest File must have,
+	// as pinned by a Lockfile. It is checked instead of the one published
+	// at URL, whatever the Checksum policy.
+	SHA256 string `json:"sha256,omitempty"`
+
+	// Mode, on a StepDedupe, is how files are shared: reflink or
+	// hardlink.
+	Mode string `json:"mode,omitempty"`
+
+	// Slim, on a StepUnpack or StepStream, leaves the tests, the
+	// documentation and the API data of the release out of Target, as
+	// DownloaderOptions.Slim describes.
+	Slim bool `json:"slim,omitempty"`
+}
+
+// phase returns the install phase the step belongs to.
+func (s Step) phase() Phase {
+	switch s.Kind {
+	case StepDownload:
+		return PhaseDownload
+	case StepVerify, StepVerifyModule:
+		return PhaseVerify
+	case StepStream:
+		return PhaseDownload
+	default:
+		return PhaseUnpack
+	}
+}
+
+// sumURL returns the URL of the checksum published for the archive that
+// the verify or stream step s checks.
+func (s Step) sumURL() string {
+	if s.Kind == StepStream {
+		return s.URL + ".sha256"
+	}
+	return s.URL
+}
+
+// slimNote returns what the unpack or stream step s leaves out, to follow
+// its target in a description, if anything.
+func (s Step) slimNote() string {
+	if s.Slim {
+		return ", without tests, docs and API data"
+	}
+	return ""
+}
+
+// String formats the plan for people, one step per line.
+func (p *Plan) String() string {
+	if p.Installed {
+		return fmt.Sprintf("%s is already installed in %s\n", p.Version, p.GOROOT)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "install %s into %s\n", p.Version, p.GOROOT)
+	for _, s := range p.Steps {
+		switch s.Kind {
+		case StepDownload:
+			if s.Offset > 0 {
+				fmt.Fprintf(&b, "  resume %s from byte %d of %d into %s\n", s.URL, s.Offset, s.Size, s.File)
+			} else {
+				fmt.Fprintf(&b, "  download %s (%d bytes) to %s\n", s.URL, s.Size, s.File)
+			}
+		case StepVerify:
+			if s.SHA256 != "" {
+				fmt.Fprintf(&b, "  verify %s against pinned SHA-256 %s\n", s.File, s.SHA256)
+			} else if s.Checksum == ChecksumSkip {
+				fmt.Fprintf(&b, "  skip verification of %s\n", s.File)
+			} else {
+				fmt.Fprintf(&b, "  verify %s against %s (%s)\n", s.File, s.URL, s.Checksum)
+			}
+		case StepVerifyModule:
+			if s.Checksum == ChecksumSkip {
+				fmt.Fprintf(&b, "  skip verification of %s\n", s.File)
+			} else if s.URL == "" {
+				fmt.Fprintf(&b, "  verify %s, with no checksum database to check it against (%s)\n", s.File, s.Checksum)
+			} else {
+				fmt.Fprintf(&b, "  verify %s against the checksum database at %s (%s)\n", s.File, s.URL, s.Checksum)
+			}
+		case StepUnpack:
+			fmt.Fprintf(&b, "  unpack %s into %s%s\n", s.File, s.Target, s.slimNote())
+		case StepStream:
+			fmt.Fprintf(&b, "  stream %s (%d bytes) into %s%s, then verify it\n", s.URL, s.Size, s.Target, s.slimNote())
+		case StepDedupe:
+			fmt.Fprintf(&b, "  share files of %s with the nearest installed release (%s)\n", s.Target, s.Mode)
+		case StepMarkInstalled:
+			fmt.Fprintf(&b, "  mark %s installed with %s\n", s.Target, s.File)
+		default:
+			fmt.Fprintf(&b, "  %s\n", s.Kind)
+		}
+	}
+	return b.String()
+}
+
+// Plan returns the steps installing release v would take.
+// It contacts the server to learn the archive's size, but changes nothing.
+func (d *Downloader) Plan(ctx context.Context, v Version) (*Plan, error) {
+	root, err := v.GorootPath()
+	if err != nil {
+		return nil, err
+	}
+	return d.plan(ctx, root, v.String())
+}
+
+// Plan is like Downloader.Plan, using a downloader configured by opts.
+func (v Version) Plan(ctx context.Context, opts *DownloaderOptions) (*Plan, error) {
+	d, err := newDownloaderFrom(opts)
+	if err != nil {
+		return nil, err
+	}
+	return d.Plan(ctx, v)
+}
+
+func (d *Downloader) plan(ctx context.Context, targetDir, version string) (*Plan, error) {
+	p := &Plan{Version: version, GOROOT: targetDir}
+	marker := filepath.Join(targetDir, unpackedOkay)
+	if _, err := os.Stat(marker); err == nil {
+		p.Installed = true
+		return p, nil
+	}
+
+	arch, emulated := d.arch()
+	f, err := d.listedArchive(ctx, version, d.goos(), arch)
+	if err != nil && emulated {
+		// Releases from before the native architecture was supported,
+		// such as darwin/arm64 before go1.16, still run emulated.
+		if g, gerr := d.listedArchive(ctx, version, d.goos(), runtime.GOARCH); gerr == nil {
+			log.Printf("Note: %s has no native %s release; installing the %s release, which runs emulated", version, arch, runtime.GOARCH)
+			arch, emulated, f, err = runtime.GOARCH, false, g, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	goURL := d.baseURL + archiveName(version, d.goos(), arch)
+	if f.Filename != "" {
+		goURL = d.baseURL + f.Filename
+	}
+	name := path.Base(goURL)
+	if d.opts.ModuleProxy != "" {
+		goURL, name = d.moduleZipURL(version, d.goos(), arch), moduleArchiveName(version, d.goos(), arch)
+	}
+	switch {
+	case emulated:
+		log.Printf("Note: this %s program is running emulated; installing the native %s release", runtime.GOARCH, arch)
+	case arch != runtime.GOARCH && d.opts.GOARCH == "":
+		log.Printf("Note: installing the %s release to match this system's 32-bit programs", arch)
+	}
+	// The archive is spooled to a file, beside the target or in the
+	// cache, and unpacked from there, even as a zip.
+	archiveDir := targetDir
+	if d.opts.CacheDir != "" {
+		archiveDir = d.opts.CacheDir
+	}
+	archiveFile := filepath.Join(archiveDir, name)
+
+	if d.opts.Offline {
+		// Trust the size of a cached archive; verification will catch
+		// a partial one.
+		fi, err := os.Stat(archiveFile)
+		if err != nil || d.opts.CacheDir == "" {
+			return nil, netGate(true, goURL)
+		}
+		p.URL, p.Size = goURL, fi.Size()
+	} else {
+		res, err := d.do(ctx, http.MethodHead, goURL)
+		if err != nil {
+			return nil, err
+		}
+		_ = res.Body.Close()
+		switch {
+		case d.opts.ModuleProxy != "" && (res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusGone):
+			return nil, fmt.Errorf("no %v for %v/%v in the %s module at %v, which has the releases from go1.21.0 on; unset %s to download the release archive", version, d.goos(), arch, toolchainModule, goURL, envModuleProxy)
+		case res.StatusCode == http.StatusNotFound:
+			return nil, fmt.Errorf("no binary release of %v for %v/%v at %v; use gotip to build Go from source", version, d.goos(), arch, goURL)
+		}
+		if res.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("server returned %v checking size of %v", http.StatusText(res.StatusCode), goURL)
+		}
+		p.URL, p.Size = goURL, res.ContentLength
+	}
+	if fi, err := os.Stat(archiveFile); err != nil || fi.Size() != p.Size {
+		if err != nil && !os.IsNotExist(err) {
+			// Something weird. Don't try to download.
+			return nil, err
+		}
+		step := Step{Kind: StepDownload, URL: goURL, File: archiveFile, Size: p.Size}
+		if d.opts.Resume {
+			step.Offset = resumeOffset(archiveFile, goURL, p.Size)
+		}
+		if d.streams(step) {
+			p.Steps = append(p.Steps, Step{Kind: StepStream, URL: goURL, Size: p.Size, Checksum: d.opts.Checksum, Target: targetDir, Slim: d.opts.Slim})
+			p.Steps = append(p.Steps, d.finishSteps(targetDir, marker)...)
+			return p, nil
+		}
+		p.Steps = append(p.Steps, step)
+	}
+	verify := Step{Kind: StepVerify, URL: goURL + ".sha256", File: archiveFile, Checksum: d.opts.Checksum}
+	if d.opts.ModuleProxy != "" {
+		verify = Step{Kind: StepVerifyModule, URL: d.moduleLookupURL(version, d.goos(), arch), File: archiveFile, Checksum: d.opts.Checksum}
+	}
+	p.Steps = append(p.Steps,
+		verify,
+		Step{Kind: StepUnpack, File: archiveFile, Target: targetDir, Slim: d.opts.Slim},
+	)
+	p.Steps = append(p.Steps, d.finishSteps(targetDir, marker)...)
+	return p, nil
+}
+
+// finishSteps returns the steps that follow unpacking a release into
+// targetDir: sharing its files with another release's, if
+// DownloaderOptions.Dedupe says to, and marking it installed with marker.
+func (d *Downloader) finishSteps(targetDir, marker string) []Step {
+	var steps []Step
+	if mode := d.opts.Dedupe; mode != "" && mode != dedupeOff {
+		steps = append(steps, Step{Kind: StepDedupe, Target: targetDir, Mode: mode})
+	}
+	return append(steps, Step{Kind: StepMarkInstalled, File: marker, Target: targetDir})
+}
+
+// streams reports whether the archive that the download step s fetches
+// is to be unpacked as it arrives instead, as DownloaderOptions.Stream
+// describes.
+func (d *Downloader) streams(s Step) bool {
+	return d.opts.Stream && d.opts.CacheDir == "" && s.Offset == 0 &&
+		strings.HasSuffix(s.URL, ".tar.gz") && len(segmentRanges(s.Size, d.opts.Segments)) <= 1
+}
+
+// listedArchive returns the archive of version for goos/goarch that the
+// release listing names, when downloading from DefaultBaseURL, which the
+// listing describes, or from a mirror of it with a mirror of the listing,
+// such as ChinaBaseURL. It returns the zero File when the listing can't be
+// had or doesn't include version, and an error when it shows that version
+// has no archive for the platform. Newer ports such as linux/riscv64 and
+// linux/loong64 only have archives from some release on, so the error
+// names the oldest release that does.
+func (d *Downloader) listedArchive(ctx context.Context, version, goos, goarch string) (File, error) {
+	if d.baseURL != DefaultBaseURL && d.catalogURL() == "" || d.catalog == nil {
+		return File{}, nil
+	}
+	v, err := ParseVersion(version)
+	if err != nil {
+		return File{}, nil
+	}
+	rs, err := d.catalog.All(ctx, Filter{})
+	if err != nil {
+		return File{}, nil
+	}
+	listed := false
+	var oldest Version
+	for _, r := range rs {
+		f, ok := releaseArchive(r, goos, goarch)
+		if r.Version.Compare(v) == 0 {
+			if ok {
+				return f, nil
+			}
+			listed = true
+		}
+		if ok && (oldest == (Version{}) || r.Version.Less(oldest)) {
+			oldest = r.Version
+		}
+	}
+	switch {
+	case !listed:
+		return File{}, nil
+	case oldest == (Version{}):
+		return File{}, fmt.Errorf("no binary release for %s/%s in %s, nor in any other release; use gotip to build Go from source", goos, goarch, version)
+	case v.Less(oldest):
+		return File{}, fmt.Errorf("no binary release for %s/%s in %s; the oldest release with one is %s, or use gotip to build Go from source", goos, goarch, version, oldest)
+	default:
+		return File{}, fmt.Errorf("no binary release for %s/%s in %s; use gotip to build Go from source", goos, goarch, version)
+	}
+}
+
+// releaseArchive returns r's archive for goos/goarch, under any of the
+// names the platform's archives are published as.
+func releaseArchive(r Release, goos, goarch string) (File, bool) {
+	for _, g := range releaseOSes(goos) {
+		if f, ok := r.Archive(g, releaseArch(g, goarch)); ok {
+			return f, true
+		}
+	}
+	return File{}, false
+}
+
+// Execute carries out p, which must have been made by a Downloader with
+// the same options.
+func (d *Downloader) Execute(ctx context.Context, p *Plan) (err error) {
+	em := d.emitter()
+	start := time.Now()
+	phase := PhaseResolve
+	defer func() {
+		if err != nil {
+			em.emit(Failed{Err: err, Phase: phase})
+		} else {
+			em.emit(Completed{GOROOT: p.GOROOT, Duration: time.Since(start)})
+		}
+	}()
+
+	if p.Installed {
+		log.Printf("%s: already downloaded in %v", p.Version, p.GOROOT)
+		return nil
+	}
+	defer d.holdCache()()
+	removeAsideDirs(p.GOROOT)
+	if err := os.MkdirAll(p.GOROOT, 0755); err != nil {
+		return err
+	}
+	if !d.opts.Cross {
+		if err := checkExecAllowed(p.GOROOT); err != nil {
+			return err
+		}
+	}
+	if err := checkSpace(spaceNeeded(p), freeSpace); err != nil {
+		return err
+	}
+	em.emit(ResolutionDone{Version: p.Version, URL: p.URL, Size: p.Size})
+	verbosef("Installing %s from %s (%s) into %s", p.Version, p.URL, formatByteSize(p.Size), p.GOROOT)
+	if d.opts.CacheDir != "" && p.fromCache(d.opts.CacheDir) {
+		hit := true
+		for _, s := range p.Steps {
+			if s.Kind == StepDownload {
+				hit = false
+			}
+		}
+		d.opts.Metrics.cache(hit)
+		recordCacheUse(d.opts.CacheDir, filepath.Base(p.archive()), hit, time.Now())
+	}
+	for _, s := range p.Steps {
+		phase = s.phase()
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := d.runStep(ctx, s); err != nil {
+			return err
+		}
+	}
+	verbosef("Installed %s in %v", p.Version, time.Since(start).Round(time.Millisecond))
+	if d.opts.Cross {
+		arch, _ := d.arch()
+		log.Printf("Success. %v for %s/%s is in %v", p.Version, d.goos(), arch, p.GOROOT)
+		return nil
+	}
+	log.Printf("Success. You may now run '%v'", p.Version)
+	return nil
+}
+
+// abandonUnpack removes what an unpack into targetDir from archive,
+// interrupted, left there, as removeUnpacked does.
+func (d *Downloader) abandonUnpack(targetDir, archive string) {
+	if err := removeUnpacked(targetDir, archive); err != nil {
+		log.Printf("Note: could not remove the partly unpacked %s: %v", targetDir, err)
+		return
+	}
+	verbosef("Removed the partly unpacked %s", targetDir)
+}
+
+// fromCache reports whether p unpacks an archive in the cache dir, rather
+// than one installed from elsewhere.
+func (p *Plan) fromCache(dir string) bool {
+	archive := p.archive()
+	return archive != "" && filepath.Dir(archive) == filepath.Clean(dir)
+}
+
+// archive returns the archive file that p unpacks, or "" if it unpacks
+// none.
+func (p *Plan) archive() string {
+	for _, s := range p.Steps {
+		if s.Kind == StepUnpack {
+			return s.File
+		}
+	}
+	return ""
+}
+
+// download fetches url, size bytes long, to file, starting at offset, in
+// segments if d is configured to and the download starts afresh. It
+// returns the number of bytes transferred and the host that sent them.
+func (d *Downloader) download(ctx context.Context, file, url string, size, offset int64) (int64, string, error) {
+	if ranges := segmentRanges(size, d.opts.Segments); offset == 0 && len(ranges) > 1 {
+		n, host, err := d.copySegments(ctx, file, url, size, ranges)
+		if !errors.Is(err, errNoRanges) {
+			return n, host, err
+		}
+		log.Printf("Note: %v, so %v is downloaded whole", err, url)
+	}
+	return d.copyFromURL(ctx, file, url, offset)
+}
+
+func (d *Downloader) runStep(ctx context.Context, s Step) error {
+	switch s.Kind {
+	case StepDownload:
+		if err := os.MkdirAll(filepath.Dir(s.File), 0755); err != nil {
+			return err
+		}
+		if s.Offset > 0 {
+			log.Printf("Resuming download of %v at byte %d", s.URL, s.Offset)
+		}
+		verbosef("Downloading %v to %v", s.URL, s.File)
+		start := time.Now()
+		n, host, err := d.download(ctx, s.File, s.URL, s.Size, s.Offset)
+		// A download that broke off is retried, resuming it if
+		// partial downloads are kept.
+		for attempt := 0; err != nil && attempt < d.opts.Retries && retryable(ctx, err); attempt++ {
+			delay := d.retryPolicy().delay(attempt, 0)
+			log.Printf("Note: downloading %v: %v; retrying in %v (%d of %d)", s.URL, err, delay.Round(100*time.Millisecond), attempt+1, d.opts.Retries)
+			d.opts.Metrics.retry(s.URL, attempt+1, delay, err)
+			if err = sleepContext(ctx, delay); err != nil {
+				break
+			}
+			var m int64
+			m, host, err = d.download(ctx, s.File, s.URL, s.Size, resumeOffset(s.File, s.URL, s.Size))
+			n += m
+		}
+		elapsed := time.Since(start)
+		d.opts.Metrics.download(host, n, elapsed, err)
+		if err != nil {
+			return fmt.Errorf("error downloading %v: %w", s.URL, err)
+		}
+		verbosef("Downloaded %s in %v (%s/s)", formatByteSize(n), elapsed.Round(time.Millisecond), formatByteSize(int64(float64(n)/elapsed.Seconds())))
+		fi, err := os.Stat(s.File)
+		if err != nil {
+			return err
+		}
+		if fi.Size() != s.Size {
+			return fmt.Errorf("downloaded file %s size %v doesn't match server size %v", s.File, fi.Size(), s.Size)
+		}
+		return nil
+	case StepVerify:
+		return d.verify(ctx, s.File, strings.TrimSuffix(s.URL, ".sha256"), s.Checksum, s.SHA256)
+	case StepVerifyModule:
+		return d.verifyModule(ctx, s)
+	case StepStream:
+		return d.stream(ctx, s)
+	case StepUnpack:
+		log.Printf("Unpacking %v ...", s.File)
+		start := time.Now()
+		var size int64
+		if fi, err := os.Stat(s.File); err == nil {
+			size = fi.Size()
+		}
+		progress, err := unpackArchive(ctx, s.Target, s.File, d.emitter(), d.newMeter("Unpacked", size, 0, false), unpackFilter(s.Slim))
+		elapsed := time.Since(start)
+		d.opts.Metrics.unpack(progress, elapsed, err)
+		if err != nil {
+			if ctx.Err() != nil {
+				d.abandonUnpack(s.Target, s.File)
+			}
+			return fmt.Errorf("extracting archive %v: %w", s.File, err)
+		}
+		verbosef("Unpacked %d files, %s, into %v in %v", progress.Files, formatByteSize(progress.Bytes), s.Target, elapsed.Round(time.Millisecond))
+		clearQuarantine(s.Target)
+		return nil
+	case StepDedupe:
+		return d.dedupe(ctx, s)
+	case StepMarkInstalled:
+		arch, _ := d.arch()
+		if d.opts.Cross {
+			verbosef("Not running the %s/%s go command in %v, which is for another machine", d.goos(), arch, s.Target)
+		} else if err := smokeTest(ctx, s.Target, d.goos(), arch); err != nil {
+			return err
+		}
+		return ioutil.WriteFile(s.File, nil, 0644)
+	default:
+		return fmt.Errorf("unknown install step %q", s.Kind)
+	}
+}
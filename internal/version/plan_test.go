@@ -0,0 +1,202 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files in testdata")
+
+// checkGolden compares got with testdata/name, or rewrites the file
+// if -update is set.
+func checkGolden(t *testing.T, name, got string) {
+	t.Helper()
+	file := filepath.Join("testdata", name)
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(file, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("%s mismatch (rerun with -update if intended)\ngot:\n%s\nwant:\n%s", file, got, want)
+	}
+}
+
+func TestPlanGolden(t *testing.T) {
+	ts := newTestServer(t)
+	goURL := versionArchiveURL(DefaultBaseURL, "go1.99")
+	archive := ts.tar
+	if strings.HasSuffix(goURL, ".zip") {
+		archive = ts.zip
+	}
+	base := filepath.Base(goURL)
+
+	tests := []struct {
+		name  string
+		opts  DownloaderOptions
+		setup func(t *testing.T, goroot, cache string)
+	}{
+		{name: "fresh"},
+		{name: "installed", setup: func(t *testing.T, goroot, cache string) {
+			writeTestFile(t, filepath.Join(goroot, unpackedOkay), nil)
+		}},
+		{name: "archive-present", setup: func(t *testing.T, goroot, cache string) {
+			writeTestFile(t, filepath.Join(goroot, base), archive)
+		}},
+		{name: "partial-resume", opts: DownloaderOptions{Resume: true}, setup: func(t *testing.T, goroot, cache string) {
+			writeTestFile(t, filepath.Join(goroot, base+partialSuffix), archive[:len(archive)/2])
+			if err := writePartialInfo(filepath.Join(goroot, base), partialInfo{URL: goURL, Size: int64(len(archive))}); err != nil {
+				t.Fatal(err)
+			}
+		}},
+		{name: "partial-restart", setup: func(t *testing.T, goroot, cache string) {
+			writeTestFile(t, filepath.Join(goroot, base), archive[:len(archive)/2])
+		}},
+		{name: "cache-dir", opts: DownloaderOptions{CacheDir: "$CACHE"}},
+		{name: "cache-hit", opts: DownloaderOptions{CacheDir: "$CACHE"}, setup: func(t *testing.T, goroot, cache string) {
+			writeTestFile(t, filepath.Join(cache, base), archive)
+		}},
+		{name: "checksum-skip", opts: DownloaderOptions{Checksum: ChecksumSkip}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmp := t.TempDir()
+			goroot, cache := filepath.Join(tmp, "go1.99"), filepath.Join(tmp, "cache")
+			if tt.opts.CacheDir == "$CACHE" {
+				tt.opts.CacheDir = cache
+			}
+			if tt.setup != nil {
+				tt.setup(t, goroot, cache)
+			}
+			d := ts.downloader(t, tt.opts)
+			p, err := d.plan(context.Background(), goroot, "go1.99")
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Planning must not have created anything.
+			if tt.setup == nil {
+				if _, err := os.Stat(goroot); !os.IsNotExist(err) {
+					t.Errorf("planning created %s", goroot)
+				}
+			}
+			got := strings.NewReplacer(
+				filepath.ToSlash(goroot), "$GOROOT",
+				filepath.ToSlash(cache), "$CACHE",
+				base, "$ARCHIVE",
+				strings.TrimSuffix(DefaultBaseURL, "/"), "$BASE_URL",
+				" "+strconv.Itoa(len(archive)/2)+" ", " $HALF ",
+				strconv.Itoa(len(archive)), "$SIZE",
+			).Replace(filepath.ToSlash(p.String()))
+			checkGolden(t, filepath.Join("plan", tt.name+".golden"), got)
+
+			// Executing the plan installs the release.
+			if err := d.Execute(context.Background(), p); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(goroot, unpackedOkay)); err != nil {
+				t.Errorf("not installed after Execute: %v", err)
+			}
+		})
+	}
+}
+
+func TestPlanEmulatedFallback(t *testing.T) {
+	ts := newTestServer(t)
+	d := ts.downloader(t, DownloaderOptions{GOOS: "darwin"})
+	native := "arm64"
+	if runtime.GOARCH == native {
+		native = "amd64"
+	}
+	d.hostArch = func() (string, bool) { return native, true }
+	running := releaseArch("darwin", runtime.GOARCH)
+	d.catalog.releases = []Release{
+		{Version: Version{Major: 1, Minor: 22, Patch: 7}, Files: []File{
+			{Filename: "go1.22.7.darwin-" + native + ".tar.gz", OS: "darwin", Arch: native, Kind: "archive"},
+			{Filename: "go1.22.7.darwin-" + running + ".tar.gz", OS: "darwin", Arch: running, Kind: "archive"},
+		}},
+		{Version: Version{Major: 1, Minor: 15}, Files: []File{
+			{Filename: "go1.15.darwin-" + running + ".tar.gz", OS: "darwin", Arch: running, Kind: "archive"},
+		}},
+	}
+
+	// A release with a native archive gets it.
+	p, err := d.plan(context.Background(), filepath.Join(t.TempDir(), "go1.22.7"), "go1.22.7")
+	if err != nil || !strings.HasSuffix(p.URL, "/go1.22.7.darwin-"+native+".tar.gz") {
+		t.Fatalf("plan(go1.22.7) = %+v, %v; want the native archive", p, err)
+	}
+	// One from before the native architecture was supported falls back
+	// to the architecture this program runs as.
+	p, err = d.plan(context.Background(), filepath.Join(t.TempDir(), "go1.15"), "go1.15")
+	if err != nil || !strings.HasSuffix(p.URL, "/go1.15.darwin-"+running+".tar.gz") {
+		t.Fatalf("plan(go1.15) = %+v, %v; want the emulated archive", p, err)
+	}
+
+	// Without emulation, a missing archive is still an error.
+	d.hostArch = func() (string, bool) { return native, false }
+	if _, err := d.plan(context.Background(), filepath.Join(t.TempDir(), "go1.15"), "go1.15"); err == nil || !strings.Contains(err.Error(), "no binary release for darwin/"+native) {
+		t.Errorf("plan(go1.15) without emulation: %v; want no binary release", err)
+	}
+}
+
+func writeTestFile(t *testing.T, file string, data []byte) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(file, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPrintDryRun(t *testing.T) {
+	const url = "https://dl.google.com/go/go1.99.linux-amd64.tar.gz"
+	p := &Plan{Version: "go1.99", GOROOT: "/sdk/go1.99", URL: url, Size: 3 << 20, Steps: []Step{
+		{Kind: StepDownload, URL: url, File: "/sdk/go1.99/go1.99.linux-amd64.tar.gz", Size: 3 << 20},
+		{Kind: StepVerify, URL: url + ".sha256", File: "/sdk/go1.99/go1.99.linux-amd64.tar.gz"},
+		{Kind: StepUnpack, File: "/sdk/go1.99/go1.99.linux-amd64.tar.gz", Target: "/sdk/go1.99"},
+	}}
+	var b strings.Builder
+	printDryRun(&b, p, false)
+	want := `go1.99 would be installed into /sdk/go1.99
+  archive:  https://dl.google.com/go/go1.99.linux-amd64.tar.gz
+  size:     3145728 bytes (3.0 MiB)
+  download: to /sdk/go1.99/go1.99.linux-amd64.tar.gz
+  checksum: https://dl.google.com/go/go1.99.linux-amd64.tar.gz.sha256
+`
+	if b.String() != want {
+		t.Errorf("printDryRun printed:\n%s\nwant:\n%s", b.String(), want)
+	}
+
+	// An archive in the cache, verified against a pinned checksum.
+	p.Steps = p.Steps[1:]
+	p.Steps[0].SHA256 = "abc123"
+	b.Reset()
+	printDryRun(&b, p, false)
+	for _, line := range []string{
+		"  download: none; /sdk/go1.99/go1.99.linux-amd64.tar.gz is already downloaded\n",
+		"  checksum: pinned SHA-256 abc123\n",
+	} {
+		if !strings.Contains(b.String(), line) {
+			t.Errorf("printDryRun printed:\n%s\nwant it to include %q", b.String(), line)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// A progressRecord is a line of the JSON progress of an install, for
+// DownloaderOptions.ProgressJSON. Event is "resolved", "progress",
+// "verified", "output", "completed" or "failed"; the other fields are set
+// as the event has them.
+type progressRecord struct {
+	Time    time.Time `json:"time"`
+	Phase   Phase     `json:"phase"`
+	Event   string    `json:"event"`
+	Version string    `json:"version,omitempty"`
+	URL     string    `json:"url,omitempty"`
+
+	// Bytes and Total count the bytes downloaded or unpacked, and Percent
+	// and Speed, in bytes per second since the download started, follow
+	// from them. Total is -1 if unknown, and Percent then unset. They are
+	// pointers so that a record of no bytes yet, or of a total of 0,
+	// still has them, while events that count no bytes don't.
+	Bytes   *int64   `json:"bytes,omitempty"`
+	Total   *int64   `json:"total,omitempty"`
+	Percent *float64 `json:"percent,omitempty"`
+	Speed   int64    `json:"bytes_per_second,omitempty"`
+	Files   int      `json:"files,omitempty"`
+
+	SHA256  string  `json:"sha256,omitempty"`
+	Step    string  `json:"step,omitempty"`
+	Line    string  `json:"line,omitempty"`
+	Elapsed float64 `json:"elapsed_seconds,omitempty"`
+	GOROOT  string  `json:"goroot,omitempty"`
+	Error   string  `json:"error,omitempty"`
+}
+
+// A jsonProgress writes events to w as progressRecords, one per line.
+// It is safe for concurrent use. A nil *jsonProgress writes nothing.
+type jsonProgress struct {
+	mu      sync.Mutex
+	enc     *json.Encoder
+	started map[string]time.Time // of each download, by URL
+}
+
+// newJSONProgress returns a jsonProgress writing to w, or nil if w is.
+func newJSONProgress(w io.Writer) *jsonProgress {
+	if w == nil {
+		return nil
+	}
+	return &jsonProgress{enc: json.NewEncoder(w), started: map[string]time.Time{}}
+}
+
+// record writes the record of ev.
+func (p *jsonProgress) record(ev Event) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	r := progressRecord{Time: now.UTC()}
+	switch ev := ev.(type) {
+	case ResolutionDone:
+		r.Phase, r.Event = PhaseResolve, "resolved"
+		r.Version, r.URL, r.Total = ev.Version, ev.URL, &ev.Size
+	case DownloadProgress:
+		r.Phase, r.Event = PhaseDownload, "progress"
+		r.URL, r.Bytes, r.Total = ev.URL, &ev.Bytes, &ev.Total
+		switch {
+		case ev.Total > 0:
+			pct := float64(int(1000*float64(ev.Bytes)/float64(ev.Total))) / 10
+			r.Percent = &pct
+		case ev.Total == 0:
+			pct := 100.0
+			r.Percent = &pct
+		}
+		start, ok := p.started[ev.URL]
+		if !ok {
+			p.started[ev.URL] = now
+		} else if d := now.Sub(start); d > 0 {
+			r.Speed = int64(float64(ev.Bytes) / d.Seconds())
+		}
+	case VerificationResult:
+		r.Phase, r.Event = PhaseVerify, "verified"
+		r.SHA256 = ev.SHA256
+		if ev.Err != nil {
+			r.Event, r.Error = "failed", ev.Err.Error()
+		}
+	case UnpackProgress:
+		r.Phase, r.Event = PhaseUnpack, "progress"
+		r.Files, r.Bytes = ev.Files, &ev.Bytes
+	case BuildOutputLine:
+		r.Phase, r.Event = PhaseBuild, "output"
+		r.Line = ev.Line
+	case BuildProgress:
+		r.Phase, r.Event = PhaseBuild, "progress"
+		r.Step, r.Elapsed = ev.Phase, ev.Elapsed.Seconds()
+	case Completed:
+		r.Event = "completed"
+		r.GOROOT, r.Elapsed = ev.GOROOT, ev.Duration.Seconds()
+	case Failed:
+		r.Phase, r.Event = ev.Phase, "failed"
+		r.Error = ev.Err.Error()
+	default:
+		panic(fmt.Sprintf("unknown event %T", ev))
+	}
+	// A consumer that went away doesn't stop the install.
+	_ = p.enc.Encode(r)
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestJSONProgress(t *testing.T) {
+	ts := newTestServer(t)
+	var out bytes.Buffer
+	d := ts.downloader(t, DownloaderOptions{ProgressJSON: &out})
+	if err := d.install(context.Background(), t.TempDir(), "go1.99"); err != nil {
+		t.Fatal(err)
+	}
+	var events []string
+	var last progressRecord
+	sc := bufio.NewScanner(&out)
+	for sc.Scan() {
+		var r progressRecord
+		if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+			t.Fatalf("line %q isn't a JSON record: %v", sc.Text(), err)
+		}
+		events = append(events, string(r.Phase)+" "+r.Event)
+		if r.Phase == PhaseDownload {
+			last = r
+		}
+	}
+	want := map[string]bool{"resolve resolved": true, "download progress": true, "verify verified": true, " completed": true}
+	for _, e := range events {
+		delete(want, e)
+	}
+	if len(want) > 0 {
+		t.Errorf("JSON progress %q lacks %v", events, want)
+	}
+	if last.Bytes == nil || last.Total == nil || last.Percent == nil || *last.Bytes == 0 || *last.Bytes != *last.Total || *last.Percent != 100 {
+		t.Errorf("last download record = %+v; want all bytes, at 100%%", last)
+	}
+
+	// A download that hasn't started, or whose size is unknown, still
+	// reports its bytes; events that count none don't.
+	for _, tt := range []struct {
+		ev   Event
+		want []string
+		not  []string
+	}{
+		{DownloadProgress{URL: "u", Bytes: 0, Total: 100}, []string{`"bytes":0,`, `"total":100,`, `"percent":0}`}, nil},
+		{DownloadProgress{URL: "u", Bytes: 0, Total: -1}, []string{`"bytes":0,`, `"total":-1`}, []string{`"percent"`}},
+		{DownloadProgress{URL: "u", Bytes: 0, Total: 0}, []string{`"bytes":0,`, `"total":0,`, `"percent":100`}, nil},
+		{VerificationResult{SHA256: "ab"}, nil, []string{`"bytes"`, `"total"`, `"percent"`}},
+	} {
+		out.Reset()
+		newJSONProgress(&out).record(tt.ev)
+		line := out.String()
+		for _, w := range tt.want {
+			if !strings.Contains(line, w) {
+				t.Errorf("record of %+v = %s; want it to have %s", tt.ev, line, w)
+			}
+		}
+		for _, w := range tt.not {
+			if strings.Contains(line, w) {
+				t.Errorf("record of %+v = %s; want no %s", tt.ev, line, w)
+			}
+		}
+	}
+
+	out.Reset()
+	newJSONProgress(&out).record(Failed{Err: errors.New("no space left"), Phase: PhaseUnpack})
+	var r progressRecord
+	if err := json.Unmarshal(out.Bytes(), &r); err != nil || r.Phase != PhaseUnpack || r.Event != "failed" || r.Error != "no space left" {
+		t.Errorf("record of a failure = %+v, %v; want the unpack phase and its error", r, err)
+	}
+}
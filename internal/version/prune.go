@@ -0,0 +1,51 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import "errors"
+
+// A prunePolicy says which installed releases dl prune keeps: the newest
+// keepPerMinor of each minor version, such as go1.22, counting its betas
+// and release candidates, and the newest keepLatest overall.
+type prunePolicy struct {
+	keepPerMinor int
+	keepLatest   int
+}
+
+// defaultPrunePolicy is the policy of dl prune without flags.
+var defaultPrunePolicy = prunePolicy{keepPerMinor: 1}
+
+func (p prunePolicy) check() error {
+	if p.keepPerMinor < 0 || p.keepLatest < 0 {
+		return errors.New("can't keep a negative number of releases")
+	}
+	if p.keepPerMinor == 0 && p.keepLatest == 0 {
+		return errors.New("the policy keeps nothing; use dl purge to remove every toolchain")
+	}
+	return nil
+}
+
+// pruneCandidates returns the releases of installed that p doesn't keep,
+// oldest first.
+func pruneCandidates(installed []Version, p prunePolicy) []Version {
+	vs := append([]Version(nil), installed...)
+	SortVersions(vs)
+
+	type minor struct{ major, minor int }
+	perMinor := make(map[minor]int)
+	keep := make([]bool, len(vs))
+	for i := len(vs) - 1; i >= 0; i-- {
+		m := minor{vs[i].Major, vs[i].Minor}
+		perMinor[m]++
+		keep[i] = len(vs)-i <= p.keepLatest || perMinor[m] <= p.keepPerMinor
+	}
+	var out []Version
+	for i, v := range vs {
+		if !keep[i] {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,51 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPruneCandidates(t *testing.T) {
+	var installed []Version
+	for _, s := range []string{"go1.22.7", "go1.21.0", "go1.22rc1", "go1.20.14", "go1.22.6", "go1.21.13", "go1.23rc2", "go1.9"} {
+		v, err := ParseVersion(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		installed = append(installed, v)
+	}
+	tests := []struct {
+		p    prunePolicy
+		want []string
+	}{
+		{prunePolicy{keepPerMinor: 1}, []string{"go1.21.0", "go1.22rc1", "go1.22.6"}},
+		{prunePolicy{keepPerMinor: 2}, []string{"go1.22rc1"}},
+		{prunePolicy{keepPerMinor: 1, keepLatest: 3}, []string{"go1.21.0", "go1.22rc1"}},
+		{prunePolicy{keepLatest: 3}, []string{"go1.9", "go1.20.14", "go1.21.0", "go1.21.13", "go1.22rc1"}},
+		{prunePolicy{keepPerMinor: 5}, nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, v := range pruneCandidates(installed, tt.p) {
+			got = append(got, v.String())
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("pruneCandidates(%+v) = %q; want %q", tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestPrunePolicyCheck(t *testing.T) {
+	for _, p := range []prunePolicy{{0, 0}, {-1, 3}, {1, -1}} {
+		if p.check() == nil {
+			t.Errorf("%+v passed the check; want an error", p)
+		}
+	}
+	if err := (prunePolicy{keepPerMinor: 1}).check(); err != nil {
+		t.Errorf("the default policy failed the check: %v", err)
+	}
+}
@@ -0,0 +1,243 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// A PurgeItem is a file or directory created by the tool, which purging
+// removes.
+type PurgeItem struct {
+	Path string
+	What string // such as "toolchain go1.22.7"
+	Size int64  // total size of the files in it
+
+	// IfEmpty marks a directory that is only removed if nothing else is
+	// left in it.
+	IfEmpty bool
+}
+
+// An UnknownEntriesError reports that the SDK root holds entries the tool
+// didn't create, which purging would destroy.
+type UnknownEntriesError struct {
+	Dir     string
+	Entries []string
+}
+
+func (e *UnknownEntriesError) Error() string {
+	return fmt.Sprintf("%s holds entries not created by this tool: %s", e.Dir, strings.Join(e.Entries, ", "))
+}
+
+// ignorableEntries are files that file managers leave in any directory
+// they show.
+var ignorableEntries = map[string]bool{
+	".DS_Store":   true,
+	"Thumbs.db":   true,
+	"desktop.ini": true,
+}
+
+// PurgeItems lists everything the tool has created under l's SDK root and
+// in cacheDir, the archive cache, if non-empty, followed by those
+// directories themselves. Unless force is set, it returns an
+// *UnknownEntriesError if the SDK root holds anything the tool wouldn't
+// have put there, in case it is not the directory it is believed to be;
+// if force is set, such entries are left alone.
+func (l *Locator) PurgeItems(cacheDir string, force bool) ([]PurgeItem, error) {
+	root, err := l.SDKRoot()
+	if err != nil {
+		return nil, err
+	}
+	var items []PurgeItem
+	fis, err := ioutil.ReadDir(root)
+	if os.IsNotExist(err) {
+		root = ""
+	} else if err != nil {
+		return nil, err
+	}
+	sortEntries(fis)
+	var unknown []string
+	for _, fi := range fis {
+		name := fi.Name()
+		what := toolchainEntry(name)
+		if cacheDir != "" && filepath.Join(root, name) == filepath.Clean(cacheDir) {
+			what = "archive cache"
+		}
+		if what == "" {
+			if !ignorableEntries[name] {
+				unknown = append(unknown, name)
+			}
+			continue
+		}
+		p := filepath.Join(root, name)
+		items = append(items, PurgeItem{Path: p, What: what, Size: diskUsage(p)})
+	}
+	if len(unknown) > 0 && !force {
+		return nil, &UnknownEntriesError{Dir: root, Entries: unknown}
+	}
+
+	if cacheDir != "" && (root == "" || !within(cacheDir, root)) {
+		fis, err := ioutil.ReadDir(cacheDir)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		for _, fi := range fis {
+			switch name := fi.Name(); {
+			case !fi.Mode().IsRegular():
+			case isArchiveName(name):
+				items = append(items, PurgeItem{Path: filepath.Join(cacheDir, name), What: "cached archive", Size: fi.Size()})
+			case isPartialName(name):
+				items = append(items, PurgeItem{Path: filepath.Join(cacheDir, name), What: "partial download", Size: fi.Size()})
+			case name == cacheLockName || strings.HasPrefix(name, cacheStatsName):
+				items = append(items, PurgeItem{Path: filepath.Join(cacheDir, name), What: "archive cache bookkeeping", Size: fi.Size()})
+			}
+		}
+		if err == nil {
+			items = append(items, PurgeItem{Path: cacheDir, What: "archive cache", IfEmpty: true})
+		}
+	}
+	if root != "" {
+		items = append(items, PurgeItem{Path: root, What: "SDK root", IfEmpty: true})
+	}
+	return items, nil
+}
+
+// toolFiles are where the tool keeps files of its own, outside the SDK
+// root and the archive cache.
+type toolFiles struct {
+	Config  string // the default config file
+	Journal string // the install journal, rotated to Journal+".1"
+	Listing string // the directory the release listing is cached in
+	GOBIN   string // where dl default installs the go shim
+}
+
+// purgeItems lists those of f's files that exist, the go shim only if it
+// is one, followed by the directories they are in, which are only
+// removed if nothing else is left in them.
+func (f toolFiles) purgeItems() []PurgeItem {
+	var items []PurgeItem
+	add := func(p, what string) {
+		if fi, err := os.Stat(p); err == nil && fi.Mode().IsRegular() {
+			items = append(items, PurgeItem{Path: p, What: what, Size: fi.Size()})
+		}
+	}
+	var dirs []PurgeItem
+	if f.Config != "" {
+		add(f.Config, "config file")
+		dirs = append(dirs, PurgeItem{Path: filepath.Dir(f.Config), What: "config directory"})
+	}
+	if f.Journal != "" {
+		add(f.Journal, "install journal")
+		add(f.Journal+".1", "older install journal")
+		dirs = append(dirs, PurgeItem{Path: filepath.Dir(f.Journal), What: "config directory"})
+	}
+	if f.Listing != "" {
+		add(filepath.Join(f.Listing, catalogCacheFile), "cached release listing")
+		add(filepath.Join(f.Listing, catalogMetaFile), "cached release listing's validators")
+		dirs = append(dirs, PurgeItem{Path: f.Listing, What: "cache directory"})
+	}
+	if shim := filepath.Join(f.GOBIN, "go"+exe()); f.GOBIN != "" && isShim(shim) {
+		add(shim, "go shim")
+	}
+	seen := make(map[string]bool)
+	for _, dir := range dirs {
+		if !seen[dir.Path] && isDir(dir.Path) {
+			seen[dir.Path] = true
+			dir.IfEmpty = true
+			items = append(items, dir)
+		}
+	}
+	return items
+}
+
+// toolchainEntry describes the entry of the SDK root with the given name,
+// or returns "" if the tool doesn't create such entries.
+func toolchainEntry(name string) string {
+	base := name
+	if i := strings.Index(name, asideSuffix); i > 0 {
+		base = name[:i]
+	}
+	if base != "gotip" && !isCrossName(base) {
+		if _, err := ParseVersion(base); err != nil {
+			return ""
+		}
+	}
+	if base != name {
+		return "old copy of " + base
+	}
+	return "toolchain " + name
+}
+
+// isArchiveName reports whether name is that of a release archive, or of
+// the checksum saved with it, which the archive cache holds.
+func isArchiveName(name string) bool {
+	name = strings.TrimSuffix(name, ".sha256")
+	return strings.HasPrefix(name, "go") && (strings.HasSuffix(name, ".tar.gz") || strings.HasSuffix(name, ".zip"))
+}
+
+// within reports whether path is dir or inside it.
+func within(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// diskUsage returns the total size of the files under path.
+func diskUsage(path string) int64 {
+	var n int64
+	_ = filepath.Walk(path, func(_ string, fi os.FileInfo, err error) error {
+		if err == nil && fi.Mode().IsRegular() {
+			n += fi.Size()
+		}
+		return nil
+	})
+	return n
+}
+
+// Purge removes items, as listed by PurgeItems, carrying on past failures,
+// and returns an error for each item it couldn't remove.
+func Purge(items []PurgeItem) []error {
+	var errs []error
+	for _, it := range items {
+		var err error
+		if it.IfEmpty {
+			err = removeIfEmpty(it.Path)
+		} else {
+			err = purgePath(it.Path)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// purgePath removes path and anything under it. On Windows, files that
+// running programs hold open can't be removed, and the error names the
+// programs.
+func purgePath(path string) error {
+	err := os.RemoveAll(path)
+	if err != nil && isInUse(err) {
+		if procs := lockingProcesses(path); len(procs) > 0 {
+			return fmt.Errorf("can't remove %s while it is in use by %s", path, strings.Join(procs, ", "))
+		}
+	}
+	return err
+}
+
+// removeIfEmpty removes the directory dir if there is nothing in it.
+func removeIfEmpty(dir string) error {
+	fis, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) || err == nil && len(fis) > 0 {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return os.Remove(dir)
+}
@@ -0,0 +1,162 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// makeTree creates the named files, with their contents, under dir.
+func makeTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, body := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestPurge(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "go_sdk")
+	cache := t.TempDir()
+	makeTree(t, root, map[string]string{
+		"go1.22.7/bin/go":          "12345",
+		"go1.22.7/" + unpackedOkay: "",
+		"gotip/src/make.bash":      "123",
+		"go1.21.0.old-abc/VERSION": "go1.21.0",
+		".DS_Store":                "x",
+	})
+	makeTree(t, cache, map[string]string{
+		"go1.22.7.linux-amd64.tar.gz": "1234567",
+		"notes.txt":                   "mine",
+	})
+	// The tool's own files, beside the SDK root.
+	home := filepath.Dir(root)
+	makeTree(t, home, map[string]string{
+		"config/godl/config":          "sdk_dir = x\n",
+		"config/godl/journal.jsonl":   "{}\n",
+		"config/godl/journal.jsonl.1": "{}\n{}\n",
+		"cache/godl/releases.json":    "[]",
+		"gobin/go" + exe():            string(shimMarker),
+		"gopath/bin/go" + exe():       "a go command of its own",
+	})
+	files := toolFiles{
+		Config:  filepath.Join(home, "config", "godl", "config"),
+		Journal: filepath.Join(home, "config", "godl", "journal.jsonl"),
+		Listing: filepath.Join(home, "cache", "godl"),
+		GOBIN:   filepath.Join(home, "gobin"),
+	}
+
+	l := &Locator{Root: root}
+	items, err := l.PurgeItems(cache, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	items = append(items, files.purgeItems()...)
+	if other := (toolFiles{GOBIN: filepath.Join(home, "gopath", "bin")}).purgeItems(); len(other) > 0 {
+		t.Errorf("a go command that isn't a shim would be purged: %v", other)
+	}
+	var got []string
+	for _, it := range items {
+		rel, _ := filepath.Rel(filepath.Dir(root), it.Path)
+		switch {
+		case it.Path == cache:
+			rel = "cache"
+		case filepath.Dir(it.Path) == cache:
+			rel = filepath.Join("cache", filepath.Base(it.Path))
+		}
+		got = append(got, filepath.ToSlash(rel)+" "+it.What+" "+formatByteSize(it.Size))
+	}
+	want := []string{
+		"go_sdk/go1.21.0.old-abc old copy of go1.21.0 8 B",
+		"go_sdk/go1.22.7 toolchain go1.22.7 5 B",
+		"go_sdk/gotip toolchain gotip 3 B",
+		"cache/go1.22.7.linux-amd64.tar.gz cached archive 7 B",
+		"cache archive cache 0 B",
+		"go_sdk SDK root 0 B",
+		"config/godl/config config file 12 B",
+		"config/godl/journal.jsonl install journal 3 B",
+		"config/godl/journal.jsonl.1 older install journal 6 B",
+		"cache/godl/releases.json cached release listing 2 B",
+		"gobin/go" + exe() + " go shim " + formatByteSize(int64(len(shimMarker))),
+		"config/godl config directory 0 B",
+		"cache/godl cache directory 0 B",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("PurgeItems:\n%q\nwant:\n%q", got, want)
+	}
+
+	if errs := Purge(items); len(errs) > 0 {
+		t.Fatalf("Purge: %v", errs)
+	}
+	// The file manager's litter keeps the SDK root, and the user's own
+	// file the cache directory.
+	for _, p := range []string{filepath.Join(root, ".DS_Store"), filepath.Join(cache, "notes.txt")} {
+		if _, err := os.Stat(p); err != nil {
+			t.Errorf("%s was removed", p)
+		}
+	}
+	for _, p := range []string{filepath.Join(root, "go1.22.7"), filepath.Join(root, "gotip"), filepath.Join(cache, "go1.22.7.linux-amd64.tar.gz"), filepath.Join(home, "config", "godl"), filepath.Join(home, "cache", "godl"), filepath.Join(home, "gobin", "go"+exe())} {
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Errorf("%s was not removed", p)
+		}
+	}
+}
+
+func TestPurgeUnknownEntries(t *testing.T) {
+	root := t.TempDir()
+	makeTree(t, root, map[string]string{
+		"go1.22.7/bin/go":   "",
+		"Documents/tax.pdf": "",
+	})
+	l := &Locator{Root: root}
+	_, err := l.PurgeItems("", false)
+	var uerr *UnknownEntriesError
+	if !errors.As(err, &uerr) || !reflect.DeepEqual(uerr.Entries, []string{"Documents"}) {
+		t.Fatalf("PurgeItems = %v; want an error about Documents", err)
+	}
+
+	items, err := l.PurgeItems("", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs := Purge(items); len(errs) > 0 {
+		t.Fatalf("Purge: %v", errs)
+	}
+	if _, err := os.Stat(filepath.Join(root, "Documents", "tax.pdf")); err != nil {
+		t.Errorf("forced purge removed an unknown entry: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "go1.22.7")); !os.IsNotExist(err) {
+		t.Errorf("forced purge left go1.22.7")
+	}
+}
+
+func TestFormatByteSize(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1536, "1.5 KiB"},
+		{68951015, "65.8 MiB"},
+		{5 << 30, "5.0 GiB"},
+		{3 << 40, "3072.0 GiB"},
+	}
+	for _, tt := range tests {
+		if got := formatByteSize(tt.n); got != tt.want {
+			t.Errorf("formatByteSize(%d) = %q; want %q", tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+)
+
+// quarantineAttr is the extended attribute marking files from the
+// internet, which makes Gatekeeper refuse to run unsigned binaries.
+const quarantineAttr = "com.apple.quarantine"
+
+// xattrNoFollow is XATTR_NOFOLLOW: act on symbolic links themselves.
+const xattrNoFollow = 0x0001
+
+// clearQuarantine removes the quarantine attribute from every file under
+// root, as archives that passed through a browser pass it on to what is
+// extracted from them. Failures are logged rather than returned.
+func clearQuarantine(root string) {
+	var firstErr error
+	_ = filepath.Walk(root, func(path string, fi os.FileInfo, err error) error {
+		if err == nil {
+			err = removeXattr(path, quarantineAttr)
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		return nil
+	})
+	if firstErr != nil {
+		log.Printf("Warning: could not clear the %s attribute under %s: %v; macOS may refuse to run the go command", quarantineAttr, root, firstErr)
+	}
+}
+
+// checkQuarantine clears the quarantine attribute from the go binary of the
+// toolchain in root, if it has one.
+func checkQuarantine(root string) {
+	gobin := filepath.Join(root, "bin", "go")
+	if err := removeXattr(gobin, quarantineAttr); err != nil && !os.IsNotExist(err) {
+		log.Printf("Warning: %s is quarantined and could not be cleared: %v; run 'xattr -dr %s %s'", gobin, err, quarantineAttr, root)
+	}
+}
+
+// removeXattr removes the named extended attribute of path, returning nil
+// if it had none.
+func removeXattr(path, attr string) error {
+	p, err := syscall.BytePtrFromString(path)
+	if err != nil {
+		return err
+	}
+	a, err := syscall.BytePtrFromString(attr)
+	if err != nil {
+		return err
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_REMOVEXATTR,
+		uintptr(unsafe.Pointer(p)), uintptr(unsafe.Pointer(a)), xattrNoFollow)
+	if errno != 0 && errno != syscall.ENOATTR {
+		return &os.PathError{Op: "removexattr", Path: path, Err: errno}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"unsafe"
+)
+
+func setXattr(t *testing.T, path, attr, value string) {
+	t.Helper()
+	p, _ := syscall.BytePtrFromString(path)
+	a, _ := syscall.BytePtrFromString(attr)
+	v := []byte(value)
+	_, _, errno := syscall.Syscall6(syscall.SYS_SETXATTR,
+		uintptr(unsafe.Pointer(p)), uintptr(unsafe.Pointer(a)),
+		uintptr(unsafe.Pointer(&v[0])), uintptr(len(v)), 0, xattrNoFollow)
+	if errno != 0 {
+		t.Skipf("setxattr: %v", errno)
+	}
+}
+
+func hasXattr(path, attr string) bool {
+	p, _ := syscall.BytePtrFromString(path)
+	a, _ := syscall.BytePtrFromString(attr)
+	n, _, errno := syscall.Syscall6(syscall.SYS_GETXATTR,
+		uintptr(unsafe.Pointer(p)), uintptr(unsafe.Pointer(a)), 0, 0, 0, xattrNoFollow)
+	return errno == 0 && n > 0
+}
+
+func TestClearQuarantine(t *testing.T) {
+	root := t.TempDir()
+	gobin := filepath.Join(root, "bin", "go")
+	if err := os.MkdirAll(filepath.Dir(gobin), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(gobin, []byte("go"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	setXattr(t, gobin, quarantineAttr, "0081;00000000;Safari;")
+	setXattr(t, filepath.Dir(gobin), quarantineAttr, "0081;00000000;Safari;")
+
+	clearQuarantine(root)
+	for _, p := range []string{gobin, filepath.Dir(gobin)} {
+		if hasXattr(p, quarantineAttr) {
+			t.Errorf("%s still quarantined", p)
+		}
+	}
+
+	// Clearing an unquarantined tree is a silent no-op.
+	if err := removeXattr(gobin, quarantineAttr); err != nil {
+		t.Errorf("removeXattr of missing attribute: %v", err)
+	}
+}
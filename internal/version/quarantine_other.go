@@ -0,0 +1,16 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !darwin
+// +build !darwin
+
+package version
+
+// clearQuarantine removes the macOS quarantine attribute from files under
+// root. Other systems have no such attribute.
+func clearQuarantine(root string) {}
+
+// checkQuarantine clears the macOS quarantine attribute from the go binary
+// of the toolchain in root.
+func checkQuarantine(root string) {}
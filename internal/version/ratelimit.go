@@ -0,0 +1,144 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A rateLimitedReader reads from r at no more than rate bytes per second,
+// averaged since its first read.
+type rateLimitedReader struct {
+	ctx   context.Context
+	r     io.Reader
+	rate  int64
+	start time.Time
+	n     int64
+}
+
+func newRateLimitedReader(ctx context.Context, r io.Reader, rate int64) *rateLimitedReader {
+	return &rateLimitedReader{ctx: ctx, r: r, rate: rate}
+}
+
+func (l *rateLimitedReader) Read(p []byte) (int, error) {
+	if l.start.IsZero() {
+		l.start = time.Now()
+	}
+	// Keep bursts to about a second's worth of data.
+	if int64(len(p)) > l.rate {
+		p = p[:l.rate]
+	}
+	n, err := l.r.Read(p)
+	l.n += int64(n)
+	due := time.Duration(float64(l.n) / float64(l.rate) * float64(time.Second))
+	if wait := due - time.Since(l.start); wait > 0 {
+		t := time.NewTimer(wait)
+		defer t.Stop()
+		select {
+		case <-t.C:
+		case <-l.ctx.Done():
+			return n, l.ctx.Err()
+		}
+	}
+	return n, err
+}
+
+// startThrottleProxy starts a proxy on the loopback interface for git,
+// which has no rate limit of its own. It tunnels CONNECT requests, reading
+// from the server no faster than rate bytes per second on each. It
+// returns the proxy's URL and a function that stops it.
+func startThrottleProxy(rate int64) (string, func(), error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			http.Error(w, "only CONNECT is supported", http.StatusMethodNotAllowed)
+			return
+		}
+		server, err := net.DialTimeout("tcp", r.Host, defaultConnectTimeout)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer server.Close()
+		hj, ok := w.(http.Hijacker)
+		if !ok {
+			http.Error(w, "can't tunnel", http.StatusInternalServerError)
+			return
+		}
+		client, buf, err := hj.Hijack()
+		if err != nil {
+			return
+		}
+		defer client.Close()
+		if _, err := io.WriteString(client, "HTTP/1.1 200 Connection Established\r\n\r\n"); err != nil {
+			return
+		}
+		go func() {
+			_, _ = io.Copy(server, buf.Reader)
+			if tc, ok := server.(*net.TCPConn); ok {
+				_ = tc.CloseWrite()
+			}
+		}()
+		_, _ = io.Copy(client, newRateLimitedReader(ctx, server, rate))
+	})}
+	go func() {
+		_ = srv.Serve(l)
+	}()
+	stop := func() {
+		cancel()
+		_ = srv.Close()
+	}
+	return "http://" + l.Addr().String(), stop, nil
+}
+
+// parseByteSize parses a byte count such as "1500", "500K", "2MiB" or
+// "1G". Units are powers of 1024 whether or not they are spelled with
+// the "i".
+func parseByteSize(s string) (int64, error) {
+	num := strings.TrimRight(s, "KMGiBkmgb")
+	unit := strings.ToUpper(s[len(num):])
+	mult := int64(1)
+	switch strings.TrimSuffix(strings.TrimSuffix(unit, "B"), "I") {
+	case "":
+	case "K":
+		mult = 1 << 10
+	case "M":
+		mult = 1 << 20
+	case "G":
+		mult = 1 << 30
+	default:
+		return 0, fmt.Errorf("invalid size %q: unknown unit %q", s, s[len(num):])
+	}
+	n, err := strconv.ParseInt(num, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return n * mult, nil
+}
+
+// formatByteSize formats a byte count for people, such as "1.5 MiB".
+func formatByteSize(n int64) string {
+	const unit = 1 << 10
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit && exp < 2; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMG"[exp])
+}
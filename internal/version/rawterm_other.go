@@ -0,0 +1,18 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !darwin && !freebsd && !linux
+
+package version
+
+import (
+	"errors"
+	"os"
+)
+
+// makeRaw would put the terminal in raw mode for the picker, which isn't
+// supported on this system.
+func makeRaw(f *os.File) (restore func(), err error) {
+	return nil, errors.New("raw terminal mode is not supported on this system")
+}
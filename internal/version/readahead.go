@@ -0,0 +1,127 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"compress/gzip"
+	"io"
+)
+
+const (
+	// readaheadBlock is the size of the blocks that a gzipReadahead
+	// decompresses into.
+	readaheadBlock = 1 << 20
+
+	// readaheadBlocks is how many blocks a gzipReadahead decompresses
+	// ahead of its reader.
+	readaheadBlocks = 4
+)
+
+// A gzipReadahead decompresses a gzip stream on a goroutine of its own,
+// ahead of what is read from it, as github.com/klauspost/pgzip does, so
+// that unpacking a tar.gz archive keeps one core decompressing while
+// another parses the tar stream and writes out its files. DEFLATE can't be
+// decoded from the middle, so this is as parallel as decompressing one
+// stream gets.
+type gzipReadahead struct {
+	blocks chan []byte // decompressed, in order
+	free   chan []byte // for the decompressor to fill
+	stop   chan struct{}
+	exited chan struct{}
+	block  []byte // being read
+	cur    []byte // the rest of block
+	err    error  // the decompressor's, once blocks is closed
+	closed bool
+}
+
+// newGzipReadahead returns a gzipReadahead decompressing r. The gzip
+// header is read before it returns, so that r not being gzip data is an
+// error here, as it is from gzip.NewReader. The reader must be closed,
+// which waits for the decompressor to stop reading r.
+func newGzipReadahead(r io.Reader) (*gzipReadahead, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	g := &gzipReadahead{
+		blocks: make(chan []byte, readaheadBlocks),
+		free:   make(chan []byte, readaheadBlocks),
+		stop:   make(chan struct{}),
+		exited: make(chan struct{}),
+	}
+	for i := 0; i < readaheadBlocks; i++ {
+		g.free <- make([]byte, readaheadBlock)
+	}
+	go g.decompress(zr)
+	return g, nil
+}
+
+func (g *gzipReadahead) decompress(zr *gzip.Reader) {
+	defer close(g.exited)
+	defer close(g.blocks)
+	for {
+		var buf []byte
+		select {
+		case buf = <-g.free:
+		case <-g.stop:
+			return
+		}
+		n, err := fillBlock(zr, buf)
+		if n > 0 {
+			select {
+			case g.blocks <- buf[:n]:
+			case <-g.stop:
+				return
+			}
+		}
+		if err != nil {
+			g.err = err
+			return
+		}
+	}
+}
+
+// fillBlock reads from r into buf until it is full or r fails, returning
+// r's error unchanged, unlike io.ReadFull, so that a truncated stream's
+// io.ErrUnexpectedEOF isn't mistaken for its end.
+func fillBlock(r io.Reader, buf []byte) (int, error) {
+	n := 0
+	for n < len(buf) {
+		m, err := r.Read(buf[n:])
+		n += m
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+func (g *gzipReadahead) Read(p []byte) (int, error) {
+	if len(g.cur) == 0 {
+		if g.block != nil {
+			g.free <- g.block[:cap(g.block)]
+			g.block = nil
+		}
+		buf, ok := <-g.blocks
+		if !ok {
+			return 0, g.err
+		}
+		g.block, g.cur = buf, buf
+	}
+	n := copy(p, g.cur)
+	g.cur = g.cur[n:]
+	return n, nil
+}
+
+// Close stops the decompressor, and waits for it to stop reading the
+// compressed stream.
+func (g *gzipReadahead) Close() error {
+	if !g.closed {
+		g.closed = true
+		close(g.stop)
+	}
+	<-g.exited
+	return nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+func gzipped(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestGzipReadahead(t *testing.T) {
+	// Several blocks' worth, read a little at a time.
+	data := make([]byte, readaheadBlocks*readaheadBlock*2+12345)
+	rand.New(rand.NewSource(1)).Read(data)
+	g, err := newGzipReadahead(bytes.NewReader(gzipped(t, data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ioutil.ReadAll(iotest.OneByteReader(io.LimitReader(g, 1000)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rest, err := ioutil.ReadAll(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got = append(got, rest...); !bytes.Equal(got, data) {
+		t.Errorf("read %d bytes that don't match the %d compressed", len(got), len(data))
+	}
+	if err := g.Close(); err != nil {
+		t.Error(err)
+	}
+
+	// A truncated stream is an error, not the end of the data.
+	z := gzipped(t, data)
+	g, err = newGzipReadahead(bytes.NewReader(z[:len(z)/2]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ioutil.ReadAll(g); err != io.ErrUnexpectedEOF {
+		t.Errorf("reading a truncated stream = %v; want %v", err, io.ErrUnexpectedEOF)
+	}
+	_ = g.Close()
+
+	// Closing early stops the decompressor reading.
+	r := &countingReader{r: bytes.NewReader(z)}
+	g, err = newGzipReadahead(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Close(); err != nil {
+		t.Error(err)
+	}
+	if n := r.count(); n == int64(len(z)) {
+		t.Errorf("decompressor read all %d bytes after Close", n)
+	}
+
+	if _, err := newGzipReadahead(strings.NewReader("not gzip")); err == nil {
+		t.Errorf("newGzipReadahead of a non-gzip stream succeeded")
+	}
+}
+
+func TestWritePool(t *testing.T) {
+	dir := t.TempDir()
+	p := newWritePool()
+	big := strings.Repeat("x", pooledFileMax+1)
+	mtime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	for i, w := range []struct{ name, body string }{
+		{"a", "first"},
+		{"b", "b"},
+		{"a", "second"}, // a later entry wins
+		{"c", "small"},
+		{"c", big}, // even written as it is read
+	} {
+		if _, err := p.write(filepath.Join(dir, w.name), 0644, strings.NewReader(w.body), int64(len(w.body)), mtime); err != nil {
+			t.Fatalf("write %d: %v", i, err)
+		}
+	}
+	if err := p.wait(); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{"a": "second", "b": "b", "c": big} {
+		b, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil || string(b) != want {
+			t.Errorf("%s = %.20q, %v; want %.20q", name, b, err, want)
+		}
+		if fi, err := os.Stat(filepath.Join(dir, name)); err != nil || !fi.ModTime().Equal(mtime) {
+			t.Errorf("%s modified at %v, %v; want %v", name, fi.ModTime(), err, mtime)
+		}
+	}
+
+	// A failed write is reported by a later one, or by wait.
+	p = newWritePool()
+	if _, err := p.write(filepath.Join(dir, "missing", "a"), 0644, strings.NewReader("a"), 1, time.Time{}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := p.wait(); err == nil {
+		t.Errorf("wait after writing into a missing directory succeeded")
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// asideSuffix marks toolchain directories moved aside by removeInstall.
+const asideSuffix = ".old-"
+
+// removeInstall removes the toolchain directory dir, if it exists.
+//
+// The directory is first renamed aside and then deleted, so that it is
+// never left half deleted. On Windows, deleting fails for files in use by
+// a running program, such as a go.exe or gopls, but renaming their
+// directory often still works; the remnant is then left for
+// removeAsideDirs to delete later. If dir can't even be renamed, the error
+// names the programs using it, when Windows can tell.
+func removeInstall(dir string) error {
+	if _, err := os.Lstat(dir); os.IsNotExist(err) {
+		return nil
+	}
+	aside := dir + asideSuffix + strconv.FormatInt(time.Now().UnixNano(), 36)
+	if err := os.Rename(dir, aside); err != nil {
+		if isInUse(err) {
+			if procs := lockingProcesses(dir); len(procs) > 0 {
+				return fmt.Errorf("can't replace %s while it is in use by %s", dir, strings.Join(procs, ", "))
+			}
+			return fmt.Errorf("can't replace %s while it is in use: %v", dir, err)
+		}
+		return err
+	}
+	if err := os.RemoveAll(aside); err != nil {
+		log.Printf("Note: could not remove the old %s yet (%v); it will be removed by a later install", aside, err)
+	}
+	return nil
+}
+
+// removeAsideDirs deletes what remains of directories that removeInstall
+// moved aside from dir, ignoring any that are still in use.
+func removeAsideDirs(dir string) {
+	fis, err := ioutil.ReadDir(filepath.Dir(dir))
+	if err != nil {
+		return
+	}
+	prefix := filepath.Base(dir) + asideSuffix
+	for _, fi := range fis {
+		if fi.IsDir() && strings.HasPrefix(fi.Name(), prefix) {
+			_ = os.RemoveAll(filepath.Join(filepath.Dir(dir), fi.Name()))
+		}
+	}
+}
+
+// removeUnpacked removes what an interrupted unpack wrote into the
+// toolchain directory dir, so that no half-written GOROOT is left behind.
+// The archive being unpacked, and the files kept beside it, such as its
+// checksum, are left if they are in dir, to be unpacked again without
+// downloading it again; dir itself is removed if nothing is left.
+func removeUnpacked(dir, archive string) error {
+	fis, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, fi := range fis {
+		name := filepath.Join(dir, fi.Name())
+		if archive != "" && filepath.Dir(archive) == filepath.Clean(dir) && strings.HasPrefix(fi.Name(), filepath.Base(archive)) {
+			continue
+		}
+		if err := os.RemoveAll(name); err != nil {
+			return err
+		}
+	}
+	_ = os.Remove(dir)
+	return nil
+}
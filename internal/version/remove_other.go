@@ -0,0 +1,19 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !windows
+// +build !windows
+
+package version
+
+// isInUse reports whether err is from removing or renaming a file that a
+// running program holds open. Only Windows prevents that.
+func isInUse(err error) bool {
+	return false
+}
+
+// lockingProcesses returns the programs holding files under dir open.
+func lockingProcesses(dir string) []string {
+	return nil
+}
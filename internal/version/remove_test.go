@@ -0,0 +1,90 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRemoveInstall(t *testing.T) {
+	sdk := t.TempDir()
+	dir := filepath.Join(sdk, "go1.99")
+	if err := os.MkdirAll(filepath.Join(dir, "bin"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "bin", "go"), []byte("go"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// A remnant of an earlier replacement, and an unrelated neighbor.
+	for _, d := range []string{"go1.99" + asideSuffix + "1", "go1.99.1"} {
+		if err := os.Mkdir(filepath.Join(sdk, d), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := removeInstall(dir); err != nil {
+		t.Fatalf("removeInstall: %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("%s still exists: %v", dir, err)
+	}
+	if err := removeInstall(dir); err != nil {
+		t.Errorf("removeInstall of missing directory: %v", err)
+	}
+
+	removeAsideDirs(dir)
+	fis, err := ioutil.ReadDir(sdk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fis) != 1 || fis[0].Name() != "go1.99.1" {
+		var names []string
+		for _, fi := range fis {
+			names = append(names, fi.Name())
+		}
+		t.Errorf("SDK directory holds %q; want only go1.99.1", names)
+	}
+}
+
+func TestInterruptedUnpack(t *testing.T) {
+	ts := newTestServer(t)
+	d := ts.downloader(t, DownloaderOptions{})
+	root := filepath.Join(t.TempDir(), "go1.99")
+	archive := filepath.Join(root, "go1.99.linux-amd64.tar.gz")
+	writeTestFile(t, archive, ts.tar)
+	writeTestFile(t, filepath.Join(root, "bin", "go"), []byte("left by an earlier attempt"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := d.runStep(ctx, Step{Kind: StepUnpack, File: archive, Target: root})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("unpack once interrupted = %v; want context.Canceled", err)
+	}
+	// Only the archive is left, to be unpacked again.
+	fis, err := ioutil.ReadDir(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fis) != 1 || fis[0].Name() != filepath.Base(archive) {
+		var names []string
+		for _, fi := range fis {
+			names = append(names, fi.Name())
+		}
+		t.Errorf("interrupted unpack left %q; want only the archive", names)
+	}
+
+	// Without the archive in it, the directory goes too.
+	if err := removeUnpacked(root, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(root); !os.IsNotExist(err) {
+		t.Errorf("removeUnpacked left %s: %v", root, err)
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	errorSharingViolation syscall.Errno = 32
+	errorLockViolation    syscall.Errno = 33
+	errorMoreData         syscall.Errno = 234
+)
+
+// isInUse reports whether err is from removing or renaming a file that a
+// running program holds open.
+func isInUse(err error) bool {
+	return errors.Is(err, syscall.ERROR_ACCESS_DENIED) ||
+		errors.Is(err, errorSharingViolation) ||
+		errors.Is(err, errorLockViolation)
+}
+
+var (
+	modrstrtmgr             = syscall.NewLazyDLL("rstrtmgr.dll")
+	procRmStartSession      = modrstrtmgr.NewProc("RmStartSession")
+	procRmRegisterResources = modrstrtmgr.NewProc("RmRegisterResources")
+	procRmGetList           = modrstrtmgr.NewProc("RmGetList")
+	procRmEndSession        = modrstrtmgr.NewProc("RmEndSession")
+)
+
+// rmProcessInfo is RM_PROCESS_INFO.
+type rmProcessInfo struct {
+	ProcessID        uint32
+	ProcessStartTime syscall.Filetime
+	AppName          [256]uint16
+	ServiceShortName [64]uint16
+	ApplicationType  uint32
+	AppStatus        uint32
+	TSSessionID      uint32
+	Restartable      int32
+}
+
+// maxLockedFiles bounds how many files under a directory are checked.
+const maxLockedFiles = 500
+
+// lockingProcesses returns the programs holding the executables and
+// libraries under dir open, using the Restart Manager.
+func lockingProcesses(dir string) []string {
+	if procRmStartSession.Find() != nil {
+		return nil
+	}
+	var files []*uint16
+	_ = filepath.Walk(dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil || fi.IsDir() || len(files) >= maxLockedFiles {
+			return nil
+		}
+		if p, err := syscall.UTF16PtrFromString(path); err == nil {
+			files = append(files, p)
+		}
+		return nil
+	})
+	if len(files) == 0 {
+		return nil
+	}
+
+	var session uint32
+	var key [33]uint16 // CCH_RM_SESSION_KEY+1
+	if r, _, _ := procRmStartSession.Call(uintptr(unsafe.Pointer(&session)), 0, uintptr(unsafe.Pointer(&key[0]))); r != 0 {
+		return nil
+	}
+	defer procRmEndSession.Call(uintptr(session))
+	if r, _, _ := procRmRegisterResources.Call(uintptr(session),
+		uintptr(len(files)), uintptr(unsafe.Pointer(&files[0])),
+		0, 0, 0, 0); r != 0 {
+		return nil
+	}
+
+	var infos []rmProcessInfo
+	for {
+		var needed, n, reasons uint32
+		n = uint32(len(infos))
+		var p *rmProcessInfo
+		if n > 0 {
+			p = &infos[0]
+		}
+		r, _, _ := procRmGetList.Call(uintptr(session),
+			uintptr(unsafe.Pointer(&needed)), uintptr(unsafe.Pointer(&n)),
+			uintptr(unsafe.Pointer(p)), uintptr(unsafe.Pointer(&reasons)))
+		if syscall.Errno(r) == errorMoreData {
+			infos = make([]rmProcessInfo, needed)
+			continue
+		}
+		if r != 0 {
+			return nil
+		}
+		infos = infos[:n]
+		break
+	}
+	var procs []string
+	for _, info := range infos {
+		name := strings.TrimSpace(syscall.UTF16ToString(info.AppName[:]))
+		procs = append(procs, fmt.Sprintf("%s (pid %d)", name, info.ProcessID))
+	}
+	return procs
+}
@@ -0,0 +1,47 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRemoveInstallInUse(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "go1.99")
+	gobin := filepath.Join(dir, "bin", "go.exe")
+	if err := os.MkdirAll(filepath.Dir(gobin), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(gobin, []byte("go"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// Hold a file open, as a running go.exe would.
+	f, err := os.Open(gobin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = removeInstall(dir)
+	if err != nil && !strings.Contains(err.Error(), "in use") {
+		t.Errorf("removeInstall of directory in use = %v; want error saying it is in use", err)
+	}
+	if err != nil {
+		t.Logf("removeInstall: %v", err)
+		if _, serr := os.Stat(gobin); serr != nil {
+			t.Errorf("failed removeInstall left %s half deleted: %v", dir, serr)
+		}
+	}
+	f.Close()
+
+	if err := removeInstall(dir); err != nil {
+		t.Fatalf("removeInstall after closing: %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("%s still exists: %v", dir, err)
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Defaults of the retry policy: the commands retry, though the zero
+// DownloaderOptions don't.
+const (
+	defaultRetries      = 3
+	defaultRetryBackoff = time.Second
+	defaultRetryJitter  = 0.5
+)
+
+// maxRetryDelay bounds the wait before a retry, however many came before
+// it, and however long the server asks to be left alone.
+const maxRetryDelay = 30 * time.Second
+
+// A retryPolicy says how requests that fail for a reason that may pass
+// are retried: up to retries times, first after backoff, doubling each
+// time, with each delay shortened by up to the fraction jitter at random,
+// so that clients that failed together don't retry together.
+type retryPolicy struct {
+	retries int
+	backoff time.Duration
+	jitter  float64
+}
+
+// delay returns how long to wait before retry number attempt, counting
+// from 0, or after, if positive, which a server asked for.
+func (p retryPolicy) delay(attempt int, after time.Duration) time.Duration {
+	if after > 0 {
+		if after > maxRetryDelay {
+			return maxRetryDelay
+		}
+		return after
+	}
+	d := p.backoff
+	for i := 0; i < attempt && d < maxRetryDelay; i++ {
+		d *= 2
+	}
+	if d > maxRetryDelay {
+		d = maxRetryDelay
+	}
+	return d - time.Duration(p.jitter*rand.Float64()*float64(d))
+}
+
+// A retryDoer retries the body-less requests made with d, which are all
+// the downloader makes, when they fail for a reason that may pass: a
+// network error or timeout, or a response saying that the server is
+// overloaded or down.
+type retryDoer struct {
+	d       Doer
+	policy  retryPolicy
+	metrics *Metrics
+}
+
+func (r *retryDoer) Do(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		res, err := r.d.Do(req)
+		var after time.Duration
+		var why string
+		switch {
+		case err != nil:
+			if !retryable(req.Context(), err) {
+				return nil, err
+			}
+			why = err.Error()
+		case retryableStatus(res.StatusCode):
+			after = retryAfter(res)
+			why = fmt.Sprintf("%s: %s", req.URL, res.Status)
+		default:
+			return res, nil
+		}
+		if attempt == r.policy.retries {
+			if err != nil {
+				return nil, &retriedError{err, attempt}
+			}
+			return res, nil
+		}
+		if res != nil {
+			_, _ = io.Copy(ioutil.Discard, io.LimitReader(res.Body, 4<<10))
+			_ = res.Body.Close()
+		}
+		delay := r.policy.delay(attempt, after)
+		log.Printf("Note: %s; retrying in %v (%d of %d)", why, delay.Round(100*time.Millisecond), attempt+1, r.policy.retries)
+		if err == nil {
+			err = errors.New(why)
+		}
+		r.metrics.retry(req.URL.String(), attempt+1, delay, err)
+		if err := sleepContext(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// A retriedError is the error of a request that failed every retry.
+type retriedError struct {
+	err      error
+	attempts int
+}
+
+func (e *retriedError) Error() string {
+	return fmt.Sprintf("%v (after %d retries)", e.err, e.attempts)
+}
+
+func (e *retriedError) Unwrap() error { return e.err }
+
+// retryable reports whether err, of a request made in ctx, may pass if
+// the request is repeated: it is a network error or timeout, or the
+// connection ended early, and ctx hasn't ended.
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var re *retriedError
+	if errors.As(err, &re) {
+		return false // already retried
+	}
+	var ne net.Error
+	var oe *net.OpError
+	return errors.As(err, &ne) && ne.Timeout() ||
+		errors.As(err, &oe) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
+
+// retryableStatus reports whether a response status says that the server
+// is overloaded or down for now.
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests,
+		http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter returns how long res asks the client to wait, in its
+// Retry-After header, or 0.
+func retryAfter(res *http.Response) time.Duration {
+	h := res.Header.Get("Retry-After")
+	if secs, err := strconv.Atoi(h); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(h); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
+
+// sleepContext waits for d, or until ctx ends.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryDoer(t *testing.T) {
+	var calls int32
+	var failures int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		switch {
+		case r.URL.Path == "/missing":
+			http.NotFound(w, r)
+		case n <= atomic.LoadInt32(&failures):
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+		default:
+			io.WriteString(w, "ok")
+		}
+	}))
+	defer srv.Close()
+
+	policy := retryPolicy{retries: 2, backoff: time.Millisecond}
+	get := func(path string) (*http.Response, error) {
+		req, err := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return (&retryDoer{srv.Client(), policy, nil}).Do(req)
+	}
+	for _, tt := range []struct {
+		path      string
+		failures  int32
+		wantCode  int
+		wantCalls int32
+	}{
+		{"/go1.99.tar.gz", 0, http.StatusOK, 1},
+		{"/go1.99.tar.gz", 2, http.StatusOK, 3},
+		{"/go1.99.tar.gz", 5, http.StatusServiceUnavailable, 3},
+		{"/missing", 5, http.StatusNotFound, 1},
+	} {
+		atomic.StoreInt32(&calls, 0)
+		atomic.StoreInt32(&failures, tt.failures)
+		res, err := get(tt.path)
+		if err != nil {
+			t.Fatalf("GET %s after %d failures: %v", tt.path, tt.failures, err)
+		}
+		res.Body.Close()
+		if res.StatusCode != tt.wantCode || calls != tt.wantCalls {
+			t.Errorf("GET %s after %d failures = %d in %d requests; want %d in %d", tt.path, tt.failures, res.StatusCode, calls, tt.wantCode, tt.wantCalls)
+		}
+	}
+
+	// Network errors are retried too, and reported once retries run out.
+	url := srv.URL
+	srv.Close()
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	_, err := (&retryDoer{http.DefaultClient, policy, nil}).Do(req)
+	var re *retriedError
+	if !errors.As(err, &re) || re.attempts != policy.retries {
+		t.Errorf("GET from a closed server = %v; want an error after %d retries", err, policy.retries)
+	}
+	if retryable(context.Background(), err) {
+		t.Errorf("retryable(%v) = true; want false, as it was retried", err)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	p := retryPolicy{retries: 10, backoff: time.Second, jitter: 0.5}
+	for attempt, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, maxRetryDelay, maxRetryDelay} {
+		for i := 0; i < 10; i++ {
+			if got := p.delay(attempt, 0); got > want || got < want/2 {
+				t.Errorf("delay(%d, 0) = %v; want from %v to %v", attempt, got, want/2, want)
+			}
+		}
+	}
+	if got := p.delay(0, 5*time.Second); got != 5*time.Second {
+		t.Errorf("delay(0, 5s) = %v; want the 5s the server asked for", got)
+	}
+	if got := p.delay(0, time.Hour); got != maxRetryDelay {
+		t.Errorf("delay(0, 1h) = %v; want at most %v", got, maxRetryDelay)
+	}
+}
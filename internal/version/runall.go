@@ -0,0 +1,124 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// A matrixResult is how the command of dl run-all ended under one
+// toolchain.
+type matrixResult struct {
+	Toolchain string
+	ExitCode  int   // -1 if it didn't start, or was killed
+	Err       error // why it didn't start, or was killed
+	Duration  time.Duration
+}
+
+// runMatrix runs cmds, the command of dl run-all under each of the
+// toolchains names, at most parallel at once, and returns how each ended,
+// in order. Each line the commands write goes to stdout or stderr as they
+// wrote it, labelled with its toolchain, so that their output can be told
+// apart when it interleaves.
+func runMatrix(names []string, cmds []*exec.Cmd, stdout, stderr io.Writer, parallel int) []matrixResult {
+	width := 0
+	for _, name := range names {
+		if len(name) > width {
+			width = len(name)
+		}
+	}
+	var mu sync.Mutex
+	results := make([]matrixResult, len(cmds))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, parallel)
+	for i, cmd := range cmds {
+		label := fmt.Sprintf("[%-*s] ", width, names[i])
+		out := &labelWriter{mu: &mu, w: stdout, label: label}
+		errOut := &labelWriter{mu: &mu, w: stderr, label: label}
+		cmd.Stdout, cmd.Stderr = out, errOut
+		wg.Add(1)
+		go func(r *matrixResult, cmd *exec.Cmd) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			start := time.Now()
+			err := cmd.Run()
+			r.Duration = time.Since(start)
+			out.flush()
+			errOut.flush()
+			var ee *exec.ExitError
+			switch {
+			case err == nil:
+			case errors.As(err, &ee) && ee.ExitCode() >= 0:
+				r.ExitCode = ee.ExitCode()
+			default:
+				r.ExitCode, r.Err = -1, err
+			}
+		}(&results[i], cmd)
+		results[i].Toolchain = names[i]
+	}
+	wg.Wait()
+	return results
+}
+
+// writeMatrixSummary writes results to w in the form dl run-all prints
+// them at the end, and returns how many failed.
+func writeMatrixSummary(w io.Writer, results []matrixResult) (failed int) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, r := range results {
+		status := "PASS"
+		switch {
+		case r.Err != nil:
+			status = "FAIL (" + r.Err.Error() + ")"
+		case r.ExitCode != 0:
+			status = fmt.Sprintf("FAIL (exit status %d)", r.ExitCode)
+		}
+		if status != "PASS" {
+			failed++
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", r.Toolchain, status, r.Duration.Round(100*time.Millisecond))
+	}
+	tw.Flush()
+	return failed
+}
+
+// A labelWriter writes each line written to it to w, prefixed with label.
+// labelWriters sharing mu write whole lines, so that theirs don't mix.
+type labelWriter struct {
+	mu    *sync.Mutex
+	w     io.Writer
+	label string
+	buf   []byte // the start of a line not yet written
+}
+
+func (lw *labelWriter) Write(p []byte) (int, error) {
+	lw.buf = append(lw.buf, p...)
+	for {
+		i := bytes.IndexByte(lw.buf, '\n')
+		if i < 0 {
+			break
+		}
+		lw.mu.Lock()
+		io.WriteString(lw.w, lw.label+strings.TrimSuffix(string(lw.buf[:i]), "\r")+"\n")
+		lw.mu.Unlock()
+		lw.buf = lw.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// flush writes the last line, if the command didn't end it.
+func (lw *labelWriter) flush() {
+	if len(lw.buf) > 0 {
+		lw.Write([]byte("\n"))
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"bytes"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestLabelWriter(t *testing.T) {
+	var out bytes.Buffer
+	lw := &labelWriter{mu: new(sync.Mutex), w: &out, label: "[go1.22.7] "}
+	lw.Write([]byte("ok  \texample.com/m\t0.0"))
+	lw.Write([]byte("12s\r\nFAIL\nno newline"))
+	lw.flush()
+	want := "[go1.22.7] ok  \texample.com/m\t0.012s\n[go1.22.7] FAIL\n[go1.22.7] no newline\n"
+	if out.String() != want {
+		t.Errorf("labelled output = %q; want %q", out.String(), want)
+	}
+}
+
+func TestRunMatrix(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+		t.Skip("runs sh")
+	}
+	names := []string{"go1.21.13", "go1.22.7", "gotip"}
+	cmds := []*exec.Cmd{
+		exec.Command("sh", "-c", "echo ok"),
+		exec.Command("sh", "-c", "echo broken >&2; exit 3"),
+		exec.Command("/nonexistent/go"),
+	}
+	var stdout, stderr bytes.Buffer
+	results := runMatrix(names, cmds, &stdout, &stderr, 2)
+	if stdout.String() != "[go1.21.13] ok\n" || stderr.String() != "[go1.22.7 ] broken\n" {
+		t.Errorf("output = %q, errors = %q; want each line labelled with its toolchain", stdout.String(), stderr.String())
+	}
+	for i, want := range []int{0, 3, -1} {
+		if r := results[i]; r.Toolchain != names[i] || r.ExitCode != want || (r.Err != nil) != (want < 0) {
+			t.Errorf("result %d = %+v; want %s to exit %d", i, r, names[i], want)
+		}
+	}
+
+	var summary bytes.Buffer
+	if failed := writeMatrixSummary(&summary, results); failed != 2 {
+		t.Errorf("writeMatrixSummary reports %d failures; want 2", failed)
+	}
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(summary.String()), "\n") {
+		f := strings.Fields(line)
+		lines = append(lines, f[0]+" "+f[1])
+	}
+	if want := "go1.21.13 PASS,go1.22.7 FAIL,gotip FAIL"; strings.Join(lines, ",") != want {
+		t.Errorf("summary:\n%s\nwant %s", summary.String(), want)
+	}
+}
@@ -0,0 +1,401 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// emptySHA256 is the SHA-256 of no bytes, the payload of every request
+// made to S3.
+const emptySHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// An s3Client makes requests for s3://bucket/key URLs, signed with AWS
+// Signature Version 4 if there are credentials, and unsigned, for public
+// buckets, if there are none.
+type s3Client struct {
+	d    Doer
+	meta Doer // for the container and instance metadata services
+
+	mu      sync.Mutex
+	creds   *awsCredentials // nil if there are none
+	looked  bool            // whether creds has been looked up
+	regions map[string]string
+}
+
+// awsCredentials are AWS access keys, which expire at Expires if set.
+type awsCredentials struct {
+	AccessKeyID     string    `json:"AccessKeyId"`
+	SecretAccessKey string    `json:"SecretAccessKey"`
+	SessionToken    string    `json:"Token"`
+	Expires         time.Time `json:"Expiration"`
+}
+
+func (s *s3Client) do(req *http.Request) (*http.Response, error) {
+	creds, err := s.credentials(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("finding AWS credentials for %s: %w", req.URL, err)
+	}
+	bucket := req.URL.Host
+	region := s.region(bucket)
+	res, err := s.send(req, creds, bucket, region)
+	if err != nil {
+		return nil, err
+	}
+	// A bucket in another region than the one assumed says which one.
+	if r := res.Header.Get("X-Amz-Bucket-Region"); r != "" && r != region && res.StatusCode != http.StatusOK && res.StatusCode != http.StatusPartialContent {
+		_ = res.Body.Close()
+		s.mu.Lock()
+		s.regions[bucket] = r
+		s.mu.Unlock()
+		return s.send(req, creds, bucket, r)
+	}
+	return res, nil
+}
+
+// send makes req for the object in bucket, in region, with creds.
+func (s *s3Client) send(req *http.Request, creds *awsCredentials, bucket, region string) (*http.Response, error) {
+	r, err := objectRequest(req, s3URL(bucket, req.URL.Path, region))
+	if err != nil {
+		return nil, err
+	}
+	if creds != nil {
+		signV4(r, creds, region, "s3", time.Now())
+	}
+	return s.d.Do(r)
+}
+
+// s3URL returns the https URL of key, which starts with a slash, in
+// bucket in region: the virtual-hosted one, or the path-style one at the
+// endpoint that AWS_ENDPOINT_URL_S3 or AWS_ENDPOINT_URL name, as for an
+// S3-compatible store, or for a bucket with dots in its name, which
+// doesn't match the certificate of S3's virtual hosts.
+func s3URL(bucket, key, region string) string {
+	key = (&url.URL{Path: key}).EscapedPath()
+	for _, env := range []string{"AWS_ENDPOINT_URL_S3", "AWS_ENDPOINT_URL"} {
+		if e := os.Getenv(env); e != "" {
+			return strings.TrimSuffix(e, "/") + "/" + bucket + key
+		}
+	}
+	if strings.Contains(bucket, ".") {
+		return "https://s3." + region + ".amazonaws.com/" + bucket + key
+	}
+	return "https://" + bucket + ".s3." + region + ".amazonaws.com" + key
+}
+
+// region returns the region bucket is in: the one it said it is in, or
+// else the one configured as the AWS command line tools find it, or else
+// us-east-1.
+func (s *s3Client) region(bucket string) string {
+	s.mu.Lock()
+	r := s.regions[bucket]
+	s.mu.Unlock()
+	if r != "" {
+		return r
+	}
+	for _, env := range []string{"AWS_REGION", "AWS_DEFAULT_REGION"} {
+		if r := os.Getenv(env); r != "" {
+			return r
+		}
+	}
+	if r := awsProfileValue(awsConfigFile(), "profile "+awsProfile(), "region"); r != "" {
+		return r
+	}
+	return "us-east-1"
+}
+
+// credentials returns the AWS credentials to sign requests with, looked up
+// as the AWS SDKs do, though without single sign-on or web identities:
+// from the environment, the shared credentials file, the container
+// credentials endpoint, or the EC2 instance metadata service. It returns
+// nil if there are none anywhere.
+func (s *s3Client) credentials(ctx context.Context) (*awsCredentials, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.looked && (s.creds == nil || s.creds.Expires.IsZero() || time.Until(s.creds.Expires) > 5*time.Minute) {
+		return s.creds, nil
+	}
+	creds, err := lookupAWSCredentials(ctx, s.meta)
+	if err != nil {
+		return nil, err
+	}
+	s.creds, s.looked = creds, true
+	return creds, nil
+}
+
+func lookupAWSCredentials(ctx context.Context, meta Doer) (*awsCredentials, error) {
+	if id, secret := os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY"); id != "" && secret != "" {
+		return &awsCredentials{AccessKeyID: id, SecretAccessKey: secret, SessionToken: os.Getenv("AWS_SESSION_TOKEN")}, nil
+	}
+	file, profile := awsCredentialsFile(), awsProfile()
+	if id := awsProfileValue(file, profile, "aws_access_key_id"); id != "" {
+		return &awsCredentials{
+			AccessKeyID:     id,
+			SecretAccessKey: awsProfileValue(file, profile, "aws_secret_access_key"),
+			SessionToken:    awsProfileValue(file, profile, "aws_session_token"),
+		}, nil
+	}
+	if u, token := containerCredentialsURL(); u != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		if token != "" {
+			req.Header.Set("Authorization", token)
+		}
+		var creds awsCredentials
+		if err := fetchJSON(meta, req, &creds); err != nil {
+			return nil, fmt.Errorf("container credentials: %w", err)
+		}
+		return &creds, nil
+	}
+	if strings.EqualFold(os.Getenv("AWS_EC2_METADATA_DISABLED"), "true") {
+		return nil, nil
+	}
+	return instanceCredentials(ctx, meta)
+}
+
+// containerCredentialsURL returns the URL that ECS or EKS serves the
+// container's credentials at, and the authorization to send with it, or
+// "" outside such a container.
+func containerCredentialsURL() (u, token string) {
+	token = os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN")
+	if file := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE"); file != "" {
+		if data, err := ioutil.ReadFile(file); err == nil {
+			token = strings.TrimSpace(string(data))
+		}
+	}
+	if rel := os.Getenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"); rel != "" {
+		return "http://169.254.170.2" + rel, token
+	}
+	return os.Getenv("AWS_CONTAINER_CREDENTIALS_FULL_URI"), token
+}
+
+// instanceCredentials returns the credentials of the role of the EC2
+// instance, from its metadata service, with IMDSv2, or nil if there is no
+// metadata service or role.
+func instanceCredentials(ctx context.Context, meta Doer) (*awsCredentials, error) {
+	endpoint := strings.TrimSuffix(os.Getenv("AWS_EC2_METADATA_SERVICE_ENDPOINT"), "/")
+	if endpoint == "" {
+		endpoint = "http://169.254.169.254"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint+"/latest/api/token", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Aws-Ec2-Metadata-Token-Ttl-Seconds", "300")
+	res, err := meta.Do(req)
+	if err != nil {
+		// Not on EC2.
+		return nil, nil
+	}
+	token, err := ioutil.ReadAll(res.Body)
+	_ = res.Body.Close()
+	if err != nil || res.StatusCode != http.StatusOK {
+		return nil, nil
+	}
+	get := func(path string) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"/latest/meta-data/iam/security-credentials/"+path, nil)
+		if err == nil {
+			req.Header.Set("X-Aws-Ec2-Metadata-Token", string(token))
+		}
+		return req, err
+	}
+	req, err = get("")
+	if err != nil {
+		return nil, err
+	}
+	res, err = meta.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("instance metadata: %w", err)
+	}
+	roles, err := ioutil.ReadAll(res.Body)
+	_ = res.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("instance metadata: %w", err)
+	}
+	role := strings.TrimSpace(strings.SplitN(string(roles), "\n", 2)[0])
+	if res.StatusCode == http.StatusNotFound || role == "" {
+		// An instance without a role.
+		return nil, nil
+	}
+	if req, err = get(role); err != nil {
+		return nil, err
+	}
+	var creds awsCredentials
+	if err := fetchJSON(meta, req, &creds); err != nil {
+		return nil, fmt.Errorf("instance metadata: %w", err)
+	}
+	return &creds, nil
+}
+
+// awsProfile returns the AWS profile in use.
+func awsProfile() string {
+	if p := os.Getenv("AWS_PROFILE"); p != "" {
+		return p
+	}
+	return "default"
+}
+
+// awsCredentialsFile returns the AWS shared credentials file.
+func awsCredentialsFile() string {
+	if f := os.Getenv("AWS_SHARED_CREDENTIALS_FILE"); f != "" {
+		return f
+	}
+	return awsHomeFile("credentials")
+}
+
+// awsConfigFile returns the AWS config file.
+func awsConfigFile() string {
+	if f := os.Getenv("AWS_CONFIG_FILE"); f != "" {
+		return f
+	}
+	return awsHomeFile("config")
+}
+
+func awsHomeFile(name string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".aws", name)
+}
+
+// awsProfileValue returns the value of key in section of the INI file,
+// or "" if it has none. The default profile's section is named default
+// in the config file too.
+func awsProfileValue(file, section, key string) string {
+	if file == "" {
+		return ""
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return ""
+	}
+	if section == "profile default" {
+		section = "default"
+	}
+	in := false
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			in = strings.TrimSpace(line[1:len(line)-1]) == section
+			continue
+		}
+		if !in {
+			continue
+		}
+		if i := strings.Index(line, "="); i > 0 && strings.TrimSpace(line[:i]) == key {
+			return strings.TrimSpace(line[i+1:])
+		}
+	}
+	return ""
+}
+
+// signV4 signs req, a request without a body, for service in region with
+// creds, as of now, with AWS Signature Version 4. The host, the range and
+// the x-amz- headers are signed.
+func signV4(req *http.Request, creds *awsCredentials, region, service string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", emptySHA256)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		k = strings.ToLower(k)
+		if k == "range" || strings.HasPrefix(k, "x-amz-") {
+			headers[k] = strings.TrimSpace(strings.Join(v, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	var segments []string
+	for _, seg := range strings.Split(req.URL.Path, "/") {
+		segments = append(segments, awsURIEncode(seg))
+	}
+	canonicalURI := strings.Join(segments, "/")
+	if canonicalURI == "" {
+		canonicalURI = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI,
+		awsCanonicalQuery(req),
+		canonicalHeaders.String(),
+		signedHeaders,
+		emptySHA256,
+	}, "\n")
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	sum := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(sum[:])
+	key := []byte("AWS4" + creds.SecretAccessKey)
+	for _, part := range []string{date, region, service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		creds.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// awsCanonicalQuery returns the query of req as Signature Version 4
+// canonicalizes it: encoded, and sorted by name and then value.
+func awsCanonicalQuery(req *http.Request) string {
+	var params []string
+	for k, vs := range req.URL.Query() {
+		for _, v := range vs {
+			params = append(params, awsURIEncode(k)+"="+awsURIEncode(v))
+		}
+	}
+	sort.Slice(params, func(i, j int) bool { return params[i] < params[j] })
+	return strings.Join(params, "&")
+}
+
+// awsURIEncode percent-encodes every byte of s but the unreserved
+// characters, as Signature Version 4 requires.
+func awsURIEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '.' || c == '_' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,75 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ensureSDKRoot checks, before an install, that toolchains can be put in
+// the configured SDK directory, and makes it that of defaultLocator, for
+// goroot. If they can't, it asks for another
+// directory, or fails, without prompts, suggesting -sdk-dir and
+// GODL_SDK_DIR. A directory given either way instead of an SDK directory
+// that can't be written to is saved as sdk_dir in the config file, so that
+// later installs and the run path use it too; the run path also still
+// finds the toolchains installed before.
+func ensureSDKRoot(cfg *Config) error {
+	defaultLocator = cfg.Locator()
+	root, err := defaultLocator.SDKRoot()
+	if err != nil {
+		return err
+	}
+	werr := checkCreatable(root)
+	if cfg.values["sdk_dir"].source == SourceFlag {
+		if werr != nil {
+			return fmt.Errorf("-sdk-dir %s: %v", root, werr)
+		}
+		prev, err := (&Locator{Root: cfg.flagged["sdk_dir"].value}).SDKRoot()
+		if err == nil && prev != root && checkCreatable(prev) != nil {
+			return rememberSDKDir(cfg, root)
+		}
+		return nil
+	}
+	if werr == nil {
+		return nil
+	}
+	dir, err := ask(fmt.Sprintf("Can't install toolchains in %s: %v.\nInstall them in which directory instead? ", root, werr), "-sdk-dir")
+	if err != nil {
+		return fmt.Errorf("can't install toolchains in %s: %v; choose another directory with -sdk-dir, which is remembered, or with %s", root, werr, envSDKDir)
+	}
+	if dir == "" {
+		return fmt.Errorf("can't install toolchains in %s, and no other directory was given", root)
+	}
+	if strings.HasPrefix(dir, "~"+string(filepath.Separator)) || strings.HasPrefix(dir, "~/") {
+		if home, err := homedir(); err == nil {
+			dir = filepath.Join(home, dir[2:])
+		}
+	}
+	if dir, err = filepath.Abs(dir); err != nil {
+		return err
+	}
+	if err := checkCreatable(dir); err != nil {
+		return fmt.Errorf("%s: %v", dir, err)
+	}
+	defaultLocator = &Locator{Root: dir}
+	return rememberSDKDir(cfg, dir)
+}
+
+// rememberSDKDir saves dir as sdk_dir in the config file.
+func rememberSDKDir(cfg *Config, dir string) error {
+	if err := cfg.save("sdk_dir", dir); err != nil {
+		return fmt.Errorf("saving sdk_dir in the config file: %v", err)
+	}
+	log.Printf("Note: toolchains are now installed in %s, as sdk_dir in %s says.", dir, cfg.File)
+	if os.Getenv(envSDKDir) != "" {
+		log.Printf("Note: %s is set, and takes precedence over the config file.", envSDKDir)
+	}
+	return nil
+}
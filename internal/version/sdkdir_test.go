@@ -0,0 +1,137 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestConfigSave(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config")
+	if err := ioutil.WriteFile(file, []byte("# mirror\nbase_url = \"https://mirror.example.com/go/\"\nsdk_dir = '/old'\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := LoadConfig(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(t.TempDir(), `odd "dir"`)
+	if err := c.save("sdk_dir", dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.save("offline", "true"); err != nil {
+		t.Fatal(err)
+	}
+	c, err = LoadConfig(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Locator().Root; got != dir {
+		t.Errorf("after save, sdk_dir = %q; want %q", got, dir)
+	}
+	data, _ := ioutil.ReadFile(file)
+	if !strings.HasPrefix(string(data), "# mirror\nbase_url") || strings.Count(string(data), "sdk_dir") != 1 || !strings.HasSuffix(string(data), "offline = \"true\"\n") {
+		t.Errorf("after save, config file is\n%s", data)
+	}
+}
+
+// sdkDirConfig returns a Config whose config file sets sdk_dir to dir and,
+// if flagDir is non-empty, whose -sdk-dir flag is flagDir.
+func sdkDirConfig(t *testing.T, dir, flagDir string) *Config {
+	t.Setenv(envSDKDir, "")
+	file := filepath.Join(t.TempDir(), "config")
+	if err := ioutil.WriteFile(file, []byte("sdk_dir = "+`"`+filepath.ToSlash(dir)+`"`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := LoadConfig(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if flagDir != "" {
+		flags := flag.NewFlagSet("test", flag.ContinueOnError)
+		flags.String("sdk-dir", "", "")
+		if err := flags.Parse([]string{"-sdk-dir", flagDir}); err != nil {
+			t.Fatal(err)
+		}
+		c.setFlags(flags)
+	}
+	old := defaultLocator
+	t.Cleanup(func() { defaultLocator = old })
+	return c
+}
+
+func TestEnsureSDKRoot(t *testing.T) {
+	// A path through a file can't be created, even by root.
+	file := filepath.Join(t.TempDir(), "file")
+	if err := ioutil.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	blocked := filepath.Join(file, "sdk")
+
+	c := sdkDirConfig(t, t.TempDir(), "")
+	if err := ensureSDKRoot(c); err != nil {
+		t.Errorf("ensureSDKRoot with a writable SDK directory: %v", err)
+	}
+
+	// Standard input is not a terminal under go test, so there are no
+	// prompts.
+	c = sdkDirConfig(t, blocked, "")
+	if err := ensureSDKRoot(c); err == nil || !strings.Contains(err.Error(), "-sdk-dir") || !strings.Contains(err.Error(), envSDKDir) {
+		t.Errorf("ensureSDKRoot with an SDK directory through a file = %v; want an error suggesting -sdk-dir and %s", err, envSDKDir)
+	}
+
+	dir := t.TempDir()
+	c = sdkDirConfig(t, blocked, dir)
+	if err := ensureSDKRoot(c); err != nil {
+		t.Fatalf("ensureSDKRoot with -sdk-dir: %v", err)
+	}
+	if root, _ := defaultLocator.SDKRoot(); root != dir {
+		t.Errorf("after ensureSDKRoot, the SDK directory is %s; want %s", root, dir)
+	}
+	if c, err := LoadConfig(c.File); err != nil || c.Locator().Root != dir {
+		t.Errorf("-sdk-dir %s was not saved in the config file", dir)
+	}
+
+	c = sdkDirConfig(t, t.TempDir(), blocked)
+	if err := ensureSDKRoot(c); err == nil || !strings.Contains(err.Error(), "-sdk-dir") {
+		t.Errorf("ensureSDKRoot with an -sdk-dir through a file = %v; want an error", err)
+	}
+}
+
+func TestEnsureSDKRootReadOnly(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+		t.Skipf("directory permissions don't prevent writes on %s", runtime.GOOS)
+	}
+	if os.Geteuid() == 0 {
+		t.Skip("root can write to read-only directories")
+	}
+	parent := t.TempDir()
+	if err := os.Chmod(parent, 0555); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chmod(parent, 0755) })
+
+	c := sdkDirConfig(t, filepath.Join(parent, "sdk"), "")
+	if err := ensureSDKRoot(c); err == nil || !strings.Contains(err.Error(), "-sdk-dir") {
+		t.Errorf("ensureSDKRoot under a read-only parent = %v; want an error suggesting -sdk-dir", err)
+	}
+
+	// The flag is honored, and remembered, since the configured
+	// directory can't be written to.
+	dir := t.TempDir()
+	c = sdkDirConfig(t, filepath.Join(parent, "sdk"), dir)
+	if err := ensureSDKRoot(c); err != nil {
+		t.Fatal(err)
+	}
+	if c, err := LoadConfig(c.File); err != nil || c.Locator().Root != dir {
+		t.Errorf("-sdk-dir %s was not saved in the config file", dir)
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	// maxSegments bounds DownloaderOptions.Segments, to stay a polite
+	// client of the download server.
+	maxSegments = 16
+
+	// minSegmentSize is the smallest part of an archive fetched on a
+	// connection of its own; below it, the extra requests cost more than
+	// they save.
+	minSegmentSize = 4 << 20
+)
+
+// errNoRanges reports that a server doesn't serve byte ranges, so that a
+// segmented download must be made as one.
+var errNoRanges = errors.New("the server doesn't serve byte ranges")
+
+// A byteRange is the bytes of a file from start up to but not including
+// end.
+type byteRange struct{ start, end int64 }
+
+// segmentRanges splits size bytes into at most n ranges of at least
+// minSegmentSize bytes each, in order.
+func segmentRanges(size int64, n int) []byteRange {
+	if max := size / minSegmentSize; int64(n) > max {
+		n = int(max)
+	}
+	if n < 1 {
+		n = 1
+	}
+	ranges := make([]byteRange, n)
+	for i := range ranges {
+		ranges[i] = byteRange{size * int64(i) / int64(n), size * int64(i+1) / int64(n)}
+	}
+	return ranges
+}
+
+// copySegments downloads srcURL, size bytes long, to dstFile, as
+// copyFromURL does, but fetching each of ranges on a connection of its
+// own, all at once, and writing each into place in the partial download.
+// The rate limit, if any, is shared between them. If the server doesn't
+// serve ranges, it returns errNoRanges, keeping nothing. A segmented
+// download isn't resumed; if it fails, it is removed. The host it
+// returns is the one that sent the first range.
+func (d *Downloader) copySegments(ctx context.Context, dstFile, srcURL string, size int64, ranges []byteRange) (n int64, host string, err error) {
+	host = hostOf(srcURL)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	partial := dstFile + partialSuffix
+	removePartial(dstFile)
+	f, err := os.OpenFile(partial, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return 0, host, err
+	}
+	defer func() {
+		if err != nil {
+			_ = f.Close()
+			removePartial(dstFile)
+		}
+	}()
+	if err = f.Truncate(size); err != nil {
+		return 0, host, err
+	}
+
+	pw := &progressWriter{w: ioutil.Discard, total: size, url: srcURL, em: d.emitter(), m: d.newMeter("Downloaded", size, 0, true)}
+	defer pw.m.stop()
+	var mu sync.Mutex // guards pw and n
+	errs := make([]error, len(ranges))
+	hosts := make([]string, len(ranges))
+	var wg sync.WaitGroup
+	for i, r := range ranges {
+		wg.Add(1)
+		go func(i int, r byteRange) {
+			defer wg.Done()
+			hosts[i], errs[i] = d.copyRange(ctx, f, srcURL, r, len(ranges), func(chunk []byte) {
+				mu.Lock()
+				defer mu.Unlock()
+				n += int64(len(chunk))
+				_, _ = pw.Write(chunk)
+			})
+			if errs[i] != nil {
+				cancel()
+			}
+		}(i, r)
+	}
+	wg.Wait()
+	host = hosts[0]
+	for _, e := range errs {
+		if errors.Is(e, errNoRanges) {
+			return n, host, e
+		}
+	}
+	for _, e := range errs {
+		if e != nil && !errors.Is(e, context.Canceled) {
+			return n, host, e
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return n, host, err
+	}
+	pw.m.finish(pw.n, "")
+	pw.em.emit(DownloadProgress{URL: srcURL, Bytes: pw.n, Total: size})
+	if err = f.Close(); err != nil {
+		return n, host, err
+	}
+	if err = os.Rename(partial, dstFile); err != nil {
+		return n, host, err
+	}
+	return n, host, nil
+}
+
+// copyRange fetches the range r of srcURL into the same bytes of f, one
+// of segments fetched at once, calling progress with each chunk it
+// writes. It returns the host that sent the range.
+func (d *Downloader) copyRange(ctx context.Context, f *os.File, srcURL string, r byteRange, segments int, progress func([]byte)) (host string, err error) {
+	host = hostOf(srcURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srcURL, nil)
+	if err != nil {
+		return host, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", r.start, r.end-1))
+	res, err := d.client.Do(req)
+	if err != nil {
+		return host, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	host = responseHost(res, srcURL)
+	switch res.StatusCode {
+	case http.StatusPartialContent:
+		if want := fmt.Sprintf("bytes %d-%d/", r.start, r.end-1); !strings.HasPrefix(res.Header.Get("Content-Range"), want) {
+			return host, fmt.Errorf("server sent unexpected range %q", res.Header.Get("Content-Range"))
+		}
+	case http.StatusOK:
+		return host, errNoRanges
+	default:
+		return host, errors.New(res.Status)
+	}
+	var body io.Reader = res.Body
+	if d.opts.MaxRate > 0 {
+		rate := d.opts.MaxRate / int64(segments)
+		if rate < 1 {
+			rate = 1
+		}
+		body = newRateLimitedReader(ctx, body, rate)
+	}
+	off := r.start
+	buf := make([]byte, 32<<10)
+	for off < r.end {
+		if rest := r.end - off; rest < int64(len(buf)) {
+			buf = buf[:rest]
+		}
+		k, err := body.Read(buf)
+		if k > 0 {
+			if _, werr := f.WriteAt(buf[:k], off); werr != nil {
+				return host, werr
+			}
+			off += int64(k)
+			progress(buf[:k])
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return host, err
+		}
+	}
+	if off != r.end {
+		return host, fmt.Errorf("copied %v bytes of range %d-%d; expected %v", off-r.start, r.start, r.end-1, r.end-r.start)
+	}
+	return host, nil
+}
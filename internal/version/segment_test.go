@@ -0,0 +1,89 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSegmentRanges(t *testing.T) {
+	const mib = 1 << 20
+	tests := []struct {
+		size int64
+		n    int
+		want []byteRange
+	}{
+		{70 * mib, 1, []byteRange{{0, 70 * mib}}},
+		{70 * mib, 0, []byteRange{{0, 70 * mib}}},
+		{12 * mib, 4, []byteRange{{0, 4 * mib}, {4 * mib, 8 * mib}, {8 * mib, 12 * mib}}},
+		{10, 4, []byteRange{{0, 10}}},
+		{70 * mib, 4, []byteRange{{0, 70 * mib / 4}, {70 * mib / 4, 70 * mib / 2}, {70 * mib / 2, 70 * mib * 3 / 4}, {70 * mib * 3 / 4, 70 * mib}}},
+	}
+	for _, tt := range tests {
+		if got := segmentRanges(tt.size, tt.n); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("segmentRanges(%d, %d) = %v; want %v", tt.size, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestCopySegments(t *testing.T) {
+	ts := newTestServer(t)
+	url := versionArchiveURL(DefaultBaseURL, "go1.99")
+	archive := ts.tar
+	if strings.HasSuffix(url, ".zip") {
+		archive = ts.zip
+	}
+	size := int64(len(archive))
+	file := filepath.Join(t.TempDir(), filepath.Base(url))
+	d := ts.downloader(t, DownloaderOptions{Segments: 3})
+	ranges := []byteRange{{0, 100}, {100, 101}, {101, size}}
+	n, _, err := d.copySegments(context.Background(), file, url, size, ranges)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != size || string(got) != string(archive) {
+		t.Errorf("copySegments copied %d bytes, making a different archive; want %d bytes", n, size)
+	}
+	if gets := len(ts.requests()); gets != len(ranges) {
+		t.Errorf("copySegments made %d requests; want one per segment, %d", gets, len(ranges))
+	}
+	if _, err := os.Stat(file + partialSuffix); !os.IsNotExist(err) {
+		t.Errorf("the partial download was left behind: %v", err)
+	}
+
+	// A server that ignores Range is told apart, and nothing is kept.
+	whole := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(archive)
+	}))
+	defer whole.Close()
+	file = filepath.Join(t.TempDir(), filepath.Base(url))
+	d, err = NewDownloader(DownloaderOptions{Segments: 3, Quiet: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := d.copySegments(context.Background(), file, whole.URL+"/"+filepath.Base(url), size, ranges); !errors.Is(err, errNoRanges) {
+		t.Errorf("copySegments from a server without ranges = %v; want %v", err, errNoRanges)
+	}
+	if fis, _ := ioutil.ReadDir(filepath.Dir(file)); len(fis) > 0 {
+		t.Errorf("copySegments left %s behind", fis[0].Name())
+	}
+
+	if _, err := NewDownloader(DownloaderOptions{Segments: maxSegments + 1}); err == nil {
+		t.Errorf("NewDownloader accepted %d segments", maxSegments+1)
+	}
+}
@@ -0,0 +1,331 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+)
+
+// modulePath is the module the dl command and the wrappers are built
+// from. It has no upper-case letters, so it needs no escaping in module
+// proxy URLs.
+const modulePath = "github.com/rustatian/dl"
+
+// defaultProxy is the module proxy used when GOPROXY names none.
+const defaultProxy = "https://proxy.golang.org"
+
+// buildInfo describes how the running program was built.
+type buildInfo struct {
+	Package string // import path of the main package
+	Module  string // path of the main module
+	Version string // version of the main module, or "(devel)"
+}
+
+// toolBuildInfo returns the build information the go command embedded in
+// the running program.
+func toolBuildInfo() buildInfo {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return buildInfo{Version: "(devel)"}
+	}
+	return buildInfo{Package: bi.Path, Module: bi.Main.Path, Version: bi.Main.Version}
+}
+
+// checkOwnership returns an error explaining why the program at exe, built
+// as bi, must not replace itself: because it wasn't installed with
+// go install from a released version, or because something else, such as
+// a system package manager, owns the directory it is in.
+func checkOwnership(exe string, bi buildInfo) error {
+	if bi.Module != modulePath {
+		return fmt.Errorf("%s was built as part of %s, not installed with go install; update it the way it was built", exe, bi.Module)
+	}
+	if bi.Version == "" || bi.Version == "(devel)" {
+		return fmt.Errorf("%s was built from a source checkout, so it has no version to compare; update the checkout and reinstall it", exe)
+	}
+	slash := filepath.ToSlash(exe)
+	for _, dir := range []string{"/usr/bin/", "/usr/sbin/", "/bin/", "/sbin/", "/usr/local/Cellar/", "/opt/homebrew/", "/home/linuxbrew/", "/nix/store/", "/snap/", "/usr/pkg/", "/opt/local/"} {
+		if strings.HasPrefix(slash, dir) {
+			return fmt.Errorf("%s is in %s, which belongs to a package manager; update it with that package manager", exe, strings.TrimSuffix(dir, "/"))
+		}
+	}
+	return nil
+}
+
+// moduleProxy returns the first module proxy that GOPROXY lists.
+func moduleProxy(goproxy string) (string, error) {
+	if goproxy == "" {
+		return defaultProxy, nil
+	}
+	for _, p := range strings.FieldsFunc(goproxy, func(r rune) bool { return r == ',' || r == '|' }) {
+		if strings.HasPrefix(p, "https://") || strings.HasPrefix(p, "http://") {
+			return strings.TrimSuffix(p, "/"), nil
+		}
+	}
+	return "", fmt.Errorf("GOPROXY=%s lists no module proxy to look for updates in", goproxy)
+}
+
+// latestToolVersion asks the module proxy for the latest version of the
+// module.
+func (d *Downloader) latestToolVersion(ctx context.Context, proxy string) (string, error) {
+	body, err := d.slurpURLToString(ctx, proxy+"/"+modulePath+"/@latest")
+	if err != nil {
+		return "", fmt.Errorf("looking up the latest version: %w", err)
+	}
+	var info struct{ Version string }
+	if err := json.Unmarshal([]byte(body), &info); err != nil || info.Version == "" {
+		return "", fmt.Errorf("looking up the latest version: unexpected reply from %s", proxy)
+	}
+	return info.Version, nil
+}
+
+// semverLess reports whether the semantic version v, such as "v1.2.3" or
+// "v0.0.0-20240101000000-abcdef012345", is older than w.
+func semverLess(v, w string) bool {
+	vcore, vpre := splitSemver(v)
+	wcore, wpre := splitSemver(w)
+	for i := range vcore {
+		if vcore[i] != wcore[i] {
+			return vcore[i] < wcore[i]
+		}
+	}
+	switch {
+	case vpre == wpre:
+		return false
+	case vpre == "":
+		return false
+	case wpre == "":
+		return true
+	}
+	vs, ws := strings.Split(vpre, "."), strings.Split(wpre, ".")
+	for i := 0; i < len(vs) && i < len(ws); i++ {
+		if vs[i] == ws[i] {
+			continue
+		}
+		vn, verr := strconv.Atoi(vs[i])
+		wn, werr := strconv.Atoi(ws[i])
+		switch {
+		case verr == nil && werr == nil:
+			return vn < wn
+		case verr == nil:
+			return true // numeric identifiers sort first
+		case werr == nil:
+			return false
+		}
+		return vs[i] < ws[i]
+	}
+	return len(vs) < len(ws)
+}
+
+// splitSemver returns the major, minor and patch numbers of v, and its
+// pre-release suffix. Build metadata is ignored.
+func splitSemver(v string) (core [3]int, pre string) {
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.Index(v, "+"); i >= 0 {
+		v = v[:i]
+	}
+	if i := strings.Index(v, "-"); i >= 0 {
+		v, pre = v[:i], v[i+1:]
+	}
+	for i, f := range strings.SplitN(v, ".", 3) {
+		core[i], _ = strconv.Atoi(f)
+	}
+	return core, pre
+}
+
+// findGo returns a go command to build with: the one in $PATH, or else
+// that of the newest release installed by the wrappers.
+func findGo() (string, error) {
+	if p, err := exec.LookPath("go"); err == nil {
+		return p, nil
+	}
+	vs, err := defaultLocator.Installed()
+	if err == nil && len(vs) > 0 {
+		root, err := vs[len(vs)-1].GorootPath()
+		if err == nil {
+			return filepath.Join(root, "bin", "go"+exe()), nil
+		}
+	}
+	return "", errors.New("no go command in $PATH or installed by this tool to build the update with")
+}
+
+// buildTool builds the main package pkg at version of the module with the
+// go command gobin, into dir, and returns the built program. The go
+// command checks the module against the checksum database, and the
+// program is checked to be of the requested version.
+func buildTool(ctx context.Context, gobin, pkg, version, dir string) (string, error) {
+	cmd := exec.CommandContext(ctx, gobin, "install", pkg+"@"+version)
+	cmd.Env = dedupEnv(caseInsensitiveEnv, append(os.Environ(), "GOBIN="+dir))
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("go install %s@%s: %v", pkg, version, err)
+	}
+	built := filepath.Join(dir, path.Base(pkg)+exe())
+	out, err := exec.CommandContext(ctx, gobin, "version", "-m", built).Output()
+	if err != nil {
+		return "", fmt.Errorf("checking the built program: %v", err)
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		f := strings.Fields(line)
+		if len(f) >= 3 && f[0] == "mod" && f[1] == modulePath && f[2] == version {
+			return built, nil
+		}
+	}
+	return "", fmt.Errorf("the built program is not %s %s", modulePath, version)
+}
+
+// oldExeSuffix marks a program replaced by replaceExecutable on Windows,
+// which can't remove a running program.
+const oldExeSuffix = ".old"
+
+// replaceExecutable atomically replaces the program at exe with the one at
+// newExe, which must be in the same directory. Windows doesn't let a
+// running program be overwritten, but it may be renamed, so there it is
+// moved aside first, and removed by removeOldExecutable when next run.
+func replaceExecutable(exe, newExe string) error {
+	if runtime.GOOS != "windows" {
+		return os.Rename(newExe, exe)
+	}
+	old := exe + oldExeSuffix
+	_ = os.Remove(old)
+	if err := os.Rename(exe, old); err != nil {
+		return err
+	}
+	if err := os.Rename(newExe, exe); err != nil {
+		if rerr := os.Rename(old, exe); rerr != nil {
+			return fmt.Errorf("%v; and restoring %s failed: %v", err, exe, rerr)
+		}
+		return err
+	}
+	return nil
+}
+
+// removeOldExecutable removes the copy of the running program that an
+// update left behind on Windows.
+func removeOldExecutable() {
+	if runtime.GOOS != "windows" {
+		return
+	}
+	if exe, err := os.Executable(); err == nil {
+		_ = os.Remove(exe + oldExeSuffix)
+	}
+}
+
+// checkWritable reports whether files can be created in dir.
+func checkWritable(dir string) error {
+	f, err := ioutil.TempFile(dir, ".dl-write-test-")
+	if err != nil {
+		return fmt.Errorf("can't write to %s: %v", dir, err)
+	}
+	_ = f.Close()
+	return os.Remove(f.Name())
+}
+
+// A wrapper is a program built from this module and installed beside the
+// dl command: a release wrapper, such as go1.22.7 or gotip, or the go shim
+// dl default installs, which is a copy of the dl command.
+type wrapper struct {
+	File    string
+	Package string // such as github.com/rustatian/dl/go1.22.7
+	Version string // of the module it was built from
+}
+
+// isShim reports whether w is a copy of the dl command, as the go shim is.
+func (w wrapper) isShim() bool {
+	return w.Package == modulePath+"/dl" && isShimName(w.File)
+}
+
+// installedWrappers returns the wrappers in dir, as told by the go command
+// gobin, other than the dl command exe itself.
+func installedWrappers(ctx context.Context, gobin, dir, exe string) ([]wrapper, error) {
+	if !isDir(dir) {
+		return nil, nil
+	}
+	out, err := exec.CommandContext(ctx, gobin, "version", "-m", dir).Output()
+	if err != nil {
+		return nil, fmt.Errorf("go version -m %s: %v", dir, err)
+	}
+	var ws []wrapper
+	for _, w := range parseWrappers(string(out)) {
+		if !sameFile(w.File, exe) {
+			ws = append(ws, w)
+		}
+	}
+	return ws, nil
+}
+
+// parseWrappers returns the wrappers that the output of go version -m
+// lists, with their build information, ignoring programs built from other
+// modules.
+func parseWrappers(out string) []wrapper {
+	var ws []wrapper
+	var w *wrapper
+	done := func() {
+		if w != nil && w.Version != "" && (w.isShim() || wrapperName(path.Base(w.Package))) {
+			ws = append(ws, *w)
+		}
+		w = nil
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if !strings.HasPrefix(line, "\t") {
+			done()
+			if i := strings.LastIndex(line, ": "); i > 0 {
+				w = &wrapper{File: line[:i]}
+			}
+			continue
+		}
+		f := strings.Split(strings.TrimPrefix(line, "\t"), "\t")
+		switch {
+		case w == nil:
+		case f[0] == "path" && len(f) >= 2 && strings.HasPrefix(f[1], modulePath+"/"):
+			w.Package = f[1]
+		case f[0] == "mod" && len(f) >= 3 && f[1] == modulePath:
+			w.Version = f[2]
+		}
+	}
+	done()
+	return ws
+}
+
+// wrapperName reports whether name is that of a release wrapper's
+// package.
+func wrapperName(name string) bool {
+	if name == "gotip" || name == "golatest" {
+		return true
+	}
+	_, err := ParseVersion(name)
+	return err == nil
+}
+
+// updateWrapper rebuilds the release wrapper w at version of the module
+// with the go command gobin, in place, or, for the go shim, copies the dl
+// command exe over it.
+func updateWrapper(ctx context.Context, gobin, exe, version string, w wrapper) error {
+	if w.isShim() {
+		return installShim(w.File, exe, false)
+	}
+	tmp, err := ioutil.TempDir(filepath.Dir(w.File), ".dl-update-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	built, err := buildTool(ctx, gobin, w.Package, version, tmp)
+	if err != nil {
+		return err
+	}
+	return replaceExecutable(w.File, built)
+}
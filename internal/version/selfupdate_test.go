@@ -0,0 +1,158 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSemverLess(t *testing.T) {
+	tests := []struct {
+		v, w string
+		less bool
+	}{
+		{"v0.1.0", "v0.2.0", true},
+		{"v0.2.0", "v0.1.0", false},
+		{"v0.2.0", "v0.2.0", false},
+		{"v0.9.0", "v0.10.0", true},
+		{"v1.0.0", "v0.99.99", false},
+		{"v1.2.3", "v1.2.4", true},
+		{"v1.2.3-rc.1", "v1.2.3", true},
+		{"v1.2.3", "v1.2.3-rc.1", false},
+		{"v1.2.3-rc.1", "v1.2.3-rc.2", true},
+		{"v1.2.3-rc.2", "v1.2.3-rc.10", true},
+		{"v1.2.3-rc", "v1.2.3-rc.1", true},
+		{"v1.2.3-1", "v1.2.3-alpha", true},
+		{"v0.0.0-20240101000000-abcdef012345", "v0.0.0-20250101000000-012345abcdef", true},
+		{"v0.0.0-20250101000000-012345abcdef", "v0.1.0", true},
+		{"v1.2.3+incompatible", "v1.2.3", false},
+	}
+	for _, tt := range tests {
+		if got := semverLess(tt.v, tt.w); got != tt.less {
+			t.Errorf("semverLess(%q, %q) = %v; want %v", tt.v, tt.w, got, tt.less)
+		}
+	}
+}
+
+func TestModuleProxy(t *testing.T) {
+	tests := []struct {
+		goproxy, want string
+	}{
+		{"", defaultProxy},
+		{"https://proxy.golang.org,direct", "https://proxy.golang.org"},
+		{"https://goproxy.example.com/|https://proxy.golang.org", "https://goproxy.example.com"},
+		{"direct,http://athens.internal:3000", "http://athens.internal:3000"},
+		{"off", ""},
+		{"direct", ""},
+	}
+	for _, tt := range tests {
+		got, err := moduleProxy(tt.goproxy)
+		if got != tt.want || (err != nil) != (tt.want == "") {
+			t.Errorf("moduleProxy(%q) = %q, %v; want %q", tt.goproxy, got, err, tt.want)
+		}
+	}
+}
+
+func TestCheckOwnership(t *testing.T) {
+	released := buildInfo{Package: modulePath + "/dl", Module: modulePath, Version: "v0.3.0"}
+	tests := []struct {
+		exe string
+		bi  buildInfo
+		ok  bool
+	}{
+		{"/home/me/go/bin/dl", released, true},
+		{`C:\Users\me\go\bin\dl.exe`, released, true},
+		{"/usr/local/bin/dl", released, true},
+		{"/usr/bin/dl", released, false},
+		{"/opt/homebrew/bin/dl", released, false},
+		{"/nix/store/abc-dl/bin/dl", released, false},
+		{"/home/me/go/bin/dl", buildInfo{Package: modulePath + "/dl", Module: modulePath, Version: "(devel)"}, false},
+		{"/home/me/go/bin/dl", buildInfo{Package: "example.com/tools/dl", Module: "example.com/tools", Version: "v1.0.0"}, false},
+	}
+	for _, tt := range tests {
+		err := checkOwnership(tt.exe, tt.bi)
+		if ok := err == nil; ok != tt.ok {
+			t.Errorf("checkOwnership(%s, %+v) = %v; want ok=%v", tt.exe, tt.bi, err, tt.ok)
+		}
+	}
+}
+
+func TestLatestToolVersion(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/"+modulePath+"/@latest" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"Version":"v0.4.0","Time":"2026-09-01T00:00:00Z"}`)
+	}))
+	defer ts.Close()
+
+	d, err := NewDownloader(DownloaderOptions{Client: ts.Client()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := d.latestToolVersion(context.Background(), ts.URL)
+	if err != nil || v != "v0.4.0" {
+		t.Errorf("latestToolVersion = %q, %v; want v0.4.0", v, err)
+	}
+	if _, err := d.latestToolVersion(context.Background(), ts.URL+"/missing"); err == nil {
+		t.Error("latestToolVersion succeeded for a proxy without the module")
+	}
+}
+
+func TestReplaceExecutable(t *testing.T) {
+	dir := t.TempDir()
+	exe, newExe := filepath.Join(dir, "dl"), filepath.Join(dir, "dl.new")
+	if err := ioutil.WriteFile(exe, []byte("old"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(newExe, []byte("new"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := replaceExecutable(exe, newExe); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := ioutil.ReadFile(exe); err != nil || string(b) != "new" {
+		t.Errorf("after replaceExecutable, %s holds %q, %v; want new", exe, b, err)
+	}
+}
+
+func TestParseWrappers(t *testing.T) {
+	out := "/home/gopher/go/bin/go1.22.7: go1.22.7\n" +
+		"\tpath\tgithub.com/rustatian/dl/go1.22.7\n" +
+		"\tmod\tgithub.com/rustatian/dl\tv0.3.0\th1:abc=\n" +
+		"\tbuild\t-compiler=gc\n" +
+		"/home/gopher/go/bin/go: go1.22.7\n" +
+		"\tpath\tgithub.com/rustatian/dl/dl\n" +
+		"\tmod\tgithub.com/rustatian/dl\tv0.4.0\th1:def=\n" +
+		"/home/gopher/go/bin/dl-copy: go1.22.7\n" +
+		"\tpath\tgithub.com/rustatian/dl/dl\n" +
+		"\tmod\tgithub.com/rustatian/dl\tv0.4.0\th1:def=\n" +
+		"/home/gopher/go/bin/gopls: go1.22.7\n" +
+		"\tpath\tgolang.org/x/tools/gopls\n" +
+		"\tmod\tgolang.org/x/tools/gopls\tv0.16.0\th1:ghi=\n" +
+		"/home/gopher/go/bin/gotip: go1.21.13\n" +
+		"\tpath\tgithub.com/rustatian/dl/gotip\n" +
+		"\tmod\tgithub.com/rustatian/dl\tv0.2.1\th1:jkl=\n"
+	got := parseWrappers(out)
+	want := []wrapper{
+		{"/home/gopher/go/bin/go1.22.7", "github.com/rustatian/dl/go1.22.7", "v0.3.0"},
+		{"/home/gopher/go/bin/go", "github.com/rustatian/dl/dl", "v0.4.0"},
+		{"/home/gopher/go/bin/gotip", "github.com/rustatian/dl/gotip", "v0.2.1"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseWrappers:\n%+v\nwant:\n%+v", got, want)
+	}
+	if !got[1].isShim() || got[0].isShim() {
+		t.Errorf("only %s is the go shim", got[1].File)
+	}
+}
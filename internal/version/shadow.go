@@ -0,0 +1,95 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// envNoPathCheck, set to 1, silences the warnings about go commands in
+// PATH that shadow the toolchain just installed or run.
+const envNoPathCheck = "GODL_NO_PATH_CHECK"
+
+// lookGo returns the go command that the PATH list selects, or "" if
+// there is none. It only looks at the file system.
+func lookGo(list string) string {
+	for _, dir := range filepath.SplitList(list) {
+		if dir == "" {
+			continue
+		}
+		p := filepath.Join(dir, "go"+exe())
+		if fi, err := os.Stat(p); err == nil && fi.Mode().IsRegular() {
+			return p
+		}
+	}
+	return ""
+}
+
+// managedRoots returns the SDK directories toolchains are found in: l's
+// and, if l has its own, the default one.
+func managedRoots(l *Locator) []string {
+	var roots []string
+	for _, l := range []*Locator{l, {}} {
+		if root, err := l.SDKRoot(); err == nil {
+			roots = append(roots, root)
+		}
+	}
+	return roots
+}
+
+// inDir reports whether file is in dir or below it.
+func inDir(file, dir string) bool {
+	rel, err := filepath.Rel(dir, file)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// shadowingGo returns the go command that the PATH list selects if it is
+// neither that of the toolchain in root nor in any of the SDK directories
+// roots, or "" if there is none or it is one of those.
+func shadowingGo(list, root string, roots []string) string {
+	found := lookGo(list)
+	if found == "" {
+		return ""
+	}
+	if fi, err := os.Stat(filepath.Join(root, "bin", "go"+exe())); err == nil {
+		if ffi, err := os.Stat(found); err == nil && os.SameFile(fi, ffi) {
+			return ""
+		}
+	}
+	for _, r := range roots {
+		if inDir(found, r) {
+			return ""
+		}
+	}
+	return found
+}
+
+// shadowFix says how to make the toolchain name, in root, the one that
+// go runs: the PATH edit, or running it by name.
+func shadowFix(name, root string) string {
+	bin := filepath.Join(root, "bin")
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+		return fmt.Sprintf("put %s first in PATH, or run '%s' instead of go", bin, name)
+	}
+	return fmt.Sprintf(`run 'export PATH="%s%c$PATH"', or run '%s' instead of go`, bin, filepath.ListSeparator, name)
+}
+
+// warnShadowing warns if the go command in PATH shadows the toolchain
+// name, in root, unless GODL_NO_PATH_CHECK is set. The check only looks at
+// the file system.
+func warnShadowing(name, root string, roots []string) {
+	if os.Getenv(envNoPathCheck) == "1" {
+		return
+	}
+	if found := shadowingGo(os.Getenv(pathVar()), root, roots); found != "" {
+		log.Printf("Warning: go in PATH is %s, not %s. To use %s as go, %s. (Set %s=1 to silence this.)",
+			found, name, name, shadowFix(name, root), envNoPathCheck)
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestShadowingGo(t *testing.T) {
+	sys, sdk := t.TempDir(), t.TempDir()
+	makeTree(t, sys, map[string]string{"go" + exe(): ""})
+	makeTree(t, sdk, map[string]string{
+		"go1.22.7/bin/go" + exe(): "",
+		"go1.21.0/bin/go" + exe(): "",
+	})
+	root := filepath.Join(sdk, "go1.22.7")
+	bin, other := filepath.Join(root, "bin"), filepath.Join(sdk, "go1.21.0", "bin")
+	sysGo := filepath.Join(sys, "go"+exe())
+	join := func(dirs ...string) string { return strings.Join(dirs, string(filepath.ListSeparator)) }
+	tests := []struct {
+		list  string
+		roots []string
+		want  string
+	}{
+		{"", []string{sdk}, ""},
+		{join(sys, bin), []string{sdk}, sysGo},
+		{join(bin, sys), []string{sdk}, ""},
+		{join(other, sys), []string{sdk}, ""},                     // another toolchain installed here
+		{join(other, sys), nil, filepath.Join(other, "go"+exe())}, // as for gotip
+		{join(sys), nil, sysGo},
+	}
+	for _, tt := range tests {
+		if got := shadowingGo(tt.list, root, tt.roots); got != tt.want {
+			t.Errorf("shadowingGo(%q, %v) = %q; want %q", tt.list, tt.roots, got, tt.want)
+		}
+	}
+	if fix := shadowFix("go1.22.7", root); !strings.Contains(fix, bin) || !strings.Contains(fix, "'go1.22.7'") {
+		t.Errorf("shadowFix = %q; want the bin directory and the command named", fix)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// envShell is set, in the shells that "go1.N.M shell" starts, to the
+// toolchain the shell has on PATH, so that a prompt can show it.
+const envShell = "GODL_SHELL"
+
+// runShell starts the user's shell with the toolchain name, in root,
+// first on PATH and GOROOT set to it, as "go1.N.M shell" and "gotip shell"
+// do, passing it args. It exits as the shell does, leaving the
+// environment the shell was started from as it was.
+func runShell(name, root string, args []string) {
+	style, args, err := shellArgs(args)
+	if err != nil {
+		usagef("%s shell: %v", name, err)
+	}
+	sh := userShell()
+	cmd, err := toolchainCommand(context.Background(), root, sh, args...)
+	if err != nil {
+		fatal(name+" shell", err)
+	}
+	cmd.Env = dedupEnv(caseInsensitiveEnv, append(cmd.Env, envShell+"="+name))
+	if len(args) == 0 && isTerminal(os.Stdin) {
+		log.Printf("Starting %s with %s on PATH, from %s; exit it to return.", sh, name, style.Format(filepath.Join(root, "bin")))
+	}
+	runCommand(cmd)
+}
+
+// shellArgs takes a -path-style flag, for the paths the shell subcommand
+// prints, from the front of args, and returns the style it sets and the
+// rest of args, which go to the shell.
+func shellArgs(args []string) (PathStyle, []string, error) {
+	style := PathStyleAuto
+	if len(args) == 0 || !strings.HasPrefix(args[0], "-") {
+		return style, args, nil
+	}
+	switch a := strings.TrimPrefix(args[0][1:], "-"); {
+	case a == "path-style" && len(args) > 1:
+		err := style.Set(args[1])
+		return style, args[2:], err
+	case strings.HasPrefix(a, "path-style="):
+		err := style.Set(a[len("path-style="):])
+		return style, args[1:], err
+	}
+	return style, args, nil
+}
+
+// userShell returns the user's shell: SHELL, or /bin/sh if it isn't set,
+// ComSpec on Windows, and rc on Plan 9.
+func userShell() string {
+	switch runtime.GOOS {
+	case "windows":
+		if sh := os.Getenv("ComSpec"); sh != "" {
+			return sh
+		}
+		return "cmd.exe"
+	case "plan9":
+		return "rc"
+	}
+	if sh := os.Getenv("SHELL"); sh != "" {
+		return sh
+	}
+	return "/bin/sh"
+}
@@ -0,0 +1,48 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestUserShell(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+		t.Skip("the shell isn't taken from SHELL")
+	}
+	t.Setenv("SHELL", "/usr/bin/zsh")
+	if got := userShell(); got != "/usr/bin/zsh" {
+		t.Errorf("userShell() = %q; want SHELL", got)
+	}
+	t.Setenv("SHELL", "")
+	if got := userShell(); got != "/bin/sh" {
+		t.Errorf("userShell() with SHELL unset = %q; want /bin/sh", got)
+	}
+}
+
+func TestShellArgs(t *testing.T) {
+	tests := []struct {
+		args  []string
+		style PathStyle
+		rest  []string
+	}{
+		{nil, PathStyleAuto, nil},
+		{[]string{"-c", "make test"}, PathStyleAuto, []string{"-c", "make test"}},
+		{[]string{"-path-style=unix"}, PathStyleUnix, []string{}},
+		{[]string{"--path-style", "windows", "-c", "go version"}, PathStyleWindows, []string{"-c", "go version"}},
+		{[]string{"-l", "-path-style=unix"}, PathStyleAuto, []string{"-l", "-path-style=unix"}},
+	}
+	for _, tt := range tests {
+		style, rest, err := shellArgs(tt.args)
+		if err != nil || style != tt.style || strings.Join(rest, " ") != strings.Join(tt.rest, " ") {
+			t.Errorf("shellArgs(%q) = %q, %q, %v; want %q, %q", tt.args, style, rest, err, tt.style, tt.rest)
+		}
+	}
+	if _, _, err := shellArgs([]string{"-path-style=dos"}); err == nil {
+		t.Errorf("shellArgs accepted -path-style=dos")
+	}
+}
@@ -0,0 +1,294 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// defaultKey is the config file key of the default release, the one the
+// go shim runs: a line
+//
+//	default = "go1.22.7"
+//
+// makes go, when it is the shim dl default installs, run go1.22.7.
+const defaultKey = "default"
+
+// checkDefault reports whether name may be the default release: a release
+// name, such as go1.22.7 or go1.22, latest, or the name of an alias.
+func checkDefault(name string) error {
+	if name == "gotip" {
+		return errors.New("default: gotip can't be the default release")
+	}
+	_, err := ParseVersion(name)
+	if err == nil || name == "latest" || aliasNameRE.MatchString(name) {
+		return nil
+	}
+	return fmt.Errorf("default: %v", err)
+}
+
+// DefaultRelease returns the default release the config file sets, as
+// given to dl default, or "" if there is none.
+func (c *Config) DefaultRelease() string {
+	return c.defaultRelease
+}
+
+// saveDefault makes name the default release in the config file, or, if
+// name is empty, removes it.
+func (c *Config) saveDefault(name string) error {
+	if name == "" {
+		if err := c.unsave(defaultKey); err != nil {
+			return err
+		}
+		c.defaultRelease = ""
+		return nil
+	}
+	if err := checkDefault(name); err != nil {
+		return err
+	}
+	if err := c.save(defaultKey, name); err != nil {
+		return err
+	}
+	c.defaultRelease = name
+	return nil
+}
+
+// isShimName reports whether the program was run as go, as the shim is.
+func isShimName(arg0 string) bool {
+	base := filepath.Base(arg0)
+	if ext := filepath.Ext(base); strings.EqualFold(ext, ".exe") {
+		base = strings.TrimSuffix(base, ext)
+	}
+	return strings.EqualFold(base, "go")
+}
+
+// goBinDir returns the directory go install installs commands in, where
+// the shim goes: GOBIN, or else the bin directory of the first GOPATH
+// entry, or of its default, ~/go.
+func goBinDir() (string, error) {
+	if dir := os.Getenv("GOBIN"); dir != "" {
+		return dir, nil
+	}
+	if list := filepath.SplitList(os.Getenv("GOPATH")); len(list) > 0 && list[0] != "" {
+		return filepath.Join(list[0], "bin"), nil
+	}
+	home, err := homedir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "go", "bin"), nil
+}
+
+// shimMarker is part of the build information every build of the dl
+// command carries, and so every shim.
+var shimMarker = []byte("path\t" + modulePath + "/dl\n")
+
+// isShim reports whether file is a copy of the dl command, and so a shim
+// that dl default may replace or remove.
+func isShim(file string) bool {
+	data, err := ioutil.ReadFile(file)
+	return err == nil && bytes.Contains(data, shimMarker)
+}
+
+// installShim copies the dl command at self to file, replacing the shim
+// that is there, if any. Any other file is only replaced if force is set,
+// since it is likely a go command of its own.
+func installShim(file, self string, force bool) error {
+	if _, err := os.Stat(file); err == nil && !force && !isShim(file) {
+		return fmt.Errorf("%s is not a shim installed by dl default; remove it, or use -force to replace it", file)
+	}
+	data, err := ioutil.ReadFile(self)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(file), ".go-shim-")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(f.Name(), 0755)
+	}
+	if err == nil {
+		if _, serr := os.Stat(file); os.IsNotExist(serr) {
+			err = os.Rename(f.Name(), file)
+		} else {
+			err = replaceExecutable(file, f.Name())
+		}
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+	}
+	return err
+}
+
+// removeShim removes the shim at file, reporting whether there was one.
+// Any other file is left alone.
+func removeShim(file string) (bool, error) {
+	if !isShim(file) {
+		return false, nil
+	}
+	return true, os.Remove(file)
+}
+
+// goVersionFile is the name of the file that pins the release the go
+// shim runs in a directory and those below it, as goenv's does.
+const goVersionFile = ".go-version"
+
+// findGoVersion looks for a .go-version file in dir and each of its
+// parents in turn, and returns the release the nearest one names, and
+// that file. If there is none, it returns "", "".
+func findGoVersion(dir string) (name, file string, err error) {
+	for {
+		file = filepath.Join(dir, goVersionFile)
+		data, err := ioutil.ReadFile(file)
+		if err == nil {
+			name, err := parseGoVersion(data)
+			if err != nil {
+				return "", "", fmt.Errorf("%s: %v", file, err)
+			}
+			return name, file, nil
+		}
+		if !os.IsNotExist(err) {
+			return "", "", err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", "", nil
+		}
+		dir = parent
+	}
+}
+
+// parseGoVersion returns the release a .go-version file names: its first
+// line that isn't blank or a comment, as a release name, such as go1.22.7
+// or, as goenv writes it, 1.22.7. It may also be anything dl default
+// takes, such as go1.22, latest or an alias.
+func parseGoVersion(data []byte) (string, error) {
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if line[0] >= '0' && line[0] <= '9' {
+			line = "go" + line
+		}
+		if err := checkDefault(line); err != nil {
+			return "", errors.New(strings.TrimPrefix(err.Error(), "default: "))
+		}
+		return line, nil
+	}
+	return "", errors.New("names no release")
+}
+
+// runShim runs the go command of the default release with args, as the
+// shim does when run as go, installing it first if need be. A .go-version
+// file in the current directory or above it takes precedence over the
+// default, and a release named by GOTOOLCHAIN over both. Then, as the go
+// command does, it switches to a newer release if the go.mod file of the
+// current module requires one, unless GOTOOLCHAIN forbids it. Unlike
+// go1.N.M, it treats no arguments specially, so that it can stand in for
+// any go command. It does not return.
+func runShim(args []string) {
+	cfg, err := loadConfig("", nil)
+	if err != nil {
+		fatal("go", err)
+	}
+	wd, wdErr := os.Getwd()
+	name, source := cfg.DefaultRelease(), "the default release"
+	if wdErr == nil {
+		pinned, file, err := findGoVersion(wd)
+		if err != nil {
+			fatal("go", err)
+		}
+		if pinned != "" {
+			name, source = pinned, "pinned by "+file
+		}
+	}
+	forced, switching := parseGOTOOLCHAIN(os.Getenv("GOTOOLCHAIN"))
+	if forced != "" {
+		name, source = forced, "named by GOTOOLCHAIN"
+	}
+	var version string
+	if name != "" {
+		if version, err = resolveDefault(cfg, name); err != nil {
+			fatal("go", err)
+		}
+	}
+	if switching && wdErr == nil {
+		r, file, err := findGoMod(wd)
+		if err != nil {
+			fatal("go", err)
+		}
+		if want := r.switchFrom(version); want != "" {
+			if version, err = resolvePartial(cfg, want); err != nil {
+				fatal("go", err)
+			}
+			source = "required by " + file
+		}
+	}
+	if version == "" {
+		log.Printf("go: this go command is a shim installed by dl default; with no %s file here or above, it runs the default release, but %v", goVersionFile, errNoDefault)
+		os.Exit(ExitNotInstalled)
+	}
+	root, err := whichGoroot(defaultLocator, version)
+	if err != nil {
+		log.Printf("%s, %s, is not installed; downloading it first.", version, source)
+		if root, err = installRelease(cfg, version); err != nil {
+			fatal(version+": download failed", err)
+		}
+	}
+	checkQuarantine(root)
+	runGo(root, args)
+}
+
+// resolveDefault returns the release the default release name stands for
+// now: that of an alias, the newest stable release for latest, and the
+// newest patch release for a minor version.
+func resolveDefault(cfg *Config, name string) (string, error) {
+	name = cfg.resolveAlias(name)
+	if name != "latest" {
+		if _, err := ParseVersion(name); err != nil {
+			return "", err
+		}
+		return resolvePartial(cfg, name)
+	}
+	opts, err := cfg.Options()
+	if err != nil {
+		return "", err
+	}
+	d, err := NewDownloader(opts)
+	if err != nil {
+		return "", err
+	}
+	return newestLatest(context.Background(), d.Catalog(), cfg.Locator())
+}
+
+// errNoDefault reports that no default release is set.
+var errNoDefault = errors.New("no default release is set; run 'dl default <release, such as go1.22.7>'")
+
+// sameFile reports whether the files a and b exist and are the same file.
+func sameFile(a, b string) bool {
+	fa, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	fb, err := os.Stat(b)
+	return err == nil && os.SameFile(fa, fb)
+}
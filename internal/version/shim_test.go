@@ -0,0 +1,180 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIsShimName(t *testing.T) {
+	for arg0, want := range map[string]bool{
+		"go":                  true,
+		"/home/u/go/bin/go":   true,
+		"GO.EXE":              true,
+		"dl":                  false,
+		"/usr/local/bin/dl":   false,
+		"gotip":               false,
+		"go1.22.7":            false,
+		"/home/u/go/bin/go.s": false,
+	} {
+		if got := isShimName(arg0); got != want {
+			t.Errorf("isShimName(%q) = %v; want %v", arg0, got, want)
+		}
+	}
+}
+
+func TestGoBinDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("GOBIN", "")
+	t.Setenv("GOPATH", "")
+	if got, _ := goBinDir(); got != filepath.Join(home, "go", "bin") {
+		t.Errorf("goBinDir() = %s; want ~/go/bin", got)
+	}
+	gopath := t.TempDir()
+	t.Setenv("GOPATH", gopath+string(filepath.ListSeparator)+home)
+	if got, _ := goBinDir(); got != filepath.Join(gopath, "bin") {
+		t.Errorf("goBinDir() = %s; want the first GOPATH entry's bin", got)
+	}
+	t.Setenv("GOBIN", "/opt/bin")
+	if got, _ := goBinDir(); got != "/opt/bin" {
+		t.Errorf("goBinDir() = %s; want GOBIN", got)
+	}
+}
+
+func TestInstallShim(t *testing.T) {
+	dir := t.TempDir()
+	self := filepath.Join(dir, "dl")
+	if err := ioutil.WriteFile(self, append([]byte("build info: "), shimMarker...), 0755); err != nil {
+		t.Fatal(err)
+	}
+	shim := filepath.Join(dir, "bin", "go"+exe())
+	if err := installShim(shim, self, false); err != nil {
+		t.Fatal(err)
+	}
+	if !isShim(shim) {
+		t.Fatalf("installShim left no shim at %s", shim)
+	}
+	// A shim is replaced, as by an updated dl.
+	if err := installShim(shim, self, false); err != nil {
+		t.Errorf("replacing the shim: %v", err)
+	}
+
+	// Any other go command is left alone, unless forced.
+	if err := ioutil.WriteFile(shim, []byte("a real go command"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := installShim(shim, self, false); err == nil || !strings.Contains(err.Error(), "-force") {
+		t.Errorf("installShim over a go command = %v; want an error suggesting -force", err)
+	}
+	if removed, err := removeShim(shim); removed || err != nil {
+		t.Errorf("removeShim of a go command = %v, %v; want false, nil", removed, err)
+	}
+	if err := installShim(shim, self, true); err != nil || !isShim(shim) {
+		t.Errorf("installShim -force = %v; want the shim installed", err)
+	}
+
+	if removed, err := removeShim(shim); !removed || err != nil {
+		t.Errorf("removeShim = %v, %v; want true, nil", removed, err)
+	}
+	if _, err := os.Stat(shim); !os.IsNotExist(err) {
+		t.Errorf("the shim is still there after removeShim")
+	}
+}
+
+func TestSaveDefault(t *testing.T) {
+	c, err := LoadConfig(writeConfig(t, "offline = true\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"gotip", "1.22"} {
+		if err := c.saveDefault(name); err == nil {
+			t.Errorf("saveDefault(%q) succeeded; want an error", name)
+		}
+	}
+	if err := c.saveDefault("go1.22"); err != nil {
+		t.Fatal(err)
+	}
+	c, err = LoadConfig(c.File)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.DefaultRelease(); got != "go1.22" {
+		t.Errorf("DefaultRelease() = %q after saving go1.22", got)
+	}
+	if err := c.saveDefault(""); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := ioutil.ReadFile(c.File)
+	if got := string(data); got != "offline = true\n" || c.DefaultRelease() != "" {
+		t.Errorf("after removing the default, the config file is %q", got)
+	}
+}
+
+func TestParseGoVersion(t *testing.T) {
+	tests := []struct {
+		data string
+		want string // or, if it starts with !, the error
+	}{
+		{"1.22.7\n", "go1.22.7"},
+		{"go1.22.7", "go1.22.7"},
+		{"# pinned for the team\n\n  go1.22 \n", "go1.22"},
+		{"latest\n", "latest"},
+		{"work\n", "work"},
+		{"1.23rc1\r\n", "go1.23rc1"},
+		{"", "!names no release"},
+		{"# nothing\n", "!names no release"},
+		{"gotip\n", "!gotip can't be"},
+		{"1.x\n", "!"},
+	}
+	for _, tt := range tests {
+		got, err := parseGoVersion([]byte(tt.data))
+		if strings.HasPrefix(tt.want, "!") {
+			if err == nil || !strings.Contains(err.Error(), tt.want[1:]) {
+				t.Errorf("parseGoVersion(%q) = %q, %v; want error containing %q", tt.data, got, err, tt.want[1:])
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("parseGoVersion(%q) = %q, %v; want %s", tt.data, got, err, tt.want)
+		}
+	}
+}
+
+func TestFindGoVersion(t *testing.T) {
+	root := t.TempDir()
+	makeTree(t, root, map[string]string{
+		"repo/" + goVersionFile:        "1.22.7\n",
+		"repo/sub/pkg/x.go":            "package x",
+		"repo/other/" + goVersionFile:  "go1.21.13\n",
+		"repo/broken/" + goVersionFile: "\n",
+		"unpinned/x.go":                "package x",
+	})
+	tests := []struct {
+		dir, name, file string
+	}{
+		{"repo", "go1.22.7", "repo/" + goVersionFile},
+		{"repo/sub/pkg", "go1.22.7", "repo/" + goVersionFile},
+		{"repo/other", "go1.21.13", "repo/other/" + goVersionFile},
+	}
+	for _, tt := range tests {
+		name, file, err := findGoVersion(filepath.Join(root, filepath.FromSlash(tt.dir)))
+		if err != nil || name != tt.name || file != filepath.Join(root, filepath.FromSlash(tt.file)) {
+			t.Errorf("findGoVersion(%s) = %q, %q, %v; want %s from %s", tt.dir, name, file, err, tt.name, tt.file)
+		}
+	}
+	if _, _, err := findGoVersion(filepath.Join(root, "repo", "broken")); err == nil || !strings.Contains(err.Error(), goVersionFile) {
+		t.Errorf("findGoVersion of a broken file = %v; want an error naming it", err)
+	}
+	// The temporary directory is not expected to be below a .go-version file.
+	if name, file, err := findGoVersion(filepath.Join(root, "unpinned")); err != nil || name != "" || file != "" {
+		t.Errorf("findGoVersion(unpinned) = %q, %q, %v; want none", name, file, err)
+	}
+}
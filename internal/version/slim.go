@@ -0,0 +1,42 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import "strings"
+
+// slimDirs are the top-level directories of a GOROOT that a slim install
+// leaves out: the compiler and runtime tests, the documentation, and the
+// API data that only the release process checks against.
+var slimDirs = []string{"test", "doc", "api"}
+
+// slimKeep reports whether a slim install unpacks the archive entry at
+// rel, a slash-separated path relative to the GOROOT. It leaves out
+// slimDirs, and the tests of the standard library and commands under src,
+// with their testdata: none of them is read to build or run Go programs,
+// and together they are about a third of a release.
+func slimKeep(rel string) bool {
+	rel = strings.TrimSuffix(rel, "/")
+	for _, dir := range slimDirs {
+		if rel == dir || strings.HasPrefix(rel, dir+"/") {
+			return false
+		}
+	}
+	if strings.HasPrefix(rel, "src/") {
+		if strings.HasSuffix(rel, "_test.go") || strings.HasSuffix(rel, "/testdata") || strings.Contains(rel, "/testdata/") {
+			return false
+		}
+	}
+	return true
+}
+
+// unpackFilter returns the function deciding which entries of an archive
+// to unpack, by their path relative to the GOROOT: slimKeep for a slim
+// install, and otherwise nil, for all of them.
+func unpackFilter(slim bool) func(rel string) bool {
+	if slim {
+		return slimKeep
+	}
+	return nil
+}
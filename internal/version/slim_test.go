@@ -0,0 +1,95 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSlimKeep(t *testing.T) {
+	for rel, want := range map[string]bool{
+		"":                                 true,
+		"bin/go":                           true,
+		"src/strings/builder.go":           true,
+		"src/cmd/go/main.go":               true,
+		"misc/wasm/wasm_exec.js":           true,
+		"testing/x":                        true,
+		"test/":                            false,
+		"test/fixedbugs/issue1.go":         false,
+		"doc/go_spec.html":                 false,
+		"api/go1.txt":                      false,
+		"api":                              false,
+		"src/strings/builder_test.go":      false,
+		"src/image/png/testdata/":          false,
+		"src/image/png/testdata/a.png":     false,
+		"src/cmd/go/testdata/script/a.txt": false,
+		"pkg/tool/linux_amd64/compile":     true,
+	} {
+		if got := slimKeep(rel); got != want {
+			t.Errorf("slimKeep(%q) = %v; want %v", rel, got, want)
+		}
+	}
+}
+
+func TestSlimInstall(t *testing.T) {
+	files := map[string]string{
+		"go/VERSION":                   "go1.99",
+		"go/bin/go":                    "#!/bin/sh\necho fake go\n",
+		"go/src/a/a.go":                "package a\n",
+		"go/src/a/a_test.go":           "package a\n",
+		"go/src/a/testdata/x.txt":      "x\n",
+		"go/test/run.go":               "package main\n",
+		"go/doc/go_spec.html":          "<html>\n",
+		"go/api/go1.txt":               "pkg a\n",
+		"go/misc/wasm/wasm_exec.js":    "\n",
+		"go/pkg/tool/x/compile":        "#!/bin/sh\n",
+		"go/lib/time/zoneinfo.zip":     "zip\n",
+		"go/src/cmd/go/testdata/a.txt": "a\n",
+	}
+	check := func(what, root string) {
+		t.Helper()
+		for name := range files {
+			rel := strings.TrimPrefix(name, "go/")
+			_, err := os.Stat(filepath.Join(root, filepath.FromSlash(rel)))
+			if kept := err == nil; kept != slimKeep(rel) {
+				t.Errorf("%s: %s unpacked = %v; want %v", what, rel, kept, !kept)
+			}
+		}
+		for _, dir := range []string{"test", "doc", "api", "src/a/testdata"} {
+			if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(dir))); err == nil {
+				t.Errorf("%s: %s was made", what, dir)
+			}
+		}
+	}
+
+	ts := newTestServer(t)
+	ts.tar = makeTestArchive(t, files, false)
+	ts.zip = makeTestArchive(t, files, true)
+	d := ts.downloader(t, DownloaderOptions{Slim: true})
+	root := filepath.Join(t.TempDir(), "go1.99")
+	p, err := d.plan(context.Background(), root, "go1.99")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(p.String(), "without tests, docs and API data") {
+		t.Errorf("plan = %v; want the unpack step to leave things out", p)
+	}
+	if err := d.Execute(context.Background(), p); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	check("install", root)
+
+	zipFile := filepath.Join(t.TempDir(), "go1.99.zip")
+	writeTestFile(t, zipFile, ts.zip)
+	root = t.TempDir()
+	if _, err := unpackZip(context.Background(), root, zipFile, nil, nil, &entrySet{}, slimKeep); err != nil {
+		t.Fatal(err)
+	}
+	check("zip", root)
+}
@@ -0,0 +1,40 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// smokeTimeout bounds running the installed go command.
+const smokeTimeout = 30 * time.Second
+
+// smokeTest runs "go version" from the toolchain in root, for goos/goarch,
+// to check that this machine can execute it. Only a binary the system
+// won't execute at all fails the test; other failures are logged.
+func smokeTest(ctx context.Context, root, goos, goarch string) error {
+	ctx, cancel := context.WithTimeout(ctx, smokeTimeout)
+	defer cancel()
+	cmd := goCommand(ctx, root, "version")
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	if isExecFormatError(err) {
+		return fmt.Errorf("the installed %s/%s go command can't run on this machine: %v; set %s to install a different architecture", goos, goarch, err, envGOARCH)
+	}
+	if errors.Is(err, os.ErrPermission) {
+		return fmt.Errorf("programs in %s are not allowed to run: %v", root, err)
+	}
+	log.Printf("Warning: %s version failed: %v %s", filepath.Join(root, "bin", "go"+exe()), err, strings.TrimSpace(string(out)))
+	return nil
+}
@@ -0,0 +1,13 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import "strings"
+
+// isExecFormatError reports whether err is from executing a file that is
+// not a program for this machine.
+func isExecFormatError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "exec header invalid")
+}
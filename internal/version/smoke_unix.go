@@ -0,0 +1,19 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build aix || darwin || dragonfly || freebsd || js || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd js linux netbsd openbsd solaris
+
+package version
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isExecFormatError reports whether err is from executing a file that is
+// not a program for this machine.
+func isExecFormatError(err error) bool {
+	return errors.Is(err, syscall.ENOEXEC)
+}
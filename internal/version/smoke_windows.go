@@ -0,0 +1,20 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"errors"
+	"syscall"
+)
+
+// errorBadExeFormat is ERROR_BAD_EXE_FORMAT, "%1 is not a valid Win32
+// application".
+const errorBadExeFormat syscall.Errno = 193
+
+// isExecFormatError reports whether err is from executing a file that is
+// not a program for this machine.
+func isExecFormatError(err error) bool {
+	return errors.Is(err, errorBadExeFormat)
+}
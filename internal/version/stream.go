@@ -0,0 +1,133 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"time"
+)
+
+// stream carries out the stream step s: it downloads the tar.gz archive
+// at s.URL and unpacks it into s.Target as it arrives, hashing it on the
+// way, then checks the hash as the verify step would have. If the archive
+// doesn't match, s.Target is removed, so that nothing unverified is left
+// to be mistaken for an install; it is never marked installed anyway.
+func (d *Downloader) stream(ctx context.Context, s Step) error {
+	// Learn what to expect first, so that an archive without a
+	// checksum is refused before anything is unpacked.
+	wantSHA, source, err := d.expectedChecksum(ctx, s.URL, s.URL, s.Checksum, s.SHA256)
+	if err != nil {
+		return err
+	}
+	var want []byte
+	if wantSHA != "" {
+		if want, err = parseSHA256(wantSHA); err != nil {
+			d.opts.Metrics.verify(VerifyError)
+			return fmt.Errorf("error verifying SHA256 of %v: %w", s.URL, err)
+		}
+	}
+
+	log.Printf("Downloading and unpacking %v into %v ...", s.URL, s.Target)
+	start := time.Now()
+	n, host, sum, progress, err := d.streamOnce(ctx, s)
+	// Like a download, a stream that broke off is retried, though from
+	// the start, unpacking over what it unpacked before.
+	for attempt := 0; err != nil && attempt < d.opts.Retries && retryable(ctx, err); attempt++ {
+		delay := d.retryPolicy().delay(attempt, 0)
+		log.Printf("Note: downloading %v: %v; retrying in %v (%d of %d)", s.URL, err, delay.Round(100*time.Millisecond), attempt+1, d.opts.Retries)
+		d.opts.Metrics.retry(s.URL, attempt+1, delay, err)
+		if err = sleepContext(ctx, delay); err != nil {
+			break
+		}
+		var m int64
+		m, host, sum, progress, err = d.streamOnce(ctx, s)
+		n += m
+	}
+	elapsed := time.Since(start)
+	d.opts.Metrics.download(host, n, elapsed, err)
+	d.opts.Metrics.unpack(progress, elapsed, err)
+	if err != nil {
+		if ctx.Err() != nil {
+			d.abandonUnpack(s.Target, "")
+		}
+		return fmt.Errorf("error downloading and unpacking %v: %w", s.URL, err)
+	}
+	verbosef("Downloaded %s and unpacked %d files, %s, into %v in %v", formatByteSize(n), progress.Files, formatByteSize(progress.Bytes), s.Target, elapsed.Round(time.Millisecond))
+
+	if want == nil {
+		clearQuarantine(s.Target)
+		return nil
+	}
+	if subtle.ConstantTimeCompare(sum, want) != 1 {
+		err = &ChecksumError{File: s.URL, Want: hex.EncodeToString(want), Got: hex.EncodeToString(sum)}
+	}
+	d.emitter().emit(VerificationResult{File: s.URL, SHA256: wantSHA, Err: err})
+	if err != nil {
+		d.opts.Metrics.verify(VerifyMismatch)
+		if rmErr := removeInstall(s.Target); rmErr != nil {
+			log.Printf("Note: could not remove what was unpacked from %v: %v", s.URL, rmErr)
+		}
+		return fmt.Errorf("error verifying SHA256 of %v: %w", s.URL, err)
+	}
+	verbosef("Verified SHA256 %s of %v, from %s", wantSHA, s.URL, source)
+	d.opts.Metrics.verify(VerifyOK)
+	clearQuarantine(s.Target)
+	return nil
+}
+
+// streamOnce makes one attempt at the stream step s, returning the number
+// of bytes downloaded, the host that sent them, their SHA-256 digest and
+// what was unpacked.
+func (d *Downloader) streamOnce(ctx context.Context, s Step) (n int64, host string, sum []byte, progress UnpackProgress, err error) {
+	host = hostOf(s.URL)
+	res, err := d.do(ctx, http.MethodGet, s.URL)
+	if err != nil {
+		return 0, host, nil, progress, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	host = responseHost(res, s.URL)
+	if res.StatusCode != http.StatusOK {
+		return 0, host, nil, progress, &statusError{URL: s.URL, Code: res.StatusCode, Status: res.Status}
+	}
+	seen, err := newEntrySet(s.Target)
+	if err != nil {
+		return 0, host, nil, progress, err
+	}
+
+	var body io.Reader = res.Body
+	if d.opts.MaxRate > 0 {
+		body = newRateLimitedReader(ctx, body, d.opts.MaxRate)
+	}
+	// The hash sees every byte downloaded, read by the unpacker or not;
+	// the meter shows the download along with what has been unpacked.
+	h := sha256.New()
+	pw := &progressWriter{w: h, total: res.ContentLength, url: s.URL, em: d.emitter()}
+	r := &countingReader{r: io.TeeReader(body, pw)}
+	m := d.newMeter("Downloaded", res.ContentLength, 0, true)
+	defer m.stop()
+	progress, err = untarGz(ctx, s.Target, r, d.emitter(), m, seen, unpackFilter(s.Slim))
+	if err != nil {
+		return r.count(), host, nil, progress, err
+	}
+	// The tar archive ends before the gzip stream and the download do.
+	if _, err := io.Copy(ioutil.Discard, r); err != nil {
+		return r.count(), host, nil, progress, err
+	}
+	if res.ContentLength != -1 && r.count() != res.ContentLength {
+		return r.count(), host, nil, progress, fmt.Errorf("copied %v bytes; expected %v", r.count(), res.ContentLength)
+	}
+	pw.em.emit(DownloadProgress{URL: s.URL, Bytes: pw.n, Total: pw.total})
+	return r.count(), host, h.Sum(nil), progress, nil
+}
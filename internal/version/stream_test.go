@@ -0,0 +1,86 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStream(t *testing.T) {
+	ts := newTestServer(t)
+	ctx := context.Background()
+	d := ts.downloader(t, DownloaderOptions{Stream: true})
+	root := filepath.Join(t.TempDir(), "go1.99")
+	p, err := d.plan(ctx, root, "go1.99")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(p.URL, ".tar.gz") {
+		t.Skipf("%s archives are not streamed", p.URL)
+	}
+	var kinds []StepKind
+	for _, s := range p.Steps {
+		kinds = append(kinds, s.Kind)
+	}
+	if want := []StepKind{StepStream, StepMarkInstalled}; !reflect.DeepEqual(kinds, want) {
+		t.Fatalf("plan steps = %v; want %v", kinds, want)
+	}
+	if err := d.Execute(ctx, p); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, unpackedOkay)); err != nil {
+		t.Errorf("streamed install isn't marked complete: %v", err)
+	}
+	fis, _ := ioutil.ReadDir(root)
+	for _, fi := range fis {
+		if strings.Contains(fi.Name(), ".tar.gz") {
+			t.Errorf("streamed install left %s on disk", fi.Name())
+		}
+	}
+
+	// The checksum is fetched first, and the archive only once.
+	name := filepath.Base(p.URL)
+	var gets []string
+	for _, r := range ts.requests() {
+		if strings.HasPrefix(r, "GET ") && strings.Contains(r, name) {
+			gets = append(gets, path.Base(r))
+		}
+	}
+	if want := []string{name + ".sha256", name}; !reflect.DeepEqual(gets, want) {
+		t.Errorf("GET requests = %q; want %q", gets, want)
+	}
+
+	// An archive that doesn't match its checksum leaves nothing behind.
+	bad := filepath.Join(t.TempDir(), "go1.99")
+	p, err = d.plan(ctx, bad, "go1.99")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Steps[0].SHA256 = strings.Repeat("0", 64)
+	var ce *ChecksumError
+	if err := d.Execute(ctx, p); !errors.As(err, &ce) {
+		t.Fatalf("Execute with the wrong checksum = %v; want a checksum error", err)
+	}
+	if _, err := os.Stat(bad); !os.IsNotExist(err) {
+		t.Errorf("Execute with the wrong checksum left %s: %v", bad, err)
+	}
+
+	// With a cache, the archive is kept there and not streamed.
+	d = ts.downloader(t, DownloaderOptions{Stream: true, CacheDir: t.TempDir()})
+	if p, err = d.plan(ctx, t.TempDir(), "go1.99"); err != nil {
+		t.Fatal(err)
+	}
+	if hasStep(p, StepStream) {
+		t.Errorf("plan with a cache streams the archive")
+	}
+}
@@ -0,0 +1,231 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// defaultSumDB is the checksum database module zips are verified with
+// unless SumDB names another.
+const defaultSumDB = "sum.golang.org"
+
+// knownSumDBs holds the verifier keys of the checksum databases that can
+// be named without one, as the go command knows them.
+var knownSumDBs = map[string]string{
+	"sum.golang.org":       "sum.golang.org+033de0ae+Ac4zctda0e5eza+HJyk9SxEdh+s3Ux18htTTAD8OuAn8",
+	"sum.golang.google.cn": "sum.golang.org+033de0ae+Ac4zctda0e5eza+HJyk9SxEdh+s3Ux18htTTAD8OuAn8 https://sum.golang.google.cn",
+}
+
+// A sumDB is a checksum database: what signs its tree heads, and where
+// it is served.
+type sumDB struct {
+	name string
+	hash uint32 // of the key, which identifies it in signatures
+	key  ed25519.PublicKey
+	url  string // without a trailing slash
+}
+
+// parseSumDB parses the SumDB option, which is written as GOSUMDB is: a
+// known database's name, a verifier key "name+hash+key", either followed
+// by the URL it is served at if that isn't https://name, or "off". It
+// returns nil for "off".
+func parseSumDB(s string) (*sumDB, error) {
+	s = strings.TrimSpace(s)
+	switch s {
+	case "":
+		s = defaultSumDB
+	case "off":
+		return nil, nil
+	}
+	f := strings.Fields(s)
+	if len(f) > 2 {
+		return nil, fmt.Errorf("invalid checksum database %q: want a name or verifier key, and optionally its URL", s)
+	}
+	// A known database can be named alone, or with a URL of its own.
+	if known, ok := knownSumDBs[f[0]]; ok {
+		f = append(strings.Fields(known), f[1:]...)
+		if len(f) == 3 {
+			f = []string{f[0], f[2]}
+		}
+	}
+	db, err := parseVerifierKey(f[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid checksum database %q: %v", s, err)
+	}
+	db.url = "https://" + db.name
+	if len(f) == 2 {
+		if err := checkCatalogURL(f[1]); err != nil {
+			return nil, fmt.Errorf("invalid checksum database URL %q: must be an absolute http or https URL", f[1])
+		}
+		db.url = strings.TrimSuffix(f[1], "/")
+	}
+	return db, nil
+}
+
+// parseVerifierKey parses a note verifier key, "name+hash+key", where hash
+// is the first four bytes, in hex, of the SHA-256 of the name, a newline
+// and key, and key is an Ed25519 public key, in base64 after a byte
+// naming the algorithm.
+func parseVerifierKey(vkey string) (*sumDB, error) {
+	f := strings.SplitN(vkey, "+", 3)
+	if len(f) != 3 || f[0] == "" {
+		return nil, errors.New("malformed verifier key")
+	}
+	hash, err := hex.DecodeString(f[1])
+	if err != nil || len(hash) != 4 {
+		return nil, errors.New("malformed verifier key hash")
+	}
+	key, err := base64.StdEncoding.DecodeString(f[2])
+	if err != nil || len(key) != 1+ed25519.PublicKeySize || key[0] != 1 {
+		return nil, errors.New("malformed or non-Ed25519 verifier key")
+	}
+	db := &sumDB{name: f[0], hash: binary.BigEndian.Uint32(hash), key: ed25519.PublicKey(key[1:])}
+	if db.hash != keyHash(db.name, key) {
+		return nil, errors.New("verifier key hash doesn't match the key")
+	}
+	return db, nil
+}
+
+// keyHash returns the hash that identifies the key, including its
+// algorithm byte, of the database name in signatures.
+func keyHash(name string, key []byte) uint32 {
+	h := sha256.New()
+	h.Write([]byte(name + "\n"))
+	h.Write(key)
+	return binary.BigEndian.Uint32(h.Sum(nil))
+}
+
+// errNoRecord reports that the checksum database has no record of a
+// module version.
+var errNoRecord = errors.New("no record in the checksum database")
+
+// lookup returns the go.sum hash, "h1:" and base64, that db records for
+// version of module mod. It asks the module proxy, if proxy is set, which
+// may mirror db as the go command expects, and db itself when the proxy
+// doesn't. The lookup's tree head must be signed by db; as dl keeps no
+// record of the tree, the record's inclusion in it isn't proved, which
+// leaves the database, reached over https, trusted to serve the record
+// it logged.
+func (d *Downloader) lookup(ctx context.Context, db *sumDB, proxy, mod, version string) (string, error) {
+	path := "/lookup/" + mod + "@" + version
+	var urls []string
+	if proxy != "" {
+		urls = append(urls, proxy+"/sumdb/"+db.name+path)
+	}
+	urls = append(urls, db.url+path)
+	var body string
+	var err error
+	for _, u := range urls {
+		body, err = d.slurpURLToString(ctx, u)
+		if !isNotFound(err) && !isGone(err) {
+			break
+		}
+	}
+	if isNotFound(err) || isGone(err) {
+		return "", fmt.Errorf("%s@%s: %w", mod, version, errNoRecord)
+	}
+	if err != nil {
+		return "", fmt.Errorf("looking up %s@%s in %s: %w", mod, version, db.name, err)
+	}
+	i := strings.Index(body, "\n\n")
+	if i < 0 {
+		return "", fmt.Errorf("looking up %s@%s in %s: malformed reply", mod, version, db.name)
+	}
+	records, note := body[:i+1], body[i+2:]
+	if err := db.verifyNote(note); err != nil {
+		return "", fmt.Errorf("looking up %s@%s in %s: %v", mod, version, db.name, err)
+	}
+	for _, line := range strings.Split(records, "\n") {
+		if f := strings.Fields(line); len(f) == 3 && f[0] == mod && f[1] == version && strings.HasPrefix(f[2], "h1:") {
+			return f[2], nil
+		}
+	}
+	return "", fmt.Errorf("looking up %s@%s in %s: the reply has no go.sum hash for it", mod, version, db.name)
+}
+
+// isGone reports whether err is a 410 response, which the checksum
+// database and module proxies send for what they don't have.
+func isGone(err error) bool {
+	var se *statusError
+	return errors.As(err, &se) && se.Code == http.StatusGone
+}
+
+// verifyNote checks that the signed note, a tree head, is signed by db.
+// A note is its text, a blank line, and signature lines of the form
+// "— name base64", where the base64 holds the signer's key hash and the
+// Ed25519 signature of the text.
+func (db *sumDB) verifyNote(note string) error {
+	i := strings.LastIndex(note, "\n\n")
+	if i < 0 || !strings.HasPrefix(note, "go.sum database tree\n") {
+		return errors.New("malformed tree head")
+	}
+	text := note[:i+1]
+	for _, line := range strings.Split(note[i+2:], "\n") {
+		if !strings.HasPrefix(line, "— ") {
+			continue
+		}
+		f := strings.Fields(strings.TrimPrefix(line, "— "))
+		if len(f) != 2 || f[0] != db.name {
+			continue
+		}
+		sig, err := base64.StdEncoding.DecodeString(f[1])
+		if err != nil || len(sig) != 4+ed25519.SignatureSize || binary.BigEndian.Uint32(sig) != db.hash {
+			continue
+		}
+		if ed25519.Verify(db.key, []byte(text), sig[4:]) {
+			return nil
+		}
+		return errors.New("tree head signature doesn't verify")
+	}
+	return fmt.Errorf("tree head isn't signed by %s", db.name)
+}
+
+// hashZip returns the go.sum hash, "h1:" and base64, of the module zip
+// file, as the go command computes it: the SHA-256 of a summary listing
+// the SHA-256 of each file in the zip with its name, in name order.
+func hashZip(file string) (string, error) {
+	zr, err := zip.OpenReader(file)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = zr.Close()
+	}()
+	files := append([]*zip.File(nil), zr.File...)
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+	var summary bytes.Buffer
+	for _, f := range files {
+		if strings.Contains(f.Name, "\n") {
+			return "", fmt.Errorf("file name %q in %s contains a newline", f.Name, file)
+		}
+		r, err := f.Open()
+		if err != nil {
+			return "", err
+		}
+		h := sha256.New()
+		_, err = io.Copy(h, r)
+		_ = r.Close()
+		if err != nil {
+			return "", fmt.Errorf("reading %s in %s: %w", f.Name, file, err)
+		}
+		fmt.Fprintf(&summary, "%x  %s\n", h.Sum(nil), f.Name)
+	}
+	sum := sha256.Sum256(summary.Bytes())
+	return "h1:" + base64.StdEncoding.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// VersionsLockName is the name of the lockfile that dl lock writes when
+// given several releases, and dl sync reads, in the current directory.
+const VersionsLockName = "versions.lock"
+
+// A VersionsLock pins every release a project needs, such as the matrix
+// a monorepo tests against, each as a Lockfile pins one. Its text form is
+// that of a Lockfile with a go line for each release, followed by the
+// archive lines that pin it:
+//
+//	format 1
+//	go go1.21.13
+//	archive linux/amd64 go1.21.13.linux-amd64.tar.gz 1a2b...
+//	go go1.22.7
+//	archive linux/amd64 go1.22.7.linux-amd64.tar.gz 4a7b...
+type VersionsLock struct {
+	Format   int
+	Releases []*Lockfile
+}
+
+// NewVersionsLock returns a VersionsLock pinning the binary archives of
+// rs, in their order.
+func NewVersionsLock(rs []Release) *VersionsLock {
+	vl := &VersionsLock{Format: LockfileFormat}
+	for _, r := range rs {
+		vl.Releases = append(vl.Releases, NewLockfile(r))
+	}
+	return vl
+}
+
+// ParseVersionsLock parses the text form of a VersionsLock. It rejects
+// what ParseLockfile does, and releases listed twice.
+func ParseVersionsLock(data []byte) (*VersionsLock, error) {
+	ls, err := parseLocks(VersionsLockName, data, true)
+	if err != nil {
+		return nil, err
+	}
+	return &VersionsLock{Format: ls[0].Format, Releases: ls}, nil
+}
+
+// Bytes returns the text form of vl.
+func (vl *VersionsLock) Bytes() []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# Generated by 'dl lock'. 'dl sync' installs exactly these.\n")
+	fmt.Fprintf(&b, "format %d\n", vl.Format)
+	for _, l := range vl.Releases {
+		fmt.Fprintf(&b, "\ngo %s\n", l.Go)
+		for _, a := range l.Archives {
+			fmt.Fprintf(&b, "archive %s/%s %s %s\n", a.OS, a.Arch, a.Filename, a.SHA256)
+		}
+	}
+	return b.Bytes()
+}
+
+// Sync statuses.
+const (
+	SyncOK         = "ok"
+	SyncMissing    = "missing"
+	SyncMismatched = "mismatched" // installed from another archive than the pinned one
+	SyncInstalled  = "installed"
+	SyncFailed     = "failed"
+)
+
+// A SyncResult reports the state of one release of a VersionsLock, for
+// dl sync.
+type SyncResult struct {
+	Toolchain string
+	Status    string
+	Note      string
+}
+
+// checkSync compares the releases vl pins with those installed under l on
+// this platform, goos/goarch. An installed release is mismatched if the
+// journal records that it was installed from an archive with another
+// checksum than the one pinned, or if vl pins no archive for this
+// platform; one installed without a recorded checksum can't be checked,
+// and is taken to be the pinned one.
+func checkSync(l *Locator, vl *VersionsLock, journal []JournalEntry, goos, goarch string) []SyncResult {
+	var results []SyncResult
+	for _, lf := range vl.Releases {
+		v := lf.Go.String()
+		r := SyncResult{Toolchain: v, Status: SyncOK}
+		loc := localInfo(l, v, journal, "")
+		a, pinned := lf.Archive(goos, goarch)
+		switch {
+		case !loc.Installed:
+			r.Status = SyncMissing
+		case !pinned:
+			r.Status = SyncMismatched
+			r.Note = fmt.Sprintf("%s pins no %s/%s archive", VersionsLockName, goos, goarch)
+		case loc.SHA256 == "" || loc.Platform != goos+"/"+goarch:
+			r.Note = "installed without a recorded checksum, so unchecked"
+		case !strings.EqualFold(loc.SHA256, a.SHA256):
+			r.Status = SyncMismatched
+			r.Note = fmt.Sprintf("installed from an archive with SHA-256 %s, not %s", loc.SHA256, a.SHA256)
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// writeSync writes results to w in the form dl sync prints them.
+func writeSync(w io.Writer, results []SyncResult) {
+	for _, r := range results {
+		fmt.Fprintf(w, "%-10s  %s", r.Status, r.Toolchain)
+		if r.Note != "" {
+			fmt.Fprintf(w, ": %s", r.Note)
+		}
+		fmt.Fprintln(w)
+	}
+}
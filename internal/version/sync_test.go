@@ -0,0 +1,105 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestVersionsLockRoundTrip(t *testing.T) {
+	var rs []Release
+	for _, name := range []string{"go1.21.13", "go1.22.7"} {
+		v, err := ParseVersion(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rs = append(rs, Release{Version: v, Files: []File{
+			{Filename: name + ".linux-amd64.tar.gz", OS: "linux", Arch: "amd64", Kind: "archive", SHA256: strings.Repeat("a", 64)},
+			{Filename: name + ".src.tar.gz", Kind: "source", SHA256: strings.Repeat("b", 64)},
+		}})
+	}
+	vl := NewVersionsLock(rs)
+	parsed, err := ParseVersionsLock(vl.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed, vl) {
+		t.Errorf("ParseVersionsLock(%q) = %+v; want %+v", vl.Bytes(), parsed, vl)
+	}
+
+	// A godl.lock is a versions.lock of one release.
+	l := NewLockfile(rs[1])
+	parsed, err = ParseVersionsLock(l.Bytes())
+	if err != nil || len(parsed.Releases) != 1 || !reflect.DeepEqual(parsed.Releases[0], l) {
+		t.Errorf("ParseVersionsLock(%q) = %+v, %v; want just %+v", l.Bytes(), parsed, err, l)
+	}
+}
+
+func TestParseVersionsLockErrors(t *testing.T) {
+	sum := strings.Repeat("a", 64)
+	tests := []struct {
+		text, err string
+	}{
+		{"format 1\n", "missing go line"},
+		{"format 1\narchive linux/amd64 go1.22.7.linux-amd64.tar.gz " + sum + "\ngo go1.22.7\n", "versions.lock:2: archive line before any go line"},
+		{"format 1\ngo go1.22.7\ngo go1.21.13\ngo go1.22.7\n", "versions.lock:4: go1.22.7 is listed twice"},
+		{"format 1\ngo latest\n", "not an exact release name"},
+	}
+	for _, tt := range tests {
+		if _, err := ParseVersionsLock([]byte(tt.text)); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("ParseVersionsLock(%q) = %v; want error containing %q", tt.text, err, tt.err)
+		}
+	}
+}
+
+func TestCheckSync(t *testing.T) {
+	sdk := t.TempDir()
+	makeTree(t, sdk, map[string]string{
+		"go1.20.14/" + unpackedOkay: "",
+		"go1.21.13/" + unpackedOkay: "",
+		"go1.22.7/" + unpackedOkay:  "",
+		"go1.23.1/" + unpackedOkay:  "",
+	})
+	pinned, other := strings.Repeat("a", 64), strings.Repeat("c", 64)
+	lock := func(name string, platforms ...string) *Lockfile {
+		v, err := ParseVersion(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		l := &Lockfile{Format: LockfileFormat, Go: v}
+		for _, p := range platforms {
+			goos, arch := splitPlatform(p)
+			l.Archives = append(l.Archives, LockedArchive{goos, arch, name + "." + goos + "-" + arch + ".tar.gz", pinned})
+		}
+		return l
+	}
+	vl := &VersionsLock{Format: LockfileFormat, Releases: []*Lockfile{
+		lock("go1.20.14", "linux/amd64"),
+		lock("go1.21.13", "linux/amd64"),
+		lock("go1.22.7", "linux/amd64"),
+		lock("go1.23.1", "darwin/arm64"),
+		lock("go1.24.0", "linux/amd64"),
+	}}
+	journal := []JournalEntry{
+		{Toolchain: "go1.21.13", SHA256: pinned, Platform: "linux/amd64"},
+		{Toolchain: "go1.22.7", SHA256: other, Platform: "linux/amd64"},
+	}
+	var got []string
+	for _, r := range checkSync(&Locator{Root: sdk}, vl, journal, "linux", "amd64") {
+		got = append(got, r.Toolchain+" "+r.Status)
+	}
+	want := []string{
+		"go1.20.14 ok", // installed without a recorded checksum
+		"go1.21.13 ok",
+		"go1.22.7 mismatched",
+		"go1.23.1 mismatched", // no pin for this platform
+		"go1.24.0 missing",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("checkSync = %q; want %q", got, want)
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// termuxPrefix returns the installation prefix of Termux, the Android
+// terminal environment, such as /data/data/com.termux/files/usr, if this
+// program runs in it, or else "".
+func termuxPrefix() string {
+	if runtime.GOOS != "android" && runtime.GOOS != "linux" {
+		return ""
+	}
+	p := os.Getenv("PREFIX")
+	if strings.Contains(p, "/com.termux/") || p != "" && os.Getenv("TERMUX_VERSION") != "" {
+		return p
+	}
+	return ""
+}
+
+// tempEnv returns the environment variables that keep the temporary files
+// of commands run by the installer where they are allowed. Android's
+// default temporary directory is not writable by apps, so in Termux they
+// go in Termux's own, unless TMPDIR already says otherwise.
+func tempEnv() []string {
+	if p := termuxPrefix(); p != "" && os.Getenv("TMPDIR") == "" {
+		return []string{"TMPDIR=" + filepath.Join(p, "tmp")}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"bufio"
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestTermux(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "android" {
+		t.Skipf("Termux only runs on Android")
+	}
+	const prefix = "/data/data/com.termux/files/usr"
+	tests := []struct {
+		prefix, version, tmpdir string
+		want                    []string
+	}{
+		{"", "", "", nil},
+		{"/usr/local", "", "", nil},
+		{prefix, "", "", []string{"TMPDIR=" + prefix + "/tmp"}},
+		{"/data/data/com.example.termux/files/usr", "0.118", "", []string{"TMPDIR=/data/data/com.example.termux/files/usr/tmp"}},
+		{prefix, "0.118", "/sdcard/tmp", nil},
+	}
+	for _, tt := range tests {
+		t.Setenv("PREFIX", tt.prefix)
+		t.Setenv("TERMUX_VERSION", tt.version)
+		t.Setenv("TMPDIR", tt.tmpdir)
+		if got := tempEnv(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("tempEnv() with PREFIX=%q TERMUX_VERSION=%q TMPDIR=%q = %q; want %q", tt.prefix, tt.version, tt.tmpdir, got, tt.want)
+		}
+	}
+}
+
+func TestCheckExecAllowed(t *testing.T) {
+	if err := checkExecAllowed(t.TempDir()); err != nil {
+		t.Logf("temporary directory: %v", err)
+	}
+	if runtime.GOOS != "linux" {
+		return
+	}
+	f, err := os.Open("/proc/mounts")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) < 4 || !strings.Contains(","+fields[3]+",", ",noexec,") {
+			continue
+		}
+		if err := checkExecAllowed(fields[1]); err == nil {
+			t.Errorf("checkExecAllowed(%s) = nil for a noexec mount", fields[1])
+		}
+		return
+	}
+	t.Skip("no noexec mount to check")
+}
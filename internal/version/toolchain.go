@@ -0,0 +1,141 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// A Toolchain is a Go toolchain that can be installed and run.
+// Version implements it for published releases, and Tip returns an
+// implementation for the development tree.
+type Toolchain interface {
+	// Name returns the toolchain's command name, such as "go1.22.7"
+	// or "gotip".
+	Name() string
+
+	// GorootPath returns the toolchain's GOROOT, whether or not it is
+	// installed.
+	GorootPath() (string, error)
+
+	// Installed reports whether the toolchain is installed and ready to run.
+	Installed() bool
+
+	// Install downloads the toolchain, or for gotip fetches and builds
+	// the latest development tree.
+	Install(ctx context.Context, opts *DownloaderOptions) error
+
+	// Run runs the toolchain's go command with args, attached to the
+	// standard input, output and error of the current process.
+	Run(ctx context.Context, args ...string) error
+}
+
+// Tip returns the gotip toolchain in the default location.
+func Tip() Toolchain {
+	return defaultLocator.Tip()
+}
+
+func (v Version) locator() *Locator {
+	if v.loc == nil {
+		return defaultLocator
+	}
+	return v.loc
+}
+
+// Name returns the release name, such as "go1.22.7".
+func (v Version) Name() string {
+	return v.String()
+}
+
+// GorootPath returns the directory the release is installed to.
+func (v Version) GorootPath() (string, error) {
+	return v.locator().Goroot(v.String())
+}
+
+// Installed reports whether the release was downloaded and unpacked
+// successfully.
+func (v Version) Installed() bool {
+	root, err := v.GorootPath()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(filepath.Join(root, unpackedOkay))
+	return err == nil
+}
+
+// Install downloads and unpacks the release. It does nothing if the release
+// is already installed.
+func (v Version) Install(ctx context.Context, opts *DownloaderOptions) error {
+	d, err := newDownloaderFrom(opts)
+	if err != nil {
+		return err
+	}
+	return d.Install(ctx, v)
+}
+
+// Run runs the release's go command with args.
+func (v Version) Run(ctx context.Context, args ...string) error {
+	return runToolchain(ctx, v, args)
+}
+
+// tipToolchain is the Toolchain for the gotip development tree.
+type tipToolchain struct {
+	loc *Locator
+}
+
+func (t tipToolchain) Name() string {
+	return "gotip"
+}
+
+func (t tipToolchain) GorootPath() (string, error) {
+	return t.loc.Goroot("gotip")
+}
+
+func (t tipToolchain) Installed() bool {
+	root, err := t.GorootPath()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(filepath.Join(root, "bin", "go"+exe()))
+	return err == nil
+}
+
+// Install updates the development tree to the latest master and builds it.
+// The tree is fetched with git, so only the Events, ProgressJSON, Metrics,
+// Offline, Proxy and MaxRate fields of opts are used.
+func (t tipToolchain) Install(ctx context.Context, opts *DownloaderOptions) error {
+	root, err := t.GorootPath()
+	if err != nil {
+		return err
+	}
+	var em *emitter
+	var m *Metrics
+	if opts != nil {
+		em, m = newProgressEmitter(opts.Events, newJSONProgress(opts.ProgressJSON)), opts.Metrics
+	}
+	return installTip(ctx, root, "", em, m, opts)
+}
+
+func (t tipToolchain) Run(ctx context.Context, args ...string) error {
+	return runToolchain(ctx, t, args)
+}
+
+func runToolchain(ctx context.Context, t Toolchain, args []string) error {
+	if !t.Installed() {
+		return fmt.Errorf("%s: not installed", t.Name())
+	}
+	root, err := t.GorootPath()
+	if err != nil {
+		return err
+	}
+	cmd := goCommand(ctx, root, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
@@ -0,0 +1,125 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// envTrace, set to 1, makes the run path print the command it is about
+// to run to standard error; set to 2, it also prints the command's
+// environment, with secrets redacted.
+const envTrace = "DL_TRACE"
+
+// traceLevel parses the value of DL_TRACE: 0 if it is empty or 0, the
+// level if it is a number, and 1 for anything else, such as true.
+func traceLevel(s string) int {
+	if s == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 1
+	}
+	return n
+}
+
+// traceCommand writes cmd, which is about to run, to w as a line such as
+//
+//	DL_TRACE exec path="/home/gopher/sdk/go1.22.7/bin/go" argv=["go" "version"] dir="/src" GOROOT="/home/gopher/sdk/go1.22.7" PATH="..."
+//
+// and, if level is 2 or more, its environment as a line per variable,
+// sorted, such as
+//
+//	DL_TRACE env GOPROXY="https://proxy.golang.org"
+//
+// Each value is quoted as in Go, and the keys and their order won't
+// change, so that the lines can be searched for in bug reports.
+func traceCommand(w io.Writer, level int, cmd *exec.Cmd) {
+	if level <= 0 {
+		return
+	}
+	dir := cmd.Dir
+	if dir == "" {
+		dir, _ = os.Getwd()
+	}
+	env := cmd.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "DL_TRACE exec path=%q argv=%q dir=%q GOROOT=%q %s=%q\n",
+		cmd.Path, cmd.Args, dir, lookupEnv(env, "GOROOT"), pathVar(), lookupEnv(env, pathVar()))
+	if level >= 2 {
+		env = append([]string(nil), env...)
+		sort.Slice(env, func(i, j int) bool { return env[i] < env[j] })
+		for _, kv := range env {
+			if k, v, ok := cutEnv(kv); ok {
+				fmt.Fprintf(&b, "DL_TRACE env %s=%q\n", k, redactEnv(k, v))
+			}
+		}
+	}
+	io.WriteString(w, b.String())
+}
+
+// lookupEnv returns the value of the last setting of key in env, which
+// is what a child process sees.
+func lookupEnv(env []string, key string) string {
+	v := ""
+	for _, kv := range env {
+		if k, val, ok := cutEnv(kv); ok && envKeyEqual(k, key) {
+			v = val
+		}
+	}
+	return v
+}
+
+// cutEnv splits the setting kv into its name and value. A leading = is
+// part of the name, as in Windows' =C:=C:\.
+func cutEnv(kv string) (k, v string, ok bool) {
+	if kv == "" {
+		return "", "", false
+	}
+	i := strings.Index(kv[1:], "=")
+	if i < 0 {
+		return "", "", false
+	}
+	return kv[:i+1], kv[i+2:], true
+}
+
+func envKeyEqual(a, b string) bool {
+	if caseInsensitiveEnv {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+// secretKeyRE matches the names of environment variables that usually
+// hold secrets.
+var secretKeyRE = regexp.MustCompile(`(?i)TOKEN|SECRET|PASSWORD|PASSWD|PASSPHRASE|CREDENTIAL|COOKIE|PRIVATE_?KEY|API_?KEY|ACCESS_?KEY|AUTH`)
+
+// urlPasswordRE matches the password of the user information in a URL,
+// also in a list of them such as GOPROXY's.
+var urlPasswordRE = regexp.MustCompile(`(://[^/:@\s,|]*):[^/@\s,|]*@`)
+
+// redactEnv returns the value v of the environment variable k to trace:
+// the whole value if k looks like it names a secret, and otherwise v with
+// the passwords of any URLs in it, redacted.
+func redactEnv(k, v string) string {
+	if v == "" {
+		return v
+	}
+	if secretKeyRE.MatchString(k) {
+		return "xxxxx"
+	}
+	return urlPasswordRE.ReplaceAllString(v, "$1:xxxxx@")
+}
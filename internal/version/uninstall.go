@@ -0,0 +1,149 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// maxLocalChanges bounds how many changed files an uninstall refusal
+// names.
+const maxLocalChanges = 5
+
+// A localChangesError reports that a toolchain wasn't removed because
+// files in it were changed or added since it was installed.
+type localChangesError struct {
+	dir   string
+	files []string // relative to dir, at most maxLocalChanges of them
+	more  bool     // whether there are more than files
+}
+
+func (e *localChangesError) Error() string {
+	list := strings.Join(e.files, ", ")
+	if e.more {
+		list += ", ..."
+	}
+	return fmt.Sprintf("%s has local changes (%s); use -force to remove it anyway", e.dir, list)
+}
+
+// localChanges returns the files under the toolchain dir that were changed
+// or added since it was installed, as told by their modification times
+// being later than its install marker's: an install unpacks the files
+// with the archive's times, and writes the marker last. A toolchain
+// without the marker, never completely installed, has no changes to lose.
+func localChanges(dir string) (files []string, more bool, err error) {
+	fi, err := os.Stat(filepath.Join(dir, unpackedOkay))
+	if err != nil {
+		return nil, false, nil
+	}
+	installed := fi.ModTime()
+	errEnough := errors.New("enough changes found")
+	err = filepath.Walk(dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if fi.IsDir() || !fi.ModTime().After(installed) {
+			return nil
+		}
+		if len(files) == maxLocalChanges {
+			more = true
+			return errEnough
+		}
+		rel, _ := filepath.Rel(dir, path)
+		files = append(files, rel)
+		return nil
+	})
+	if err == errEnough {
+		err = nil
+	}
+	return files, more, err
+}
+
+// uninstallDirs returns the directories the release name occupies: under
+// l's SDK root and, if l has its own, under the default one, as
+// whichGoroot searches them.
+func uninstallDirs(l *Locator, name string) ([]string, error) {
+	locs := []*Locator{l}
+	if l != nil && l.Root != "" {
+		locs = append(locs, &Locator{})
+	}
+	var dirs []string
+	for _, l := range locs {
+		v, err := l.Parse(name)
+		if err != nil {
+			return nil, err
+		}
+		root, err := v.GorootPath()
+		if err != nil {
+			continue
+		}
+		if isDir(root) {
+			dirs = append(dirs, root)
+		}
+	}
+	return dirs, nil
+}
+
+// uninstall removes the release name, installed completely or not, from
+// the SDK directories l uses, with any remnants of earlier reinstalls, and
+// its archives from the archive cache cacheDir, if set, and returns the
+// directories it removed. A toolchain with local changes is left alone,
+// with a *localChangesError, unless force is set.
+func uninstall(l *Locator, name, cacheDir string, force bool) ([]string, error) {
+	v, err := ParseVersion(name)
+	if err != nil {
+		return nil, err
+	}
+	dirs, err := uninstallDirs(l, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(dirs) == 0 {
+		return nil, &notInstalledError{name}
+	}
+	if !force {
+		for _, dir := range dirs {
+			files, more, err := localChanges(dir)
+			if err != nil {
+				return nil, err
+			}
+			if len(files) > 0 {
+				return nil, &localChangesError{dir, files, more}
+			}
+		}
+	}
+
+	var removed []string
+	for _, dir := range dirs {
+		if err := removeInstall(dir); err != nil {
+			return removed, err
+		}
+		removeAsideDirs(dir)
+		removed = append(removed, dir)
+		// Only Windows has a PATH of new shells to clean up.
+		if changed, err := removeFromUserPath(filepath.Join(dir, "bin")); err == nil && changed {
+			log.Printf("Removed %s from your PATH.", filepath.Join(dir, "bin"))
+		}
+	}
+
+	if cacheDir != "" && isDir(cacheDir) {
+		unlock, err := lockCache(cacheDir, true, false)
+		if err != nil {
+			log.Printf("Note: the archive cache is in use, so the archives of %s were left in %s: %v", name, cacheDir, err)
+			return removed, nil
+		}
+		defer unlock()
+		if _, err := cleanCache(cacheDir, cacheSelector{version: &v}, time.Now()); err != nil {
+			return removed, err
+		}
+	}
+	return removed, nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// makeInstall makes a toolchain dir as an install leaves it: its files
+// with the archive's old times, then the marker.
+func makeInstall(t *testing.T, dir string) {
+	t.Helper()
+	files := map[string]string{"bin/go": "go", "src/a.go": "package a", "VERSION": "go1.22.7"}
+	makeTree(t, dir, files)
+	old := time.Now().Add(-365 * 24 * time.Hour)
+	for name := range files {
+		if err := os.Chtimes(filepath.Join(dir, name), old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+	makeTree(t, dir, map[string]string{unpackedOkay: ""})
+	marker := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, unpackedOkay), marker, marker); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLocalChanges(t *testing.T) {
+	dir := t.TempDir()
+	makeInstall(t, dir)
+	if files, more, err := localChanges(dir); err != nil || len(files) != 0 || more {
+		t.Errorf("localChanges of a fresh install = %q, %v, %v; want none", files, more, err)
+	}
+	makeTree(t, dir, map[string]string{"src/a.go": "package a // edited", "src/b.go": "package a"})
+	files, more, err := localChanges(dir)
+	want := []string{filepath.Join("src", "a.go"), filepath.Join("src", "b.go")}
+	if err != nil || !reflect.DeepEqual(files, want) || more {
+		t.Errorf("localChanges after edits = %q, %v, %v; want %q", files, more, err, want)
+	}
+	for _, name := range []string{"c", "d", "e", "f"} {
+		makeTree(t, dir, map[string]string{"src/" + name + ".go": "package a"})
+	}
+	if files, more, _ := localChanges(dir); len(files) != maxLocalChanges || !more {
+		t.Errorf("localChanges after many edits = %q, %v; want %d and more", files, more, maxLocalChanges)
+	}
+
+	partial := t.TempDir()
+	makeTree(t, partial, map[string]string{"bin/go": "go"})
+	if files, _, err := localChanges(partial); err != nil || len(files) != 0 {
+		t.Errorf("localChanges of an incomplete install = %q, %v; want none", files, err)
+	}
+}
+
+func TestUninstall(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	sdk := t.TempDir()
+	l := &Locator{Root: sdk}
+	dir := filepath.Join(sdk, "go1.22.7")
+	makeInstall(t, dir)
+	makeTree(t, sdk, map[string]string{
+		"go1.22.7" + asideSuffix + "abc/bin/go": "old",
+		"go1.21.0/bin/go":                       "half", // not completely installed
+	})
+	cache := t.TempDir()
+	makeTree(t, cache, map[string]string{
+		"go1.22.7.linux-amd64.tar.gz":         "archive",
+		"go1.22.7.linux-amd64.tar.gz.sha256":  "sum",
+		"go1.21.13.linux-amd64.tar.gz":        "archive",
+		"go1.21.13.linux-amd64.tar.gz.sha256": "sum",
+	})
+
+	makeTree(t, dir, map[string]string{"src/local.go": "package a"})
+	var lc *localChangesError
+	if _, err := uninstall(l, "go1.22.7", cache, false); !errors.As(err, &lc) {
+		t.Fatalf("uninstall of a changed toolchain = %v; want a refusal", err)
+	}
+	if !isDir(dir) {
+		t.Fatalf("refused uninstall removed %s", dir)
+	}
+
+	removed, err := uninstall(l, "go1.22.7", cache, true)
+	if err != nil || !reflect.DeepEqual(removed, []string{dir}) {
+		t.Fatalf("uninstall -force = %q, %v; want %s removed", removed, err, dir)
+	}
+	for _, name := range []string{"go1.22.7", "go1.22.7" + asideSuffix + "abc"} {
+		if isDir(filepath.Join(sdk, name)) {
+			t.Errorf("uninstall left %s", name)
+		}
+	}
+	entries, _ := cacheEntries(cache)
+	if got := entryNames(entries); !reflect.DeepEqual(got, []string{"go1.21.13.linux-amd64.tar.gz"}) {
+		t.Errorf("after uninstall, the cache holds %q; want only go1.21.13's archive", got)
+	}
+
+	// An incomplete install can be removed too.
+	if removed, err := uninstall(l, "go1.21.0", "", false); err != nil || len(removed) != 1 {
+		t.Errorf("uninstall of an incomplete install = %q, %v", removed, err)
+	}
+
+	var ni *notInstalledError
+	if _, err := uninstall(l, "go1.22.7", cache, false); !errors.As(err, &ni) {
+		t.Errorf("second uninstall = %v; want a not-installed error", err)
+	}
+	if _, err := uninstall(l, "gotip", cache, false); err == nil {
+		t.Errorf("uninstall gotip succeeded; want an error")
+	}
+}
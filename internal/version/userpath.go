@@ -0,0 +1,129 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// addToUserPath appends dir to the PATH of the current user, as opposed
+// to that of the system, so that shells started from now on find the
+// commands in it. It does nothing if dir is already listed, and reports
+// whether it changed anything.
+func addToUserPath(dir string) (changed bool, err error) {
+	return editUserPath(dir, true)
+}
+
+// removeFromUserPath removes every entry for dir from the PATH of the
+// current user, and reports whether there was any.
+func removeFromUserPath(dir string) (changed bool, err error) {
+	return editUserPath(dir, false)
+}
+
+func editUserPath(dir string, add bool) (bool, error) {
+	list, err := getUserPath()
+	if err != nil {
+		return false, fmt.Errorf("reading your PATH: %v", err)
+	}
+	newList, changed := editPathList(list, dir, add)
+	if !changed {
+		return false, nil
+	}
+	if err := setUserPath(newList); err != nil {
+		return false, fmt.Errorf("updating your PATH: %v", err)
+	}
+	return true, nil
+}
+
+// editPathList adds dir to the end of the PATH-style list, or removes it
+// from it, reporting whether the list changed. Entries are compared
+// ignoring case and trailing separators, as Windows does, and empty
+// entries are dropped.
+func editPathList(list, dir string, add bool) (string, bool) {
+	var entries []string
+	found := false
+	for _, e := range strings.Split(list, ";") {
+		switch {
+		case strings.TrimSpace(e) == "":
+		case samePathEntry(e, dir):
+			found = true
+			if add {
+				entries = append(entries, e)
+			}
+		default:
+			entries = append(entries, e)
+		}
+	}
+	if found == add {
+		return list, false
+	}
+	if add {
+		entries = append(entries, dir)
+	}
+	return strings.Join(entries, ";"), true
+}
+
+func samePathEntry(a, b string) bool {
+	clean := func(s string) string {
+		return strings.TrimRight(strings.TrimSpace(s), `\/`)
+	}
+	return strings.EqualFold(clean(a), clean(b))
+}
+
+// registerPath adds or removes the bin directory of the toolchain in root
+// from the user's PATH, and says what it did.
+func registerPath(root string, add bool) error {
+	dir := filepath.Join(root, "bin")
+	var (
+		changed bool
+		err     error
+	)
+	if add {
+		changed, err = addToUserPath(dir)
+	} else {
+		changed, err = removeFromUserPath(dir)
+	}
+	switch {
+	case err != nil:
+		return err
+	case !changed && add:
+		log.Printf("%s is already on your PATH", dir)
+	case !changed:
+		log.Printf("%s is not on your PATH", dir)
+	case add:
+		log.Printf("Added %s to your PATH. Terminals that are already open must be restarted to see the change.", dir)
+	default:
+		log.Printf("Removed %s from your PATH. Terminals that are already open must be restarted to see the change.", dir)
+	}
+	return nil
+}
+
+// offerPathRegistration asks whether to add the bin directory of the
+// toolchain in root to the user's PATH, if that is possible here and the
+// user is at a console to answer. Without prompts, PATH is left alone.
+func offerPathRegistration(root string) {
+	if !isConsole(os.Stdin) || !isConsole(os.Stderr) {
+		return
+	}
+	dir := filepath.Join(root, "bin")
+	list, err := getUserPath()
+	if err != nil {
+		return
+	}
+	if _, changed := editPathList(list, dir, true); !changed {
+		return
+	}
+	answer, err := ask(fmt.Sprintf("Add %s to your PATH? [y/n] ", dir), "-add-to-path")
+	if err != nil || answer != "y" {
+		return
+	}
+	if err := registerPath(root, true); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+}
@@ -0,0 +1,23 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !windows
+// +build !windows
+
+package version
+
+import (
+	"errors"
+	"os"
+)
+
+var errUserPath = errors.New("changing the PATH of new shells is only supported on Windows; edit your shell's profile instead")
+
+func getUserPath() (string, error) { return "", errUserPath }
+
+func setUserPath(list string) error { return errUserPath }
+
+// isConsole reports whether f is a console that PATH registration can be
+// offered on. It is only offered on Windows.
+func isConsole(f *os.File) bool { return false }
@@ -0,0 +1,34 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import "testing"
+
+func TestEditPathList(t *testing.T) {
+	const dir = `C:\Users\me\sdk\go1.22.7\bin`
+	tests := []struct {
+		list    string
+		add     bool
+		want    string
+		changed bool
+	}{
+		{"", true, dir, true},
+		{`C:\tools`, true, `C:\tools;` + dir, true},
+		{`C:\tools;;`, true, `C:\tools;` + dir, true},
+		{`C:\tools;` + dir, true, `C:\tools;` + dir, false},
+		{`c:\users\ME\sdk\go1.22.7\bin\;C:\tools`, true, `c:\users\ME\sdk\go1.22.7\bin\;C:\tools`, false},
+		{`C:\Users\me\sdk\go1.22.7;C:\tools`, true, `C:\Users\me\sdk\go1.22.7;C:\tools;` + dir, true},
+		{`C:\tools`, false, `C:\tools`, false},
+		{`C:\tools;` + dir, false, `C:\tools`, true},
+		{dir + `;C:\tools;` + dir + `\`, false, `C:\tools`, true},
+		{`%USERPROFILE%\go\bin;` + dir, false, `%USERPROFILE%\go\bin`, true},
+	}
+	for _, tt := range tests {
+		got, changed := editPathList(tt.list, dir, tt.add)
+		if got != tt.want || changed != tt.changed {
+			t.Errorf("editPathList(%q, add=%v) = %q, %v; want %q, %v", tt.list, tt.add, got, changed, tt.want, tt.changed)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// The user's PATH lives in this key of HKEY_CURRENT_USER. The system PATH,
+// under HKEY_LOCAL_MACHINE, is never touched.
+const userEnvKey = "Environment"
+
+var (
+	modadvapi32        = syscall.NewLazyDLL("advapi32.dll")
+	procRegSetValueExW = modadvapi32.NewProc("RegSetValueExW")
+
+	moduser32               = syscall.NewLazyDLL("user32.dll")
+	procSendMessageTimeoutW = moduser32.NewProc("SendMessageTimeoutW")
+)
+
+const (
+	hwndBroadcast    = 0xffff
+	wmSettingChange  = 0x001a
+	smtoAbortIfHung  = 0x0002
+	broadcastTimeout = 5000 // milliseconds
+)
+
+// getUserPath returns the user's PATH as stored, with any %VARIABLE%
+// references unexpanded.
+func getUserPath() (string, error) {
+	list, _, err := readUserPath()
+	return list, err
+}
+
+func readUserPath() (list string, valtype uint32, err error) {
+	k, err := openUserEnv(syscall.KEY_QUERY_VALUE)
+	if err != nil {
+		return "", 0, err
+	}
+	defer syscall.RegCloseKey(k)
+	name, _ := syscall.UTF16PtrFromString("Path")
+	var n uint32
+	err = syscall.RegQueryValueEx(k, name, nil, &valtype, nil, &n)
+	if err == syscall.ERROR_FILE_NOT_FOUND {
+		return "", syscall.REG_EXPAND_SZ, nil
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	buf := make([]uint16, n/2+1)
+	if err := syscall.RegQueryValueEx(k, name, nil, &valtype, (*byte)(unsafe.Pointer(&buf[0])), &n); err != nil {
+		return "", 0, err
+	}
+	return syscall.UTF16ToString(buf), valtype, nil
+}
+
+// setUserPath stores list as the user's PATH, keeping the type of the
+// existing value, and tells running programs, such as Explorer, that the
+// environment changed so that the shells they start see it.
+func setUserPath(list string) error {
+	_, valtype, err := readUserPath()
+	if err != nil {
+		return err
+	}
+	if valtype != syscall.REG_SZ {
+		valtype = syscall.REG_EXPAND_SZ
+	}
+	k, err := openUserEnv(syscall.KEY_SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer syscall.RegCloseKey(k)
+	name, _ := syscall.UTF16PtrFromString("Path")
+	data, err := syscall.UTF16FromString(list)
+	if err != nil {
+		return err
+	}
+	if r, _, _ := procRegSetValueExW.Call(uintptr(k), uintptr(unsafe.Pointer(name)), 0, uintptr(valtype),
+		uintptr(unsafe.Pointer(&data[0])), uintptr(len(data)*2)); r != 0 {
+		return syscall.Errno(r)
+	}
+
+	env, _ := syscall.UTF16PtrFromString(userEnvKey)
+	var result uintptr
+	procSendMessageTimeoutW.Call(hwndBroadcast, wmSettingChange, 0, uintptr(unsafe.Pointer(env)),
+		smtoAbortIfHung, broadcastTimeout, uintptr(unsafe.Pointer(&result)))
+	return nil
+}
+
+func openUserEnv(access uint32) (syscall.Handle, error) {
+	var k syscall.Handle
+	name, _ := syscall.UTF16PtrFromString(userEnvKey)
+	err := syscall.RegOpenKeyEx(syscall.HKEY_CURRENT_USER, name, 0, access, &k)
+	return k, err
+}
+
+// isConsole reports whether f is a Windows console.
+func isConsole(f *os.File) bool {
+	var mode uint32
+	return syscall.GetConsoleMode(syscall.Handle(f.Fd()), &mode) == nil
+}
@@ -8,9 +8,9 @@ package version
 import (
 	"archive/tar"
 	"archive/zip"
-	"compress/gzip"
-	"crypto/sha256"
+	"context"
 	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -20,157 +20,693 @@ import (
 	"os/exec"
 	"os/signal"
 	"os/user"
-	"path"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"time"
 )
 
-func init() {
-	http.DefaultTransport = &userAgentTransport{http.DefaultTransport}
-}
-
 // Run runs the "go" tool of the provided Go version.
 func Run(version string) {
 	log.SetFlags(0)
+	runRelease(version, os.Args[1:], "", false)
+}
 
-	root, err := goroot(version)
+// runRelease runs the go command of the release name with args, for its
+// go1.N.M wrapper and for dl go1.N.M. If args are download or remove and
+// their flags, it installs or removes the release instead, and if they
+// are shell and its arguments, it starts a shell with the release on
+// PATH. A name such as
+// go1.22 stands for the newest patch release of that minor version; see
+// resolvePartial. A release that isn't installed is an error, unless
+// onDemand is set, in which case it is installed first. Settings are read
+// from configFile, if set, instead of the default config file. It does
+// not return.
+func runRelease(name string, args []string, configFile string, onDemand bool) {
+	v, err := ParseVersion(name)
 	if err != nil {
-		log.Fatalf("%s: %v", version, err)
+		fatal(name, err)
+	}
+	if len(args) >= 1 && args[0] == "download" {
+		flags := flag.NewFlagSet(name+" download", flag.ExitOnError)
+		flags.StringVar(&configFile, "config", configFile, "read settings from this file instead of the default config file")
+		addToPath := flags.Bool("add-to-path", false, "add the toolchain's bin directory to your PATH (Windows only)")
+		removeFromPath := flags.Bool("remove-from-path", false, "remove the toolchain's bin directory from your PATH, instead of downloading (Windows only)")
+		addInstallFlags(flags)
+		addOutputFlags(flags)
+		flags.BoolVar(&nonInteractive, "non-interactive", false, "never ask questions; also the default when not at a terminal or when CI=true")
+		dryRun := flags.Bool("dry-run", false, "print the archive, its size, where its checksum comes from and the target directory, without installing anything")
+		from := flags.String("from", "", "install from the release archive `file` instead of downloading it, verified against -sha256, the checksum in file.sha256 or else the published one")
+		sum := flags.String("sha256", "", "the SHA-256 `digest` of the -from archive")
+		goos := flags.String("goos", "", "download the release for another machine's operating system `goos`, such as linux, into a directory of its own that this wrapper doesn't run")
+		goarch := flags.String("goarch", "", "download the release for another machine's architecture `goarch`, such as arm64, into a directory of its own that this wrapper doesn't run")
+		flags.Parse(args[1:])
+		if flags.NArg() > 0 || *addToPath && *removeFromPath {
+			flags.Usage()
+			os.Exit(2)
+		}
+		if *sum != "" && *from == "" {
+			usagef("%s download: -sha256 is for the archive -from names", name)
+		}
+		plat := crossPlatform(*goos, *goarch)
+		if plat.cross() && (*addToPath || *removeFromPath) {
+			usagef("%s download: the %s release isn't for this machine, so can't be added to PATH", name, plat)
+		}
+		cfg, err := loadConfig(configFile, flags)
+		if err != nil {
+			fatal(name, err)
+		}
+		version, err := resolvePartial(cfg, name)
+		if err != nil {
+			fatal(name, err)
+		}
+		if *dryRun {
+			if err := dryRunRelease(cfg, version, plat, *from, *sum); err != nil {
+				fatal(name, err)
+			}
+			os.Exit(0)
+		}
+		if *removeFromPath {
+			root, err := goroot(version)
+			if err != nil {
+				fatal(name, err)
+			}
+			if err := registerPath(root, false); err != nil {
+				fatal(name, err)
+			}
+			os.Exit(0)
+		}
+		var root string
+		if *from != "" {
+			root, err = installReleaseFile(cfg, version, plat, *from, *sum)
+		} else {
+			root, err = installCrossRelease(cfg, version, plat)
+		}
+		if err != nil {
+			fatal(version+": download failed", err)
+		}
+		if plat.cross() {
+			os.Exit(0)
+		}
+		if *addToPath {
+			if err := registerPath(root, true); err != nil {
+				fatal(name, err)
+			}
+		} else {
+			offerPathRegistration(root)
+		}
+		warnShadowing(version, root, managedRoots(defaultLocator))
+		os.Exit(0)
 	}
 
-	if len(os.Args) == 2 && os.Args[1] == "download" {
-		if err := install(root, version); err != nil {
-			log.Fatalf("%s: download failed: %v", version, err)
+	if len(args) >= 1 && args[0] == "remove" {
+		flags := flag.NewFlagSet(name+" remove", flag.ExitOnError)
+		flags.StringVar(&configFile, "config", configFile, "read settings from this file instead of the default config file")
+		force := flags.Bool("force", false, "remove the toolchain even if files in it were changed or added since it was installed")
+		flags.Parse(args[1:])
+		if flags.NArg() > 0 {
+			flags.Usage()
+			os.Exit(2)
+		}
+		if partialRelease(v, name) {
+			usagef("%s names a minor version, not a release; remove an exact release, as listed by dl list", name)
+		}
+		cfg, err := loadConfig(configFile, flags)
+		if err != nil {
+			fatal(name, err)
+		}
+		if err := removeRelease(cfg, name, *force); err != nil {
+			fatal(name+": remove failed", err)
 		}
 		os.Exit(0)
 	}
 
-	if _, err := os.Stat(filepath.Join(root, unpackedOkay)); err != nil {
-		log.Fatalf("%s: not downloaded. Run '%s download' to install to %v", version, version, root)
+	cfg, err := loadConfig(configFile, nil)
+	if err != nil {
+		fatal(name, err)
 	}
+	version, err := resolvePartial(cfg, name)
+	if err != nil {
+		fatal(name, err)
+	}
+	root, err := whichGoroot(defaultLocator, version)
+	if err != nil && onDemand {
+		log.Printf("%s is not installed; downloading it first.", version)
+		if root, err = installRelease(cfg, version); err != nil {
+			fatal(version+": download failed", err)
+		}
+	} else if err != nil {
+		root, err := goroot(version)
+		if err != nil {
+			fatal(name, err)
+		}
+		log.Printf("%s: not downloaded. Run '%s download' to install to %v", version, name, root)
+		os.Exit(ExitNotInstalled)
+	}
+	checkQuarantine(root)
 
-	runGo(root)
+	if len(args) >= 1 && args[0] == "shell" {
+		runShell(version, root, args[1:])
+	}
+	runGo(root, args)
 }
 
-func runGo(root string) {
-	gobin := filepath.Join(root, "bin", "go"+exe())
-	cmd := exec.Command(gobin, os.Args[1:]...)
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	newPath := filepath.Join(root, "bin")
-	if p := os.Getenv("PATH"); p != "" {
-		newPath += string(filepath.ListSeparator) + p
+// partialRelease reports whether the release name v was parsed from names
+// a minor version rather than a release: from Go 1.21 on, a minor
+// version's first release is go1.N.0, so go1.N is none. Earlier names such
+// as go1.20 are releases of their own.
+func partialRelease(v Version, name string) bool {
+	return v.Major == 1 && v.Minor >= 21 && v.Patch == 0 && v.Pre == "" && !strings.HasSuffix(name, ".0")
+}
+
+// resolvePartial returns the release name runs: name itself, unless it
+// names a minor version, such as go1.22, in which case it is the newest
+// stable patch release of it in the release listing, or, if the listing
+// can't be loaded, the newest one installed.
+func resolvePartial(cfg *Config, name string) (string, error) {
+	v, err := ParseVersion(name)
+	if err != nil || !partialRelease(v, name) {
+		return name, err
 	}
-	cmd.Env = dedupEnv(caseInsensitiveEnv, append(os.Environ(), "GOROOT="+root, "PATH="+newPath))
+	opts, err := cfg.Options()
+	if err != nil {
+		return "", err
+	}
+	d, err := NewDownloader(opts)
+	if err != nil {
+		return "", err
+	}
+	return newestPatch(context.Background(), d.Catalog(), cfg.Locator(), v)
+}
 
-	handleSignals()
+// newestPatch returns the newest stable patch release of the minor version
+// v that c lists, as c.Resolve resolves it, or, if c can't be loaded,
+// that is installed under l.
+func newestPatch(ctx context.Context, c *Catalog, l *Locator, v Version) (string, error) {
+	r, err := c.Resolve(ctx, v.String())
+	if err == nil {
+		return r.Version.String(), nil
+	}
+	return newestInstalled(l, v.String(), err, func(u Version) bool {
+		return u.Major == v.Major && u.Minor == v.Minor
+	})
+}
 
-	if err := cmd.Run(); err != nil {
-		// TODO: return the same exit status maybe.
-		os.Exit(1)
+// newestLatest returns the newest stable release that c lists, or, if c
+// can't be loaded, that is installed under l. The listing is cached, so
+// this only asks the server once in a while.
+func newestLatest(ctx context.Context, c *Catalog, l *Locator) (string, error) {
+	r, err := c.Latest(ctx, true)
+	if err == nil {
+		return r.Version.String(), nil
 	}
-	os.Exit(0)
+	return newestInstalled(l, "stable release", err, func(Version) bool { return true })
 }
 
-// install installs a version of Go to the named target directory, creating the
-// directory as needed.
-func install(targetDir, version string) error {
-	if _, err := os.Stat(filepath.Join(targetDir, unpackedOkay)); err == nil {
-		log.Printf("%s: already downloaded in %v", version, targetDir)
-		return nil
+// newestInstalled returns the newest stable release installed under l that
+// match accepts, as the newest what, because loading the release listing
+// failed with err, and says so. If there is none, or if err is a
+// *NotFoundError rather than a failure to load, it returns err.
+func newestInstalled(l *Locator, what string, err error, match func(Version) bool) (string, error) {
+	var nf *NotFoundError
+	if errors.As(err, &nf) {
+		return "", err
+	}
+	installed, ierr := installedReleases(l)
+	for i := len(installed) - 1; ierr == nil && i >= 0; i-- {
+		if u := installed[i]; u.Pre == "" && match(u) {
+			log.Printf("Note: using %s, the newest %s installed, without checking for a newer one: %v", u, what, err)
+			return u.String(), nil
+		}
 	}
+	return "", err
+}
+
+// RunLatest runs the go command of the newest stable release, as the
+// golatest command does, installing it first if need be. Its download
+// argument installs it without running anything.
+func RunLatest() {
+	log.SetFlags(0)
+	runLatest(os.Args[1:], "")
+}
 
-	if err := os.MkdirAll(targetDir, 0755); err != nil {
+// runLatest runs the go command of the newest stable release with args,
+// for golatest and dl latest. It does not return.
+func runLatest(args []string, configFile string) {
+	cfg, err := loadConfig(configFile, nil)
+	if err != nil {
+		fatal("golatest", err)
+	}
+	opts, err := cfg.Options()
+	if err != nil {
+		fatal("golatest", err)
+	}
+	d, err := NewDownloader(opts)
+	if err != nil {
+		fatal("golatest", err)
+	}
+	version, err := newestLatest(context.Background(), d.Catalog(), cfg.Locator())
+	if err != nil {
+		fatal("golatest", err)
+	}
+	runRelease(version, args, configFile, true)
+}
+
+// removeRelease uninstalls the release version, as go1.N.M remove and dl
+// uninstall do, and says what it removed.
+func removeRelease(cfg *Config, version string, force bool) error {
+	opts, err := cfg.Options()
+	if err != nil {
 		return err
 	}
-	goURL := versionArchiveURL(version)
-	res, err := http.Head(goURL)
+	removed, err := uninstall(cfg.Locator(), version, opts.CacheDir, force)
+	for _, dir := range removed {
+		log.Printf("Removed %s", dir)
+	}
+	return err
+}
+
+// installRelease installs the release version in the SDK directory cfg
+// sets, records the install in the journal, and returns its GOROOT.
+func installRelease(cfg *Config, version string) (root string, err error) {
+	return installCrossRelease(cfg, version, platform{})
+}
+
+// installCrossRelease is like installRelease, but installs the release
+// for plat; see platform.goroot.
+func installCrossRelease(cfg *Config, version string, plat platform) (root string, err error) {
+	return installReleaseWith(cfg, version, plat, func(ctx context.Context, d *Downloader, root string) error {
+		return d.install(ctx, root, version)
+	})
+}
+
+// installReleaseFile is like installRelease, but installs the release
+// from the archive file, verified against sum if it is set; see
+// Downloader.planFile.
+func installReleaseFile(cfg *Config, version string, plat platform, file, sum string) (root string, err error) {
+	return installReleaseWith(cfg, version, plat, func(ctx context.Context, d *Downloader, root string) error {
+		return d.installFile(ctx, root, version, file, sum)
+	})
+}
+
+// installReleaseWith installs version into its GOROOT for plat with
+// install, recording the install in the journal.
+func installReleaseWith(cfg *Config, version string, plat platform, install func(ctx context.Context, d *Downloader, root string) error) (root string, err error) {
+	if err := ensureSDKRoot(cfg); err != nil {
+		return "", err
+	}
+	if root, err = plat.goroot(version); err != nil {
+		return "", err
+	}
+	opts, err := cfg.Options()
+	if err != nil {
+		return "", err
+	}
+	plat.options(&opts)
+	rec := newJournalRecorder(version)
+	opts.Events = rec.events
+	d, err := NewDownloader(opts)
+	if err != nil {
+		return "", err
+	}
+	ctx, stop := interruptContext()
+	err = install(ctx, d, root)
+	stop()
+	if e, ok := rec.wait(); ok {
+		arch, _ := d.arch()
+		e.Platform = d.goos() + "/" + arch
+		recordInstall(e)
+	}
+	return root, err
+}
+
+// dryRunRelease prints what installing version would do, from the
+// archive file if it is set, for plat, as printDryRun does. It downloads
+// nothing and changes nothing.
+func dryRunRelease(cfg *Config, version string, plat platform, file, sum string) error {
+	root, err := plat.goroot(version)
 	if err != nil {
 		return err
 	}
-	if res.StatusCode == http.StatusNotFound {
-		return fmt.Errorf("no binary release of %v for %v/%v at %v", version, getOS(), runtime.GOARCH, goURL)
+	opts, err := cfg.Options()
+	if err != nil {
+		return err
 	}
-	if res.StatusCode != http.StatusOK {
-		return fmt.Errorf("server returned %v checking size of %v", http.StatusText(res.StatusCode), goURL)
+	plat.options(&opts)
+	d, err := NewDownloader(opts)
+	if err != nil {
+		return err
 	}
-	base := path.Base(goURL)
-	archiveFile := filepath.Join(targetDir, base)
-	if fi, err := os.Stat(archiveFile); err != nil || fi.Size() != res.ContentLength {
-		if err != nil && !os.IsNotExist(err) {
-			// Something weird. Don't try to download.
-			return err
+	var p *Plan
+	if file != "" {
+		p, err = d.planFile(root, version, file, sum)
+	} else {
+		ctx, stop := interruptContext()
+		defer stop()
+		p, err = d.plan(ctx, root, version)
+	}
+	if err != nil {
+		return err
+	}
+	printDryRun(os.Stdout, p, opts.Offline)
+	return nil
+}
+
+// printDryRun prints to w the archive p would download, its size, where
+// its checksum would come from and where it would be unpacked.
+func printDryRun(w io.Writer, p *Plan, offline bool) {
+	if p.Installed {
+		fmt.Fprint(w, p)
+		return
+	}
+	fmt.Fprintf(w, "%s would be installed into %s\n", p.Version, p.GOROOT)
+	fmt.Fprintf(w, "  archive:  %s\n", p.URL)
+	fmt.Fprintf(w, "  size:     %d bytes (%s)\n", p.Size, formatByteSize(p.Size))
+	for _, s := range p.Steps {
+		switch {
+		case s.Kind == StepDownload && s.Offset > 0:
+			fmt.Fprintf(w, "  download: to %s, resuming at byte %d\n", s.File, s.Offset)
+		case s.Kind == StepDownload:
+			fmt.Fprintf(w, "  download: to %s\n", s.File)
+		case s.Kind == StepStream:
+			fmt.Fprintf(w, "  download: streamed into %s, without keeping the archive\n", s.Target)
+			fmt.Fprintf(w, "  checksum: %s, checked once the archive has arrived\n", checksumSource(s, offline))
+		case s.Kind == StepVerify || s.Kind == StepVerifyModule:
+			if !hasStep(p, StepDownload) {
+				fmt.Fprintf(w, "  download: none; %s is already downloaded\n", s.File)
+			}
+			fmt.Fprintf(w, "  checksum: %s\n", checksumSource(s, offline))
+		case s.Kind == StepDedupe:
+			fmt.Fprintf(w, "  dedupe:   files shared with the nearest installed release, as %ss\n", s.Mode)
 		}
-		if err := copyFromURL(archiveFile, goURL); err != nil {
-			return fmt.Errorf("error downloading %v: %v", goURL, err)
+		if s.Slim {
+			fmt.Fprintf(w, "  slim:     tests, docs and API data left out\n")
 		}
-		fi, err = os.Stat(archiveFile)
-		if err != nil {
-			return err
+	}
+}
+
+// checksumSource describes where the verify or stream step s gets its
+// checksum.
+func checksumSource(s Step, offline bool) string {
+	switch {
+	case s.SHA256 != "":
+		return "pinned SHA-256 " + s.SHA256
+	case s.Checksum == ChecksumSkip:
+		return "none; verification is skipped (" + envChecksum + "=skip)"
+	case s.Kind == StepVerifyModule && s.URL == "":
+		return "none; there is no checksum database (" + envSumDB + "=off)"
+	case offline:
+		return s.File + ".sha256, saved with the cached archive"
+	case s.Checksum == ChecksumIfPublished:
+		return s.sumURL() + ", if it is published"
+	default:
+		return s.sumURL()
+	}
+}
+
+// hasStep reports whether p has a step of kind k.
+func hasStep(p *Plan, k StepKind) bool {
+	for _, s := range p.Steps {
+		if s.Kind == k {
+			return true
+		}
+	}
+	return false
+}
+
+func runGo(root string, args []string) {
+	runCommand(goCommand(context.Background(), root, args...))
+}
+
+// runCommand runs cmd with the standard input and output of the process,
+// and exits with its exit status.
+func runCommand(cmd *exec.Cmd) {
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	handleSignals()
+
+	if s := os.Getenv(envTrace); s != "" {
+		traceCommand(os.Stderr, traceLevel(s), cmd)
+	}
+	if err := cmd.Run(); err != nil {
+		var ee *exec.ExitError
+		if errors.As(err, &ee) && ee.ExitCode() > 0 {
+			os.Exit(ee.ExitCode())
+		}
+		os.Exit(ExitFailure)
+	}
+	os.Exit(0)
+}
+
+// goCommand returns a command that runs the go tool of the toolchain in
+// root with args, with GOROOT and PATH set to refer to that toolchain.
+func goCommand(ctx context.Context, root string, args ...string) *exec.Cmd {
+	gobin := filepath.Join(root, "bin", "go"+exe())
+	cmd := exec.CommandContext(ctx, gobin, args...)
+	cmd.Env = toolchainEnv(root)
+	return cmd
+}
+
+// toolchainCommand returns a command that runs name with args in the
+// environment goCommand gives the go tool. A name without a path
+// separator is looked up in that environment's PATH, so that the
+// toolchain's go and gofmt come first.
+func toolchainCommand(ctx context.Context, root, name string, args ...string) (*exec.Cmd, error) {
+	file := name
+	if !strings.ContainsAny(name, `/\`) {
+		var err error
+		if file, err = lookPathIn(name, toolchainPath(root)); err != nil {
+			return nil, err
+		}
+	}
+	cmd := exec.CommandContext(ctx, file, args...)
+	cmd.Env = toolchainEnv(root)
+	return cmd, nil
+}
+
+// lookPathIn is exec.LookPath searching the directories of the list path,
+// as PATH is, rather than PATH itself. On Windows, a name without an
+// extension is tried with each of those PATHEXT lists.
+func lookPathIn(name, path string) (string, error) {
+	exts := []string{""}
+	if getOS() == "windows" && filepath.Ext(name) == "" {
+		exts = []string{".com", ".exe", ".bat", ".cmd"}
+		if x := os.Getenv("PATHEXT"); x != "" {
+			exts = filepath.SplitList(strings.ToLower(x))
 		}
-		if fi.Size() != res.ContentLength {
-			return fmt.Errorf("downloaded file %s size %v doesn't match server size %v", archiveFile, fi.Size(), res.ContentLength)
+	}
+	for _, dir := range filepath.SplitList(path) {
+		if dir == "" {
+			// Unix shell semantics: an empty entry means the current
+			// directory.
+			dir = "."
+		}
+		for _, ext := range exts {
+			file := filepath.Join(dir, name+ext)
+			if fi, err := os.Stat(file); err == nil && !fi.IsDir() && (getOS() == "windows" || fi.Mode()&0111 != 0) {
+				return file, nil
+			}
 		}
 	}
-	wantSHA, err := slurpURLToString(goURL + ".sha256")
+	return "", &exec.Error{Name: name, Err: exec.ErrNotFound}
+}
+
+// toolchainEnv returns the environment of the process with GOROOT and
+// PATH set to refer to the toolchain in root.
+func toolchainEnv(root string) []string {
+	env := append(os.Environ(), tempEnv()...)
+	return dedupEnv(caseInsensitiveEnv, append(env, "GOROOT="+root, pathVar()+"="+toolchainPath(root)))
+}
+
+// toolchainPath returns PATH with the bin directory of the toolchain in
+// root put first.
+func toolchainPath(root string) string {
+	p := filepath.Join(root, "bin")
+	if old := os.Getenv(pathVar()); old != "" {
+		p += string(filepath.ListSeparator) + old
+	}
+	return p
+}
+
+// pathVar returns the name of the environment variable listing the
+// directories searched for commands.
+func pathVar() string {
+	if runtime.GOOS == "plan9" {
+		return "path"
+	}
+	return "PATH"
+}
+
+// install installs a version of Go to the named target directory, creating the
+// directory as needed.
+func (d *Downloader) install(ctx context.Context, targetDir, version string) error {
+	// Hold the cache from planning on, so that the archives the plan
+	// counts on stay there.
+	defer d.holdCache()()
+	p, err := d.plan(ctx, targetDir, version)
 	if err != nil {
+		d.emitter().emit(Failed{Err: err, Phase: PhaseResolve})
 		return err
 	}
-	if err := verifySHA256(archiveFile, strings.TrimSpace(wantSHA)); err != nil {
-		return fmt.Errorf("error verifying SHA256 of %v: %v", archiveFile, err)
+	return d.Execute(ctx, p)
+}
+
+// verify checks archiveFile against the checksum published for goURL,
+// according to policy, or against pinned if it is set.
+func (d *Downloader) verify(ctx context.Context, archiveFile, goURL string, policy ChecksumPolicy, pinned string) error {
+	wantSHA, source, err := d.expectedChecksum(ctx, archiveFile, goURL, policy, pinned)
+	if err != nil || wantSHA == "" {
+		return err
 	}
-	log.Printf("Unpacking %v ...", archiveFile)
-	if err := unpackArchive(targetDir, archiveFile); err != nil {
-		return fmt.Errorf("extracting archive %v: %v", archiveFile, err)
+	err = VerifyFileSHA256(archiveFile, wantSHA, nil)
+	d.emitter().emit(VerificationResult{File: archiveFile, SHA256: wantSHA, Err: err})
+	var ce *ChecksumError
+	switch {
+	case err == nil:
+		verbosef("Verified SHA256 %s of %v, from %s", wantSHA, archiveFile, source)
+		d.opts.Metrics.verify(VerifyOK)
+	case errors.As(err, &ce):
+		d.opts.Metrics.verify(VerifyMismatch)
+	default:
+		d.opts.Metrics.verify(VerifyError)
 	}
-	if err := ioutil.WriteFile(filepath.Join(targetDir, unpackedOkay), nil, 0644); err != nil {
-		return err
+	if err != nil {
+		return fmt.Errorf("error verifying SHA256 of %v: %w", archiveFile, err)
 	}
-	log.Printf("Success. You may now run '%v'", version)
 	return nil
 }
 
+// expectedChecksum returns the SHA-256 the archive at goURL, downloaded
+// to archiveFile, must have, according to policy, or pinned if it is
+// set, and where it comes from. It returns "" if the archive isn't to be
+// verified, having said why.
+func (d *Downloader) expectedChecksum(ctx context.Context, archiveFile, goURL string, policy ChecksumPolicy, pinned string) (sum, source string, err error) {
+	if pinned != "" {
+		return strings.TrimSpace(pinned), "the checksum pinned for it", nil
+	}
+	if policy == ChecksumSkip {
+		log.Printf("Skipping checksum verification of %v", archiveFile)
+		d.opts.Metrics.verify(VerifySkipped)
+		return "", "", nil
+	}
+	sum, err = d.publishedChecksum(ctx, archiveFile, goURL)
+	source = goURL + ".sha256"
+	if d.opts.Offline {
+		source = archiveFile + ".sha256"
+	}
+	if err != nil {
+		if policy == ChecksumIfPublished && isNotFound(err) {
+			log.Printf("No checksum published for %v; installing without verification", goURL)
+			d.opts.Metrics.verify(VerifyUnpublished)
+			return "", "", nil
+		}
+		d.opts.Metrics.verify(VerifyError)
+		return "", "", err
+	}
+	return strings.TrimSpace(sum), source, nil
+}
+
+// publishedChecksum returns the SHA-256 published for the archive at
+// goURL. The checksums of archives in the cache are saved beside them, for
+// verifying them in offline mode.
+func (d *Downloader) publishedChecksum(ctx context.Context, archiveFile, goURL string) (string, error) {
+	saved := archiveFile + ".sha256"
+	if d.opts.Offline {
+		sum, err := ioutil.ReadFile(saved)
+		if err != nil {
+			return "", netGate(true, goURL+".sha256")
+		}
+		return string(sum), nil
+	}
+	sum, err := d.slurpURLToString(ctx, goURL+".sha256")
+	if err == nil && d.opts.CacheDir != "" {
+		_ = writeFileAtomic(saved, []byte(sum))
+	}
+	return sum, err
+}
+
 // unpackArchive unpacks the provided archive zip or tar.gz file to targetDir,
-// removing the "go/" prefix from file entries.
-func unpackArchive(targetDir, archiveFile string) error {
+// removing the "go/" prefix from file entries, or the module directory
+// from those of a module zip. Progress is reported to em, and the final
+// tally returned. Entries that would overwrite each other on a
+// case-insensitive file system are an error. Only the entries that keep,
+// if non-nil, reports true for, by their slash-separated path relative to
+// targetDir, are unpacked. Unpacking stops between entries once ctx is
+// done.
+func unpackArchive(ctx context.Context, targetDir, archiveFile string, em *emitter, m *meter, keep func(string) bool) (UnpackProgress, error) {
+	seen, err := newEntrySet(targetDir)
+	if err != nil {
+		return UnpackProgress{}, err
+	}
 	switch {
 	case strings.HasSuffix(archiveFile, ".zip"):
-		return unpackZip(targetDir, archiveFile)
+		return unpackZip(ctx, targetDir, archiveFile, em, m, seen, keep)
 	case strings.HasSuffix(archiveFile, ".tar.gz"):
-		return unpackTarGz(targetDir, archiveFile)
+		return unpackTarGz(ctx, targetDir, archiveFile, em, m, seen, keep)
 	default:
-		return errors.New("unsupported archive file")
+		return UnpackProgress{}, errors.New("unsupported archive file")
 	}
 }
 
 // unpackTarGz is the tar.gz implementation of unpackArchive.
-func unpackTarGz(targetDir, archiveFile string) error {
-	r, err := os.Open(archiveFile)
+func unpackTarGz(ctx context.Context, targetDir, archiveFile string, em *emitter, m *meter, seen *entrySet, keep func(string) bool) (progress UnpackProgress, err error) {
+	f, err := os.Open(archiveFile)
 	if err != nil {
-		return err
+		return progress, err
 	}
 	defer func() {
-		_ = r.Close()
+		_ = f.Close()
 	}()
+	return untarGz(ctx, targetDir, &countingReader{r: f}, em, m, seen, keep)
+}
+
+// untarGz unpacks the tar.gz archive read from r into targetDir, as
+// unpackArchive does. The archive is decompressed ahead of the tar reader
+// on a goroutine of its own, and small files are written out on a pool of
+// others. The meter counts the bytes of the archive read, which, unlike
+// those unpacked, are known in advance.
+func untarGz(ctx context.Context, targetDir string, r *countingReader, em *emitter, m *meter, seen *entrySet, keep func(string) bool) (progress UnpackProgress, err error) {
 	madeDir := map[string]bool{}
-	zr, err := gzip.NewReader(r)
+	zr, err := newGzipReadahead(r)
 	if err != nil {
-		return err
+		return progress, err
 	}
+	defer func() {
+		_ = zr.Close()
+	}()
+	pool := newWritePool()
+	defer func() {
+		if werr := pool.wait(); err == nil && werr != nil {
+			err = werr
+		}
+	}()
 	tr := tar.NewReader(zr)
 	for {
+		if err := ctx.Err(); err != nil {
+			return progress, err
+		}
 		f, err := tr.Next()
 		if err == io.EOF {
+			if err := pool.wait(); err != nil {
+				return progress, err
+			}
+			em.emit(progress)
+			m.finish(r.count(), unpackDetail(progress))
 			break
 		}
 		if err != nil {
-			return err
+			return progress, err
 		}
 		if !validRelPath(f.Name) {
-			return fmt.Errorf("tar file contained invalid name %q", f.Name)
+			return progress, fmt.Errorf("tar file contained invalid name %q", f.Name)
+		}
+		if keep != nil && !keep(strings.TrimPrefix(f.Name, "go/")) {
+			continue
+		}
+		if err := seen.add(f.Name); err != nil {
+			return progress, err
 		}
 		rel := filepath.FromSlash(strings.TrimPrefix(f.Name, "go/"))
 		abs := filepath.Join(targetDir, rel)
@@ -186,116 +722,122 @@ func unpackTarGz(targetDir, archiveFile string) error {
 			dir := filepath.Dir(abs)
 			if !madeDir[dir] {
 				if err := os.MkdirAll(filepath.Dir(abs), 0755); err != nil {
-					return err
+					return progress, err
 				}
 				madeDir[dir] = true
 			}
-			wf, err := os.OpenFile(abs, os.O_RDWR|os.O_CREATE|os.O_TRUNC, mode.Perm())
+			n, err := pool.write(abs, mode, tr, f.Size, f.ModTime)
 			if err != nil {
-				return err
-			}
-			n, err := io.Copy(wf, tr)
-			if closeErr := wf.Close(); closeErr != nil && err == nil {
-				err = closeErr
-			}
-			if err != nil {
-				return fmt.Errorf("error writing to %s: %v", abs, err)
-			}
-			if n != f.Size {
-				return fmt.Errorf("only wrote %d bytes to %s; expected %d", n, abs, f.Size)
-			}
-			if !f.ModTime.IsZero() {
-				if err := os.Chtimes(abs, f.ModTime, f.ModTime); err != nil {
-					// benign error. Gerrit doesn't even set the
-					// modtime in these, and we don't end up relying
-					// on it anywhere (the gomote push command relies
-					// on digests only), so this is a little pointless
-					// for now.
-					log.Printf("error changing modtime: %v", err)
-				}
+				return progress, err
 			}
+			progress.Files++
+			progress.Bytes += n
+			em.progress(progress)
+			m.update(r.count(), unpackDetail(progress))
 		case mode.IsDir():
 			if err := os.MkdirAll(abs, 0755); err != nil {
-				return err
+				return progress, err
 			}
 			madeDir[abs] = true
 		default:
-			return fmt.Errorf("tar file entry %s contained unsupported file type %v", f.Name, mode)
+			return progress, fmt.Errorf("tar file entry %s contained unsupported file type %v", f.Name, mode)
 		}
 	}
-	return nil
+	return progress, nil
 }
 
-// unpackZip is the zip implementation of unpackArchive.
-func unpackZip(targetDir, archiveFile string) error {
+// unpackZip is the zip implementation of unpackArchive. The archive is
+// read from the file it was downloaded to, an entry at a time, through its
+// central directory, so that it is never held in memory whole.
+func unpackZip(ctx context.Context, targetDir, archiveFile string, em *emitter, m *meter, seen *entrySet, keep func(string) bool) (progress UnpackProgress, err error) {
 	zr, err := zip.OpenReader(archiveFile)
 	if err != nil {
-		return err
+		return progress, err
 	}
 	defer func() {
 		_ = zr.Close()
 	}()
 
+	// The meter counts the compressed bytes of the files unpacked.
+	var read int64
+	var files []*zip.File
 	for _, f := range zr.File {
-		name := strings.TrimPrefix(f.Name, "go/")
+		if keep == nil || keep(zipEntryName(f.Name)) {
+			files = append(files, f)
+		}
+	}
+	if m != nil {
+		m.total = 0
+		for _, f := range files {
+			m.total += int64(f.CompressedSize64)
+		}
+	}
+	for _, f := range files {
+		if err := ctx.Err(); err != nil {
+			return progress, err
+		}
+		if err := seen.add(f.Name); err != nil {
+			return progress, err
+		}
+		name := zipEntryName(f.Name)
+		mode := f.Mode()
+		if moduleZipPrefix(f.Name) != "" {
+			mode = moduleFileMode(name)
+		}
 
 		outpath := filepath.Join(targetDir, name)
 		if f.FileInfo().IsDir() {
 			if err := os.MkdirAll(outpath, 0755); err != nil {
-				return err
+				return progress, err
 			}
 			continue
 		}
 
 		rc, err := f.Open()
 		if err != nil {
-			return err
+			return progress, err
 		}
 
 		// File
 		if err := os.MkdirAll(filepath.Dir(outpath), 0755); err != nil {
-			return err
+			return progress, err
 		}
-		out, err := os.OpenFile(outpath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode())
+		out, err := os.OpenFile(outpath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
 		if err != nil {
-			return err
+			return progress, err
 		}
-		_, err = io.Copy(out, rc)
+		n, err := io.Copy(out, rc)
 		_ = rc.Close()
 		if err != nil {
 			_ = out.Close()
-			return err
+			return progress, err
 		}
 		if err := out.Close(); err != nil {
-			return err
+			return progress, err
 		}
+		progress.Files++
+		progress.Bytes += n
+		read += int64(f.CompressedSize64)
+		em.progress(progress)
+		m.update(read, unpackDetail(progress))
 	}
-	return nil
+	em.emit(progress)
+	m.finish(read, unpackDetail(progress))
+	return progress, nil
 }
 
-// verifySHA256 reports whether the named file has contents with
-// SHA-256 of the given wantHex value.
-func verifySHA256(file, wantHex string) error {
-	f, err := os.Open(file)
-	if err != nil {
-		return err
-	}
-	defer func() {
-		_ = f.Close()
-	}()
-	hash := sha256.New()
-	if _, err := io.Copy(hash, f); err != nil {
-		return err
-	}
-	if fmt.Sprintf("%x", hash.Sum(nil)) != wantHex {
-		return fmt.Errorf("%s corrupt? does not have expected SHA-256 of %v", file, wantHex)
+// zipEntryName returns the path relative to the GOROOT of the entry name
+// of a release zip, or of a module zip.
+func zipEntryName(name string) string {
+	if prefix := moduleZipPrefix(name); prefix != "" {
+		return strings.TrimPrefix(name, prefix)
 	}
-	return nil
+	return strings.TrimPrefix(name, "go/")
 }
 
 // slurpURLToString downloads the given URL and returns it as a string.
-func slurpURLToString(url_ string) (string, error) {
-	res, err := http.Get(url_)
+func (d *Downloader) slurpURLToString(ctx context.Context, url_ string) (string, error) {
+	res, err := d.do(ctx, http.MethodGet, url_)
 	if err != nil {
 		return "", err
 	}
@@ -303,88 +845,128 @@ func slurpURLToString(url_ string) (string, error) {
 		_ = res.Body.Close()
 	}()
 	if res.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("%s: %v", url_, res.Status)
+		return "", &statusError{URL: url_, Code: res.StatusCode, Status: res.Status}
 	}
 	slurp, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return "", fmt.Errorf("reading %s: %v", url_, err)
+		return "", fmt.Errorf("reading %s: %w", url_, err)
 	}
 	return string(slurp), nil
 }
 
-// copyFromURL downloads srcURL to dstFile.
-func copyFromURL(dstFile, srcURL string) (err error) {
-	f, err := os.Create(dstFile)
+// copyFromURL downloads srcURL to dstFile, by way of a partial download
+// beside it that is renamed into place once complete. If offset is
+// positive, the partial download already holds that many bytes of srcURL
+// and the rest is appended, unless the server says the file has changed
+// since. It returns the number of bytes transferred and the host that
+// sent them, which a mirror may have done in place of srcURL's.
+func (d *Downloader) copyFromURL(ctx context.Context, dstFile, srcURL string, offset int64) (n int64, host string, err error) {
+	host = hostOf(srcURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srcURL, nil)
 	if err != nil {
-		return err
+		return n, host, err
 	}
-	defer func() {
-		if err != nil {
-			_ = f.Close()
-			_ = os.Remove(dstFile)
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		if info, ok := readPartialInfo(dstFile); ok && info.ifRange() != "" {
+			req.Header.Set("If-Range", info.ifRange())
 		}
-	}()
-	c := &http.Client{
-		Transport: &userAgentTransport{&http.Transport{
-			// It's already compressed. Prefer accurate ContentLength.
-			// (Not that GCS would try to compress it, though)
-			DisableCompression: true,
-			DisableKeepAlives:  true,
-			Proxy:              http.ProxyFromEnvironment,
-		}},
-	}
-	res, err := c.Get(srcURL)
+	}
+	res, err := d.client.Do(req)
 	if err != nil {
-		return err
+		return n, host, err
 	}
 	defer func() {
 		_ = res.Body.Close()
 	}()
-	if res.StatusCode != http.StatusOK {
-		return errors.New(res.Status)
+	host = responseHost(res, srcURL)
+	oflag := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	switch res.StatusCode {
+	case http.StatusOK:
+		// The server ignored the Range header, if any, or the file
+		// changed. Start over.
+		if offset > 0 {
+			log.Printf("Note: the server sent all of %v, so its download starts over", srcURL)
+		}
+		offset = 0
+	case http.StatusPartialContent:
+		if want := fmt.Sprintf("bytes %d-", offset); offset == 0 || !strings.HasPrefix(res.Header.Get("Content-Range"), want) {
+			return n, host, fmt.Errorf("server sent unexpected range %q", res.Header.Get("Content-Range"))
+		}
+		oflag = os.O_WRONLY | os.O_APPEND
+	default:
+		return n, host, errors.New(res.Status)
+	}
+	partial := dstFile + partialSuffix
+	if offset == 0 {
+		removePartial(dstFile)
+		if res.ContentLength != -1 {
+			// Without the size, a partial download can't be resumed.
+			if err := writePartialInfo(dstFile, newPartialInfo(srcURL, res)); err != nil {
+				return n, host, err
+			}
+		}
 	}
-	pw := &progressWriter{w: f, total: res.ContentLength}
-	n, err := io.Copy(pw, res.Body)
+	f, err := os.OpenFile(partial, oflag, 0644)
 	if err != nil {
-		return err
+		return n, host, err
+	}
+	defer func() {
+		if err != nil {
+			_ = f.Close()
+			// Keep what we have if it can be resumed later.
+			if !d.opts.Resume {
+				removePartial(dstFile)
+			}
+		}
+	}()
+	total := res.ContentLength
+	if total != -1 {
+		total += offset
+	}
+	pw := &progressWriter{w: f, n: offset, total: total, url: srcURL, em: d.emitter(), m: d.newMeter("Downloaded", total, offset, true)}
+	defer pw.m.stop()
+	var body io.Reader = res.Body
+	if d.opts.MaxRate > 0 {
+		body = newRateLimitedReader(ctx, body, d.opts.MaxRate)
+	}
+	n, err = io.Copy(pw, body)
+	if err != nil {
+		return n, host, err
 	}
 	if res.ContentLength != -1 && res.ContentLength != n {
-		return fmt.Errorf("copied %v bytes; expected %v", n, res.ContentLength)
+		return n, host, fmt.Errorf("copied %v bytes; expected %v", n, res.ContentLength)
+	}
+	pw.m.finish(pw.n, "")
+	pw.em.emit(DownloadProgress{URL: srcURL, Bytes: pw.n, Total: total})
+	if err = f.Close(); err != nil {
+		return n, host, err
 	}
-	pw.update() // 100%
-	return f.Close()
+	if err = os.Rename(partial, dstFile); err != nil {
+		return n, host, err
+	}
+	removePartial(dstFile)
+	return n, host, nil
 }
 
+// A progressWriter counts the bytes written through it to w, showing
+// them on m and reporting them to em.
 type progressWriter struct {
 	w     io.Writer
 	n     int64
 	total int64
 	last  time.Time
-}
-
-func (p *progressWriter) update() {
-	end := " ..."
-	if p.n == p.total {
-		end = ""
-	}
-	_, _ = fmt.Fprintf(os.Stderr, "Downloaded %5.1f%% (%*d / %d bytes)%s\n",
-		(100.0*float64(p.n))/float64(p.total),
-		ndigits(p.total), p.n, p.total, end)
-}
-
-func ndigits(i int64) int {
-	var n int
-	for ; i != 0; i /= 10 {
-		n++
-	}
-	return n
+	url   string
+	em    *emitter
+	m     *meter
 }
 
 func (p *progressWriter) Write(buf []byte) (n int, err error) {
 	n, err = p.w.Write(buf)
 	p.n += int64(n)
+	p.m.update(p.n, "")
 	if now := time.Now(); now.Unix() != p.last.Unix() {
-		p.update()
+		p.em.progress(DownloadProgress{URL: p.url, Bytes: p.n, Total: p.total})
 		p.last = now
 	}
 	return
@@ -396,19 +978,11 @@ func getOS() string {
 	return runtime.GOOS
 }
 
-// versionArchiveURL returns the zip or tar.gz URL of the given Go version.
-func versionArchiveURL(version string) string {
-	goos := getOS()
-
-	ext := ".tar.gz"
-	if goos == "windows" {
-		ext = ".zip"
-	}
-	arch := runtime.GOARCH
-	if goos == "linux" && runtime.GOARCH == "arm" {
-		arch = "armv6l"
-	}
-	return "https://dl.google.com/go/" + version + "." + goos + "-" + arch + ext
+// versionArchiveURL returns the zip or tar.gz URL of the given Go version
+// for this machine, relative to baseURL, which ends in a slash.
+func versionArchiveURL(baseURL, version string) string {
+	arch, _ := hostArch()
+	return baseURL + archiveName(version, getOS(), arch)
 }
 
 const caseInsensitiveEnv = runtime.GOOS == "windows"
@@ -425,11 +999,7 @@ func exe() string {
 }
 
 func goroot(version string) (string, error) {
-	home, err := homedir()
-	if err != nil {
-		return "", fmt.Errorf("failed to get home directory: %v", err)
-	}
-	return filepath.Join(home, "Cache/go_sdk", version), nil
+	return defaultLocator.Goroot(version)
 }
 
 func homedir() (string, error) {
@@ -439,7 +1009,10 @@ func homedir() (string, error) {
 	// prioritize $HOME. See also Issue 26463.
 	switch getOS() {
 	case "plan9":
-		return "", fmt.Errorf("%q not yet supported", runtime.GOOS)
+		if dir := os.Getenv("home"); dir != "" {
+			return dir, nil
+		}
+		return "", errors.New("can't find user home directory; $home is empty")
 	case "windows":
 		if dir := os.Getenv("USERPROFILE"); dir != "" {
 			return dir, nil
@@ -463,18 +1036,39 @@ func validRelPath(p string) bool {
 	return true
 }
 
+// A userAgentTransport sends every request with userAgent, or the default
+// User-Agent if it is empty, and with the extra headers in header.
 type userAgentTransport struct {
-	rt http.RoundTripper
+	userAgent string
+	header    http.Header
+	rt        http.RoundTripper
 }
 
 func (uat userAgentTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	// A RoundTripper mustn't modify the request it is given.
+	r = r.Clone(r.Context())
+	for name, values := range uat.header {
+		for _, v := range values {
+			r.Header.Add(name, v)
+		}
+	}
+	ua := uat.userAgent
+	if ua == "" {
+		ua = defaultUserAgent()
+	}
+	r.Header.Set("User-Agent", ua)
+	return uat.rt.RoundTrip(r)
+}
+
+// defaultUserAgent returns the User-Agent requests are sent with unless
+// the user_agent setting says otherwise.
+func defaultUserAgent() string {
 	version := runtime.Version()
 	if strings.Contains(version, "devel") {
 		// Strip the SHA hash and date. We don't want spaces or other tokens (see RFC2616 14.43)
 		version = "devel"
 	}
-	r.Header.Set("User-Agent", "golang-x-build-version/"+version)
-	return uat.rt.RoundTrip(r)
+	return "golang-x-build-version/" + version
 }
 
 // dedupEnv returns a copy of env with any duplicates removed, in favor of
@@ -5,7 +5,14 @@
 package version
 
 import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -33,3 +40,180 @@ func TestDedupEnv(t *testing.T) {
 		}
 	}
 }
+
+func TestGoCommandEnv(t *testing.T) {
+	root := filepath.Join("sdk", "go1.99")
+	t.Setenv(pathVar(), "elsewhere")
+	cmd := goCommand(context.Background(), root, "version")
+	want := []string{
+		"GOROOT=" + root,
+		pathVar() + "=" + filepath.Join(root, "bin") + string(filepath.ListSeparator) + "elsewhere",
+	}
+	for _, w := range want {
+		found := false
+		for _, kv := range cmd.Env {
+			if kv == w {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("goCommand environment lacks %q", w)
+		}
+	}
+	if !strings.HasPrefix(filepath.Base(cmd.Path), "go") {
+		t.Errorf("goCommand runs %s; want the toolchain's go", cmd.Path)
+	}
+}
+
+func TestToolchainCommand(t *testing.T) {
+	root, elsewhere := t.TempDir(), t.TempDir()
+	for _, file := range []string{filepath.Join(root, "bin", "gofmt"+exe()), filepath.Join(elsewhere, "gofmt"+exe()), filepath.Join(elsewhere, "make"+exe())} {
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(file, []byte("#!/bin/sh\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv(pathVar(), elsewhere)
+	for name, want := range map[string]string{
+		"gofmt": filepath.Join(root, "bin", "gofmt"+exe()), // the toolchain's comes first
+		"make":  filepath.Join(elsewhere, "make"+exe()),
+	} {
+		cmd, err := toolchainCommand(context.Background(), root, name, "-h")
+		if err != nil {
+			t.Errorf("toolchainCommand(%s): %v", name, err)
+			continue
+		}
+		if cmd.Path != want {
+			t.Errorf("toolchainCommand(%s) runs %s; want %s", name, cmd.Path, want)
+		}
+	}
+	if _, err := toolchainCommand(context.Background(), root, "mage"); err == nil {
+		t.Errorf("toolchainCommand(mage) found a command that isn't on PATH")
+	}
+	if got := os.Getenv(pathVar()); got != elsewhere {
+		t.Errorf("after toolchainCommand, %s = %q; want it left as %q", pathVar(), got, elsewhere)
+	}
+}
+
+func TestPartialRelease(t *testing.T) {
+	for name, want := range map[string]bool{
+		"go1.22":    true,
+		"go1.21":    true,
+		"go1.22.0":  false,
+		"go1.22.7":  false,
+		"go1.22rc1": false,
+		"go1.20":    false,
+		"go1.9":     false,
+	} {
+		v, err := ParseVersion(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := partialRelease(v, name); got != want {
+			t.Errorf("partialRelease(%s) = %v; want %v", name, got, want)
+		}
+	}
+}
+
+func TestNewestPatch(t *testing.T) {
+	ctx := context.Background()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	sdk := t.TempDir()
+	makeTree(t, sdk, map[string]string{
+		"go1.22.5/" + unpackedOkay:  "",
+		"go1.22.6/" + unpackedOkay:  "",
+		"go1.23rc1/" + unpackedOkay: "",
+	})
+	l := &Locator{Root: sdk}
+	online := newCatalogServer(t).catalog(t.TempDir())
+	offline := &Catalog{CacheDir: t.TempDir(), Offline: true}
+	tests := []struct {
+		c    *Catalog
+		name string
+		want string // or, if it starts with !, the error
+	}{
+		{online, "go1.22", "go1.22.7"},
+		{online, "go1.21", "go1.21.13"},
+		{online, "go1.23", "!no stable release of go1.23 found"},
+		{offline, "go1.22", "go1.22.6"},
+		{offline, "go1.23", "!offline"},
+		{offline, "go1.21", "!offline"},
+	}
+	for _, tt := range tests {
+		v, _ := ParseVersion(tt.name)
+		got, err := newestPatch(ctx, tt.c, l, v)
+		if strings.HasPrefix(tt.want, "!") {
+			if err == nil || !strings.Contains(err.Error(), tt.want[1:]) {
+				t.Errorf("newestPatch(%s) = %q, %v; want error containing %q", tt.name, got, err, tt.want[1:])
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("newestPatch(%s) = %q, %v; want %s", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestResolvePartial(t *testing.T) {
+	const listing = `[
+ {"version": "go1.22.7", "stable": true, "files": []},
+ {"version": "go1.22.6", "stable": true, "files": []},
+ {"version": "go1.20.14", "stable": true, "files": []},
+ {"version": "go1.20", "stable": true, "files": []}
+]`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(listing))
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+	c := &Catalog{URL: srv.URL, CacheDir: t.TempDir(), Client: srv.Client()}
+	l := &Locator{Root: t.TempDir()}
+	for name, want := range map[string]string{
+		"go1.20":   "go1.20",
+		"go1.22":   "go1.22.7",
+		"go1.22.6": "go1.22.6",
+	} {
+		r, err := c.Resolve(ctx, name)
+		if err != nil || r.Version.String() != want {
+			t.Errorf("Resolve(%s) = %v, %v; want %s", name, r.Version, err, want)
+		}
+		// As resolvePartial does for the wrappers.
+		v, _ := ParseVersion(name)
+		got := name
+		if partialRelease(v, name) {
+			got, err = newestPatch(ctx, c, l, v)
+		}
+		if err != nil || got != want {
+			t.Errorf("the wrappers resolve %s to %q, %v; want %s", name, got, err, want)
+		}
+	}
+}
+
+func TestNewestLatest(t *testing.T) {
+	ctx := context.Background()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	sdk := t.TempDir()
+	l := &Locator{Root: sdk}
+
+	if got, err := newestLatest(ctx, newCatalogServer(t).catalog(t.TempDir()), l); err != nil || got != "go1.22.7" {
+		t.Errorf("newestLatest online = %q, %v; want go1.22.7", got, err)
+	}
+	offline := &Catalog{CacheDir: t.TempDir(), Offline: true}
+	if _, err := newestLatest(ctx, offline, l); err == nil {
+		t.Errorf("newestLatest offline with nothing installed succeeded; want an error")
+	}
+	makeTree(t, sdk, map[string]string{
+		"go1.21.13/" + unpackedOkay: "",
+		"go1.22.6/" + unpackedOkay:  "",
+		"go1.23rc1/" + unpackedOkay: "",
+	})
+	if got, err := newestLatest(ctx, offline, l); err != nil || got != "go1.22.6" {
+		t.Errorf("newestLatest offline = %q, %v; want go1.22.6", got, err)
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"log"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// pooledFileMax is the size of the largest file that a writePool writes,
+// holding it in memory until it does; larger ones are written as they are
+// read, so that the memory a writePool takes is bounded.
+const pooledFileMax = 1 << 20
+
+// A writePool writes files out on several goroutines, so that unpacking
+// a tar stream of thousands of small files isn't held up by creating,
+// writing and closing each in turn.
+type writePool struct {
+	jobs []chan writeJob // a file is always written by the same one
+	wg   sync.WaitGroup
+	once sync.Once
+
+	pooled map[string]bool // paths given to a goroutine to write
+
+	mu  sync.Mutex
+	err error // the first a write failed with
+}
+
+type writeJob struct {
+	path    string
+	mode    os.FileMode
+	data    []byte
+	modTime time.Time
+	done    chan struct{} // if set, closed instead of writing
+}
+
+// newWritePool returns a writePool with a goroutine for each CPU, up to 8.
+// It must be waited for.
+func newWritePool() *writePool {
+	n := runtime.GOMAXPROCS(0)
+	if n > 8 {
+		n = 8
+	}
+	p := &writePool{pooled: map[string]bool{}}
+	p.wg.Add(n)
+	for i := 0; i < n; i++ {
+		jobs := make(chan writeJob, 2)
+		p.jobs = append(p.jobs, jobs)
+		go func() {
+			defer p.wg.Done()
+			for j := range jobs {
+				if j.done != nil {
+					close(j.done)
+					continue
+				}
+				if p.failed() != nil {
+					continue
+				}
+				if _, err := writeUnpackedFile(j.path, j.mode, bytes.NewReader(j.data), int64(len(j.data)), j.modTime); err != nil {
+					p.mu.Lock()
+					if p.err == nil {
+						p.err = err
+					}
+					p.mu.Unlock()
+				}
+			}
+		}()
+	}
+	return p
+}
+
+// write writes size bytes read from r to path, with mode and modTime, on
+// one of p's goroutines if the file is small, and returns the number
+// written, or the error an earlier write failed with. It must be called
+// from one goroutine.
+func (p *writePool) write(path string, mode os.FileMode, r io.Reader, size int64, modTime time.Time) (int64, error) {
+	if err := p.failed(); err != nil {
+		return 0, err
+	}
+	// An archive may hold a file twice, the later one taking the place
+	// of the earlier, so both go to the same goroutine, in order.
+	jobs := p.jobs[fnvHash(path)%uint32(len(p.jobs))]
+	if size > pooledFileMax {
+		if p.pooled[path] {
+			done := make(chan struct{})
+			jobs <- writeJob{done: done}
+			<-done
+		}
+		return writeUnpackedFile(path, mode, r, size, modTime)
+	}
+	data := make([]byte, size)
+	if n, err := io.ReadFull(r, data); err != nil {
+		return int64(n), fmt.Errorf("error writing to %s: %v", path, err)
+	}
+	p.pooled[path] = true
+	jobs <- writeJob{path: path, mode: mode, data: data, modTime: modTime}
+	return size, nil
+}
+
+func fnvHash(s string) uint32 {
+	h := fnv.New32a()
+	_, _ = io.WriteString(h, s)
+	return h.Sum32()
+}
+
+func (p *writePool) failed() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+// wait waits for the files given to p to be written, and returns the
+// first error writing one. p takes no more files after.
+func (p *writePool) wait() error {
+	p.once.Do(func() {
+		for _, jobs := range p.jobs {
+			close(jobs)
+		}
+	})
+	p.wg.Wait()
+	return p.failed()
+}
+
+// writeUnpackedFile writes the size bytes read from r to path, with mode
+// and modTime, and returns the number written.
+func writeUnpackedFile(path string, mode os.FileMode, r io.Reader, size int64, modTime time.Time) (int64, error) {
+	wf, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, mode.Perm())
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(wf, r)
+	if closeErr := wf.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return n, fmt.Errorf("error writing to %s: %v", path, err)
+	}
+	if n != size {
+		return n, fmt.Errorf("only wrote %d bytes to %s; expected %d", n, path, size)
+	}
+	if !modTime.IsZero() {
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			// benign error. Gerrit doesn't even set the
+			// modtime in these, and we don't end up relying
+			// on it anywhere (the gomote push command relies
+			// on digests only), so this is a little pointless
+			// for now.
+			log.Printf("error changing modtime: %v", err)
+		}
+	}
+	return n, nil
+}
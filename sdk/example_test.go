@@ -0,0 +1,79 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sdk_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+
+	"github.com/rustatian/dl/sdk"
+)
+
+func ExampleParse() {
+	v, err := sdk.Parse("go1.22rc2")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(v.Major, v.Minor, v.Pre, v.PreNum)
+	// Output: 1 22 rc 2
+}
+
+func ExampleSort() {
+	var vs []sdk.Version
+	for _, s := range []string{"go1.10", "go1.22.0", "go1.9", "go1.22rc1", "go1.22beta1"} {
+		v, err := sdk.Parse(s)
+		if err != nil {
+			log.Fatal(err)
+		}
+		vs = append(vs, v)
+	}
+	sdk.Sort(vs)
+	fmt.Println(vs)
+	// Output: [go1.9 go1.10 go1.22beta1 go1.22rc1 go1.22.0]
+}
+
+func ExampleVersion_Run() {
+	ctx := context.Background()
+	v, err := sdk.Parse("go1.22.7")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if !v.Installed() {
+		if err := v.Install(ctx, nil); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if err := v.Run(ctx, "version"); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func ExampleTip() {
+	ctx := context.Background()
+	tip := sdk.Tip()
+	if !tip.Installed() {
+		if err := tip.Install(ctx, nil); err != nil {
+			log.Fatal(err)
+		}
+	}
+	root, err := tip.GorootPath()
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("gotip is installed in", root)
+}
+
+func ExampleLocator() {
+	loc := &sdk.Locator{Root: "/opt/go-sdks"}
+	v, err := loc.Parse("go1.21.13")
+	if err != nil {
+		log.Fatal(err)
+	}
+	root, _ := v.GorootPath()
+	fmt.Println(filepath.ToSlash(root))
+	// Output: /opt/go-sdks/go1.21.13
+}
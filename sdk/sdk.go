@@ -0,0 +1,274 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package sdk installs and runs specific versions of Go from programs.
+//
+// It is the library counterpart of the go1.N.M and gotip commands, and
+// installs toolchains to the same locations, so a toolchain installed by
+// either is visible to the other:
+//
+//	v, err := sdk.Parse("go1.22.7")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	if !v.Installed() {
+//		if err := v.Install(ctx, nil); err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+//	if err := v.Run(ctx, "build", "./..."); err != nil {
+//		log.Fatal(err)
+//	}
+package sdk
+
+import (
+	"io"
+
+	"github.com/rustatian/dl/internal/version"
+)
+
+// A Version is a published Go release, such as go1.22.7. It implements
+// Toolchain, and is ordered by its Compare method.
+type Version = version.Version
+
+// A Toolchain is a Go toolchain that can be installed and run: either
+// a Version or the development tree returned by Tip.
+type Toolchain = version.Toolchain
+
+// A Locator knows where toolchains are installed. The zero Locator uses
+// the same directories as the go1.N.M and gotip commands.
+type Locator = version.Locator
+
+// DownloaderOptions configures how toolchains are downloaded.
+// A nil *DownloaderOptions uses the defaults.
+type DownloaderOptions = version.DownloaderOptions
+
+// Doer is the subset of *http.Client used to download toolchains.
+type Doer = version.Doer
+
+// A Downloader fetches and installs releases with a fixed set of options.
+type Downloader = version.Downloader
+
+// A ChecksumPolicy says how downloaded archives are verified.
+type ChecksumPolicy = version.ChecksumPolicy
+
+// Checksum policies.
+const (
+	ChecksumRequire     = version.ChecksumRequire
+	ChecksumIfPublished = version.ChecksumIfPublished
+	ChecksumSkip        = version.ChecksumSkip
+)
+
+// DefaultBaseURL is where release archives are downloaded from by default.
+const DefaultBaseURL = version.DefaultBaseURL
+
+// ChinaBaseURL and ChinaCatalogURL are the official mirror of the download
+// site and of the release listing for mainland China.
+const (
+	ChinaBaseURL    = version.ChinaBaseURL
+	ChinaCatalogURL = version.ChinaCatalogURL
+)
+
+// NewDownloader validates opts and returns a Downloader using them.
+func NewDownloader(opts DownloaderOptions) (*Downloader, error) {
+	return version.NewDownloader(opts)
+}
+
+// FromEnvironment returns the DownloaderOptions configured by the GODL_*
+// environment variables, as used by the go1.N.M commands.
+func FromEnvironment() (DownloaderOptions, error) {
+	return version.FromEnvironment()
+}
+
+// A Config is the configuration of the go1.N.M, gotip and dl commands,
+// resolved from their config file and the environment.
+type Config = version.Config
+
+// LoadConfig resolves the configuration the commands would use, reading
+// the config file named file, if non-empty, instead of the default one.
+func LoadConfig(file string) (*Config, error) {
+	return version.LoadConfig(file)
+}
+
+// A JournalEntry records an install by the go1.N.M or gotip commands.
+type JournalEntry = version.JournalEntry
+
+// JournalFile returns the install journal the commands append to.
+func JournalFile() (string, error) {
+	return version.JournalFile()
+}
+
+// ReadJournal returns the entries of the install journal file, oldest
+// first.
+func ReadJournal(file string) ([]JournalEntry, error) {
+	return version.ReadJournal(file)
+}
+
+// A Lockfile pins a project's toolchain and its archive checksums, as
+// written by dl lock.
+type Lockfile = version.Lockfile
+
+// A LockedArchive is the archive a Lockfile pins for a platform.
+type LockedArchive = version.LockedArchive
+
+// ParseLockfile parses a godl.lock file.
+func ParseLockfile(data []byte) (*Lockfile, error) {
+	return version.ParseLockfile(data)
+}
+
+// A Manifest lists a machine's toolchains and their archive checksums,
+// as written by dl export-manifest.
+type Manifest = version.Manifest
+
+// A ManifestEntry is a release in a Manifest.
+type ManifestEntry = version.ManifestEntry
+
+// A ManifestArchive pins the archive of a release for a platform.
+type ManifestArchive = version.ManifestArchive
+
+// A ManifestTip is the gotip tree in a Manifest.
+type ManifestTip = version.ManifestTip
+
+// ParseManifest parses a manifest written by dl export-manifest.
+func ParseManifest(data []byte) (*Manifest, error) {
+	return version.ParseManifest(data)
+}
+
+// Parse parses a Go release name such as "go1.22.7" or "go1.23rc1".
+// Invalid names are reported as a *VersionError.
+func Parse(s string) (Version, error) {
+	return version.ParseVersion(s)
+}
+
+// A VersionError reports a string that is not a valid Go release name,
+// with a suggested correction for near misses.
+type VersionError = version.VersionError
+
+// Tip returns the toolchain built from the Go development tree, as used
+// by the gotip command.
+func Tip() Toolchain {
+	return version.Tip()
+}
+
+// Sort sorts vs in increasing version order.
+func Sort(vs []Version) {
+	version.SortVersions(vs)
+}
+
+// Events reported through DownloaderOptions.Events. See the Event type
+// for how they are delivered.
+type (
+	Event              = version.Event
+	Phase              = version.Phase
+	ResolutionDone     = version.ResolutionDone
+	DownloadProgress   = version.DownloadProgress
+	VerificationResult = version.VerificationResult
+	UnpackProgress     = version.UnpackProgress
+	BuildOutputLine    = version.BuildOutputLine
+	BuildProgress      = version.BuildProgress
+	Completed          = version.Completed
+	Failed             = version.Failed
+)
+
+// Install phases, as reported by Failed.
+const (
+	PhaseResolve  = version.PhaseResolve
+	PhaseDownload = version.PhaseDownload
+	PhaseVerify   = version.PhaseVerify
+	PhaseUnpack   = version.PhaseUnpack
+	PhaseBuild    = version.PhaseBuild
+)
+
+// A ChecksumError reports content whose SHA-256 digest is not the
+// expected one.
+type ChecksumError = version.ChecksumError
+
+// An OfflineError reports that an operation needed the network, which
+// DownloaderOptions.Offline forbids.
+type OfflineError = version.OfflineError
+
+// A NotFoundError reports that no published release matches a name.
+type NotFoundError = version.NotFoundError
+
+// Exit codes of the go1.N.M, gotip and dl commands. See version.ExitCode.
+const (
+	ExitOK           = version.ExitOK
+	ExitFailure      = version.ExitFailure
+	ExitUsage        = version.ExitUsage
+	ExitNotInstalled = version.ExitNotInstalled
+	ExitNotFound     = version.ExitNotFound
+	ExitNetwork      = version.ExitNetwork
+	ExitVerify       = version.ExitVerify
+	ExitBuild        = version.ExitBuild
+	ExitInterrupted  = version.ExitInterrupted
+)
+
+// ExitCode returns the exit code the commands use for err, which may
+// wrap any of the errors returned by a Downloader.
+func ExitCode(err error) int {
+	return version.ExitCode(err)
+}
+
+// VerifySHA256 reads r to EOF and checks that its SHA-256 digest is want,
+// given in hex with an optional "sha256:" prefix. If progress is non-nil,
+// it is called with the number of bytes read so far.
+func VerifySHA256(r io.Reader, want string, progress func(n int64)) error {
+	return version.VerifySHA256(r, want, progress)
+}
+
+// VerifyFileSHA256 is like VerifySHA256 for the contents of the named file.
+func VerifyFileSHA256(file, want string, progress func(n int64)) error {
+	return version.VerifyFileSHA256(file, want, progress)
+}
+
+// A Catalog answers questions about published Go releases, such as the
+// latest patch release of go1.22. The zero Catalog is ready to use.
+type Catalog = version.Catalog
+
+// A Release is a published Go release, as listed by the Catalog.
+type Release = version.Release
+
+// A File is a downloadable artifact of a Release.
+type File = version.File
+
+// A Filter selects releases from the Catalog.
+type Filter = version.Filter
+
+// DefaultCatalogURL is the listing of all published Go releases.
+const DefaultCatalogURL = version.DefaultCatalogURL
+
+// A Plan lists the steps installing a release would take, without taking
+// them. See Version.Plan and Downloader.Execute.
+type Plan = version.Plan
+
+// A Step is one action of a Plan.
+type Step = version.Step
+
+// A StepKind identifies what a Step does.
+type StepKind = version.StepKind
+
+// Kinds of Step.
+const (
+	StepDownload      = version.StepDownload
+	StepVerify        = version.StepVerify
+	StepUnpack        = version.StepUnpack
+	StepMarkInstalled = version.StepMarkInstalled
+)
+
+// Metrics is a set of telemetry hooks called during installs. See
+// DownloaderOptions.Metrics.
+type Metrics = version.Metrics
+
+// A VerifyOutcome is the result of checking an archive's checksum, as
+// reported to Metrics.Verify.
+type VerifyOutcome = version.VerifyOutcome
+
+// Outcomes of checksum verification.
+const (
+	VerifyOK          = version.VerifyOK
+	VerifyMismatch    = version.VerifyMismatch
+	VerifyUnpublished = version.VerifyUnpublished
+	VerifySkipped     = version.VerifySkipped
+	VerifyError       = version.VerifyError
+)